@@ -0,0 +1,78 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// batchChunkSize is the number of tags a DecodeBatch worker decodes at a time,
+// so workers rarely contend for work, but still share it evenly.
+const batchChunkSize = 256
+
+// DecodeBatch decodes each of data's entries with the chain, in parallel, and
+// returns their Results, in the same order. It's equivalent to calling the
+// chain's DecodeResult method for each, but spreads the work across at most the
+// chain's number of workers, set by WithWorkers, or GOMAXPROCS goroutines, which
+// decode chunks of the entries into a single Result slice, rather than using a
+// goroutine or channel send per entry. Small batches are decoded without
+// starting any goroutines.
+//
+// The Result slice is the only allocation shared across entries; DecodeBatch
+// doesn't reuse decode buffers. Each Result's URI, Value, and Warnings belong
+// to the caller and outlive the batch, so every entry allocates them, just as
+// DecodeResult does.
+//
+// The chain's decoders must be safe for concurrent use, as those in this module
+// are. data's entries must not be modified until DecodeBatch returns.
+func DecodeBatch(chain DecoderChain, data [][]byte) []Result {
+	results := make([]Result, len(data))
+	nChunks := (len(data) + batchChunkSize - 1) / batchChunkSize
+	workers := chain.workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > nChunks {
+		workers = nChunks
+	}
+	if workers <= 1 {
+		chain.decodeRange(data, results)
+		return results
+	}
+
+	var next int64 = -1
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				chunk := int(atomic.AddInt64(&next, 1))
+				if chunk >= nChunks {
+					return
+				}
+				start := chunk * batchChunkSize
+				end := start + batchChunkSize
+				if end > len(data) {
+					end = len(data)
+				}
+				chain.decodeRange(data[start:end], results[start:end])
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// decodeRange decodes each of data's entries into the Result at the same index.
+func (dc DecoderChain) decodeRange(data [][]byte, results []Result) {
+	for i, d := range data {
+		results[i] = dc.DecodeResult(d)
+	}
+}
@@ -0,0 +1,76 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+// batchData returns n tags' data, cycling through SGTINs with different
+// serials, data only the bittag decoder accepts, and data none accept.
+func batchData(w *expect.TWrapper, n int) [][]byte {
+	sgtin := w.ShouldHaveResult(hex.DecodeString("3034257BF7194E4000001A85")).([]byte)
+	data := make([][]byte, n)
+	for i := range data {
+		switch i % 3 {
+		case 0:
+			d := append([]byte(nil), sgtin...)
+			binary.BigEndian.PutUint32(d[8:], uint32(i))
+			data[i] = d
+		case 1:
+			d := make([]byte, 12)
+			d[0] = 0x0F
+			binary.BigEndian.PutUint32(d[8:], uint32(i))
+			data[i] = d
+		case 2:
+			data[i] = []byte{byte(i)}
+		}
+	}
+	return data
+}
+
+func TestDecodeBatch(t *testing.T) {
+	w := expect.WrapT(t)
+
+	chain := w.ShouldHaveResult(NewDecoderChain([]string{
+		"sgtin", "bittag:test.com,2019-01-01:8.48.40"})).(DecoderChain)
+
+	for _, n := range []int{0, 1, 10, batchChunkSize, batchChunkSize*10 + 7} {
+		data := batchData(w, n)
+		results := DecodeBatch(chain, data)
+		w.As(n).ShouldBeEqual(len(results), n)
+		for i, r := range results {
+			uri, name, err := chain.Decode(data[i])
+			w.As(i).ShouldBeEqual(r.URI, uri)
+			w.As(i).ShouldBeEqual(r.Decoder, name)
+			w.As(i).ShouldBeEqual(r.Err != nil, err != nil)
+		}
+	}
+
+	results := DecodeBatch(chain, batchData(w, 3))
+	w.ShouldBeEqual(results[0].URI, "urn:epc:id:sgtin:0614141.812345.0")
+	w.ShouldBeEqual(results[0].Decoder, "sgtin")
+	w.ShouldBeEqual(results[1].URI, "tag:test.com,2019-01-01:15.0.1")
+	w.ShouldBeEqual(results[1].Decoder, "bittag:test.com,2019-01-01:8.48.40")
+	w.ShouldFail(results[2].Err)
+}
+
+func BenchmarkDecodeBatch(b *testing.B) {
+	w := expect.WrapT(b)
+	chain := w.ShouldHaveResult(NewDecoderChain([]string{
+		"sgtin", "bittag:test.com,2019-01-01:8.48.40"})).(DecoderChain)
+	data := batchData(w, 100000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DecodeBatch(chain, data)
+	}
+}
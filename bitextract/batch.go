@@ -0,0 +1,79 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// ExtractUInt64Batch extracts bits from each of the sources and stores them in
+// the corresponding index of dst, as ExtractUInt64 does for each individually.
+// It's intended for decoding many reads at once, as it checks the extractor's
+// length once and avoids ExtractUInt64's per-call buffer management.
+//
+// This method panics if the extractor's ByteLength is greater than 8, if dst
+// is shorter than srcs, or if any source is too short for the extractor. In
+// the last case, the values of earlier sources are already stored in dst.
+func (be BitExtractor) ExtractUInt64Batch(dst []uint64, srcs [][]byte) {
+	if be.dstLen > 8 {
+		panic(fmt.Sprintf("cannot extract %d bits into a uint64", be.bitLen))
+	}
+	if len(dst) < len(srcs) {
+		panic(fmt.Sprintf("destination size %d is too small "+
+			"(should be at least %d)", len(dst), len(srcs)))
+	}
+
+	var buff [8]byte
+	field := buff[8-be.dstLen:]
+	for i, src := range srcs {
+		if len(src) < be.srcLen+be.byteStart {
+			panic(fmt.Sprintf("cannot extract %d bytes from source %d[%d:%d], "+
+				"as it only has %d total bytes", be.srcLen, i,
+				be.byteStart, be.byteStart+be.srcLen, len(src)))
+		}
+		be.extractTo(field, src)
+		dst[i] = binary.BigEndian.Uint64(buff[:])
+	}
+}
+
+// ExplodeUInt64Batch explodes each of the sources into its fields as uint64s,
+// and stores them in dst, which must have room for NumFields values per source:
+// the fields of srcs[i] are stored in dst[i*NumFields():(i+1)*NumFields()].
+//
+// This method panics if any field is wider than 64 bits, if the final field is
+// RestOfData, if dst is too short, or if any source is too short for the
+// exploder's BitLength.
+func (exp BitExploder) ExplodeUInt64Batch(dst []uint64, srcs [][]byte) {
+	if exp.rest {
+		panic("cannot batch explode a RestOfData field")
+	}
+	n := len(exp.extractors)
+	for idx, be := range exp.extractors {
+		if be.dstLen > 8 {
+			panic(fmt.Sprintf("field %d has %d bits, which is too many "+
+				"for a uint64", idx, be.bitLen))
+		}
+	}
+	if len(dst) < n*len(srcs) {
+		panic(fmt.Sprintf("destination size %d is too small "+
+			"(should be at least %d)", len(dst), n*len(srcs)))
+	}
+
+	var buff [8]byte
+	for i, src := range srcs {
+		if len(src)*ByteSize < exp.bitLength {
+			panic(fmt.Sprintf("source %d has %d bytes, but %d bits are needed",
+				i, len(src), exp.bitLength))
+		}
+		for idx, be := range exp.extractors {
+			buff = [8]byte{}
+			be.extractTo(buff[8-be.dstLen:], src)
+			dst[i*n+idx] = binary.BigEndian.Uint64(buff[:])
+		}
+	}
+}
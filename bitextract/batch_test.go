@@ -0,0 +1,97 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"math/rand"
+	"testing"
+)
+
+func randomReads(n, size int) [][]byte {
+	srcs := make([][]byte, n)
+	for i := range srcs {
+		srcs[i] = make([]byte, size)
+		rand.Read(srcs[i])
+	}
+	return srcs
+}
+
+func TestBitExtractor_ExtractUInt64Batch(t *testing.T) {
+	w := expect.WrapT(t)
+
+	rand.Seed(7)
+	srcs := randomReads(100, 12)
+	for _, be := range []BitExtractor{New(0, 8), New(14, 44), New(58, 38),
+		New(3, 64), New(5, 1), New(2, 10).WithOrder(LSBFirst)} {
+		dst := make([]uint64, len(srcs))
+		be.ExtractUInt64Batch(dst, srcs)
+		for i, src := range srcs {
+			w.ShouldBeEqual(dst[i], be.ExtractUInt64(src))
+		}
+	}
+
+	assertPanics := func(f func()) {
+		defer func() {
+			recover()
+		}()
+		f()
+		t.Fatal("expected function to panic, but it didn't")
+	}
+	assertPanics(func() { New(0, 65).ExtractUInt64Batch(make([]uint64, 100), srcs) })
+	assertPanics(func() { New(0, 8).ExtractUInt64Batch(make([]uint64, 99), srcs) })
+	assertPanics(func() { New(90, 8).ExtractUInt64Batch(make([]uint64, 100), srcs) })
+}
+
+func TestBitExploder_ExplodeUInt64Batch(t *testing.T) {
+	w := expect.WrapT(t)
+
+	rand.Seed(8)
+	srcs := randomReads(100, 12)
+	exp := w.ShouldHaveResult(NewBitExploder([]int{8, 3, 3, 44, 38})).(BitExploder)
+	dst := make([]uint64, len(srcs)*exp.NumFields())
+	exp.ExplodeUInt64Batch(dst, srcs)
+	for i, src := range srcs {
+		for idx, be := range exp.extractors {
+			w.ShouldBeEqual(dst[i*exp.NumFields()+idx], be.ExtractUInt64(src))
+		}
+	}
+
+	assertPanics := func(f func()) {
+		defer func() {
+			recover()
+		}()
+		f()
+		t.Fatal("expected function to panic, but it didn't")
+	}
+	assertPanics(func() { exp.ExplodeUInt64Batch(dst[1:], srcs) })
+	assertPanics(func() { exp.ExplodeUInt64Batch(dst, randomReads(100, 11)) })
+	wide := w.ShouldHaveResult(NewBitExploder([]int{8, 65})).(BitExploder)
+	assertPanics(func() { wide.ExplodeUInt64Batch(dst, srcs) })
+}
+
+func BenchmarkBitExtractor_ExtractUInt64_loop(b *testing.B) {
+	srcs := randomReads(10000, 12)
+	dst := make([]uint64, len(srcs))
+	be := New(58, 38)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, src := range srcs {
+			dst[j] = be.ExtractUInt64(src)
+		}
+	}
+}
+
+func BenchmarkBitExtractor_ExtractUInt64Batch(b *testing.B) {
+	srcs := randomReads(10000, 12)
+	dst := make([]uint64, len(srcs))
+	be := New(58, 38)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		be.ExtractUInt64Batch(dst, srcs)
+	}
+}
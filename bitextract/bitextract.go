@@ -9,6 +9,9 @@ package bitextract
 import (
 	"encoding/binary"
 	"fmt"
+	"github.com/pkg/errors"
+	"math/big"
+	"math/bits"
 	"sync"
 )
 
@@ -27,14 +30,96 @@ const (
 // slice and the lengths of the section to be extracted.
 //
 // Create a new one with NewBitExtractor(start, length), then use Extract(src)
-// or ExtractTo(dst, src) to extract bits from byte slices.
+// or ExtractTo(dst, src) to extract bits from byte slices. By default, bits
+// are numbered from the highest-order bit of each byte; see WithOrder.
 //
-// BitExtractors are safe for concurrent extractions, provided callers don't use
-// SetBounds during their use.
+// BitExtractors are safe for concurrent extractions. WithBounds, WithOrder, and
+// the other With methods return modified copies rather than changing the
+// extractor, so extractors may be shared, such as in package-level tables,
+// without synchronization; only the deprecated SetBounds modifies one in place.
 type BitExtractor struct {
-	bitStart, byteStart, srcLen, dstLen int
-	bias                                alignmentBias
-	rshift, lshift, mask                uint8
+	bitStart, bitLen, byteStart, srcLen, dstLen int
+	order                                       BitOrder
+	reverse, littleEndian                       bool
+	bias                                        alignmentBias
+	rshift, lshift, mask                        uint8
+}
+
+// BitOrder determines how a BitExtractor numbers the bits within each byte.
+type BitOrder uint8
+
+const (
+	// MSBFirst numbers bits from the highest-order bit of each byte, so bit 0
+	// is the highest-order bit of the first byte, and the first bit of a field
+	// is its most significant bit. This is the default.
+	MSBFirst = BitOrder(iota)
+	// LSBFirst numbers bits from the lowest-order bit of each byte, so bit 0
+	// is the lowest-order bit of the first byte, bit 8 is the lowest-order bit
+	// of the second, and so on; the first bit of a field is its least
+	// significant bit. This matches how little endian systems lay out bit
+	// fields, as if the data were one large little endian integer.
+	LSBFirst
+)
+
+func (o BitOrder) String() string {
+	switch o {
+	case MSBFirst:
+		return "MSBFirst"
+	case LSBFirst:
+		return "LSBFirst"
+	}
+	return "Unknown bit order"
+}
+
+// WithOrder returns a copy of the BitExtractor that numbers bits in the given
+// order, both for extraction and insertion. Either way, extracted values and
+// those given for insertion are big endian and right-aligned.
+func (be BitExtractor) WithOrder(order BitOrder) BitExtractor {
+	be.order = order
+	be.setBounds(be.bitStart, be.bitLen)
+	return be
+}
+
+// Order returns the order in which the BitExtractor numbers bits.
+func (be BitExtractor) Order() BitOrder {
+	return be.order
+}
+
+// WithReversedBits returns a copy of the BitExtractor that reverses the order of
+// a field's bits, so that its first bit is its least significant, rather than
+// its most. Unlike WithOrder, this doesn't change which bits of the source are
+// in the field, only how they're arranged in the extracted value. Insertion
+// reverses them back.
+func (be BitExtractor) WithReversedBits(reverse bool) BitExtractor {
+	be.reverse = reverse
+	return be
+}
+
+// ReversedBits returns true if the BitExtractor reverses the order of a field's
+// bits.
+func (be BitExtractor) ReversedBits() bool {
+	return be.reverse
+}
+
+// WithLittleEndian returns a copy of the BitExtractor that interprets a field's
+// bytes as little endian, so that its first 8 bits are its least significant
+// byte. Extracted values (and those given for insertion) are still big endian,
+// so this swaps the order of the field's bytes. If the BitExtractor also
+// reverses bits, extraction reverses them before swapping the bytes.
+//
+// Since the field must consist of whole bytes, this panics if the extractor's
+// bit length isn't a multiple of 8, as does changing its bounds to such a
+// length while the option is set.
+func (be BitExtractor) WithLittleEndian(littleEndian bool) BitExtractor {
+	be.littleEndian = littleEndian
+	be.setBounds(be.bitStart, be.bitLen)
+	return be
+}
+
+// LittleEndian returns true if the BitExtractor interprets fields as little
+// endian.
+func (be BitExtractor) LittleEndian() bool {
+	return be.littleEndian
 }
 
 // ByteLength returns the number of bytes this extractor extracts.
@@ -44,6 +129,22 @@ func (be BitExtractor) ByteLength() int {
 	return be.dstLen
 }
 
+// BitStart returns the offset of the first bit this extractor extracts.
+func (be BitExtractor) BitStart() int {
+	return be.bitStart
+}
+
+// BitLength returns the number of bits this extractor extracts.
+func (be BitExtractor) BitLength() int {
+	return be.bitLen
+}
+
+// ByteStart returns the index of the first byte of the source that holds bits
+// this extractor extracts.
+func (be BitExtractor) ByteStart() int {
+	return be.byteStart
+}
+
 // Buffer returns a buffer of the size needed by ExtractTo.
 func (be BitExtractor) Buffer() []byte {
 	return make([]byte, be.ByteLength())
@@ -55,9 +156,11 @@ func (be BitExtractor) Buffer() []byte {
 // order bit of the 0'th index of the input array. len is the number of bits to
 // extract, starting from that start bit and moving "rightward" through the slice,
 // so that later bits are extracted from higher indexes.
+//
+// New panics if the bounds are invalid; use NewChecked if they may be.
 func New(start, len int) (be BitExtractor) {
 	be = BitExtractor{}
-	be.SetBounds(start, len)
+	be.setBounds(start, len)
 	return be
 }
 
@@ -68,22 +171,68 @@ func ifAligned(size, ifYes, ifNo int) int {
 	return ifNo
 }
 
-// SetBounds changes the BitExtractor's start bit and bit length.
-func (be *BitExtractor) SetBounds(start, len int) {
+// NewChecked is like New, but returns an error instead of panicking if start is
+// negative, len is less than 1, or their sum overflows, for use when the bounds
+// come from configuration or other untrusted input.
+func NewChecked(start, len int) (BitExtractor, error) {
+	if err := checkBounds(start, len); err != nil {
+		return BitExtractor{}, err
+	}
+	return New(start, len), nil
+}
+
+// checkBounds returns an error if start and len aren't valid bounds.
+func checkBounds(start, len int) error {
 	if start < 0 || len < 1 {
-		panic(fmt.Sprintf("illegal start (%d) or length (%d)", start, len))
+		return errors.Errorf("illegal start (%d) or length (%d)", start, len)
 	}
 	if start+len < 0 {
 		// check for overflow
-		panic(fmt.Sprintf("cannot handle such a large start (%d) and length (%d)",
-			start, len))
+		return errors.Errorf("cannot handle such a large start (%d) and length (%d)",
+			start, len)
+	}
+	return nil
+}
+
+// WithBounds returns a copy of the BitExtractor with the given start bit and bit
+// length, keeping its bit order and other options. It panics if the bounds are
+// invalid; see NewChecked.
+func (be BitExtractor) WithBounds(start, len int) BitExtractor {
+	be.setBounds(start, len)
+	return be
+}
+
+// SetBounds changes the BitExtractor's start bit and bit length, keeping its
+// bit order and other options. It panics if the bounds are invalid.
+//
+// Deprecated: SetBounds modifies the BitExtractor in place, which isn't safe
+// while it's in use by other goroutines; use WithBounds instead.
+func (be *BitExtractor) SetBounds(start, len int) {
+	be.setBounds(start, len)
+}
+
+// setBounds sets the BitExtractor's bounds and the values derived from them.
+func (be *BitExtractor) setBounds(start, len int) {
+	if err := checkBounds(start, len); err != nil {
+		panic(err.Error())
+	}
+	if be.littleEndian && len%ByteSize != 0 {
+		panic(fmt.Sprintf("little endian fields must be whole bytes, "+
+			"but the length is %d bits", len))
 	}
 
 	be.bitStart = start
+	be.bitLen = len
 	be.byteStart = start / ByteSize
 	be.dstLen = len/ByteSize + ifAligned(len, 0, 1)
 	srcEndByte := ((start + len) / ByteSize) - ifAligned(start+len, 1, 0)
 	be.srcLen = srcEndByte - be.byteStart + 1
+	if be.order == LSBFirst {
+		// Reversing the source bytes the field spans turns LSB-first bit
+		// numbering into MSB-first numbering, in which the field starts at
+		// this bit, and its first bit is its most significant.
+		start = be.srcLen*ByteSize - (start - be.byteStart*ByteSize) - len
+	}
 	srcEndOffset := (start + len - 1) % ByteSize
 	be.rshift = uint8(ByteSize - srcEndOffset - 1)
 	be.lshift = uint8(srcEndOffset + 1)
@@ -118,40 +267,160 @@ func (be BitExtractor) ExtractUInt64(src []byte) uint64 {
 	return binary.BigEndian.Uint64(buff)
 }
 
+// ExtractUint32 extracts bits from the source and interprets them as a big
+// endian uint32. This method panics if the extractor's ByteLength is greater
+// than 4.
+func (be BitExtractor) ExtractUint32(src []byte) uint32 {
+	if be.dstLen > 4 {
+		panic(fmt.Sprintf("cannot extract %d bits into a uint32", be.bitLen))
+	}
+	return uint32(be.ExtractUInt64(src))
+}
+
+// ExtractInt64 extracts bits from the source and interprets them as a two's
+// complement signed integer of the extractor's bit length, so that, e.g., the
+// 4 bits 1111 are -1. This method panics if the extractor's ByteLength is
+// greater than 8.
+func (be BitExtractor) ExtractInt64(src []byte) int64 {
+	v := be.ExtractUInt64(src)
+	if be.bitLen >= 64 {
+		return int64(v)
+	}
+	// shift the field's sign bit into the int64's, then back with extension
+	shift := uint(64 - be.bitLen)
+	return int64(v<<shift) >> shift
+}
+
+// ExtractBigInt extracts bits from the source and interprets them as a big
+// endian, non-negative integer. Unlike ExtractUInt64, it handles any length.
+func (be BitExtractor) ExtractBigInt(src []byte) *big.Int {
+	return new(big.Int).SetBytes(be.Extract(src))
+}
+
 func (be BitExtractor) Extract(src []byte) []byte {
 	dest := be.Buffer()
 	be.ExtractTo(dest, src)
 	return dest
 }
 
-func (be BitExtractor) ExtractTo(dest, src []byte) {
+// TryExtract is like Extract, but returns an error instead of panicking if src
+// is too short, for use with malformed or untrusted data.
+func (be BitExtractor) TryExtract(src []byte) ([]byte, error) {
+	dest := be.Buffer()
+	if err := be.TryExtractTo(dest, src); err != nil {
+		return nil, err
+	}
+	return dest, nil
+}
+
+// TryExtractTo is like ExtractTo, but returns an error instead of panicking if
+// src or dest is too short. If it returns an error, dest is unmodified.
+func (be BitExtractor) TryExtractTo(dest, src []byte) error {
+	if err := be.checkExtract(dest, src); err != nil {
+		return err
+	}
+	be.extractTo(dest, src)
+	return nil
+}
+
+// checkExtract returns an error if the slices are too short to extract bits
+// from src into dest.
+func (be BitExtractor) checkExtract(dest, src []byte) error {
 	if len(src) < be.srcLen+be.byteStart {
-		panic(fmt.Sprintf("cannot extract %d bytes from source[%d:%d], "+
+		return errors.Errorf("cannot extract %d bytes from source[%d:%d], "+
 			"as it only has %d total bytes",
-			be.srcLen, be.byteStart, be.byteStart+be.srcLen, len(src)))
+			be.srcLen, be.byteStart, be.byteStart+be.srcLen, len(src))
 	}
 
 	if len(dest) < be.dstLen {
-		panic(fmt.Sprintf("destination size %d is too small "+
-			"(should be at least %d)", len(dest), be.dstLen))
+		return errors.Errorf("destination size %d is too small "+
+			"(should be at least %d)", len(dest), be.dstLen)
+	}
+	return nil
+}
+
+func (be BitExtractor) ExtractTo(dest, src []byte) {
+	if err := be.checkExtract(dest, src); err != nil {
+		panic(err.Error())
+	}
+	be.extractTo(dest, src)
+}
+
+// extractTo extracts bits from src into dest, which must be long enough.
+func (be BitExtractor) extractTo(dest, src []byte) {
+	src = src[be.byteStart : be.byteStart+be.srcLen]
+	if be.order == LSBFirst {
+		var buff [16]byte
+		src = reverseBytes(buff[:0], src)
 	}
 
 	switch be.bias {
 	case srcAligned:
-		copy(dest, src[be.byteStart:be.byteStart+be.dstLen])
+		copy(dest, src[:be.dstLen])
 	case srcBiasPrev:
-		dest[0] = src[be.byteStart] >> be.rshift
-		for i := 1; i < be.dstLen; i++ {
+		dest[0] = src[0] >> be.rshift
+		i := 1
+		// Long fields are shifted 8 bytes at a time; each word of dest is the
+		// same word of src shifted down, with the previous byte shifted in.
+		for rs := uint(be.rshift); i+8 <= be.dstLen; i += 8 {
+			v := binary.BigEndian.Uint64(src[i:])
+			binary.BigEndian.PutUint64(dest[i:],
+				v>>rs|uint64(src[i-1])<<(64-rs))
+		}
+		for ; i < be.dstLen; i++ {
 			// previous byte shifts up; current byte shifts down
-			dest[i] = src[i+be.byteStart-1]<<(ByteSize-be.rshift) |
-				src[i+be.byteStart]>>be.rshift
+			dest[i] = src[i-1]<<(ByteSize-be.rshift) | src[i]>>be.rshift
 		}
 	case srcBiasNext:
-		for i := 0; i < be.dstLen; i++ {
+		i := 0
+		// as above, but with the next byte shifted in
+		for rs := uint(be.rshift); i+8 <= be.dstLen; i += 8 {
+			v := binary.BigEndian.Uint64(src[i:])
+			binary.BigEndian.PutUint64(dest[i:],
+				v<<(ByteSize-rs)|uint64(src[i+8])>>rs)
+		}
+		for ; i < be.dstLen; i++ {
 			// current byte shifts up; next byte shifts down
-			dest[i] = src[i+be.byteStart]<<(ByteSize-be.rshift) |
-				src[i+be.byteStart+1]>>be.rshift
+			dest[i] = src[i]<<(ByteSize-be.rshift) | src[i+1]>>be.rshift
 		}
 	}
 	dest[0] &= be.mask
+
+	if be.reverse {
+		reverseBits(dest[:be.dstLen], be.bitLen)
+	}
+	if be.littleEndian {
+		reverseInPlace(dest[:be.dstLen])
+	}
+}
+
+// reverseInPlace reverses the order of the bytes of b.
+func reverseInPlace(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
+
+// reverseBits reverses the order of the low width bits of b, which has the
+// fewest bytes that hold them.
+func reverseBits(b []byte, width int) {
+	reverseInPlace(b)
+	for i := range b {
+		b[i] = bits.Reverse8(b[i])
+	}
+	// the bits are now at the top of b, so shift them back down
+	pad := uint(len(b)*ByteSize - width)
+	for i := len(b) - 1; i > 0; i-- {
+		b[i] = b[i]>>pad | b[i-1]<<(ByteSize-pad)
+	}
+	b[0] >>= pad
+}
+
+// reverseBytes appends the bytes of src to dst in reverse order and returns
+// the extended buffer.
+func reverseBytes(dst, src []byte) []byte {
+	for i := len(src) - 1; i >= 0; i-- {
+		dst = append(dst, src[i])
+	}
+	return dst
 }
@@ -259,6 +259,33 @@ func TestBitExtractor_CompareToString(t *testing.T) {
 	}
 }
 
+func TestBitExtractor_bounds(t *testing.T) {
+	w := expect.WrapT(t)
+	for _, tc := range []struct{ start, length, byteStart int }{
+		{0, 1, 0}, {7, 2, 0}, {8, 8, 1}, {13, 100, 1}, {16, 1, 2},
+	} {
+		be := New(tc.start, tc.length)
+		w.As(tc).ShouldBeEqual(be.BitStart(), tc.start)
+		w.As(tc).ShouldBeEqual(be.BitLength(), tc.length)
+		w.As(tc).ShouldBeEqual(be.ByteStart(), tc.byteStart)
+	}
+}
+
+func TestBitExtractor_WithBounds(t *testing.T) {
+	w := expect.WrapT(t)
+
+	be := New(3, 10).WithOrder(LSBFirst)
+	moved := be.WithBounds(12, 4)
+	w.ShouldBeEqual(be, New(3, 10).WithOrder(LSBFirst))
+	w.ShouldBeEqual(moved, New(12, 4).WithOrder(LSBFirst))
+	w.ShouldBeEqual(moved.ExtractUInt64([]byte{0x00, 0xA0}), uint64(0xA))
+	w.ShouldBeEqual(moved.WithBounds(3, 10), be)
+
+	// the deprecated SetBounds still works in place
+	be.SetBounds(12, 4)
+	w.ShouldBeEqual(be, moved)
+}
+
 func BenchmarkBitExtractor_Extract(b *testing.B) {
 	start := 92
 	length := 391 - 92
@@ -301,6 +328,26 @@ func BenchmarkBitExtractor_ExtractTo(b *testing.B) {
 	}
 }
 
+func BenchmarkBitExtractor_ExtractTo_lengths(b *testing.B) {
+	buff := make([]byte, 128)
+	rand.Seed(1)
+	rand.Read(buff)
+
+	for _, length := range []int{7, 64, 299, 1000} {
+		for _, start := range []int{0, 3, 5} {
+			be := New(start, length)
+			result := be.Buffer()
+			b.Run(fmt.Sprintf("%d:%d", start, length), func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(be.ByteLength()))
+				for i := 0; i < b.N; i++ {
+					be.ExtractTo(result, buff)
+				}
+			})
+		}
+	}
+}
+
 func BenchmarkBitStringExtraction(b *testing.B) {
 	start := 92
 	length := 391 - 92
@@ -332,7 +379,7 @@ func BenchmarkBitExtractor_Extract_random(b *testing.B) {
 		rand.Read(buff)
 		buff[start/8] = 255
 
-		be.SetBounds(start, length)
+		be = be.WithBounds(start, length)
 		result := be.Extract(buff)
 		if result[0] == 0 {
 			b.Errorf("result[0] should always be > 0")
@@ -355,7 +402,7 @@ func BenchmarkBitExtractor_ExtractTo_random(b *testing.B) {
 		rand.Read(buff)
 		buff[start/8] = 255
 
-		be.SetBounds(start, length)
+		be = be.WithBounds(start, length)
 		be.ExtractTo(resultBuff[:be.ByteLength()], buff)
 		if resultBuff[0] == 0 {
 			b.Errorf("result[0] should always be > 0")
@@ -381,3 +428,75 @@ func BenchmarkBitStringExtraction_random(b *testing.B) {
 		}
 	}
 }
+
+func TestNewChecked(t *testing.T) {
+	w := expect.WrapT(t)
+
+	w.ShouldHaveError(NewChecked(-1, 0))
+	w.ShouldHaveError(NewChecked(1, 0))
+	w.ShouldHaveError(NewChecked(1, -1))
+	w.ShouldHaveError(NewChecked(-1, 1))
+	w.ShouldHaveError(NewChecked(1<<63-1, 1<<63-1))
+
+	be := w.ShouldHaveResult(NewChecked(5, 9)).(BitExtractor)
+	w.ShouldBeEqual(be, New(5, 9))
+}
+
+func TestBitExtractor_TryExtract(t *testing.T) {
+	w := expect.WrapT(t)
+
+	be := New(5, 9)
+	holds2Bytes := make([]byte, 2)
+	data, _ := hex.DecodeString("FCDF")
+
+	w.ShouldBeEqual(w.ShouldHaveResult(be.TryExtract(data)), []byte{0x01, 0x37})
+	w.ShouldSucceed(be.TryExtractTo(holds2Bytes, data))
+	w.ShouldBeEqual(holds2Bytes, []byte{0x01, 0x37})
+
+	w.ShouldHaveError(be.TryExtract(data[1:]))
+	w.ShouldHaveError(be.TryExtract(data[2:]))
+	w.ShouldHaveError(be.TryExtract(nil))
+	w.ShouldFail(be.TryExtractTo(holds2Bytes, data[1:]))
+	w.ShouldFail(be.TryExtractTo(holds2Bytes[1:], data))
+	w.ShouldFail(be.TryExtractTo(nil, data))
+}
+
+func TestBitExtractor_typedExtraction(t *testing.T) {
+	w := expect.WrapT(t)
+	data, _ := hex.DecodeString("FCDF0123456789ABCDEF")
+
+	w.ShouldBeEqual(New(5, 9).ExtractUint32(data), uint32(0x137))
+	w.ShouldBeEqual(New(0, 32).ExtractUint32(data), uint32(0xFCDF0123))
+
+	for _, tt := range []struct {
+		start, length int
+		expected      int64
+	}{
+		{0, 4, -1},    // 1111
+		{4, 4, -4},    // 1100
+		{8, 4, -3},    // 1101
+		{12, 4, -1},   // 1111
+		{16, 4, 0},    // 0000
+		{20, 4, 1},    // 0001
+		{5, 9, -201},  // 1 0011 0111
+		{0, 1, -1},    // 1
+		{16, 8, 1},    // 0000 0001
+		{0, 16, -801}, // 0xFCDF
+		{16, 64, 0x0123456789ABCDEF},
+		{0, 64, -0x0320FEDCBA987655},
+	} {
+		w.As(fmt.Sprintf("%d:%d", tt.start, tt.length)).
+			ShouldBeEqual(New(tt.start, tt.length).ExtractInt64(data), tt.expected)
+	}
+
+	w.ShouldBeEqual(New(0, 80).ExtractBigInt(data).Text(16), "fcdf0123456789abcdef")
+	w.ShouldBeEqual(New(5, 9).ExtractBigInt(data).Int64(), int64(0x137))
+	w.ShouldBeEqual(New(16, 8).ExtractBigInt(data).Int64(), int64(1))
+	w.ShouldBeEqual(New(8, 8).WithOrder(LSBFirst).ExtractInt64(data), int64(-33))
+
+	defer func() {
+		recover()
+	}()
+	New(0, 33).ExtractUint32(data)
+	t.Fatal("expected ExtractUint32 to panic, but it didn't")
+}
@@ -11,16 +11,75 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	"io"
+	"math/big"
 	"strconv"
 	"strings"
 )
 
 // BitExploder explodes a single byte into a series of byte slices by breaking it
 // into byte slices of predefined bit widths.
+//
+// A negative width (other than RestOfData) skips that many bits, such as those
+// reserved by a tag's layout, without producing a field for them. For example,
+// the widths {8, -3, 44} explode data into two fields: its first 8 bits and
+// the 44 bits following the next 3. In width strings parsed by SplitWidths,
+// skips are written with a leading '-', as in "8.-3.44".
 type BitExploder struct {
 	bitLength  int // sum of all bit lengths
 	expByteLen int // sum of all extractor byte lengths
 	extractors []BitExtractor
+	names      []string       // nil unless created by NewNamedBitExploder
+	indexes    map[string]int // of names
+	rest       bool           // if the final field is RestOfData
+	transforms []Transform    // nil, or one per field, if any has been set
+	parity     []parityCheck
+}
+
+// RestOfData may be used as the final width of a BitExploder to declare a field
+// consisting of all the data's bits following the other fields, however many
+// there are. This allows a single BitExploder to handle data whose final field,
+// such as a serial or user data, varies in length. In width strings parsed by
+// SplitWidths, it's written as "*".
+const RestOfData = -1 << 31
+
+// Field is a named bit field, for use with NewNamedBitExploder.
+type Field struct {
+	Name  string
+	Width int
+}
+
+// NewNamedBitExploder returns a new BitExploder that explodes byte data into a
+// series of consecutive fields with the given names and widths. Fields may then
+// be accessed by name, so that they don't need to be tracked by their index.
+// Names must be unique and non-empty, except for skipped bits (fields with
+// negative widths other than RestOfData), which are unnamed.
+func NewNamedBitExploder(fields []Field) (BitExploder, error) {
+	widths := make([]int, len(fields))
+	names := make([]string, 0, len(fields))
+	indexes := make(map[string]int, len(fields))
+	for i, f := range fields {
+		widths[i] = f.Width
+		if isSkip(f.Width) {
+			continue
+		}
+		if f.Name == "" {
+			return BitExploder{}, errors.Errorf("field %d has no name", i)
+		}
+		if _, dup := indexes[f.Name]; dup {
+			return BitExploder{}, errors.Errorf("field name %q is used "+
+				"more than once", f.Name)
+		}
+		indexes[f.Name] = len(names)
+		names = append(names, f.Name)
+	}
+
+	exp, err := NewBitExploder(widths)
+	if err != nil {
+		return exp, err
+	}
+	exp.names = names
+	exp.indexes = indexes
+	return exp, nil
 }
 
 // NewBitExploder returns a new BitExploder that explodes byte data into a series
@@ -39,50 +98,214 @@ func NewBitExploder(widths []int) (BitExploder, error) {
 // a slice of bit widths.
 //
 // It splits the string on the delimiter, trims spaces around entries, converts
-// the elements into ints, and returns the result. The purpose of this function
-// is to allow calls like:
+// the elements into ints, and returns the result. An entry of "*" is converted
+// to RestOfData. The purpose of this function is to allow calls like:
 //     w, err := SplitWidths("8.44.44")
 //     if err != nil {
 //         return err
 //     }
 //     NewBitDecoder(w)
+//
+// An entry of the form "WxN" repeats the width W N times, and a parenthesized
+// group of entries may be repeated the same way, so that array-like layouts
+// needn't be written out in full. For example, these are equivalent:
+//     SplitWidths("8.7x3.(4.12)x2", ".")
+//     SplitWidths("8.7.7.7.4.12.4.12", ".")
 func SplitWidths(conf, delim string) ([]int, error) {
+	entries, err := splitTopLevel(conf, delim)
+	if err != nil {
+		return nil, err
+	}
+
 	var r []int
-	for i, wStr := range strings.Split(conf, delim) {
+	for i, wStr := range entries {
 		wStr = strings.TrimSpace(wStr)
 		if wStr == "" {
 			return nil, errors.Errorf("width %d is empty", i)
 		}
-		w, err := strconv.Atoi(wStr)
-		if err != nil {
-			return nil, errors.Wrapf(err, "unable to convert width %d", i)
+		if wStr == "*" {
+			r = append(r, RestOfData)
+			continue
+		}
+
+		unit, count := wStr, 1
+		if x := strings.LastIndexByte(wStr, 'x'); x >= 0 && !strings.HasSuffix(wStr, ")") {
+			unit = strings.TrimSpace(wStr[:x])
+			count, err = strconv.Atoi(strings.TrimSpace(wStr[x+1:]))
+			if err != nil || count <= 0 {
+				return nil, errors.Errorf("width %d has an invalid repeat "+
+					"count: %q", i, wStr)
+			}
+		}
+
+		var ws []int
+		if strings.HasPrefix(unit, "(") && strings.HasSuffix(unit, ")") {
+			ws, err = SplitWidths(unit[1:len(unit)-1], delim)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid group in width %d", i)
+			}
+		} else {
+			w, err := strconv.Atoi(unit)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to convert width %d", i)
+			}
+			ws = []int{w}
+		}
+		for ; count > 0; count-- {
+			r = append(r, ws...)
 		}
-		r = append(r, w)
 	}
 	return r, nil
 }
 
-// SetWidths sets the decoder's expected bit widths specification.
+// splitTopLevel splits conf on the delimiter, except within parentheses.
+func splitTopLevel(conf, delim string) ([]string, error) {
+	if delim == "" {
+		return strings.Split(conf, delim), nil
+	}
+
+	var entries []string
+	depth, start := 0, 0
+	for i := 0; i < len(conf); i++ {
+		switch {
+		case conf[i] == '(':
+			depth++
+		case conf[i] == ')':
+			if depth == 0 {
+				return nil, errors.Errorf("unbalanced ')' at offset %d", i)
+			}
+			depth--
+		case depth == 0 && strings.HasPrefix(conf[i:], delim):
+			entries = append(entries, conf[start:i])
+			start = i + len(delim)
+			i += len(delim) - 1
+		}
+	}
+	if depth != 0 {
+		return nil, errors.New("unbalanced '('")
+	}
+	return append(entries, conf[start:]), nil
+}
+
+// SetWidths sets the decoder's expected bit widths specification. Since the
+// widths are unnamed, it removes any field names the BitExploder had. Widths
+// must be non-zero; negative widths skip bits, and the final width may be
+// RestOfData. There must be at least one field that isn't skipped.
 func (exp *BitExploder) SetWidths(widths []int) error {
 	if len(widths) == 0 {
 		return errors.New("widths slice is empty")
 	}
 
 	exp.bitLength = 0
-	exp.extractors = make([]BitExtractor, len(widths))
+	exp.expByteLen = 0
+	exp.names = nil
+	exp.indexes = nil
+	exp.transforms = nil
+	exp.parity = nil
+	exp.rest = widths[len(widths)-1] == RestOfData
+	if exp.rest {
+		widths = widths[:len(widths)-1]
+	}
+	exp.extractors = make([]BitExtractor, 0, len(widths))
 	for i, w := range widths {
-		if w <= 0 {
-			return errors.Errorf("widths must be >0, but width %d is %d", i, w)
+		switch {
+		case w == RestOfData:
+			return errors.Errorf("only the final width may be RestOfData, "+
+				"but width %d is", i)
+		case w == 0:
+			return errors.Errorf("widths must be non-zero, but width %d is 0", i)
+		case w < 0:
+			exp.bitLength -= w
+			continue
 		}
 		be := New(exp.bitLength, w)
-		exp.extractors[i] = be
+		exp.extractors = append(exp.extractors, be)
 		exp.bitLength += w
 		exp.expByteLen += be.ByteLength()
 	}
+	if exp.NumFields() == 0 {
+		return errors.New("widths must include at least one field " +
+			"that isn't skipped")
+	}
 	return nil
 }
 
-// BitLength returns the sum of the bit fields this BitExploder uses.
+// AddField adds a field of the given width that starts at an explicit bit
+// offset, rather than following the previous field. Fields added this way may
+// overlap other fields or leave gaps between them, so that the same bits may
+// be exploded as a whole and as separate parts; for instance, an SGTIN's
+// company prefix and item reference, along with the 44 bits they share.
+//
+// The name must be empty if the BitExploder's fields are unnamed, and must be
+// non-empty and unique if they are named; if the BitExploder doesn't have any
+// fields yet, a name makes it named. AddField returns an error if the start or
+// width are invalid, or if the BitExploder has a RestOfData field, which must
+// remain last.
+func (exp *BitExploder) AddField(name string, start, width int) error {
+	if exp.rest {
+		return errors.New("fields can't be added after a RestOfData field")
+	}
+	if err := checkBounds(start, width); err != nil {
+		return err
+	}
+
+	if err := exp.addName(name); err != nil {
+		return err
+	}
+
+	// copy the slices, since copies of the BitExploder share them
+	idx := len(exp.extractors)
+	be := New(start, width)
+	exp.extractors = append(exp.extractors[:idx:idx], be)
+	if exp.transforms != nil {
+		exp.transforms = append(exp.transforms[:idx:idx], nil)
+	}
+	exp.expByteLen += be.ByteLength()
+	if end := start + width; end > exp.bitLength {
+		exp.bitLength = end
+	}
+	return nil
+}
+
+// addName validates the name of a field about to be added, and if the fields are
+// named, adds it.
+func (exp *BitExploder) addName(name string) error {
+	named := exp.names != nil || (exp.NumFields() == 0 && name != "")
+	switch {
+	case named && name == "":
+		return errors.New("the BitExploder's fields are named, " +
+			"so the field must have a name")
+	case !named && name != "":
+		return errors.Errorf("the BitExploder's fields are unnamed, "+
+			"so the field can't be named %q", name)
+	case !named:
+		return nil
+	}
+	if _, dup := exp.indexes[name]; dup {
+		return errors.Errorf("field name %q is used more than once", name)
+	}
+
+	// copy them, since copies of the BitExploder share them
+	idx := exp.NumFields()
+	exp.names = append(exp.names[:idx:idx], name)
+	indexes := make(map[string]int, len(exp.indexes)+1)
+	for n, i := range exp.indexes {
+		indexes[n] = i
+	}
+	indexes[name] = idx
+	exp.indexes = indexes
+	return nil
+}
+
+// isSkip returns true if the width skips bits rather than declaring a field.
+func isSkip(w int) bool {
+	return w < 0 && w != RestOfData
+}
+
+// BitLength returns the sum of the bit fields this BitExploder uses, including
+// any skipped bits, or if fields were added with AddField, the offset of the end
+// of the furthest field. If its final field is RestOfData, this is the minimum
+// length of data it can explode, and doesn't include that field.
 func (exp BitExploder) BitLength() int {
 	return exp.bitLength
 }
@@ -107,28 +330,137 @@ func (exp BitExploder) Explode(data []byte) ([][]byte, error) {
 			len(data)*8, exp.bitLength)
 	}
 
+	if err := exp.checkParity(data); err != nil {
+		return nil, err
+	}
+
 	bt := exp.Buffer()
+	if be, ok := exp.restExtractor(data); ok {
+		bt[len(bt)-1] = be.Buffer()
+	}
 	exp.ExplodeTo(bt, data)
+	for idx, t := range exp.transforms {
+		f, err := exp.transform(t, idx, bt[idx], data)
+		if err != nil {
+			return nil, err
+		}
+		bt[idx] = f
+	}
 	return bt, nil
 }
 
+// SetTransform attaches a Transform to the field at the given index, which
+// Explode, ExplodeToMap, and ExtractField apply to the field's bits; passing a
+// nil Transform removes it. Since they would change the fields' lengths,
+// ExplodeTo, All, BitReader, and the batch methods ignore Transforms. Transforms
+// aren't applied to empty RestOfData fields.
+//
+// SetWidths removes any Transforms. SetTransform returns an error if the index
+// is out of range.
+func (exp *BitExploder) SetTransform(idx int, t Transform) error {
+	if idx < 0 || idx >= exp.NumFields() {
+		return errors.Errorf("field index %d is out of range [0, %d)",
+			idx, exp.NumFields())
+	}
+	// copy them, since copies of the BitExploder share the slice
+	transforms := make([]Transform, exp.NumFields())
+	copy(transforms, exp.transforms)
+	transforms[idx] = t
+	exp.transforms = transforms
+	return nil
+}
+
+// transform applies t, if it's not nil, to the field at idx extracted from data.
+func (exp BitExploder) transform(t Transform, idx int, field, data []byte) ([]byte, error) {
+	if t == nil || len(field) == 0 {
+		return field, nil
+	}
+	var width int
+	if idx < len(exp.extractors) {
+		width = exp.extractors[idx].bitLen
+	} else {
+		width = len(data)*ByteSize - exp.bitLength
+	}
+	f, err := t(field, width)
+	return f, errors.Wrapf(err, "unable to transform field %d", idx)
+}
+
+// ExplodeToUints explodes data as Explode does, then interprets each field as a
+// big endian, unsigned integer. It returns an error if data is too short, if
+// any field is wider than 64 bits, or if a Transform returns more than 8 bytes.
+// See ExplodeToBigInts for wider fields.
+func (exp BitExploder) ExplodeToUints(data []byte) ([]uint64, error) {
+	for idx, be := range exp.extractors {
+		if be.bitLen > 64 {
+			return nil, errors.Errorf("field %d has %d bits, which is too "+
+				"many for a uint64", idx, be.bitLen)
+		}
+	}
+
+	fields, err := exp.Explode(data)
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]uint64, len(fields))
+	for idx, f := range fields {
+		if len(f) > 8 {
+			return nil, errors.Errorf("field %d has %d bytes, which is too "+
+				"many for a uint64", idx, len(f))
+		}
+		for _, b := range f {
+			vals[idx] = vals[idx]<<ByteSize | uint64(b)
+		}
+	}
+	return vals, nil
+}
+
+// ExplodeToBigInts explodes data as Explode does, then interprets each field as
+// a big endian, unsigned integer of any width. It returns an error if data is
+// too short.
+func (exp BitExploder) ExplodeToBigInts(data []byte) ([]*big.Int, error) {
+	fields, err := exp.Explode(data)
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]*big.Int, len(fields))
+	for idx, f := range fields {
+		vals[idx] = new(big.Int).SetBytes(f)
+	}
+	return vals, nil
+}
+
+// restExtractor returns a BitExtractor for the RestOfData field of data, or
+// false if the BitExploder has no such field, or it has no bits in data.
+func (exp BitExploder) restExtractor(data []byte) (BitExtractor, bool) {
+	n := len(data)*ByteSize - exp.bitLength
+	if !exp.rest || n <= 0 {
+		return BitExtractor{}, false
+	}
+	return New(exp.bitLength, n), true
+}
+
 // ExplodeTo explodes the data into the dst byte slices.
 //
 // If there aren't enough destination slices, or any of the destination slices
-// are too small for their respective fields, ExtractTo will panic.
+// are too small for their respective fields, ExtractTo will panic. This includes
+// the slice for a RestOfData field, which must have room for all the data after
+// the other fields; if there is no such data, that slice is left as-is.
 func (exp BitExploder) ExplodeTo(dst [][]byte, data []byte) {
-	if len(dst) < len(exp.extractors) {
+	if len(dst) < exp.NumFields() {
 		panic(fmt.Sprintf("not enough destination slices (%d) to "+
-			"extract %d fields", len(dst), len(exp.extractors)))
+			"extract %d fields", len(dst), exp.NumFields()))
 	}
 	for idx, be := range exp.extractors {
 		// panics if len(dst[idx]) < be.ByteLength()
 		be.ExtractTo(dst[idx], data)
 	}
+	if be, ok := exp.restExtractor(data); ok {
+		be.ExtractTo(dst[len(exp.extractors)], data)
+	}
 }
 
 // ExplodedByteLength returns the minimum number of bytes necessary to store the
-// exploded bit fields.
+// exploded bit fields, not including any RestOfData field.
 //
 // This number is very likely larger than the number of bytes needed to store
 // the unexploded bit fields; the exception to this is the case when each bit
@@ -141,27 +473,128 @@ func (exp BitExploder) ExplodedByteLength() int {
 //
 // That is, the returned slice has the same number of buffers as the BitExploder
 // has fields, and each of those slices are large enough to hold the number of
-// destination byte of the individual BitExtractors.
+// destination byte of the individual BitExtractors. Since its length depends on
+// the data, the buffer for a RestOfData field is empty.
 func (exp BitExploder) Buffer() [][]byte {
 	bigBuff := make([]byte, exp.expByteLen)
-	bt := make([][]byte, len(exp.extractors))
+	bt := make([][]byte, exp.NumFields())
 	for idx, be := range exp.extractors {
 		bt[idx] = bigBuff[:be.ByteLength()]
 		bigBuff = bigBuff[be.ByteLength():]
 	}
+	if exp.rest {
+		bt[len(bt)-1] = bigBuff[:0]
+	}
 	return bt
 }
 
+// FieldRange is the location of a field within the data a BitExploder explodes.
+type FieldRange struct {
+	// Start is the offset of the field's first bit.
+	Start int
+	// Length is the field's width in bits, or RestOfData if its width depends
+	// on the length of the data.
+	Length int
+}
+
+// FieldRanges returns the location of each of the BitExploder's fields, in the
+// order they're exploded. Skipped bits aren't fields, so they aren't included.
+func (exp BitExploder) FieldRanges() []FieldRange {
+	ranges := make([]FieldRange, 0, exp.NumFields())
+	for _, be := range exp.extractors {
+		ranges = append(ranges, FieldRange{Start: be.bitStart, Length: be.bitLen})
+	}
+	if exp.rest {
+		ranges = append(ranges, FieldRange{Start: exp.bitLength, Length: RestOfData})
+	}
+	return ranges
+}
+
 // NumFields returns the number of fields this decoder has.
 func (exp BitExploder) NumFields() int {
+	if exp.rest {
+		return len(exp.extractors) + 1
+	}
 	return len(exp.extractors)
 }
 
+// HasRestOfData returns true if the BitExploder's final field is RestOfData.
+func (exp BitExploder) HasRestOfData() bool {
+	return exp.rest
+}
+
+// FieldNames returns the names of the BitExploder's fields, in order, or nil if
+// it wasn't created with NewNamedBitExploder.
+func (exp BitExploder) FieldNames() []string {
+	if exp.names == nil {
+		return nil
+	}
+	return append([]string(nil), exp.names...)
+}
+
+// FieldIndex returns the index of the field with the given name, or false if
+// the BitExploder has no such field.
+func (exp BitExploder) FieldIndex(name string) (int, bool) {
+	idx, ok := exp.indexes[name]
+	return idx, ok
+}
+
+// ExtractField extracts only the field with the given name from data, or returns
+// an error if there's no such field or data is too short for the BitExploder.
+func (exp BitExploder) ExtractField(data []byte, name string) ([]byte, error) {
+	idx, ok := exp.indexes[name]
+	if !ok {
+		return nil, errors.Errorf("no field is named %q", name)
+	}
+	if len(data)*8 < exp.bitLength {
+		return nil, errors.Errorf("invalid data length %d; expected %d bits",
+			len(data)*8, exp.bitLength)
+	}
+	var field []byte
+	if idx == len(exp.extractors) {
+		be, ok := exp.restExtractor(data)
+		if !ok {
+			return []byte{}, nil
+		}
+		field = be.Extract(data)
+	} else {
+		field = exp.extractors[idx].Extract(data)
+	}
+	if exp.transforms == nil {
+		return field, nil
+	}
+	return exp.transform(exp.transforms[idx], idx, field, data)
+}
+
+// ExplodeToMap explodes data as Explode does, but returns a map of the fields'
+// names to their extracted bits. It returns an error if the BitExploder wasn't
+// created with NewNamedBitExploder or data is too short.
+func (exp BitExploder) ExplodeToMap(data []byte) (map[string][]byte, error) {
+	if exp.names == nil {
+		return nil, errors.New("the BitExploder's fields are unnamed")
+	}
+	fields, err := exp.Explode(data)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string][]byte, len(fields))
+	for idx, f := range fields {
+		m[exp.names[idx]] = f
+	}
+	return m, nil
+}
+
 // BitReader uses a BitExploder to return consecutive fields from an underlying
 // data byte slice.
+//
+// Along with its current field, a BitReader tracks its bit offset within the
+// data: the end of the last field it read, or the start of the field it was
+// sought to. Its current field is always the first that starts at or after its
+// bit offset.
 type BitReader struct {
 	exp   BitExploder
 	field int
+	bit   int
 	data  []byte
 }
 
@@ -174,6 +607,7 @@ func (exp BitExploder) NewBitReader(data []byte) (*BitReader, error) {
 // Reset the reader so that future calls to Read start at field 0.
 func (r *BitReader) Reset() {
 	r.field = 0
+	r.bit = 0
 }
 
 // SetData changes the reader's underlying data slice, resetting it in the process.
@@ -183,7 +617,7 @@ func (r *BitReader) SetData(data []byte) error {
 			"at least %d bytes, but data has only %d", r.exp.expByteLen, len(data))
 	}
 	r.data = data
-	r.field = 0
+	r.Reset()
 	return nil
 }
 
@@ -197,10 +631,31 @@ func (r *BitReader) SetData(data []byte) error {
 // have been, subsequent calls to Read return 0, io.EOF. Use SetData or Reset to
 // make use of this reader again.
 func (r *BitReader) Read(p []byte) (int, error) {
+	n, err := r.Peek(p)
+	if err == nil {
+		r.bit = r.fieldEnd(r.field)
+		r.field++
+	}
+	return n, err
+}
+
+// Peek extracts the reader's current field into p, as Read does, but without
+// advancing the reader, so that the next Read or Peek returns the same field.
+// This allows a field, such as a flag, to determine how to interpret the fields
+// that follow it.
+func (r *BitReader) Peek(p []byte) (int, error) {
 	if r.field >= r.exp.NumFields() {
 		return 0, io.EOF
 	}
-	ex := r.exp.extractors[r.field]
+
+	var ex BitExtractor
+	ok := true
+	if r.field < len(r.exp.extractors) {
+		ex = r.exp.extractors[r.field]
+	} else {
+		// an empty RestOfData field just clears p
+		ex, ok = r.exp.restExtractor(r.data)
+	}
 	if ex.dstLen > len(p) {
 		return 0, io.ErrShortBuffer
 	}
@@ -208,7 +663,129 @@ func (r *BitReader) Read(p []byte) (int, error) {
 	for i := 0; i < len(p)-ex.dstLen; i++ {
 		p[i] = 0
 	}
-	ex.ExtractTo(p[len(p)-ex.dstLen:], r.data)
-	r.field++
+	if ok {
+		ex.ExtractTo(p[len(p)-ex.dstLen:], r.data)
+	}
 	return len(p), nil
 }
+
+// Skip advances the reader n fields without extracting them, and returns the
+// number of fields skipped. If fewer than n fields remain, it skips them all
+// and returns io.EOF. It returns an error if n is negative.
+func (r *BitReader) Skip(n int) (int, error) {
+	if n < 0 {
+		return 0, errors.Errorf("can't skip a negative number of fields: %d", n)
+	}
+	var err error
+	if remaining := r.exp.NumFields() - r.field; n > remaining {
+		n = remaining
+		err = io.EOF
+	}
+	r.field += n
+	r.bit = r.fieldStart(r.field)
+	return n, err
+}
+
+// SeekField moves the reader to the start of the field at the given index, so
+// that the next Read returns it. Seeking to NumFields moves the reader to the
+// end of its fields. It returns an error if the index is out of that range.
+func (r *BitReader) SeekField(field int) error {
+	if field < 0 || field > r.exp.NumFields() {
+		return errors.Errorf("field %d is out of range [0, %d]",
+			field, r.exp.NumFields())
+	}
+	r.field = field
+	r.bit = r.fieldStart(field)
+	return nil
+}
+
+// SeekBit moves the reader to the given bit offset within its data, where 0 is
+// the most significant bit of the first byte. Its current field becomes the
+// first that starts at or after that offset, so if the offset is within a
+// field, the next Read returns the following field. It returns an error if the
+// offset is outside the data.
+func (r *BitReader) SeekBit(offset int) error {
+	if offset < 0 || offset > len(r.data)*ByteSize {
+		return errors.Errorf("bit offset %d is out of range [0, %d]",
+			offset, len(r.data)*ByteSize)
+	}
+	r.setBit(offset)
+	return nil
+}
+
+// ReadBits reads the n bits, which must be in [0, 64], at the reader's bit
+// offset, regardless of its fields, and returns them as a uint64 in which the
+// last bit read is the lowest-order bit. It advances the reader's bit offset by
+// n, and so its current field to the first that starts at or after it.
+//
+// If fewer than n bits remain, this returns io.EOF if none do, or otherwise
+// io.ErrUnexpectedEOF, and doesn't advance the reader.
+func (r *BitReader) ReadBits(n int) (uint64, error) {
+	if n < 0 || n > 64 {
+		return 0, errors.Errorf("can only read 0 to 64 bits at a time, not %d", n)
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	switch remaining := len(r.data)*ByteSize - r.bit; {
+	case remaining == 0:
+		return 0, io.EOF
+	case remaining < n:
+		return 0, io.ErrUnexpectedEOF
+	}
+	v := New(r.bit, n).ExtractUInt64(r.data)
+	r.setBit(r.bit + n)
+	return v, nil
+}
+
+// ReadByte reads the 8 bits at the reader's bit offset, as ReadBits does, so
+// that a BitReader may serve as an io.ByteReader over its data, regardless of
+// whether the bits are aligned to bytes.
+func (r *BitReader) ReadByte() (byte, error) {
+	v, err := r.ReadBits(ByteSize)
+	return byte(v), err
+}
+
+// setBit sets the reader's bit offset and moves its current field to the first
+// that starts at or after it.
+func (r *BitReader) setBit(offset int) {
+	r.bit = offset
+	r.field = 0
+	for r.field < r.exp.NumFields() && r.fieldStart(r.field) < offset {
+		r.field++
+	}
+}
+
+// Field returns the index of the reader's current field, the one the next Read
+// returns, or NumFields if it has read them all.
+func (r *BitReader) Field() int {
+	return r.field
+}
+
+// BitOffset returns the reader's bit offset within its data.
+func (r *BitReader) BitOffset() int {
+	return r.bit
+}
+
+// fieldStart returns the offset of the first bit of the field at idx, or, for
+// NumFields, the offset of the end of the BitExploder's fields.
+func (r *BitReader) fieldStart(idx int) int {
+	switch {
+	case idx < len(r.exp.extractors):
+		return r.exp.extractors[idx].bitStart
+	case idx == len(r.exp.extractors) && r.exp.rest:
+		return r.exp.bitLength
+	case r.exp.rest:
+		return len(r.data) * ByteSize
+	}
+	return r.exp.bitLength
+}
+
+// fieldEnd returns the offset of the bit following the field at idx.
+func (r *BitReader) fieldEnd(idx int) int {
+	if idx < len(r.exp.extractors) {
+		be := r.exp.extractors[idx]
+		return be.bitStart + be.bitLen
+	}
+	return len(r.data) * ByteSize
+}
@@ -7,10 +7,13 @@
 package bitextract
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/hex"
 	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
 	"io"
+	"math/big"
+	"strings"
 	"testing"
 )
 
@@ -66,6 +69,148 @@ func TestBitReader_Read(t *testing.T) {
 	w.ShouldBeEqual(n, 0)
 }
 
+func TestBitReader_PeekSkip(t *testing.T) {
+	w := expect.WrapT(t)
+	//        a    b         c              d   e           f              -
+	// data: 0b1_10100110_1101100110111101_10_100100011_10001110111011110_000
+	data := w.ShouldHaveResult(hex.DecodeString("d36cded238eef0")).([]byte)
+	exp := w.ShouldHaveResult(NewBitExploder([]int{1, 8, 16, 2, 9, 17})).(BitExploder)
+	r := w.ShouldHaveResult(exp.NewBitReader(data)).(*BitReader)
+
+	// peeking doesn't advance the reader
+	buff := make([]byte, 4)
+	for i := 0; i < 2; i++ {
+		w.ShouldBeEqual(w.ShouldHaveResult(r.Peek(buff)), 4)
+		w.ShouldBeEqual(binary.BigEndian.Uint32(buff), uint32(1))
+	}
+	w.ShouldBeEqual(w.ShouldHaveResult(r.Read(buff)), 4)
+	w.ShouldBeEqual(binary.BigEndian.Uint32(buff), uint32(1))
+
+	w.ShouldBeEqual(w.ShouldHaveResult(r.Skip(2)), 2)
+	w.ShouldBeEqual(w.ShouldHaveResult(r.Skip(0)), 0)
+	w.ShouldBeEqual(w.ShouldHaveResult(r.Peek(buff)), 4)
+	w.ShouldBeEqual(binary.BigEndian.Uint32(buff), uint32(2))
+
+	// a short buffer fails without advancing
+	_, err := r.Peek(buff[:0])
+	w.ShouldBeEqual(err, io.ErrShortBuffer)
+	w.ShouldHaveError(r.Skip(-1))
+
+	n, err := r.Skip(5)
+	w.ShouldBeEqual(err, io.EOF)
+	w.ShouldBeEqual(n, 3)
+	_, err = r.Peek(buff)
+	w.ShouldBeEqual(err, io.EOF)
+
+	r.Reset()
+	w.ShouldBeEqual(w.ShouldHaveResult(r.Skip(5)), 5)
+	w.ShouldBeEqual(w.ShouldHaveResult(r.Read(buff)), 4)
+	w.ShouldBeEqual(binary.BigEndian.Uint32(buff), uint32(73182))
+}
+
+func TestBitReader_Seek(t *testing.T) {
+	w := expect.WrapT(t)
+	//        a    b         c              d   e           f              -
+	// data: 0b1_10100110_1101100110111101_10_100100011_10001110111011110_000
+	data := w.ShouldHaveResult(hex.DecodeString("d36cded238eef0")).([]byte)
+	exp := w.ShouldHaveResult(NewBitExploder([]int{1, 8, 16, 2, 9, 17})).(BitExploder)
+	r := w.ShouldHaveResult(exp.NewBitReader(data)).(*BitReader)
+	buff := make([]byte, 4)
+
+	w.ShouldBeEqual(w.ShouldHaveResult(r.Read(buff)), 4)
+	w.ShouldBeEqual(r.Field(), 1)
+	w.ShouldBeEqual(r.BitOffset(), 1)
+
+	testCases := []struct {
+		seek   func() error
+		field  int
+		offset int
+		value  uint32
+	}{
+		{func() error { return r.SeekField(3) }, 3, 25, 2},
+		{func() error { return r.SeekField(0) }, 0, 0, 1},
+		{func() error { return r.SeekBit(9) }, 2, 9, 55741},
+		{func() error { return r.SeekBit(10) }, 3, 10, 2},
+		{func() error { return r.SeekBit(0) }, 0, 0, 1},
+		{func() error { return r.SeekBit(53) }, 6, 53, 0},
+	}
+	for i, tc := range testCases {
+		w := w.As(i)
+		w.ShouldSucceed(tc.seek())
+		w.ShouldBeEqual(r.Field(), tc.field)
+		w.ShouldBeEqual(r.BitOffset(), tc.offset)
+		if tc.field == exp.NumFields() {
+			_, err := r.Read(buff)
+			w.ShouldBeEqual(err, io.EOF)
+			continue
+		}
+		w.ShouldBeEqual(w.ShouldHaveResult(r.Read(buff)), 4)
+		w.ShouldBeEqual(binary.BigEndian.Uint32(buff), tc.value)
+	}
+
+	w.ShouldSucceed(r.SeekField(6))
+	w.ShouldBeEqual(r.BitOffset(), 53)
+	w.ShouldSucceed(r.SeekBit(56))
+	w.ShouldBeEqual(r.Field(), 6)
+
+	w.ShouldFail(r.SeekField(-1))
+	w.ShouldFail(r.SeekField(7))
+	w.ShouldFail(r.SeekBit(-1))
+	w.ShouldFail(r.SeekBit(57))
+
+	// skipped bits are stepped over when seeking to fields
+	exp = w.ShouldHaveResult(NewBitExploder([]int{-4, 8, -4, RestOfData})).(BitExploder)
+	r = w.ShouldHaveResult(exp.NewBitReader([]byte{0xAB, 0xCD, 0xEF})).(*BitReader)
+	w.ShouldSucceed(r.SeekBit(1))
+	w.ShouldBeEqual(r.Field(), 0)
+	w.ShouldBeEqual(r.BitOffset(), 1)
+	w.ShouldBeEqual(w.ShouldHaveResult(r.Read(buff)), 4)
+	w.ShouldBeEqual(binary.BigEndian.Uint32(buff), uint32(0xBC))
+	w.ShouldBeEqual(r.BitOffset(), 12)
+	w.ShouldSucceed(r.SeekField(1))
+	w.ShouldBeEqual(r.BitOffset(), 16)
+	w.ShouldBeEqual(w.ShouldHaveResult(r.Read(buff)), 4)
+	w.ShouldBeEqual(binary.BigEndian.Uint32(buff), uint32(0xEF))
+	w.ShouldBeEqual(r.BitOffset(), 24)
+}
+
+func TestBitReader_ReadBits(t *testing.T) {
+	w := expect.WrapT(t)
+	exp := w.ShouldHaveResult(NewBitExploder([]int{4, 8, 12})).(BitExploder)
+	r := w.ShouldHaveResult(exp.NewBitReader([]byte{0xAB, 0xCD, 0xEF})).(*BitReader)
+
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBits(0)), uint64(0))
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBits(3)), uint64(0x5))
+	w.ShouldBeEqual(r.BitOffset(), 3)
+	w.ShouldBeEqual(r.Field(), 1)
+
+	// bytes needn't be aligned
+	var br io.ByteReader = r
+	w.ShouldBeEqual(w.ShouldHaveResult(br.ReadByte()), byte(0x5E))
+	w.ShouldBeEqual(r.BitOffset(), 11)
+	w.ShouldBeEqual(r.Field(), 2)
+
+	// field reads continue from the current field
+	buff := make([]byte, 2)
+	w.ShouldBeEqual(w.ShouldHaveResult(r.Read(buff)), 2)
+	w.ShouldBeEqual(buff, []byte{0x0D, 0xEF})
+
+	w.ShouldSucceed(r.SeekBit(12))
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBits(12)), uint64(0xDEF))
+	_, err := r.ReadByte()
+	w.ShouldBeEqual(err, io.EOF)
+
+	w.ShouldSucceed(r.SeekBit(20))
+	_, err = r.ReadBits(8)
+	w.ShouldBeEqual(err, io.ErrUnexpectedEOF)
+	w.ShouldBeEqual(r.BitOffset(), 20)
+	w.ShouldHaveError(r.ReadBits(65))
+	w.ShouldHaveError(r.ReadBits(-1))
+
+	r.Reset()
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBits(24)), uint64(0xABCDEF))
+}
+
 func TestSplitWidths_invalidWidths(t *testing.T) {
 	w := expect.WrapT(t)
 
@@ -93,3 +238,247 @@ func TestSplitWidths_invalidWidths(t *testing.T) {
 		w.As(widths).ShouldHaveError(SplitWidths(widths, ","))
 	}
 }
+
+func TestSplitWidths_repeat(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for conf, expected := range map[string][]int{
+		"7x3":           {7, 7, 7},
+		"8.7x3.*":       {8, 7, 7, 7, RestOfData},
+		"8. 7 x 2 .4":   {8, 7, 7, 4},
+		"(4.12)x2":      {4, 12, 4, 12},
+		"8.(4.12)x2.1":  {8, 4, 12, 4, 12, 1},
+		"(1.(2x2)x2)x2": {1, 2, 2, 2, 2, 1, 2, 2, 2, 2},
+		"(16)":          {16},
+		"8,(4,12)x2,16": {8, 4, 12, 4, 12, 16},
+	} {
+		delim := "."
+		if strings.Contains(conf, ",") {
+			delim = ","
+		}
+		w.As(conf).ShouldBeEqual(w.ShouldHaveResult(SplitWidths(conf, delim)), expected)
+	}
+
+	w.ShouldHaveLength(w.ShouldHaveResult(SplitWidths("7x20", ".")), 20)
+
+	for _, conf := range []string{
+		"7x0",
+		"7x-1",
+		"7x",
+		"x3",
+		"*x2",
+		"7x2x3",
+		"(8.4",
+		"8.4)",
+		"()x2",
+		"(8..4)x2",
+	} {
+		w.As(conf).ShouldHaveError(SplitWidths(conf, "."))
+	}
+}
+
+func TestBitExploder_skip(t *testing.T) {
+	w := expect.WrapT(t)
+	widths := w.ShouldHaveResult(SplitWidths("4.-4x2.8.-4", ".")).([]int)
+	w.ShouldBeEqual(widths, []int{4, -4, -4, 8, -4})
+
+	exp := w.ShouldHaveResult(NewBitExploder(widths)).(BitExploder)
+	w.ShouldBeEqual(exp.NumFields(), 2)
+	w.ShouldBeEqual(exp.BitLength(), 24)
+	w.ShouldBeEqual(exp.ExplodedByteLength(), 2)
+
+	data := []byte{0xAB, 0xCD, 0xEF}
+	w.ShouldBeEqual(w.ShouldHaveResult(exp.Explode(data)), [][]byte{{0x0A}, {0xDE}})
+	w.ShouldHaveError(exp.Explode(data[:2]))
+
+	// skipped bits may precede a RestOfData field
+	exp = w.ShouldHaveResult(NewBitExploder([]int{-12, RestOfData})).(BitExploder)
+	w.ShouldBeEqual(w.ShouldHaveResult(exp.Explode(data)), [][]byte{{0x0D, 0xEF}})
+
+	named := w.ShouldHaveResult(NewNamedBitExploder([]Field{
+		{"a", 4}, {"", -12}, {"b", 8},
+	})).(BitExploder)
+	w.ShouldBeEqual(named.FieldNames(), []string{"a", "b"})
+	w.ShouldBeEqual(w.ShouldHaveResult(named.ExplodeToMap(data)),
+		map[string][]byte{"a": {0x0A}, "b": {0xEF}})
+
+	imp := w.ShouldHaveResult(NewBitImploder([]int{4, -4, 8})).(BitImploder)
+	w.ShouldBeEqual(w.ShouldHaveResult(imp.Implode([][]byte{{0x0A}, {0xCD}})),
+		[]byte{0xA0, 0xCD})
+
+	w.ShouldFail(NewBitExploder([]int{-8}))
+	w.ShouldFail(NewBitExploder([]int{-8, RestOfData, 8}))
+	w.ShouldFail(NewBitExploder([]int{8, 0}))
+}
+
+func TestNamedBitExploder(t *testing.T) {
+	w := expect.WrapT(t)
+	//        a    b         c              d   e           f              -
+	// data: 0b1_10100110_1101100110111101_10_100100011_10001110111011110_000
+	data := w.ShouldHaveResult(hex.DecodeString("d36cded238eef0")).([]byte)
+
+	exp := w.ShouldHaveResult(NewNamedBitExploder([]Field{
+		{"a", 1}, {"b", 8}, {"c", 16}, {"d", 2}, {"e", 9}, {"f", 17},
+	})).(BitExploder)
+	w.ShouldBeEqual(exp.NumFields(), 6)
+	w.ShouldBeEqual(exp.BitLength(), 53)
+	w.ShouldBeEqual(exp.FieldNames(), []string{"a", "b", "c", "d", "e", "f"})
+	idx, ok := exp.FieldIndex("e")
+	w.ShouldBeTrue(ok)
+	w.ShouldBeEqual(idx, 4)
+	_, ok = exp.FieldIndex("g")
+	w.ShouldBeFalse(ok)
+
+	w.ShouldBeEqual(w.ShouldHaveResult(exp.ExtractField(data, "c")), []byte{0xD9, 0xBD})
+	w.ShouldHaveError(exp.ExtractField(data, "g"))
+	w.ShouldHaveError(exp.ExtractField(data[:6], "c"))
+
+	m := w.ShouldHaveResult(exp.ExplodeToMap(data)).(map[string][]byte)
+	w.ShouldBeEqual(m, map[string][]byte{
+		"a": {0x01}, "b": {0xA6}, "c": {0xD9, 0xBD},
+		"d": {0x02}, "e": {0x01, 0x23}, "f": {0x01, 0x1D, 0xDE},
+	})
+	w.ShouldHaveError(exp.ExplodeToMap(data[:6]))
+
+	w.ShouldSucceed(exp.SetWidths([]int{8, 8}))
+	w.ShouldBeEqual(exp.ExplodedByteLength(), 2)
+	w.ShouldHaveLength(exp.FieldNames(), 0)
+	w.ShouldHaveError(exp.ExplodeToMap(data))
+
+	w.ShouldFail(NewNamedBitExploder(nil))
+	w.ShouldFail(NewNamedBitExploder([]Field{{"a", 1}, {"", 2}}))
+	w.ShouldFail(NewNamedBitExploder([]Field{{"a", 1}, {"a", 2}}))
+	w.ShouldFail(NewNamedBitExploder([]Field{{"a", 1}, {"b", 0}}))
+}
+
+func TestBitExploder_restOfData(t *testing.T) {
+	w := expect.WrapT(t)
+	widths := w.ShouldHaveResult(SplitWidths("4.8.*", ".")).([]int)
+	w.ShouldBeEqual(widths, []int{4, 8, RestOfData})
+
+	exp := w.ShouldHaveResult(NewBitExploder(widths)).(BitExploder)
+	w.ShouldBeTrue(exp.HasRestOfData())
+	w.ShouldBeEqual(exp.NumFields(), 3)
+	w.ShouldBeEqual(exp.BitLength(), 12)
+
+	// 0xA_BC_DEF0: the rest field is the final 20 bits
+	fields := w.ShouldHaveResult(exp.Explode([]byte{0xAB, 0xCD, 0xEF, 0x01})).([][]byte)
+	w.ShouldBeEqual(fields, [][]byte{{0x0A}, {0xBC}, {0x0D, 0xEF, 0x01}})
+
+	// the same exploder handles a shorter trailing field
+	fields = w.ShouldHaveResult(exp.Explode([]byte{0xAB, 0xCD, 0xEF})).([][]byte)
+	w.ShouldBeEqual(fields, [][]byte{{0x0A}, {0xBC}, {0x0D, 0xEF}})
+
+	fields = w.ShouldHaveResult(exp.Explode([]byte{0xAB, 0xC7})).([][]byte)
+	w.ShouldBeEqual(fields, [][]byte{{0x0A}, {0xBC}, {0x07}})
+	w.ShouldHaveError(exp.Explode([]byte{0xAB}))
+
+	r := w.ShouldHaveResult(exp.NewBitReader([]byte{0xAB, 0xCD, 0xEF})).(*BitReader)
+	buff := make([]byte, 4)
+	for _, expected := range [][]byte{
+		{0, 0, 0, 0x0A}, {0, 0, 0, 0xBC}, {0, 0, 0x0D, 0xEF},
+	} {
+		n, err := r.Read(buff)
+		w.ShouldSucceed(err)
+		w.ShouldBeEqual(n, 4)
+		w.ShouldBeEqual(buff, expected)
+	}
+	_, err := r.Read(buff)
+	w.ShouldBeEqual(err, io.EOF)
+
+	named := w.ShouldHaveResult(NewNamedBitExploder([]Field{
+		{"header", 8}, {"serial", RestOfData},
+	})).(BitExploder)
+	w.ShouldBeEqual(w.ShouldHaveResult(named.ExtractField([]byte{0x30, 0x12, 0x34}, "serial")),
+		[]byte{0x12, 0x34})
+	// with no data after the other fields, the trailing field is empty
+	w.ShouldBeEqual(w.ShouldHaveResult(named.ExplodeToMap([]byte{0x30})),
+		map[string][]byte{"header": {0x30}, "serial": {}})
+
+	w.ShouldFail(NewBitExploder([]int{4, RestOfData, 8}))
+	w.ShouldFail(NewBitImploder([]int{4, RestOfData}))
+}
+
+func TestBitExploder_FieldRanges(t *testing.T) {
+	w := expect.WrapT(t)
+	exp := w.ShouldHaveResult(NewBitExploder([]int{4, -3, 9, 8, RestOfData})).(BitExploder)
+	w.ShouldBeEqual(exp.FieldRanges(), []FieldRange{
+		{Start: 0, Length: 4},
+		{Start: 7, Length: 9},
+		{Start: 16, Length: 8},
+		{Start: 24, Length: RestOfData},
+	})
+}
+
+func TestBitExploder_ExplodeToUints(t *testing.T) {
+	w := expect.WrapT(t)
+	//        a    b         c              d   e           f              -
+	// data: 0b1_10100110_1101100110111101_10_100100011_10001110111011110_000
+	data := w.ShouldHaveResult(hex.DecodeString("d36cded238eef0")).([]byte)
+	exp := w.ShouldHaveResult(NewBitExploder([]int{1, 8, 16, 2, 9, 17})).(BitExploder)
+
+	w.ShouldBeEqual(w.ShouldHaveResult(exp.ExplodeToUints(data)),
+		[]uint64{1, 166, 55741, 2, 291, 73182})
+	w.ShouldHaveError(exp.ExplodeToUints(data[:6]))
+
+	bigInts := w.ShouldHaveResult(exp.ExplodeToBigInts(data)).([]*big.Int)
+	w.ShouldHaveLength(bigInts, 6)
+	for i, v := range []int64{1, 166, 55741, 2, 291, 73182} {
+		w.As(i).ShouldBeEqual(bigInts[i].Int64(), v)
+	}
+	w.ShouldHaveError(exp.ExplodeToBigInts(data[:6]))
+
+	// fields wider than 64 bits require big.Ints
+	data = bytes.Repeat([]byte{0xFF}, 10)
+	exp = w.ShouldHaveResult(NewBitExploder([]int{4, 65})).(BitExploder)
+	w.ShouldHaveError(exp.ExplodeToUints(data))
+	bigInts = w.ShouldHaveResult(exp.ExplodeToBigInts(data)).([]*big.Int)
+	w.ShouldBeEqual(bigInts[1].BitLen(), 65)
+
+	exp = w.ShouldHaveResult(NewBitExploder([]int{4, RestOfData})).(BitExploder)
+	w.ShouldHaveError(exp.ExplodeToUints(data))
+	w.ShouldBeEqual(w.ShouldHaveResult(exp.ExplodeToUints(data[:2])),
+		[]uint64{0xF, 0xFFF})
+	w.ShouldBeEqual(w.ShouldHaveResult(exp.ExplodeToUints(data[:1])),
+		[]uint64{0xF, 0xF})
+}
+
+func TestBitExploder_AddField(t *testing.T) {
+	w := expect.WrapT(t)
+
+	// urn:epc:id:sgtin:0614141.812345.6789, which has partition 5
+	data := w.ShouldHaveResult(hex.DecodeString("3034257BF7194E4000001A85")).([]byte)
+	exp := w.ShouldHaveResult(NewNamedBitExploder([]Field{
+		{"header", 8}, {"filter", 3}, {"partition", 3}, {"company", 24},
+		{"itemRef", 20}, {"serial", 38},
+	})).(BitExploder)
+	w.ShouldSucceed(exp.AddField("gcpAndItemRef", 14, 44))
+	w.ShouldBeEqual(exp.NumFields(), 7)
+	w.ShouldBeEqual(exp.BitLength(), 96)
+	w.ShouldBeEqual(exp.FieldRanges()[6], FieldRange{Start: 14, Length: 44})
+
+	m := w.ShouldHaveResult(exp.ExplodeToMap(data)).(map[string][]byte)
+	w.ShouldBeEqual(m["company"], []byte{0x09, 0x5E, 0xFD})
+	w.ShouldBeEqual(m["itemRef"], []byte{0x0C, 0x65, 0x39})
+	w.ShouldBeEqual(m["gcpAndItemRef"], []byte{0x00, 0x95, 0xEF, 0xDC, 0x65, 0x39})
+	w.ShouldBeEqual(m["serial"], []byte{0x00, 0x00, 0x00, 0x1A, 0x85})
+
+	// fields may also leave gaps, which extend the BitLength
+	var unnamed BitExploder
+	w.ShouldSucceed(unnamed.AddField("", 4, 8))
+	w.ShouldSucceed(unnamed.AddField("", 0, 4))
+	w.ShouldBeEqual(unnamed.BitLength(), 12)
+	w.ShouldSucceed(unnamed.AddField("", 20, 4))
+	w.ShouldBeEqual(unnamed.BitLength(), 24)
+	w.ShouldBeEqual(w.ShouldHaveResult(unnamed.Explode([]byte{0xAB, 0xCD, 0xEF})),
+		[][]byte{{0xBC}, {0x0A}, {0x0F}})
+
+	w.ShouldFail(unnamed.AddField("named", 0, 4))
+	w.ShouldFail(exp.AddField("", 0, 4))
+	w.ShouldFail(exp.AddField("itemRef", 0, 4))
+	w.ShouldFail(exp.AddField("neg", -1, 4))
+	w.ShouldFail(exp.AddField("zero", 0, 0))
+
+	rest := w.ShouldHaveResult(NewBitExploder([]int{8, RestOfData})).(BitExploder)
+	w.ShouldFail(rest.AddField("", 0, 4))
+}
@@ -0,0 +1,128 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+)
+
+// BitImploder is the inverse of a BitExploder: it packs a series of fields with
+// predefined bit widths into consecutive bits of a single byte slice.
+type BitImploder struct {
+	exp BitExploder
+}
+
+// NewBitImploder returns a new BitImploder that packs fields of the given widths
+// into byte data, so that a BitExploder with the same widths recovers them. The
+// widths may not include RestOfData.
+func NewBitImploder(widths []int) (BitImploder, error) {
+	exp, err := NewBitExploder(widths)
+	if err != nil {
+		return BitImploder{}, err
+	}
+	if exp.rest {
+		return BitImploder{}, errors.New("BitImploders don't support RestOfData")
+	}
+	return BitImploder{exp: exp}, nil
+}
+
+// BitLength returns the sum of the bit fields this BitImploder uses.
+func (imp BitImploder) BitLength() int {
+	return imp.exp.bitLength
+}
+
+// ByteLength returns the number of bytes needed to hold the imploded fields.
+// If BitLength isn't a multiple of 8, the final byte is padded with 0s.
+func (imp BitImploder) ByteLength() int {
+	return (imp.exp.bitLength + ByteSize - 1) / ByteSize
+}
+
+// NumFields returns the number of fields this BitImploder packs.
+func (imp BitImploder) NumFields() int {
+	return len(imp.exp.extractors)
+}
+
+// Implode packs the fields into a new byte slice of ByteLength bytes.
+//
+// Each field is a big endian, right-aligned value, as produced by Explode. It
+// may have fewer bytes than its width requires, in which case it's treated as
+// if it had leading 0s, but this returns an error if there are the wrong number
+// of fields or if any field has a value too large for its width.
+func (imp BitImploder) Implode(fields [][]byte) ([]byte, error) {
+	if len(fields) != len(imp.exp.extractors) {
+		return nil, errors.Errorf("expected %d fields, but got %d",
+			len(imp.exp.extractors), len(fields))
+	}
+
+	dst := make([]byte, imp.ByteLength())
+	for idx, be := range imp.exp.extractors {
+		field := fields[idx]
+		if extra := len(field) - be.dstLen; extra > 0 {
+			for _, b := range field[:extra] {
+				if b != 0 {
+					return nil, errors.Errorf("field %d is too large "+
+						"for its %d bits", idx, be.BitLength())
+				}
+			}
+			field = field[extra:]
+		} else if extra < 0 {
+			padded := make([]byte, be.dstLen)
+			copy(padded[-extra:], field)
+			field = padded
+		}
+		if field[0]&^be.mask != 0 {
+			return nil, errors.Errorf("field %d is too large for its %d bits",
+				idx, be.BitLength())
+		}
+		be.InsertTo(dst, field)
+	}
+	return dst, nil
+}
+
+// ImplodeTo packs the fields into dst, leaving any bits past BitLength as-is.
+//
+// Unlike Implode, each field must have at least as many bytes as its width
+// requires, as is the case for the buffers returned by BitExploder.Buffer, and
+// bits beyond a field's width are ignored. If there aren't enough fields, any
+// of them is too short, or dst is shorter than ByteLength, ImplodeTo will panic.
+func (imp BitImploder) ImplodeTo(dst []byte, fields [][]byte) {
+	if len(fields) < len(imp.exp.extractors) {
+		panic(fmt.Sprintf("not enough source slices (%d) to "+
+			"implode %d fields", len(fields), len(imp.exp.extractors)))
+	}
+	for idx, be := range imp.exp.extractors {
+		// panics if len(fields[idx]) < be.ByteLength() or dst is too short
+		be.InsertTo(dst, fields[idx])
+	}
+}
+
+// ImplodeUints packs the values into a new byte slice of ByteLength bytes. It
+// returns an error if there are the wrong number of values or if any value is
+// too large for its field's width. Fields wider than 64 bits get leading 0s.
+func (imp BitImploder) ImplodeUints(values []uint64) ([]byte, error) {
+	if len(values) != len(imp.exp.extractors) {
+		return nil, errors.Errorf("expected %d fields, but got %d",
+			len(imp.exp.extractors), len(values))
+	}
+
+	dst := make([]byte, imp.ByteLength())
+	for idx, be := range imp.exp.extractors {
+		width := be.BitLength()
+		if width < 64 && values[idx] >= 1<<uint(width) {
+			return nil, errors.Errorf("field %d is too large for its %d bits",
+				idx, width)
+		}
+		if be.dstLen > 8 {
+			// InsertUInt64 handles at most 8 bytes, so place the value
+			// in the field's final 64 bits.
+			be = New(be.bitStart+width-64, 64)
+		}
+		be.InsertUInt64(dst, values[idx])
+	}
+	return dst, nil
+}
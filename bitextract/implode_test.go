@@ -0,0 +1,99 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"math/rand"
+	"testing"
+)
+
+func TestBitImploder_Implode(t *testing.T) {
+	w := expect.WrapT(t)
+	//        a    b         c              d   e           f              -
+	// data: 0b1_10100110_1101100110111101_10_100100011_10001110111011110_000
+	data := w.ShouldHaveResult(hex.DecodeString("d36cded238eef0")).([]byte)
+	widths := []int{1, 8, 16, 2, 9, 17}
+
+	imp := w.ShouldHaveResult(NewBitImploder(widths)).(BitImploder)
+	w.ShouldBeEqual(imp.BitLength(), 53)
+	w.ShouldBeEqual(imp.ByteLength(), 7)
+	w.ShouldBeEqual(imp.NumFields(), 6)
+
+	w.ShouldBeEqual(w.ShouldHaveResult(imp.ImplodeUints(
+		[]uint64{1, 166, 55741, 2, 291, 73182})), data)
+
+	// short and long fields are fine, as long as the values fit
+	w.ShouldBeEqual(w.ShouldHaveResult(imp.Implode([][]byte{
+		{1}, {166}, {0xD9, 0xBD}, {0, 0, 2}, {0x01, 0x23}, {0x01, 0x1D, 0xDE},
+	})), data)
+	w.ShouldBeEqual(w.ShouldHaveResult(imp.Implode([][]byte{
+		{1}, {166}, {0xD9, 0xBD}, {2}, {0x01, 0x23}, {0x00, 0x00, 0x01, 0x1D, 0xDE},
+	})), data)
+
+	exp := w.ShouldHaveResult(NewBitExploder(widths)).(BitExploder)
+	fields := w.ShouldHaveResult(exp.Explode(data)).([][]byte)
+	w.ShouldBeEqual(w.ShouldHaveResult(imp.Implode(fields)), data)
+
+	dst := []byte{0, 0, 0, 0, 0, 0, 0x07}
+	imp.ImplodeTo(dst, fields)
+	w.ShouldBeEqual(dst, []byte{0xd3, 0x6c, 0xde, 0xd2, 0x38, 0xee, 0xf7})
+}
+
+func TestBitImploder_invalid(t *testing.T) {
+	w := expect.WrapT(t)
+
+	w.ShouldFail(NewBitImploder(nil))
+	w.ShouldFail(NewBitImploder([]int{8, 0}))
+
+	imp := w.ShouldHaveResult(NewBitImploder([]int{3, 9})).(BitImploder)
+	w.ShouldHaveError(imp.Implode([][]byte{{1}}))
+	w.ShouldHaveError(imp.Implode([][]byte{{8}, {1}}))
+	w.ShouldHaveError(imp.Implode([][]byte{{1}, {2, 0}}))
+	w.ShouldHaveError(imp.Implode([][]byte{{1}, {1, 0, 0}}))
+	w.ShouldHaveError(imp.ImplodeUints([]uint64{1, 2, 3}))
+	w.ShouldHaveError(imp.ImplodeUints([]uint64{1, 512}))
+	w.ShouldBeEqual(w.ShouldHaveResult(imp.ImplodeUints([]uint64{7, 511})),
+		[]byte{0xFF, 0xF0})
+
+	defer func() {
+		recover()
+	}()
+	imp.ImplodeTo(make([]byte, 2), [][]byte{{1}})
+	t.Fatal("expected ImplodeTo to panic, but it didn't")
+}
+
+func TestBitImploder_roundTrip(t *testing.T) {
+	w := expect.WrapT(t).StopOnMismatch()
+
+	rand.Seed(5)
+	for i := 0; i < 100; i++ {
+		widths := make([]int, rand.Intn(10)+1)
+		values := make([]uint64, len(widths))
+		for j := range widths {
+			widths[j] = rand.Intn(100) + 1
+			values[j] = rand.Uint64()
+			if widths[j] < 64 {
+				values[j] &= 1<<uint(widths[j]) - 1
+			}
+		}
+
+		imp := w.ShouldHaveResult(NewBitImploder(widths)).(BitImploder)
+		exp := w.ShouldHaveResult(NewBitExploder(widths)).(BitExploder)
+		data := w.ShouldHaveResult(imp.ImplodeUints(values)).([]byte)
+		w.ShouldHaveLength(data, imp.ByteLength())
+		fields := w.ShouldHaveResult(exp.Explode(data)).([][]byte)
+		w.ShouldBeEqual(w.ShouldHaveResult(imp.Implode(fields)), data)
+
+		for j, be := range exp.extractors {
+			if be.dstLen <= 8 {
+				w.ShouldBeEqual(be.ExtractUInt64(data), values[j])
+			}
+		}
+	}
+}
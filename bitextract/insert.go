@@ -0,0 +1,104 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// InsertTo is the inverse of ExtractTo: it writes the value in src into the
+// BitExtractor's range of bits in dst, leaving dst's other bits unchanged.
+//
+// src uses the layout that ExtractTo produces: ByteLength() bytes holding the
+// value right-aligned, so the last bit of the value is the lowest-order bit of
+// src[ByteLength()-1]. Any bits of src[0] above the BitExtractor's bit length
+// are ignored, as are any bytes after the first ByteLength().
+//
+// Bits are numbered according to the BitExtractor's Order, and if it reverses
+// bits or is little endian, src is converted back to the field's layout before
+// it's written. Like ExtractTo, this
+// panics if dst doesn't extend through the last byte of
+// the range or if src is shorter than ByteLength().
+func (be BitExtractor) InsertTo(dst, src []byte) {
+	if len(dst) < be.srcLen+be.byteStart {
+		panic(fmt.Sprintf("cannot insert %d bytes into destination[%d:%d], "+
+			"as it only has %d total bytes",
+			be.srcLen, be.byteStart, be.byteStart+be.srcLen, len(dst)))
+	}
+
+	if len(src) < be.dstLen {
+		panic(fmt.Sprintf("source size %d is too small "+
+			"(should be at least %d)", len(src), be.dstLen))
+	}
+
+	if be.reverse || be.littleEndian {
+		src = be.unconvert(src[:be.dstLen])
+	}
+
+	window := dst[be.byteStart : be.byteStart+be.srcLen]
+	if be.order == LSBFirst {
+		// insert into the reversed bytes, then reverse them back at the end
+		var buff [16]byte
+		window = reverseBytes(buff[:0], window)
+	}
+
+	// The value spans srcLen bytes of dst, which is either the same number of
+	// bytes it uses in src, or one more; pad it on the left to match, then
+	// shift it up into position, along with a mask of the bits it covers.
+	pad := be.srcLen - be.dstLen
+	value := func(i int) (v, mask byte) {
+		i -= pad
+		switch {
+		case i < 0 || i >= be.dstLen:
+			return 0, 0
+		case i == 0:
+			return src[0] & be.mask, be.mask
+		default:
+			return src[i], ByteMask
+		}
+	}
+
+	lshift := ByteSize - be.rshift
+	cur, curMask := value(0)
+	for i := 0; i < be.srcLen; i++ {
+		next, nextMask := value(i + 1)
+		v := cur<<be.rshift | next>>lshift
+		mask := curMask<<be.rshift | nextMask>>lshift
+		window[i] = window[i]&^mask | v
+		cur, curMask = next, nextMask
+	}
+
+	if be.order == LSBFirst {
+		reverseBytes(dst[be.byteStart:be.byteStart], window)
+	}
+}
+
+// InsertUInt64 writes v into the BitExtractor's range of bits in dst, as the
+// inverse of ExtractUInt64. Bits of v above the BitExtractor's bit length are
+// ignored. This method panics if the extractor's ByteLength is greater than 8.
+func (be BitExtractor) InsertUInt64(dst []byte, v uint64) {
+	buff := bufferPool.Get().([]byte)
+	defer bufferPool.Put(buff)
+	binary.BigEndian.PutUint64(buff, v)
+	be.InsertTo(dst, buff[8-be.dstLen:])
+}
+
+// unconvert returns a copy of src undoing the bit reversal and byte swapping
+// that extraction performs.
+func (be BitExtractor) unconvert(src []byte) []byte {
+	v := make([]byte, len(src))
+	copy(v, src)
+	v[0] &= be.mask
+	if be.littleEndian {
+		reverseInPlace(v)
+	}
+	if be.reverse {
+		reverseBits(v, be.bitLen)
+	}
+	return v
+}
@@ -0,0 +1,113 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"math/rand"
+	"testing"
+)
+
+func TestBitExtractor_InsertTo(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for _, tt := range []struct {
+		start, length int
+		dst, src      string
+		expected      string
+	}{
+		{0, 8, "0000", "AB", "AB00"},
+		{8, 8, "FFFF", "00", "FF00"},
+		{0, 12, "0000", "0FFF", "FFF0"},
+		{0, 12, "0000", "FFFF", "FFF0"}, // high bits are ignored
+		{2, 12, "0000", "0FFF", "3FFC"},
+		{5, 9, "FFFF", "0000", "F803"},
+		{5, 9, "FCDF", "0137", "FCDF"},
+		{5, 2, "0000", "02", "0400"},
+		{11, 2, "FFFF", "00", "FFE7"},
+		{4, 4, "A5", "0C", "AC"},
+		{3, 18, "000000", "03FFFF", "1FFFF8"},
+	} {
+		name := fmt.Sprintf("%d:%d %s <- %s", tt.start, tt.length, tt.dst, tt.src)
+		dst, _ := hex.DecodeString(tt.dst)
+		src, _ := hex.DecodeString(tt.src)
+		New(tt.start, tt.length).InsertTo(dst, src)
+		w.As(name).ShouldBeEqual(hex.EncodeToString(dst),
+			hex.EncodeToString(mustDecodeHex(tt.expected)))
+	}
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestBitExtractor_InsertTo_roundTrip(t *testing.T) {
+	w := expect.WrapT(t).StopOnMismatch()
+	buff := make([]byte, 50)
+	orig := make([]byte, len(buff))
+
+	rand.Seed(4)
+	for i := 0; i < 1000; i++ {
+		rand.Read(buff)
+		copy(orig, buff)
+		start := rand.Int() % ((len(buff) - 1) * 8)
+		length := (rand.Int() % ((len(buff) * 8) - start)) + 1
+		be := New(start, length)
+
+		value := be.Buffer()
+		rand.Read(value)
+		be.InsertTo(buff, value)
+		value[0] &= be.mask
+		name := fmt.Sprintf("%d:%d", start, length)
+		w.As(name).ShouldBeEqual(be.Extract(buff), value)
+
+		// bits outside the range are unchanged
+		if start > 0 {
+			before := New(0, start)
+			w.As(name).ShouldBeEqual(before.Extract(buff), before.Extract(orig))
+		}
+		if end := start + length; end < len(buff)*8 {
+			after := New(end, len(buff)*8-end)
+			w.As(name).ShouldBeEqual(after.Extract(buff), after.Extract(orig))
+		}
+	}
+}
+
+func TestBitExtractor_InsertUInt64(t *testing.T) {
+	w := expect.WrapT(t)
+
+	dst := make([]byte, 12)
+	be := New(14, 44)
+	be.InsertUInt64(dst, 0x12345678ABC)
+	w.ShouldBeEqual(be.ExtractUInt64(dst), uint64(0x12345678ABC))
+	be.InsertUInt64(dst, 1<<44|5)
+	w.ShouldBeEqual(be.ExtractUInt64(dst), uint64(5))
+	w.ShouldBeEqual(dst[0], byte(0))
+	w.ShouldBeEqual(dst[8:], []byte{0, 0, 0, 0})
+}
+
+func TestBitExtractor_InsertTo_panic(t *testing.T) {
+	assertPanics := func(f func()) {
+		defer func() {
+			recover()
+		}()
+		f()
+		t.Fatal("expected function to panic, but it didn't")
+	}
+
+	be := New(5, 9)
+	dst := make([]byte, 2)
+	src := make([]byte, 2)
+	assertPanics(func() { be.InsertTo(dst[1:], src) })
+	assertPanics(func() { be.InsertTo(dst, src[1:]) })
+}
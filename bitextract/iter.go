@@ -0,0 +1,62 @@
+//go:build go1.23
+// +build go1.23
+
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"github.com/pkg/errors"
+	"iter"
+)
+
+// All returns an iterator over the fields exploded from data, yielding each
+// field's index and its extracted bits, or an error if data is too short for
+// this BitExploder.
+//
+// Unlike Explode, All extracts each field only as the iteration reaches it, and
+// it reuses a single buffer for every field: the slice it yields is only valid
+// until the next iteration, so callers must copy it if they need to retain it.
+//
+// For Go versions before 1.23, use Explode or ExplodeTo.
+func (exp BitExploder) All(data []byte) (iter.Seq2[int, []byte], error) {
+	if len(data)*8 < exp.bitLength {
+		return nil, errors.Errorf("invalid data length %d; expected %d bits",
+			len(data)*8, exp.bitLength)
+	}
+
+	return func(yield func(int, []byte) bool) {
+		maxLen := 0
+		for _, be := range exp.extractors {
+			if be.dstLen > maxLen {
+				maxLen = be.dstLen
+			}
+		}
+
+		rest, hasRest := exp.restExtractor(data)
+		if hasRest && rest.dstLen > maxLen {
+			maxLen = rest.dstLen
+		}
+
+		buff := make([]byte, maxLen)
+		for idx, be := range exp.extractors {
+			field := buff[:be.dstLen]
+			be.ExtractTo(field, data)
+			if !yield(idx, field) {
+				return
+			}
+		}
+		if exp.rest {
+			field := buff[:0]
+			if hasRest {
+				field = buff[:rest.dstLen]
+				rest.ExtractTo(field, data)
+			}
+			yield(len(exp.extractors), field)
+		}
+	}, nil
+}
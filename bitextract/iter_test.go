@@ -0,0 +1,35 @@
+//go:build go1.23
+// +build go1.23
+
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"iter"
+	"testing"
+)
+
+func TestBitExploder_All(t *testing.T) {
+	w := expect.WrapT(t)
+	data := w.ShouldHaveResult(hex.DecodeString("d36cded238eef0")).([]byte)
+	exp := w.ShouldHaveResult(NewBitExploder([]int{1, 8, 16, 2, 9, 17})).(BitExploder)
+	expected := w.ShouldHaveResult(exp.Explode(data)).([][]byte)
+
+	fields := w.ShouldHaveResult(exp.All(data))
+	n := 0
+	for idx, field := range fields.(iter.Seq2[int, []byte]) {
+		w.ShouldBeEqual(idx, n)
+		w.ShouldBeEqual(field, expected[idx])
+		n++
+	}
+	w.ShouldBeEqual(n, exp.NumFields())
+
+	w.ShouldHaveError(exp.All(data[:2]))
+}
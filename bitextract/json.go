@@ -0,0 +1,231 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+// namedTransforms are the Transforms that may be used in JSON configurations.
+var namedTransforms = map[string]Transform{
+	"BCD":           BCD,
+	"GrayCode":      GrayCode,
+	"SixBitASCII":   SixBitASCII,
+	"SevenBitASCII": SevenBitASCII,
+}
+
+// transformName returns the name of a Transform in namedTransforms.
+func transformName(t Transform) (string, bool) {
+	ptr := reflect.ValueOf(t).Pointer()
+	for name, nt := range namedTransforms {
+		if reflect.ValueOf(nt).Pointer() == ptr {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// jsonField is the JSON representation of a field or skipped bits. Exactly one
+// of Width, Skip, or Rest is set. Start is only set for fields that don't
+// follow the previous one.
+type jsonField struct {
+	Name      string `json:"name,omitempty"`
+	Start     *int   `json:"start,omitempty"`
+	Width     int    `json:"width,omitempty"`
+	Skip      int    `json:"skip,omitempty"`
+	Rest      bool   `json:"rest,omitempty"`
+	Transform string `json:"transform,omitempty"`
+}
+
+type jsonParity struct {
+	Field  int    `json:"field"`
+	Parity string `json:"parity"`
+	Start  int    `json:"start"`
+	Length int    `json:"length"`
+}
+
+type jsonExploder struct {
+	Fields []jsonField  `json:"fields"`
+	Parity []jsonParity `json:"parity,omitempty"`
+}
+
+// MarshalJSON encodes the BitExploder's configuration, including its fields'
+// widths and names, skipped bits, Transforms, and parity checks, as JSON, so
+// that it may be stored or sent elsewhere and recreated with UnmarshalJSON.
+// For example, a BitExploder with the widths {8, -4, 44, RestOfData} and a
+// BCD Transform on its second field is encoded as:
+//     {"fields": [
+//       {"width": 8},
+//       {"skip": 4},
+//       {"width": 44, "transform": "BCD"},
+//       {"rest": true}
+//     ]}
+//
+// Fields that overlap the previous one, as AddField allows, have a "start" with
+// their bit offset; fields without one follow the previous field.
+//
+// Only the Transforms defined by this package may be encoded; MarshalJSON
+// returns an error if the BitExploder uses any others.
+func (exp BitExploder) MarshalJSON() ([]byte, error) {
+	var je jsonExploder
+	field := func(idx int) (jsonField, error) {
+		var jf jsonField
+		if exp.names != nil {
+			jf.Name = exp.names[idx]
+		}
+		if exp.transforms != nil && exp.transforms[idx] != nil {
+			name, ok := transformName(exp.transforms[idx])
+			if !ok {
+				return jf, errors.Errorf("field %d's Transform can't be "+
+					"encoded as JSON", idx)
+			}
+			jf.Transform = name
+		}
+		return jf, nil
+	}
+
+	end := 0
+	for idx, be := range exp.extractors {
+		if be.bitStart > end {
+			je.Fields = append(je.Fields, jsonField{Skip: be.bitStart - end})
+		}
+		jf, err := field(idx)
+		if err != nil {
+			return nil, err
+		}
+		if be.bitStart < end {
+			start := be.bitStart
+			jf.Start = &start
+		}
+		jf.Width = be.bitLen
+		je.Fields = append(je.Fields, jf)
+		end = be.bitStart + be.bitLen
+	}
+	if exp.bitLength > end {
+		je.Fields = append(je.Fields, jsonField{Skip: exp.bitLength - end})
+	}
+	if exp.rest {
+		jf, err := field(len(exp.extractors))
+		if err != nil {
+			return nil, err
+		}
+		jf.Rest = true
+		je.Fields = append(je.Fields, jf)
+	}
+
+	for _, pc := range exp.parity {
+		je.Parity = append(je.Parity, jsonParity{
+			Field:  pc.field,
+			Parity: pc.parity.String(),
+			Start:  pc.bits.bitStart,
+			Length: pc.bits.bitLen,
+		})
+	}
+	return json.Marshal(je)
+}
+
+// UnmarshalJSON replaces the BitExploder's configuration with one encoded by
+// MarshalJSON, or returns an error if it's invalid, in which case the
+// BitExploder is unchanged. Either all of its fields must have names, or none
+// of them; skipped bits never do.
+func (exp *BitExploder) UnmarshalJSON(data []byte) error {
+	var je jsonExploder
+	if err := json.Unmarshal(data, &je); err != nil {
+		return errors.Wrap(err, "invalid BitExploder JSON")
+	}
+
+	var e BitExploder
+	var transforms []Transform
+	cur := 0 // where the next field starts, unless it has an explicit start
+	for i, jf := range je.Fields {
+		set := 0
+		for _, isSet := range []bool{jf.Width != 0, jf.Skip != 0, jf.Rest} {
+			if isSet {
+				set++
+			}
+		}
+		if set != 1 || jf.Width < 0 || jf.Skip < 0 {
+			return errors.Errorf("field %d must have exactly one of a "+
+				"positive width, a positive skip, or rest", i)
+		}
+
+		switch {
+		case jf.Skip > 0:
+			if jf.Name != "" || jf.Transform != "" || jf.Start != nil {
+				return errors.Errorf("skipped bits can't have a name, "+
+					"start, or transform, but field %d does", i)
+			}
+			cur += jf.Skip
+			if cur > e.bitLength {
+				e.bitLength = cur
+			}
+			continue
+		case jf.Rest:
+			if i != len(je.Fields)-1 || jf.Start != nil {
+				return errors.Errorf("field %d must be last and can't have "+
+					"a start, since it's the rest of the data", i)
+			}
+			if err := e.addName(jf.Name); err != nil {
+				return errors.Wrapf(err, "invalid field %d", i)
+			}
+			e.rest = true
+		default:
+			start := cur
+			if jf.Start != nil {
+				start = *jf.Start
+			}
+			if err := e.AddField(jf.Name, start, jf.Width); err != nil {
+				return errors.Wrapf(err, "invalid field %d", i)
+			}
+			cur = start + jf.Width
+		}
+
+		var t Transform
+		if jf.Transform != "" {
+			var ok bool
+			if t, ok = namedTransforms[jf.Transform]; !ok {
+				return errors.Errorf("field %d has an unknown transform %q",
+					i, jf.Transform)
+			}
+		}
+		transforms = append(transforms, t)
+	}
+	if e.NumFields() == 0 {
+		return errors.New("BitExploder JSON must have at least one field " +
+			"that isn't skipped")
+	}
+
+	for idx, t := range transforms {
+		if t == nil {
+			continue
+		}
+		if err := e.SetTransform(idx, t); err != nil {
+			return err
+		}
+	}
+
+	for i, jp := range je.Parity {
+		var p Parity
+		switch jp.Parity {
+		case EvenParity.String():
+			p = EvenParity
+		case OddParity.String():
+			p = OddParity
+		default:
+			return errors.Errorf("parity check %d has an invalid parity %q; "+
+				"it must be %q or %q", i, jp.Parity, EvenParity, OddParity)
+		}
+		if err := e.SetParityCheck(jp.Field, p, jp.Start, jp.Length); err != nil {
+			return errors.Wrapf(err, "invalid parity check %d", i)
+		}
+	}
+
+	*exp = e
+	return nil
+}
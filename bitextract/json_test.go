@@ -0,0 +1,87 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"encoding/json"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestBitExploder_JSON(t *testing.T) {
+	w := expect.WrapT(t)
+
+	exp := w.ShouldHaveResult(NewNamedBitExploder([]Field{
+		{"flag", 1}, {"", -3}, {"count", 12}, {"", -4}, {"code", RestOfData},
+	})).(BitExploder)
+	w.ShouldSucceed(exp.SetTransform(1, BCD))
+	w.ShouldSucceed(exp.SetTransform(2, SevenBitASCII))
+	w.ShouldSucceed(exp.SetParityCheck(0, OddParity, 4, 12))
+
+	data := w.ShouldHaveResult(json.Marshal(exp)).([]byte)
+	w.ShouldBeEqual(string(data), `{"fields":[`+
+		`{"name":"flag","width":1},{"skip":3},`+
+		`{"name":"count","width":12,"transform":"BCD"},{"skip":4},`+
+		`{"name":"code","rest":true,"transform":"SevenBitASCII"}],`+
+		`"parity":[{"field":0,"parity":"odd","start":4,"length":12}]}`)
+
+	var decoded BitExploder
+	w.ShouldSucceed(json.Unmarshal(data, &decoded))
+	w.ShouldBeEqual(decoded.FieldNames(), exp.FieldNames())
+	w.ShouldBeEqual(decoded.FieldRanges(), exp.FieldRanges())
+	w.ShouldBeEqual(w.ShouldHaveResult(json.Marshal(decoded)), data)
+
+	// 1_000_0001_0010_0011_0000_1001000_1101001_0000000_0000000
+	tag := []byte{0x81, 0x23, 0x09, 0x1A, 0x40, 0x00}
+	w.ShouldBeEqual(w.ShouldHaveResult(decoded.ExplodeToMap(tag)), map[string][]byte{
+		"flag": {0x01}, "count": {0x7B}, "code": []byte("Hi"),
+	})
+	w.ShouldHaveError(decoded.Explode([]byte{0x01, 0x23, 0x09, 0x1A, 0x40, 0x00}))
+
+	unnamed := w.ShouldHaveResult(NewBitExploder([]int{8, 16})).(BitExploder)
+	data = w.ShouldHaveResult(json.Marshal(unnamed)).([]byte)
+	w.ShouldBeEqual(string(data), `{"fields":[{"width":8},{"width":16}]}`)
+	w.ShouldSucceed(json.Unmarshal(data, &decoded))
+	w.ShouldBeEqual(decoded.NumFields(), 2)
+	w.ShouldHaveLength(decoded.FieldNames(), 0)
+
+	custom := unnamed
+	w.ShouldSucceed(custom.SetTransform(0, func(f []byte, _ int) ([]byte, error) {
+		return f, nil
+	}))
+	w.ShouldHaveError(json.Marshal(custom))
+
+	overlapping := w.ShouldHaveResult(NewBitExploder([]int{4, -4, 8})).(BitExploder)
+	w.ShouldSucceed(overlapping.AddField("", 8, 4))
+	w.ShouldSucceed(overlapping.AddField("", 20, 4))
+	data = w.ShouldHaveResult(json.Marshal(overlapping)).([]byte)
+	w.ShouldBeEqual(string(data), `{"fields":[{"width":4},{"skip":4},{"width":8},`+
+		`{"start":8,"width":4},{"skip":8},{"width":4}]}`)
+	w.ShouldSucceed(json.Unmarshal(data, &decoded))
+	w.ShouldBeEqual(decoded.FieldRanges(), overlapping.FieldRanges())
+	w.ShouldBeEqual(decoded.BitLength(), overlapping.BitLength())
+
+	for _, invalid := range []string{
+		`{"fields":[{"skip":3,"start":1},{"width":8}]}`,
+		`{"fields":[{"width":8},{"rest":true,"start":4}]}`,
+		`{"fields":[{"width":8,"start":-1}]}`,
+		`{"fields":[]}`,
+		`{"fields":[{"width":8,"skip":3}]}`,
+		`{"fields":[{"width":-8}]}`,
+		`{"fields":[{"name":"a"}]}`,
+		`{"fields":[{"skip":3,"name":"a"},{"width":8}]}`,
+		`{"fields":[{"name":"a","width":8},{"width":8}]}`,
+		`{"fields":[{"width":8,"transform":"ROT13"}]}`,
+		`{"fields":[{"rest":true},{"width":8}]}`,
+		`{"fields":[{"width":8}],"parity":[{"field":0,"parity":"odd","start":0,"length":8}]}`,
+		`{"fields":[{"width":1},{"width":8}],"parity":[{"field":0,"parity":"none","start":1,"length":8}]}`,
+		`{"fields":8}`,
+	} {
+		w.As(invalid).ShouldFail(json.Unmarshal([]byte(invalid), &decoded))
+	}
+	w.ShouldBeEqual(decoded.FieldRanges(), overlapping.FieldRanges())
+}
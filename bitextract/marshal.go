@@ -0,0 +1,345 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// structField describes where a struct field is stored in bit data, according
+// to its "bits" struct tag.
+type structField struct {
+	index     int // of the field in its struct
+	name      string
+	start     int
+	width     int
+	sevenBit  bool // for strings, 7 bits per character rather than 8
+	extractor BitExtractor
+}
+
+// structLayout is the layout of all the tagged fields of a struct type.
+type structLayout struct {
+	fields    []structField
+	bitLength int // the end of the furthest field
+}
+
+// layouts caches structLayouts by their reflect.Type.
+var layouts sync.Map
+
+// layoutOf returns the structLayout for the type, which must be a struct.
+func layoutOf(t reflect.Type) (*structLayout, error) {
+	if l, ok := layouts.Load(t); ok {
+		return l.(*structLayout), nil
+	}
+
+	l := &structLayout{}
+	next := 0
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("bits")
+		if !ok || tag == "-" {
+			continue
+		}
+		if sf.PkgPath != "" {
+			return nil, errors.Errorf("field %s has a bits tag, "+
+				"but is unexported", sf.Name)
+		}
+
+		f, err := parseBitsTag(sf, tag, next)
+		if err != nil {
+			return nil, err
+		}
+		f.index = i
+		l.fields = append(l.fields, f)
+		next = f.start + f.width
+		if next > l.bitLength {
+			l.bitLength = next
+		}
+	}
+	if len(l.fields) == 0 {
+		return nil, errors.Errorf("%s has no fields with bits tags", t)
+	}
+
+	actual, _ := layouts.LoadOrStore(t, l)
+	return actual.(*structLayout), nil
+}
+
+// parseBitsTag parses a "bits" struct tag, which has the form:
+//     width[,offset=N][,name=Name][,7bit]
+// If there's no offset, the field starts at next.
+func parseBitsTag(sf reflect.StructField, tag string, next int) (structField, error) {
+	f := structField{name: sf.Name, start: next}
+	parts := strings.Split(tag, ",")
+
+	var err error
+	if f.width, err = strconv.Atoi(strings.TrimSpace(parts[0])); err != nil || f.width < 1 {
+		return f, errors.Errorf("field %s has an invalid bits width: %q",
+			sf.Name, parts[0])
+	}
+	for _, opt := range parts[1:] {
+		opt = strings.TrimSpace(opt)
+		switch {
+		case strings.HasPrefix(opt, "offset="):
+			f.start, err = strconv.Atoi(opt[len("offset="):])
+			if err != nil || f.start < 0 {
+				return f, errors.Errorf("field %s has an invalid bits "+
+					"offset: %q", sf.Name, opt)
+			}
+		case strings.HasPrefix(opt, "name="):
+			f.name = opt[len("name="):]
+		case opt == "7bit":
+			f.sevenBit = true
+		default:
+			return f, errors.Errorf("field %s has an unknown bits tag "+
+				"option: %q", sf.Name, opt)
+		}
+	}
+
+	if f.extractor, err = NewChecked(f.start, f.width); err != nil {
+		return f, errors.Wrapf(err, "invalid bounds for field %s", sf.Name)
+	}
+	return f, checkFieldKind(sf.Type, f)
+}
+
+// checkFieldKind returns an error if a field of the given type can't hold a
+// value of the given width.
+func checkFieldKind(t reflect.Type, f structField) error {
+	if f.sevenBit && t.Kind() != reflect.String {
+		return errors.Errorf("field %s uses the 7bit option, "+
+			"but isn't a string", f.name)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f.width > t.Bits() {
+			return errors.Errorf("field %s has %d bits, which is too many "+
+				"for its type, %s", f.name, f.width, t)
+		}
+		return nil
+	case reflect.String:
+		charBits := 8
+		if f.sevenBit {
+			charBits = 7
+		}
+		if f.width%charBits != 0 {
+			return errors.Errorf("string field %s must have a multiple "+
+				"of %d bits, but has %d", f.name, charBits, f.width)
+		}
+		return nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+	}
+	return errors.Errorf("field %s has unsupported type %s", f.name, t)
+}
+
+// Unmarshal extracts bit fields from data into the struct v points to, based on
+// the "bits" tags of its fields, which have the form:
+//     `bits:"width[,offset=N][,name=Name][,7bit]"`
+// Each field starts where the previous tagged field ends, or at bit 0 for the
+// first, unless it has an explicit offset. Bits are numbered as by New, and the
+// name is used in error messages, defaulting to the Go field's name. Fields
+// without a "bits" tag, or with the tag "-", are ignored. For example:
+//     type SGTIN96 struct {
+//         Header    uint8  `bits:"8"`
+//         Filter    uint8  `bits:"3"`
+//         Partition uint8  `bits:"3"`
+//         PrefixIIR uint64 `bits:"44"`
+//         Serial    uint64 `bits:"38"`
+//     }
+//
+// Tagged fields may have these types:
+//   - unsigned integers, whose types must have at least width bits
+//   - signed integers, whose types must have at least width bits, and which are
+//     interpreted as two's complement values of the given width
+//   - bool, which is true if any of its bits are 1
+//   - []byte, which is set to the bits as returned by BitExtractor.Extract
+//   - string, which holds 8 bit characters, or 7 bit characters with the 7bit
+//     option, as used by SGTIN-198 serials; trailing null characters are
+//     removed, and width must be a multiple of the character size
+//
+// Unmarshal returns an error if v isn't a non-nil pointer to a struct, its type
+// has invalid tags, or data doesn't have enough bits for all its fields.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("Unmarshal requires a non-nil pointer to a "+
+			"struct, not %T", v)
+	}
+	rv = rv.Elem()
+
+	l, err := layoutOf(rv.Type())
+	if err != nil {
+		return err
+	}
+	if len(data)*ByteSize < l.bitLength {
+		return errors.Errorf("invalid data length %d; expected %d bits",
+			len(data)*ByteSize, l.bitLength)
+	}
+
+	for _, f := range l.fields {
+		fv := rv.Field(f.index)
+		switch fv.Kind() {
+		case reflect.Bool:
+			fv.SetBool(f.extractor.ExtractBigInt(data).Sign() != 0)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			fv.SetUint(f.extractor.ExtractUInt64(data))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			fv.SetInt(f.extractor.ExtractInt64(data))
+		case reflect.Slice:
+			fv.SetBytes(f.extractor.Extract(data))
+		case reflect.String:
+			fv.SetString(f.extractString(data))
+		}
+	}
+	return nil
+}
+
+// extractString extracts a string field's characters, without trailing nulls.
+func (f structField) extractString(data []byte) string {
+	var chars []byte
+	if f.sevenBit {
+		chars = make([]byte, f.width/7)
+		for i := range chars {
+			chars[i] = byte(New(f.start+i*7, 7).ExtractUInt64(data))
+		}
+	} else {
+		chars = f.extractor.Extract(data)
+	}
+	return strings.TrimRight(string(chars), "\x00")
+}
+
+// Marshal packs the tagged fields of the struct v, or that v points to, into a
+// byte slice, using the same "bits" struct tags as Unmarshal, so that
+// Unmarshal recovers the values. The result has enough bytes for the furthest
+// field; bits not covered by any field, including those padding the final
+// byte, are 0s. If fields overlap, later fields overwrite earlier ones.
+//
+// Marshal returns an error if a value doesn't fit its field's width:
+//   - unsigned integers must be less than 2^width
+//   - signed integers must be representable in width bits of two's complement
+//   - []byte values may be shorter than the width, but not hold a larger value
+//   - strings may be shorter than the width, in which case they're padded with
+//     null characters, and with the 7bit option, may only have ASCII characters
+// A bool is encoded as 1 in the field's lowest-order bit, if it's true.
+func Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.Errorf("Marshal requires a struct or a non-nil "+
+			"pointer to one, not %T", v)
+	}
+
+	l, err := layoutOf(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, (l.bitLength+ByteSize-1)/ByteSize)
+	for _, f := range l.fields {
+		if err := f.insert(data, rv.Field(f.index)); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// insert writes the field's value into data.
+func (f structField) insert(data []byte, fv reflect.Value) error {
+	switch fv.Kind() {
+	case reflect.Bool:
+		var v []byte
+		if fv.Bool() {
+			v = []byte{1}
+		}
+		return f.insertBytes(data, v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v := fv.Uint()
+		if f.width < 64 && v >= 1<<uint(f.width) {
+			return errors.Errorf("field %s is %d, which doesn't fit "+
+				"in %d bits", f.name, v, f.width)
+		}
+		f.extractor.InsertUInt64(data, v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v := fv.Int()
+		if f.width < 64 {
+			limit := int64(1) << uint(f.width-1)
+			if v < -limit || v >= limit {
+				return errors.Errorf("field %s is %d, which doesn't fit "+
+					"in %d bits of two's complement", f.name, v, f.width)
+			}
+		}
+		f.extractor.InsertUInt64(data, uint64(v))
+	case reflect.Slice:
+		return f.insertBytes(data, fv.Bytes())
+	case reflect.String:
+		return f.insertString(data, fv.String())
+	}
+	return nil
+}
+
+// insertBytes writes a big endian, right-aligned value into data.
+func (f structField) insertBytes(data, v []byte) error {
+	field := make([]byte, f.extractor.ByteLength())
+	extra := len(v) - len(field)
+	for i := 0; i < extra; i++ {
+		if v[i] != 0 {
+			return errors.Errorf("field %s is too large for %d bits",
+				f.name, f.width)
+		}
+	}
+	if extra > 0 {
+		v = v[extra:]
+	}
+	copy(field[len(field)-len(v):], v)
+	if field[0]&^f.extractor.mask != 0 {
+		return errors.Errorf("field %s is too large for %d bits", f.name, f.width)
+	}
+	f.extractor.InsertTo(data, field)
+	return nil
+}
+
+// insertString writes a string's characters into data, padded with nulls.
+func (f structField) insertString(data []byte, s string) error {
+	charBits := 8
+	if f.sevenBit {
+		charBits = 7
+	}
+	if len(s) > f.width/charBits {
+		return errors.Errorf("field %s is %q, which has more than %d "+
+			"characters", f.name, s, f.width/charBits)
+	}
+
+	if !f.sevenBit {
+		field := make([]byte, f.width/8)
+		copy(field, s)
+		f.extractor.InsertTo(data, field)
+		return nil
+	}
+
+	for i := 0; i < f.width/7; i++ {
+		var c byte
+		if i < len(s) {
+			if c = s[i]; c > 0x7F {
+				return errors.Errorf("field %s is %q, which has non-ASCII "+
+					"characters", f.name, s)
+			}
+		}
+		New(f.start+i*7, 7).InsertUInt64(data, uint64(c))
+	}
+	return nil
+}
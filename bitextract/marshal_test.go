@@ -0,0 +1,181 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+type testSGTIN96 struct {
+	Header    uint8  `bits:"8"`
+	Filter    uint8  `bits:"3"`
+	Partition uint8  `bits:"3"`
+	Prefix    uint32 `bits:"24,name=companyPrefix"`
+	IIR       uint32 `bits:"20"`
+	Serial    uint64 `bits:"38"`
+	ignored   int
+	Ignored   int `bits:"-"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	w := expect.WrapT(t)
+	data := w.ShouldHaveResult(hex.DecodeString("3074257BF7194E4000001A85")).([]byte)
+
+	var s testSGTIN96
+	w.ShouldSucceed(Unmarshal(data, &s))
+	w.ShouldBeEqual(s, testSGTIN96{Header: 0x30, Filter: 3, Partition: 5,
+		Prefix: 614141, IIR: 812345, Serial: 6789})
+
+	w.ShouldFail(Unmarshal(data[:11], &s))
+	w.ShouldFail(Unmarshal(data, s))
+	w.ShouldFail(Unmarshal(data, (*testSGTIN96)(nil)))
+	w.ShouldFail(Unmarshal(data, new(int)))
+}
+
+func TestUnmarshal_types(t *testing.T) {
+	w := expect.WrapT(t)
+
+	type layout struct {
+		Flag    bool   `bits:"1"`
+		Wide    bool   `bits:"3"`
+		Delta   int8   `bits:"4"`
+		Raw     []byte `bits:"12"`
+		Name    string `bits:"16"`
+		Serial  string `bits:"21,7bit"`
+		Again   uint8  `bits:"8,offset=0"`
+		Overlap int16  `bits:"8"`
+	}
+
+	// 1_000_1110_000010101011_0100000101000010_1100001_0000000_0000000_000
+	data := w.ShouldHaveResult(hex.DecodeString("8E0AB4142C200000")).([]byte)
+	var l layout
+	w.ShouldSucceed(Unmarshal(data, &l))
+	w.ShouldBeEqual(l, layout{
+		Flag:    true,
+		Wide:    false,
+		Delta:   -2,
+		Raw:     []byte{0x00, 0xAB},
+		Name:    "AB",
+		Serial:  "a",
+		Again:   0x8E,
+		Overlap: 0x0A,
+	})
+}
+
+func TestUnmarshal_invalidTags(t *testing.T) {
+	w := expect.WrapT(t)
+	data := make([]byte, 16)
+
+	for _, v := range []interface{}{
+		&struct{}{},
+		&struct{ A int }{},
+		&struct {
+			A uint8 `bits:"9"`
+		}{},
+		&struct {
+			A uint8 `bits:"0"`
+		}{},
+		&struct {
+			A uint8 `bits:"x"`
+		}{},
+		&struct {
+			A uint8 `bits:"8,offset=-1"`
+		}{},
+		&struct {
+			A uint8 `bits:"8,unknown"`
+		}{},
+		&struct {
+			A uint8 `bits:"8,7bit"`
+		}{},
+		&struct {
+			A string `bits:"12"`
+		}{},
+		&struct {
+			A string `bits:"8,7bit"`
+		}{},
+		&struct {
+			A float32 `bits:"8"`
+		}{},
+		&struct {
+			A []int `bits:"8"`
+		}{},
+		&struct {
+			a uint8 `bits:"8"`
+		}{},
+	} {
+		w.As(v).ShouldFail(Unmarshal(data, v))
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	w := expect.WrapT(t)
+
+	s := testSGTIN96{Header: 0x30, Filter: 3, Partition: 5,
+		Prefix: 614141, IIR: 812345, Serial: 6789, ignored: 1, Ignored: 2}
+	data := w.ShouldHaveResult(Marshal(s)).([]byte)
+	w.ShouldBeEqual(hex.EncodeToString(data), "3074257bf7194e4000001a85")
+	w.ShouldBeEqual(w.ShouldHaveResult(Marshal(&s)), data)
+
+	type layout struct {
+		Flag    bool   `bits:"1"`
+		Wide    bool   `bits:"3"`
+		Delta   int8   `bits:"4"`
+		Raw     []byte `bits:"12"`
+		Name    string `bits:"16"`
+		Serial  string `bits:"21,7bit"`
+		Padding uint8  `bits:"3"`
+	}
+	l := layout{Flag: true, Wide: true, Delta: -2, Raw: []byte{0xAB},
+		Name: "AB", Serial: "a"}
+	data = w.ShouldHaveResult(Marshal(l)).([]byte)
+	w.ShouldBeEqual(hex.EncodeToString(data), "9e0ab4142c200000")
+
+	var round layout
+	w.ShouldSucceed(Unmarshal(data, &round))
+	w.ShouldBeEqual(round, layout{Flag: true, Wide: true, Delta: -2,
+		Raw: []byte{0x00, 0xAB}, Name: "AB", Serial: "a"})
+}
+
+func TestMarshal_outOfRange(t *testing.T) {
+	w := expect.WrapT(t)
+
+	type layout struct {
+		U   uint8  `bits:"3"`
+		I   int8   `bits:"4"`
+		B   []byte `bits:"12"`
+		S   string `bits:"16"`
+		S7  string `bits:"14,7bit"`
+		U64 uint64 `bits:"64"`
+		I64 int64  `bits:"64"`
+	}
+
+	valid := layout{U: 7, I: -8, B: []byte{0, 0x0F, 0xFF}, S: "ab", S7: "cd",
+		U64: 1<<64 - 1, I64: -1 << 63}
+	w.ShouldHaveResult(Marshal(valid))
+	valid.I = 7
+	w.ShouldHaveResult(Marshal(valid))
+
+	for _, l := range []layout{
+		{U: 8},
+		{I: 8},
+		{I: -9},
+		{B: []byte{0x10, 0x00}},
+		{B: []byte{0x01, 0x00, 0x00}},
+		{S: "abc"},
+		{S7: "abc"},
+		{S7: "\xFF"},
+	} {
+		w.As(l).ShouldHaveError(Marshal(l))
+	}
+
+	w.ShouldHaveError(Marshal(nil))
+	w.ShouldHaveError(Marshal(1))
+	w.ShouldHaveError(Marshal((*layout)(nil)))
+	w.ShouldHaveError(Marshal(struct{ A float64 }{}))
+}
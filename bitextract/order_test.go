@@ -0,0 +1,180 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestBitExtractor_WithOrder(t *testing.T) {
+	w := expect.WrapT(t)
+
+	be := New(3, 10)
+	w.ShouldBeEqual(be.Order(), MSBFirst)
+	lsb := be.WithOrder(LSBFirst)
+	w.ShouldBeEqual(lsb.Order(), LSBFirst)
+	w.ShouldBeEqual(be.Order(), MSBFirst)
+	w.ShouldBeEqual(lsb.WithOrder(MSBFirst), be)
+
+	lsb.SetBounds(4, 8)
+	w.ShouldBeEqual(lsb.Order(), LSBFirst)
+	w.ShouldBeEqual(lsb.ByteLength(), 1)
+
+	w.ShouldBeEqual(MSBFirst.String(), "MSBFirst")
+	w.ShouldBeEqual(LSBFirst.String(), "LSBFirst")
+}
+
+func TestBitExtractor_LSBFirst(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for _, tt := range []struct {
+		start, length int
+		data          []byte
+		expected      []byte
+	}{
+		{0, 1, []byte{0x01}, []byte{0x01}},
+		{0, 1, []byte{0xFE}, []byte{0x00}},
+		{7, 1, []byte{0x80}, []byte{0x01}},
+		{7, 2, []byte{0x80, 0x01}, []byte{0x03}},
+		{7, 2, []byte{0x00, 0x01}, []byte{0x02}},
+		{4, 8, []byte{0xAB, 0xCD}, []byte{0xDA}},
+		{0, 16, []byte{0xAB, 0xCD}, []byte{0xCD, 0xAB}},
+		{8, 8, []byte{0xAB, 0xCD}, []byte{0xCD}},
+		{4, 12, []byte{0xAB, 0xCD, 0xEF}, []byte{0x0C, 0xDA}},
+		{12, 9, []byte{0xAB, 0xCD, 0xEF}, []byte{0x00, 0xFC}},
+	} {
+		name := fmt.Sprintf("%d:%d %X", tt.start, tt.length, tt.data)
+		be := New(tt.start, tt.length).WithOrder(LSBFirst)
+		w.As(name).ShouldBeEqual(be.Extract(tt.data), tt.expected)
+
+		dst := make([]byte, len(tt.data))
+		be.InsertTo(dst, tt.expected)
+		w.As(name).ShouldBeEqual(be.Extract(dst), tt.expected)
+	}
+}
+
+// extractLSBFirst treats src as a little endian integer and extracts the
+// given range of bits from it, as an alternative LSBFirst implementation.
+func extractLSBFirst(src []byte, start, length int) []byte {
+	bi := new(big.Int).SetBytes(reverseBytes(nil, src))
+	bi.Rsh(bi, uint(start))
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(length))
+	bi.And(bi, mask.Sub(mask, big.NewInt(1)))
+	return bi.Bytes()
+}
+
+func TestBitExtractor_LSBFirst_CompareToBigInt(t *testing.T) {
+	w := expect.WrapT(t).StopOnMismatch()
+	buff := make([]byte, 50)
+	orig := make([]byte, len(buff))
+
+	rand.Seed(6)
+	for i := 0; i < 1000; i++ {
+		rand.Read(buff)
+		start := rand.Int() % ((len(buff) - 1) * 8)
+		length := (rand.Int() % ((len(buff) * 8) - start)) + 1
+		be := New(start, length).WithOrder(LSBFirst)
+		name := fmt.Sprintf("%d:%d", start, length)
+
+		extracted := new(big.Int).SetBytes(be.Extract(buff))
+		w.As(name).ShouldBeEqual(extracted.Bytes(), extractLSBFirst(buff, start, length))
+
+		// inserting changes only the field's bits
+		copy(orig, buff)
+		value := be.Buffer()
+		rand.Read(value)
+		be.InsertTo(buff, value)
+		value[0] &= be.mask
+		w.As(name).ShouldBeEqual(be.Extract(buff), value)
+		if start > 0 {
+			before := New(0, start).WithOrder(LSBFirst)
+			w.As(name).ShouldBeEqual(before.Extract(buff), before.Extract(orig))
+		}
+		if end := start + length; end < len(buff)*8 {
+			after := New(end, len(buff)*8-end).WithOrder(LSBFirst)
+			w.As(name).ShouldBeEqual(after.Extract(buff), after.Extract(orig))
+		}
+	}
+}
+
+func TestBitExtractor_ReversedBitsLittleEndian(t *testing.T) {
+	w := expect.WrapT(t)
+
+	testCases := []struct {
+		be       BitExtractor
+		data     []byte
+		expected uint64
+	}{
+		{New(0, 16).WithLittleEndian(true), []byte{0x34, 0x12}, 0x1234},
+		{New(8, 24).WithLittleEndian(true), []byte{0xFF, 0x56, 0x34, 0x12}, 0x123456},
+		{New(4, 16).WithLittleEndian(true), []byte{0xF3, 0x41, 0x2F}, 0x1234},
+		{New(0, 3).WithReversedBits(true), []byte{0xC0}, 0x3},
+		{New(4, 12).WithReversedBits(true), []byte{0xAB, 0xCD}, 0xB3D},
+		{New(0, 16).WithReversedBits(true).WithLittleEndian(true), []byte{0x01, 0x02}, 0x8040},
+		{New(0, 8).WithOrder(LSBFirst).WithReversedBits(true), []byte{0x01}, 0x80},
+	}
+
+	for i, tc := range testCases {
+		w := w.As(i)
+		w.ShouldBeEqual(tc.be.ExtractUInt64(tc.data), tc.expected)
+
+		dst := make([]byte, len(tc.data))
+		tc.be.InsertUInt64(dst, tc.expected)
+		w.ShouldBeEqual(tc.be.ExtractUInt64(dst), tc.expected)
+	}
+
+	be := New(3, 16).WithLittleEndian(true).WithReversedBits(true)
+	w.ShouldBeTrue(be.LittleEndian())
+	w.ShouldBeTrue(be.ReversedBits())
+	be.SetBounds(2, 24)
+	w.ShouldBeTrue(be.LittleEndian())
+	w.ShouldBeFalse(New(3, 16).LittleEndian())
+	w.ShouldBeFalse(New(3, 16).ReversedBits())
+
+	assertPanics := func(f func()) {
+		defer func() {
+			recover()
+		}()
+		f()
+		t.Fatal("expected function to panic, but it didn't")
+	}
+	assertPanics(func() { New(0, 12).WithLittleEndian(true) })
+	assertPanics(func() { be.SetBounds(0, 12) })
+}
+
+func TestBitExtractor_ReversedBits_CompareToBigInt(t *testing.T) {
+	w := expect.WrapT(t).StopOnMismatch()
+	buff := make([]byte, 20)
+
+	rand.Seed(7)
+	for i := 0; i < 1000; i++ {
+		rand.Read(buff)
+		start := rand.Int() % ((len(buff) - 1) * 8)
+		length := (rand.Int() % ((len(buff) * 8) - start)) + 1
+		be := New(start, length)
+		name := fmt.Sprintf("%d:%d", start, length)
+
+		// reverse the field's bits as a string of binary digits
+		digits := []byte(fmt.Sprintf("%0*b", length, new(big.Int).SetBytes(be.Extract(buff))))
+		for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+			digits[i], digits[j] = digits[j], digits[i]
+		}
+		expected, _ := new(big.Int).SetString(string(digits), 2)
+
+		rev := be.WithReversedBits(true)
+		extracted := rev.Extract(buff)
+		w.As(name).ShouldBeEqual(new(big.Int).SetBytes(extracted), expected)
+
+		dst := make([]byte, len(buff))
+		rev.InsertTo(dst, extracted)
+		w.As(name).ShouldBeEqual(be.Extract(dst), be.Extract(buff))
+	}
+}
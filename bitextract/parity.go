@@ -0,0 +1,117 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"github.com/pkg/errors"
+	"math/bits"
+)
+
+// Parity is whether a number of set bits is even or odd.
+type Parity int
+
+const (
+	// EvenParity means an even number of bits are set.
+	EvenParity = Parity(iota)
+	// OddParity means an odd number of bits are set.
+	OddParity
+)
+
+func (p Parity) String() string {
+	switch p {
+	case EvenParity:
+		return "even"
+	case OddParity:
+		return "odd"
+	}
+	return "Unknown parity"
+}
+
+// PopCount returns the number of set bits in the extractor's range of src.
+//
+// Like Extract, it panics if src is too short for the extractor.
+func (be BitExtractor) PopCount(src []byte) int {
+	var buff [16]byte
+	var dst []byte
+	if be.dstLen <= len(buff) {
+		dst = buff[:be.dstLen]
+	} else {
+		dst = make([]byte, be.dstLen)
+	}
+	be.ExtractTo(dst, src)
+
+	n := 0
+	for _, b := range dst {
+		n += bits.OnesCount8(b)
+	}
+	return n
+}
+
+// ParityOf returns the parity of the number of set bits in the extractor's range
+// of src. Like Extract, it panics if src is too short for the extractor.
+func (be BitExtractor) ParityOf(src []byte) Parity {
+	return Parity(be.PopCount(src) & 1)
+}
+
+// parityCheck is a 1 bit field that makes the bits in a range have a parity.
+type parityCheck struct {
+	field  int
+	parity Parity
+	bits   BitExtractor
+}
+
+// SetParityCheck marks the 1 bit field at the given index as a parity bit: when
+// the field is combined with the length bits starting at the bit offset start,
+// the number of set bits has the given parity. Explode and ExplodeToMap verify
+// parity bits, and return an error if any don't match the data; the other
+// methods don't check them. The range may include skipped bits and need not
+// align with fields, but shouldn't include the parity bit itself.
+//
+// SetWidths removes any parity checks. SetParityCheck returns an error if the
+// index is out of range or the field isn't 1 bit wide, or if the range isn't
+// within the BitExploder's BitLength.
+func (exp *BitExploder) SetParityCheck(idx int, parity Parity, start, length int) error {
+	if idx < 0 || idx >= len(exp.extractors) {
+		return errors.Errorf("field index %d is out of range [0, %d)",
+			idx, len(exp.extractors))
+	}
+	if exp.extractors[idx].bitLen != 1 {
+		return errors.Errorf("parity bits must be 1 bit wide, but field %d "+
+			"is %d bits", idx, exp.extractors[idx].bitLen)
+	}
+	if parity != EvenParity && parity != OddParity {
+		return errors.Errorf("invalid parity %d", parity)
+	}
+	if start < 0 || length <= 0 || start+length > exp.bitLength {
+		return errors.Errorf("parity range [%d, %d) isn't within the "+
+			"BitExploder's %d bits", start, start+length, exp.bitLength)
+	}
+
+	// copy them, since copies of the BitExploder share the slice
+	checks := make([]parityCheck, len(exp.parity), len(exp.parity)+1)
+	copy(checks, exp.parity)
+	exp.parity = append(checks, parityCheck{
+		field:  idx,
+		parity: parity,
+		bits:   New(start, length),
+	})
+	return nil
+}
+
+// checkParity verifies the BitExploder's parity checks against data, which must
+// have at least BitLength bits.
+func (exp BitExploder) checkParity(data []byte) error {
+	for _, pc := range exp.parity {
+		n := pc.bits.PopCount(data) + exp.extractors[pc.field].PopCount(data)
+		if Parity(n&1) != pc.parity {
+			return errors.Errorf("parity bit %d doesn't make bits [%d, %d) "+
+				"%v", pc.field, pc.bits.bitStart,
+				pc.bits.bitStart+pc.bits.bitLen, pc.parity)
+		}
+	}
+	return nil
+}
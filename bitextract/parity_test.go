@@ -0,0 +1,64 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"bytes"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestBitExtractor_PopCount(t *testing.T) {
+	w := expect.WrapT(t)
+
+	testCases := []struct {
+		be       BitExtractor
+		data     []byte
+		count    int
+		expected Parity
+	}{
+		{New(0, 8), []byte{0xFF}, 8, EvenParity},
+		{New(4, 12), []byte{0xAB, 0xCD}, 8, EvenParity},
+		{New(3, 2), []byte{0xAB, 0xCD}, 1, OddParity},
+		{New(0, 4).WithOrder(LSBFirst), []byte{0x07}, 3, OddParity},
+		{New(8, 200), bytes.Repeat([]byte{0xFF}, 26), 200, EvenParity},
+		{New(0, 1), []byte{0x00}, 0, EvenParity},
+	}
+
+	for i, tc := range testCases {
+		w.As(i).ShouldBeEqual(tc.be.PopCount(tc.data), tc.count)
+		w.As(i).ShouldBeEqual(tc.be.ParityOf(tc.data), tc.expected)
+	}
+}
+
+func TestBitExploder_SetParityCheck(t *testing.T) {
+	w := expect.WrapT(t)
+
+	exp := w.ShouldHaveResult(NewBitExploder([]int{1, 7, 8})).(BitExploder)
+	unchecked := exp
+	w.ShouldSucceed(exp.SetParityCheck(0, OddParity, 1, 15))
+
+	// bits 1-15 have 2 set bits, so the parity bit must be set
+	w.ShouldBeEqual(w.ShouldHaveResult(exp.Explode([]byte{0x81, 0x01})),
+		[][]byte{{0x01}, {0x01}, {0x01}})
+	w.ShouldHaveError(exp.Explode([]byte{0x01, 0x01}))
+	w.ShouldHaveResult(unchecked.Explode([]byte{0x01, 0x01}))
+
+	even := unchecked
+	w.ShouldSucceed(even.SetParityCheck(0, EvenParity, 1, 15))
+	w.ShouldHaveResult(even.Explode([]byte{0x01, 0x01}))
+	w.ShouldHaveError(even.Explode([]byte{0x81, 0x01}))
+
+	w.ShouldFail(exp.SetParityCheck(1, EvenParity, 0, 1))
+	w.ShouldFail(exp.SetParityCheck(3, EvenParity, 0, 1))
+	w.ShouldFail(exp.SetParityCheck(0, Parity(2), 1, 15))
+	w.ShouldFail(exp.SetParityCheck(0, EvenParity, 1, 16))
+	w.ShouldFail(exp.SetParityCheck(0, EvenParity, 1, 0))
+
+	w.ShouldSucceed(exp.SetWidths([]int{1, 7, 8}))
+	w.ShouldHaveResult(exp.Explode([]byte{0x01, 0x01}))
+}
@@ -0,0 +1,112 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"github.com/pkg/errors"
+	"math/big"
+	"strings"
+)
+
+// Transform converts a field's bits into another representation. It's given
+// the field as BitExtractor.Extract returns it, right-aligned in the fewest
+// bytes that hold it, along with the field's width in bits, and returns the
+// transformed value, or an error if the bits aren't valid for the transform.
+//
+// Transforms may be attached to a BitExploder's fields with SetTransform, so
+// that formats with encoded fields can be described declaratively. BCD,
+// GrayCode, SixBitASCII, and SevenBitASCII are common Transforms.
+type Transform func(field []byte, width int) ([]byte, error)
+
+// BCD interprets the field as Binary Coded Decimal, with each 4 bits holding a
+// decimal digit, most significant first, and returns the big endian binary
+// representation of the number, in the fewest bytes that hold it (but at least
+// one). It returns an error if the width isn't a multiple of 4 or any 4 bits
+// hold a value greater than 9.
+func BCD(field []byte, width int) ([]byte, error) {
+	if width%4 != 0 {
+		return nil, errors.Errorf("BCD fields must have a multiple of 4 bits, "+
+			"but this has %d", width)
+	}
+
+	digits := make([]byte, width/4)
+	start := len(field)*ByteSize - width
+	for i := range digits {
+		d := byte(New(start+i*4, 4).ExtractUInt64(field))
+		if d > 9 {
+			return nil, errors.Errorf("BCD digit %d is invalid: %#x", i, d)
+		}
+		digits[i] = '0' + d
+	}
+
+	v, ok := new(big.Int).SetString(string(digits), 10)
+	if !ok {
+		return []byte{0}, nil // only if there are no digits
+	}
+	if b := v.Bytes(); len(b) > 0 {
+		return b, nil
+	}
+	return []byte{0}, nil
+}
+
+// GrayCode interprets the field as a reflected binary Gray code, and returns the
+// binary value it represents, in the same number of bytes as the field.
+func GrayCode(field []byte, width int) ([]byte, error) {
+	b := make([]byte, len(field))
+	var prev byte // the previous byte's least significant bit, as 0x00 or 0xFF
+	for i, g := range field {
+		// each bit is the XOR of the Gray code bits at and above it
+		x := g ^ g>>1
+		x ^= x >> 2
+		x ^= x >> 4
+		x ^= prev
+		b[i] = x
+		prev = -(x & 1)
+	}
+	return b, nil
+}
+
+// SixBitASCII interprets the field as 6-bit characters, as used by the EPC Tag
+// Data Standard and ISO/IEC 15962, in which each character is the least
+// significant 6 bits of an ASCII character from 0x20 (space) to 0x5F ('_').
+// It returns the ASCII characters, without any trailing 0 characters, which
+// pad the encoding (and would otherwise be '@'). It returns an error if the
+// width isn't a multiple of 6.
+func SixBitASCII(field []byte, width int) ([]byte, error) {
+	if width%6 != 0 {
+		return nil, errors.Errorf("6-bit ASCII fields must have a multiple "+
+			"of 6 bits, but this has %d", width)
+	}
+
+	chars := make([]byte, width/6)
+	start := len(field)*ByteSize - width
+	for i := range chars {
+		c := byte(New(start+i*6, 6).ExtractUInt64(field))
+		if c&0x20 == 0 {
+			c |= 0x40
+		}
+		chars[i] = c
+	}
+	return []byte(strings.TrimRight(string(chars), "@")), nil
+}
+
+// SevenBitASCII interprets the field as packed 7-bit ASCII characters, and
+// returns them without any trailing null characters, which pad the encoding.
+// It returns an error if the width isn't a multiple of 7.
+func SevenBitASCII(field []byte, width int) ([]byte, error) {
+	if width%7 != 0 {
+		return nil, errors.Errorf("7-bit ASCII fields must have a multiple "+
+			"of 7 bits, but this has %d", width)
+	}
+
+	chars := make([]byte, width/7)
+	start := len(field)*ByteSize - width
+	for i := range chars {
+		chars[i] = byte(New(start+i*7, 7).ExtractUInt64(field))
+	}
+	return []byte(strings.TrimRight(string(chars), "\x00")), nil
+}
@@ -0,0 +1,105 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitextract
+
+import (
+	"encoding/binary"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestTransforms(t *testing.T) {
+	w := expect.WrapT(t)
+
+	testCases := []struct {
+		name      string
+		transform Transform
+		field     []byte
+		width     int
+		expected  []byte
+	}{
+		{"BCD", BCD, []byte{0x12, 0x34}, 16, []byte{0x04, 0xD2}},
+		{"BCD short", BCD, []byte{0x01, 0x23}, 12, []byte{0x7B}},
+		{"BCD zero", BCD, []byte{0x00, 0x00}, 16, []byte{0x00}},
+		{"Gray", GrayCode, []byte{0x06}, 3, []byte{0x04}},
+		{"Gray carry", GrayCode, []byte{0x01, 0x00}, 16, []byte{0x01, 0xFF}},
+		{"6-bit", SixBitASCII, []byte{0x00, 0x10, 0xB1}, 18, []byte("AB1")},
+		{"6-bit padded", SixBitASCII, []byte{0x00, 0x10, 0x80}, 18, []byte("AB")},
+		{"7-bit", SevenBitASCII, []byte{0x24, 0x69}, 14, []byte("Hi")},
+		{"7-bit padded", SevenBitASCII, []byte{0x12, 0x34, 0x80}, 21, []byte("Hi")},
+	}
+
+	for _, tc := range testCases {
+		w.As(tc.name).ShouldBeEqual(
+			w.ShouldHaveResult(tc.transform(tc.field, tc.width)), tc.expected)
+	}
+
+	w.ShouldHaveError(BCD([]byte{0x1A}, 8))
+	w.ShouldHaveError(BCD([]byte{0x12}, 6))
+	w.ShouldHaveError(SixBitASCII([]byte{0x12}, 8))
+	w.ShouldHaveError(SevenBitASCII([]byte{0x12}, 8))
+}
+
+func TestGrayCode_roundTrip(t *testing.T) {
+	w := expect.WrapT(t)
+	buff := make([]byte, 2)
+	for v := 0; v < 1<<16; v++ {
+		binary.BigEndian.PutUint16(buff, uint16(v^v>>1))
+		b, err := GrayCode(buff, 16)
+		w.StopOnMismatch().ShouldSucceed(err)
+		w.StopOnMismatch().As(v).ShouldBeEqual(int(binary.BigEndian.Uint16(b)), v)
+	}
+}
+
+func TestBitExploder_SetTransform(t *testing.T) {
+	w := expect.WrapT(t)
+
+	imp := w.ShouldHaveResult(NewBitImploder([]int{4, 16, 18})).(BitImploder)
+	data := w.ShouldHaveResult(imp.Implode([][]byte{
+		{0x0A}, {0x12, 0x34}, {0x00, 0x10, 0xB1},
+	})).([]byte)
+
+	exp := w.ShouldHaveResult(NewNamedBitExploder([]Field{
+		{"flags", 4}, {"count", 16}, {"code", 18},
+	})).(BitExploder)
+	untransformed := exp
+	w.ShouldSucceed(exp.SetTransform(1, BCD))
+	w.ShouldSucceed(exp.SetTransform(2, SixBitASCII))
+	w.ShouldFail(exp.SetTransform(3, BCD))
+	w.ShouldFail(exp.SetTransform(-1, BCD))
+
+	w.ShouldBeEqual(w.ShouldHaveResult(exp.Explode(data)),
+		[][]byte{{0x0A}, {0x04, 0xD2}, []byte("AB1")})
+	w.ShouldBeEqual(w.ShouldHaveResult(exp.ExtractField(data, "code")), []byte("AB1"))
+	w.ShouldBeEqual(w.ShouldHaveResult(exp.ExplodeToMap(data)), map[string][]byte{
+		"flags": {0x0A}, "count": {0x04, 0xD2}, "code": []byte("AB1"),
+	})
+
+	// copies made before the transforms were set are unaffected
+	w.ShouldBeEqual(w.ShouldHaveResult(untransformed.Explode(data)),
+		[][]byte{{0x0A}, {0x12, 0x34}, {0x00, 0x10, 0xB1}})
+
+	// the field isn't valid BCD
+	bad := w.ShouldHaveResult(imp.Implode([][]byte{
+		{0x0A}, {0x12, 0x3F}, {0x00, 0x10, 0xB1},
+	})).([]byte)
+	w.ShouldHaveError(exp.Explode(bad))
+	w.ShouldHaveError(exp.ExtractField(bad, "count"))
+
+	w.ShouldSucceed(exp.SetTransform(1, nil))
+	w.ShouldBeEqual(w.ShouldHaveResult(exp.ExtractField(bad, "count")), []byte{0x12, 0x3F})
+
+	// transforms apply to RestOfData fields, using their actual width
+	exp = w.ShouldHaveResult(NewBitExploder([]int{4, RestOfData})).(BitExploder)
+	w.ShouldSucceed(exp.SetTransform(1, SevenBitASCII))
+	w.ShouldBeEqual(w.ShouldHaveResult(exp.Explode([]byte{0x09, 0x1A, 0x50, 0x80})),
+		[][]byte{{0x00}, []byte("Hi!")})
+
+	w.ShouldSucceed(exp.SetWidths([]int{8, 8}))
+	w.ShouldBeEqual(w.ShouldHaveResult(exp.Explode([]byte{0x12, 0x34})),
+		[][]byte{{0x12}, {0x34}})
+}
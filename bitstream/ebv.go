@@ -0,0 +1,47 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitstream
+
+import (
+	"github.com/pkg/errors"
+	"io"
+)
+
+// ReadEBV reads an EBV-n, an Extensible Bit Vector of n-bit blocks, as used by
+// ISO/IEC 15962 for relative OIDs and lengths, and returns its value. n must be
+// in [2, 64]. Each block has an extension bit, which is 1 if another block
+// follows it, followed by n-1 bits of the value, most significant block first,
+// so an EBV-6 holds values up to 31 in one block, such as 000110 for 6, and
+// larger values in more, such as 100001 000000 for 32.
+//
+// It returns an error if the value doesn't fit in a uint64, or under the same
+// conditions as ReadBits, but io.ErrUnexpectedEOF if the data ends after the
+// first block.
+func (r *Reader) ReadEBV(n int) (uint64, error) {
+	if n < 2 || n > 64 {
+		return 0, errors.Errorf("EBV blocks must have 2 to 64 bits, not %d", n)
+	}
+
+	valueBits := uint(n - 1)
+	var v uint64
+	for first := true; ; first = false {
+		block, err := r.ReadBits(n)
+		if err != nil {
+			if err == io.EOF && !first {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		if v>>(64-valueBits) != 0 {
+			return 0, errors.Errorf("the EBV-%d's value is too large for 64 bits", n)
+		}
+		v = v<<valueBits | block&(1<<valueBits-1)
+		if block>>valueBits == 0 {
+			return v, nil
+		}
+	}
+}
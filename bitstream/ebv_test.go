@@ -0,0 +1,69 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitstream
+
+import (
+	"bytes"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"io"
+	"testing"
+)
+
+func TestReader_ReadEBV(t *testing.T) {
+	w := expect.WrapT(t)
+
+	testCases := []struct {
+		name string
+		n    int
+		data []byte
+		v    uint64
+		bits int64
+	}{
+		{"ebv-6 0", 6, []byte{0x00}, 0, 6},
+		{"ebv-6 6", 6, []byte{0x18}, 6, 6},
+		{"ebv-6 31", 6, []byte{0x7C}, 31, 6},
+		// 100001 000000
+		{"ebv-6 32", 6, []byte{0x84, 0x00}, 32, 12},
+		// 111111 011111
+		{"ebv-6 1023", 6, []byte{0xFD, 0xF0}, 1023, 12},
+		// 101 110 011
+		{"ebv-3 27", 3, []byte{0xB9, 0x80}, 27, 9},
+		{"ebv-8 127", 8, []byte{0x7F}, 127, 8},
+		{"ebv-8 128", 8, []byte{0x81, 0x00}, 128, 16},
+		// ten 7-bit blocks hold 64 bits, with leading 0s
+		{"ebv-8 max", 8, []byte{0x81, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+			0xFF, 0x7F}, 1<<64 - 1, 80},
+		{"ebv-64", 64, []byte{0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+			1<<63 - 1, 64},
+	}
+	for _, tc := range testCases {
+		w := w.As(tc.name)
+		r := NewReader(bytes.NewReader(tc.data))
+		w.ShouldBeEqual(w.ShouldHaveResult(r.ReadEBV(tc.n)), tc.v)
+		w.ShouldBeEqual(r.Offset(), tc.bits)
+	}
+
+	// consecutive EBVs: 000110 100001 000000 + padding
+	r := NewReader(bytes.NewReader([]byte{0x1A, 0x10, 0x00}))
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadEBV(6)), uint64(6))
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadEBV(6)), uint64(32))
+
+	r = NewReader(bytes.NewReader(nil))
+	_, err := r.ReadEBV(6)
+	w.ShouldBeEqual(err, io.EOF)
+	r = NewReader(bytes.NewReader([]byte{0x84}))
+	_, err = r.ReadEBV(6)
+	w.ShouldBeEqual(err, io.ErrUnexpectedEOF)
+
+	// too large: eleven 7-bit blocks of 1s
+	r = NewReader(bytes.NewReader(bytes.Repeat([]byte{0xFF}, 11)))
+	w.ShouldHaveError(r.ReadEBV(8))
+	for _, n := range []int{-1, 0, 1, 65} {
+		r = NewReader(bytes.NewReader([]byte{0}))
+		w.As(n).ShouldHaveError(r.ReadEBV(n))
+	}
+}
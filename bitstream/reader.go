@@ -0,0 +1,123 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package bitstream reads and writes data one bit field at a time, for formats
+// whose fields have variable widths and so can't be split at fixed offsets the
+// way package bitextract does, such as those used by ADI-var EPCs, Packed
+// Objects, and ISO/IEC 15962 compaction.
+//
+// As in package bitextract, bits are read from the highest-order bit of each
+// byte first, and multi-bit values are big endian and right-aligned.
+package bitstream
+
+import (
+	"bufio"
+	"github.com/pkg/errors"
+	"io"
+)
+
+// Reader reads bit fields from an underlying io.Reader. If that reader isn't an
+// io.ByteReader, the Reader buffers it, and so may read more than it uses.
+type Reader struct {
+	r      io.ByteReader
+	cur    byte // the current byte, of which the low nbits bits are unread
+	nbits  uint
+	offset int64
+}
+
+// NewReader returns a new Reader that reads bits from r.
+func NewReader(r io.Reader) *Reader {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Reader{r: br}
+}
+
+// Offset returns the number of bits read so far.
+func (r *Reader) Offset() int64 {
+	return r.offset
+}
+
+// ReadBits reads the next n bits, which must be in [0, 64], and returns them as
+// a uint64 in which the last bit read is the lowest-order bit.
+//
+// If the underlying reader runs out of data, this returns io.EOF if no bits
+// were read, or io.ErrUnexpectedEOF if only some were; in either case, the
+// bits that were read are consumed.
+func (r *Reader) ReadBits(n int) (uint64, error) {
+	if n < 0 || n > 64 {
+		return 0, errors.Errorf("can only read 0 to 64 bits at a time, not %d", n)
+	}
+
+	var v uint64
+	for need := uint(n); need > 0; {
+		if r.nbits == 0 {
+			b, err := r.r.ReadByte()
+			if err != nil {
+				if err == io.EOF && need != uint(n) {
+					err = io.ErrUnexpectedEOF
+				}
+				return 0, err
+			}
+			r.cur, r.nbits = b, 8
+		}
+
+		take := need
+		if take > r.nbits {
+			take = r.nbits
+		}
+		r.nbits -= take
+		need -= take
+		v = v<<take | uint64(r.cur>>r.nbits)&(1<<take-1)
+		r.offset += int64(take)
+	}
+	return v, nil
+}
+
+// ReadBytesBits reads the next n bits, which must not be negative, and returns
+// them right-aligned in a big endian byte slice, as BitExtractor.Extract does:
+// the slice has (n+7)/8 bytes, the highest-order bits of the first of which
+// are 0s when n isn't a multiple of 8.
+//
+// It returns errors under the same conditions as ReadBits.
+func (r *Reader) ReadBytesBits(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, errors.Errorf("cannot read a negative number of bits (%d)", n)
+	}
+
+	out := make([]byte, (n+7)/8)
+	start := r.offset
+	i := 0
+	if partial := n % 8; partial != 0 {
+		b, err := r.ReadBits(partial)
+		if err != nil {
+			return nil, err
+		}
+		out[0] = byte(b)
+		i++
+	}
+	for ; i < len(out); i++ {
+		b, err := r.ReadBits(8)
+		if err != nil {
+			if err == io.EOF && r.offset != start {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		out[i] = byte(b)
+	}
+	return out, nil
+}
+
+// Align discards any unread bits of the current byte, so that the next read
+// starts on a byte boundary, and returns the number of bits discarded.
+func (r *Reader) Align() int {
+	n := r.nbits
+	r.nbits = 0
+	r.offset += int64(n)
+	return int(n)
+}
@@ -0,0 +1,78 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitstream
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReader_ReadBits(t *testing.T) {
+	w := expect.WrapT(t)
+	//        a    b         c              d   e           f              -
+	// data: 0b1_10100110_1101100110111101_10_100100011_10001110111011110_000
+	data := w.ShouldHaveResult(hex.DecodeString("d36cded238eef0")).([]byte)
+	widths := []int{1, 8, 16, 2, 9, 17}
+	vals := []uint64{1, 166, 55741, 2, 291, 73182}
+
+	// with and without an underlying io.ByteReader
+	for _, src := range []io.Reader{
+		bytes.NewReader(data),
+		iotest.OneByteReader(bytes.NewReader(data)),
+	} {
+		r := NewReader(src)
+		for i, width := range widths {
+			w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBits(width)), vals[i])
+		}
+		w.ShouldBeEqual(r.Offset(), int64(53))
+		w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBits(0)), uint64(0))
+		w.ShouldBeEqual(r.Align(), 3)
+		w.ShouldBeEqual(r.Offset(), int64(56))
+		_, err := r.ReadBits(1)
+		w.ShouldBeEqual(err, io.EOF)
+	}
+
+	r := NewReader(bytes.NewReader(data))
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBits(4)), uint64(0xd))
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBits(52)), uint64(0x36cded238eef0))
+	w.ShouldHaveError(r.ReadBits(65))
+	w.ShouldHaveError(r.ReadBits(-1))
+
+	r = NewReader(bytes.NewReader(data))
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBits(64-8)), uint64(0xd36cded238eef0))
+
+	r = NewReader(bytes.NewReader(data))
+	w.ShouldHaveResult(r.ReadBits(50))
+	_, err := r.ReadBits(7)
+	w.ShouldBeEqual(err, io.ErrUnexpectedEOF)
+	w.ShouldBeEqual(r.Offset(), int64(56))
+}
+
+func TestReader_ReadBytesBits(t *testing.T) {
+	w := expect.WrapT(t)
+	data := w.ShouldHaveResult(hex.DecodeString("FCDF0123")).([]byte)
+
+	r := NewReader(bytes.NewReader(data))
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBytesBits(5)), []byte{0x1F})
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBytesBits(9)), []byte{0x01, 0x37})
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBytesBits(0)), []byte{})
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBytesBits(18)), []byte{0x03, 0x01, 0x23})
+	w.ShouldHaveError(r.ReadBytesBits(-1))
+	_, err := r.ReadBytesBits(8)
+	w.ShouldBeEqual(err, io.EOF)
+
+	r = NewReader(bytes.NewReader(data))
+	w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBytesBits(32)), data)
+
+	r = NewReader(bytes.NewReader(data))
+	_, err = r.ReadBytesBits(40)
+	w.ShouldBeEqual(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,136 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitstream
+
+import (
+	"bufio"
+	"github.com/pkg/errors"
+	"io"
+)
+
+// Padding determines the bits a Writer uses to fill out a partial final byte.
+type Padding int
+
+const (
+	// PadZeros fills out partial bytes with 0s. It's the default.
+	PadZeros = Padding(iota)
+	// PadOnes fills out partial bytes with 1s.
+	PadOnes
+)
+
+// Writer writes bit fields to an underlying io.Writer, which it buffers. Since
+// an io.Writer only accepts whole bytes, callers must call Flush when they're
+// done writing, which pads the final byte, if necessary, and writes it.
+//
+// After an error writing to the underlying io.Writer, all subsequent writes
+// return that error.
+type Writer struct {
+	w       *bufio.Writer
+	cur     byte // the current byte, of which the high nbits bits are written
+	nbits   uint
+	offset  int64
+	padding Padding
+	err     error
+}
+
+// NewWriter returns a new Writer that writes bits to w, padding with 0s.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// SetPadding changes the bits the Writer uses to fill out partial bytes.
+func (w *Writer) SetPadding(p Padding) {
+	w.padding = p
+}
+
+// Offset returns the number of bits written so far, including padding.
+func (w *Writer) Offset() int64 {
+	return w.offset
+}
+
+// WriteBits writes the lowest n bits of v, which must be in [0, 64], starting
+// with the highest-order of those bits. Bits of v above the lowest n are
+// ignored.
+func (w *Writer) WriteBits(v uint64, n int) error {
+	if n < 0 || n > 64 {
+		return errors.Errorf("can only write 0 to 64 bits at a time, not %d", n)
+	}
+	if w.err != nil {
+		return w.err
+	}
+
+	for left := uint(n); left > 0; {
+		take := 8 - w.nbits
+		if take > left {
+			take = left
+		}
+		left -= take
+		bits := byte(v>>left) & (1<<take - 1)
+		w.cur |= bits << (8 - w.nbits - take)
+		w.nbits += take
+		w.offset += int64(take)
+
+		if w.nbits == 8 {
+			if err := w.w.WriteByte(w.cur); err != nil {
+				w.err = err
+				return err
+			}
+			w.cur, w.nbits = 0, 0
+		}
+	}
+	return nil
+}
+
+// WriteBytesBits writes the last n bits of b, which holds a big endian, right-
+// aligned value, as returned by Reader.ReadBytesBits. It returns an error if b
+// has fewer than n bits; if it has more, the extra leading bits are ignored.
+func (w *Writer) WriteBytesBits(b []byte, n int) error {
+	if n < 0 || n > len(b)*8 {
+		return errors.Errorf("cannot write %d bits from %d bytes", n, len(b))
+	}
+
+	b = b[len(b)-(n+7)/8:]
+	if partial := n % 8; partial != 0 {
+		if err := w.WriteBits(uint64(b[0]), partial); err != nil {
+			return err
+		}
+		b = b[1:]
+	}
+	for _, c := range b {
+		if err := w.WriteBits(uint64(c), 8); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Align pads the current byte, if it's partially written, so that the next
+// write starts on a byte boundary, and returns the number of bits of padding.
+func (w *Writer) Align() (int, error) {
+	if w.nbits == 0 {
+		return 0, w.err
+	}
+	n := int(8 - w.nbits)
+	var pad uint64
+	if w.padding == PadOnes {
+		pad = 1<<uint(n) - 1
+	}
+	return n, w.WriteBits(pad, n)
+}
+
+// Flush aligns the output to a byte boundary, as Align does, then writes any
+// buffered data to the underlying io.Writer.
+func (w *Writer) Flush() error {
+	if _, err := w.Align(); err != nil {
+		return err
+	}
+	if err := w.w.Flush(); err != nil {
+		w.err = err
+		return err
+	}
+	return nil
+}
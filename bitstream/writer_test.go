@@ -0,0 +1,99 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bitstream
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/pkg/errors"
+	"math/rand"
+	"testing"
+)
+
+func TestWriter_WriteBits(t *testing.T) {
+	w := expect.WrapT(t)
+	widths := []int{1, 8, 16, 2, 9, 17}
+	vals := []uint64{1, 166, 55741, 2, 291, 73182}
+
+	buff := &bytes.Buffer{}
+	bw := NewWriter(buff)
+	for i, width := range widths {
+		w.ShouldSucceed(bw.WriteBits(vals[i], width))
+	}
+	w.ShouldBeEqual(bw.Offset(), int64(53))
+	w.ShouldSucceed(bw.Flush())
+	w.ShouldBeEqual(bw.Offset(), int64(56))
+	w.ShouldBeEqual(hex.EncodeToString(buff.Bytes()), "d36cded238eef0")
+
+	buff.Reset()
+	bw = NewWriter(buff)
+	bw.SetPadding(PadOnes)
+	w.ShouldSucceed(bw.WriteBits(0xFFF0, 12)) // high bits are ignored
+	w.ShouldBeEqual(w.ShouldHaveResult(bw.Align()), 4)
+	w.ShouldBeEqual(w.ShouldHaveResult(bw.Align()), 0)
+	w.ShouldSucceed(bw.WriteBits(0, 1))
+	w.ShouldSucceed(bw.Flush())
+	w.ShouldBeEqual(hex.EncodeToString(buff.Bytes()), "ff0f7f")
+
+	w.ShouldFail(bw.WriteBits(0, 65))
+	w.ShouldFail(bw.WriteBits(0, -1))
+}
+
+func TestWriter_WriteBytesBits(t *testing.T) {
+	w := expect.WrapT(t)
+
+	buff := &bytes.Buffer{}
+	bw := NewWriter(buff)
+	w.ShouldSucceed(bw.WriteBytesBits([]byte{0x1F}, 5))
+	w.ShouldSucceed(bw.WriteBytesBits([]byte{0xFF, 0x01, 0x37}, 9))
+	w.ShouldSucceed(bw.WriteBytesBits([]byte{0x03, 0x01, 0x23}, 18))
+	w.ShouldSucceed(bw.WriteBytesBits(nil, 0))
+	w.ShouldFail(bw.WriteBytesBits([]byte{0x01}, 9))
+	w.ShouldSucceed(bw.Flush())
+	w.ShouldBeEqual(hex.EncodeToString(buff.Bytes()), "fcdf0123")
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestWriter_error(t *testing.T) {
+	w := expect.WrapT(t)
+
+	bw := NewWriter(failingWriter{})
+	w.ShouldSucceed(bw.WriteBits(1, 3))
+	w.ShouldFail(bw.Flush())
+	w.ShouldFail(bw.WriteBits(1, 3))
+	w.ShouldFail(bw.Flush())
+}
+
+func TestWriter_roundTrip(t *testing.T) {
+	w := expect.WrapT(t).StopOnMismatch()
+
+	rand.Seed(9)
+	widths := make([]int, 1000)
+	vals := make([]uint64, len(widths))
+	buff := &bytes.Buffer{}
+	bw := NewWriter(buff)
+	for i := range widths {
+		widths[i] = rand.Intn(65)
+		vals[i] = rand.Uint64()
+		if widths[i] < 64 {
+			vals[i] &= 1<<uint(widths[i]) - 1
+		}
+		w.ShouldSucceed(bw.WriteBits(vals[i], widths[i]))
+	}
+	w.ShouldSucceed(bw.Flush())
+
+	r := NewReader(buff)
+	for i, width := range widths {
+		w.ShouldBeEqual(w.ShouldHaveResult(r.ReadBits(width)), vals[i])
+	}
+}
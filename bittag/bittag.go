@@ -7,8 +7,10 @@
 package bittag
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
 	"github.com/pkg/errors"
@@ -21,9 +23,17 @@ import (
 // referenceYear is used to parse tag URI authority dates.
 const referenceYear = "2006-01-02"
 
+// dateLayouts are the forms of dates RFC 4151 allows: a full date, a year and
+// month, or a year.
+var dateLayouts = []string{referenceYear, "2006-01", "2006"}
+
 // authorityRegex is based on RFC-1035 and RFC-3986#3.2 and used to check the authority string.
 var authorityRegex = regexp.MustCompile(`^[a-z](\.[-a-z0-9]{1,63}|[-a-z0-9]{1,63})*$`)
 
+// emailLocalRegex is based on RFC-4151 and used to check the part of an email
+// authority before its '@'.
+var emailLocalRegex = regexp.MustCompile(`^[-A-Za-z0-9._]+$`)
+
 // fieldsRegex is used to validate that fields are non-empty, numeric entries
 var fieldsRegex = regexp.MustCompile(`^\d(\d*)$`)
 
@@ -38,8 +48,13 @@ var fieldsRegex = regexp.MustCompile(`^\d(\d*)$`)
 // methods of BitTag.
 type BitTag struct {
 	uriPrefix string
-	// fields may be either uint64 or *big.Int to handle >64 bit fields.
+	// fields may be either uint64 or *big.Int to handle >64 bit fields, or
+	// string for ASCII fields.
 	fields []interface{}
+	names  []string // nil unless decoded by a Decoder with named fields
+	// formats are nil unless the Decoder has field formats
+	formats []fieldFormat
+	delim   string // empty for the default, "."
 }
 
 // URI returns a URI unique to this BitTag's prefix and fields.
@@ -51,21 +66,71 @@ type BitTag struct {
 // authorityName = DNSname / emailAddress
 // date = year ["-" month ["-" day]]
 // specific = BitTag's fields encoded as "." separated list of base-10 values
+//
+// Fields are written in base-10 unless the Decoder that decoded the BitTag has
+// other formats or padding for them, and separated by "." unless it has another
+// delimiter; see SetFieldFormat, SetFieldPadding, and SetDelimiter.
 func (bt BitTag) URI() string {
-	return fmt.Sprintf("%s:%s", bt.uriPrefix, bt)
+	return string(bt.AppendURI(make([]byte, 0, len(bt.uriPrefix)+1+bt.specificLen())))
 }
 
-// String formats the BitTag as a series of "." separated base-10 values.
+// AppendURI appends the BitTag's URI to dst and returns the extended buffer, as
+// URI does. It doesn't allocate if dst has enough capacity for the URI, unless
+// the BitTag has fields wider than 64 bits.
+func (bt BitTag) AppendURI(dst []byte) []byte {
+	dst = append(dst, bt.uriPrefix...)
+	dst = append(dst, ':')
+	return bt.appendSpecific(dst)
+}
+
+// String formats the BitTag as a series of "." separated base-10 values, or
+// values in the formats and with the delimiter set by the Decoder.
 func (bt BitTag) String() string {
-	if len(bt.fields) == 0 {
-		return ""
+	return string(bt.appendSpecific(make([]byte, 0, bt.specificLen())))
+}
+
+// appendSpecific appends the BitTag's fields, as written in its URI, to dst.
+func (bt BitTag) appendSpecific(dst []byte) []byte {
+	delim := bt.delimiter()
+	var ff fieldFormat
+	for i, value := range bt.fields {
+		if i > 0 {
+			dst = append(dst, delim...)
+		}
+		if bt.formats != nil {
+			ff = bt.formats[i]
+		}
+		dst = ff.append(dst, value, delim)
 	}
-	b := &strings.Builder{}
-	for i := 0; i < len(bt.fields)-1; i++ {
-		fmt.Fprintf(b, "%d.", bt.fields[i])
+	return dst
+}
+
+// specificLen estimates the length of the BitTag's fields as written in its
+// URI, so that they can usually be written without growing the buffer.
+func (bt BitTag) specificLen() int {
+	n := len(bt.delimiter()) * len(bt.fields)
+	for i, value := range bt.fields {
+		switch value := value.(type) {
+		case uint64:
+			n += 20
+		case *big.Int:
+			n += (value.BitLen() + 2) / 3
+		case string:
+			n += len(value)
+		}
+		if bt.formats != nil && bt.formats[i].digits > 20 {
+			n += bt.formats[i].digits
+		}
+	}
+	return n
+}
+
+// delimiter returns the delimiter between the BitTag's fields.
+func (bt BitTag) delimiter() string {
+	if bt.delim == "" {
+		return defaultDelimiter
 	}
-	fmt.Fprintf(b, "%d", bt.fields[len(bt.fields)-1])
-	return b.String()
+	return bt.delim
 }
 
 // NumFields returns the number of fields this BitTag has.
@@ -86,20 +151,212 @@ func (bt BitTag) HexField(idx, length int) string {
 	return fmt.Sprintf("%0[1]*X", length, bt.fields[idx])
 }
 
-// BitTagDecoder extracts data from tag data based on fixed, adjacent bit widths
+// Fields returns a copy of the BitTag's field values, which are each a uint64,
+// a *big.Int for fields wider than 64 bits, or a string for ASCII fields.
+func (bt BitTag) Fields() []interface{} {
+	fields := make([]interface{}, len(bt.fields))
+	for i, f := range bt.fields {
+		if bigInt, ok := f.(*big.Int); ok {
+			f = new(big.Int).Set(bigInt)
+		}
+		fields[i] = f
+	}
+	return fields
+}
+
+// checkIndex returns an error if idx isn't the index of one of the BitTag's
+// fields.
+func (bt BitTag) checkIndex(idx int) error {
+	if idx < 0 || idx >= len(bt.fields) {
+		return errors.Errorf("field index %d is out of range [0, %d)",
+			idx, len(bt.fields))
+	}
+	return nil
+}
+
+// Uint64Field returns the value of the field at the given index as a uint64, or
+// an error if the index is out of range, the field is text, or its value is
+// too large for a uint64.
+func (bt BitTag) Uint64Field(idx int) (uint64, error) {
+	if err := bt.checkIndex(idx); err != nil {
+		return 0, err
+	}
+	switch f := bt.fields[idx].(type) {
+	case uint64:
+		return f, nil
+	case *big.Int:
+		if !f.IsUint64() {
+			return 0, errors.Errorf("field %d's value %v is too large "+
+				"for a uint64", idx, f)
+		}
+		return f.Uint64(), nil
+	}
+	return 0, errors.Errorf("field %d isn't numeric", idx)
+}
+
+// BigIntField returns the value of the field at the given index as a new
+// *big.Int, whatever its width, or an error if the index is out of range or
+// the field is text.
+func (bt BitTag) BigIntField(idx int) (*big.Int, error) {
+	if err := bt.checkIndex(idx); err != nil {
+		return nil, err
+	}
+	switch f := bt.fields[idx].(type) {
+	case uint64:
+		return new(big.Int).SetUint64(f), nil
+	case *big.Int:
+		return new(big.Int).Set(f), nil
+	}
+	return nil, errors.Errorf("field %d isn't numeric", idx)
+}
+
+// Equal returns true if the BitTags have the same URI prefix and field values,
+// whatever the formats and delimiters of their URIs, so it's true for BitTags
+// decoded from the same data by Decoders that differ only in those. Numeric
+// fields are equal if their values are, whether they're held as a uint64 or a
+// *big.Int. Field names aren't compared.
+func (bt BitTag) Equal(other BitTag) bool {
+	if bt.uriPrefix != other.uriPrefix || len(bt.fields) != len(other.fields) {
+		return false
+	}
+	for i := range bt.fields {
+		if !fieldsEqual(bt.fields[i], other.fields[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldsEqual returns true if the field values are the same text, or the same
+// number, whether held as a uint64 or a *big.Int.
+func fieldsEqual(a, b interface{}) bool {
+	if s, ok := a.(string); ok {
+		t, ok := b.(string)
+		return ok && s == t
+	}
+	switch a := a.(type) {
+	case uint64:
+		switch b := b.(type) {
+		case uint64:
+			return a == b
+		case *big.Int:
+			return b.IsUint64() && b.Uint64() == a
+		}
+	case *big.Int:
+		switch b := b.(type) {
+		case uint64:
+			return a.IsUint64() && a.Uint64() == b
+		case *big.Int:
+			return a.Cmp(b) == 0
+		}
+	}
+	return false
+}
+
+// FieldNames returns the names of the BitTag's fields, in order, or nil if the
+// Decoder that decoded it doesn't name its fields.
+func (bt BitTag) FieldNames() []string {
+	if bt.names == nil {
+		return nil
+	}
+	return append([]string(nil), bt.names...)
+}
+
+// fieldIndex returns the index of the field with the given name, or an error if
+// the BitTag has no such field.
+func (bt BitTag) fieldIndex(name string) (int, error) {
+	for idx, n := range bt.names {
+		if n == name {
+			return idx, nil
+		}
+	}
+	return 0, errors.Errorf("no field is named %q", name)
+}
+
+// Field returns the value of the field with the given name, which is either a
+// uint64 or, for fields wider than 64 bits, a *big.Int, or a string for fields
+// with the ASCII7 or ASCII8 formats. It returns an error if the BitTag has no
+// such field.
+func (bt BitTag) Field(name string) (interface{}, error) {
+	idx, err := bt.fieldIndex(name)
+	if err != nil {
+		return nil, err
+	}
+	return bt.fields[idx], nil
+}
+
+// FormatFieldByName is like FormatField, but returns the field with the given
+// name, or an error if the BitTag has no such field.
+func (bt BitTag) FormatFieldByName(format, name string) (string, error) {
+	idx, err := bt.fieldIndex(name)
+	if err != nil {
+		return "", err
+	}
+	return bt.FormatField(format, idx), nil
+}
+
+// HexFieldByName is like HexField, but returns the field with the given name, or
+// an error if the BitTag has no such field.
+func (bt BitTag) HexFieldByName(name string, length int) (string, error) {
+	idx, err := bt.fieldIndex(name)
+	if err != nil {
+		return "", err
+	}
+	return bt.HexField(idx, length), nil
+}
+
+// bitTagJSON is the JSON representation of a BitTag.
+type bitTagJSON struct {
+	URI    string        `json:"uri"`
+	Fields []interface{} `json:"fields"`
+}
+
+// MarshalJSON encodes the BitTag as its URI along with its field values:
+//     {"uri": "tag:example.com,2019-01-01:15.5330", "fields": [15, 5330]}
+// Fields that are uint64s are encoded as numbers, but those wider than 64 bits
+// are encoded as strings of their base-10 values, since many JSON decoders
+// can't handle such large numbers. Use a Decoder's UnmarshalBitTag to decode
+// it, since decoding it requires the layout of its fields.
+func (bt BitTag) MarshalJSON() ([]byte, error) {
+	bj := bitTagJSON{URI: bt.URI(), Fields: make([]interface{}, len(bt.fields))}
+	for i, f := range bt.fields {
+		if bigInt, ok := f.(*big.Int); ok {
+			bj.Fields[i] = bigInt.String()
+		} else {
+			bj.Fields[i] = f
+		}
+	}
+	return json.Marshal(bj)
+}
+
+// Decoder extracts data from tag data based on fixed, adjacent bit widths
 // and returns BitTags with the URI prefix the Decoder was created with.
+//
+// Reserved regions of a layout may be skipped with negative widths (or "skip"
+// entries in a DecoderConfig). Skipped bits must be present in the data, but
+// they aren't fields: they don't appear in BitTags or their URIs, and they
+// aren't counted by NumFields, so a layout can reserve bits, or later assign
+// them to a field, without changing the fields of existing URIs.
 type Decoder struct {
 	// RFC-4151: "tag:" + authorityName + "," + date
 	uriPrefix string
 	bitextract.BitExploder
+	formats    []fieldFormat // nil unless set by SetFieldFormat
+	delim      string        // empty unless set by SetDelimiter
+	checksums  []checksumField
+	validators []fieldValidator
 }
 
+// Prefix returns the URI prefix of the Decoder's BitTags, which is "tag:" followed
+// by its tagging entity, such as "tag:example.com,2019-01-01".
 func (d *Decoder) Prefix() string {
 	return d.uriPrefix
 }
 
 // New returns a new Decoder with the given authority and date which will break
-// binary tag data into fields of the given bit widths.
+// binary tag data into fields of the given bit widths. Negative widths skip
+// reserved bits, so they don't appear in BitTags' fields or URIs; see
+// bitextract.BitExploder for details.
 //
 // See SetTaggingEntity for restrictions  the authority and date strings.
 func NewDecoder(authority, date string, widths []int) (Decoder, error) {
@@ -117,6 +374,26 @@ func NewDecoder(authority, date string, widths []int) (Decoder, error) {
 	return btd, nil
 }
 
+// NewNamedDecoder is like NewDecoder, but names the fields, so that they can be
+// accessed by name, both in the BitTags it decodes and in their URIs, rather
+// than by an index that changes if a field is added before them. The names
+// don't appear in URIs. As with bitextract.NewNamedBitExploder, names must be
+// unique and non-empty, except for skipped bits, which are unnamed.
+func NewNamedDecoder(authority, date string, fields []bitextract.Field) (Decoder, error) {
+	btd := Decoder{}
+	if err := btd.SetTaggingEntity(authority, date); err != nil {
+		return btd, err
+	}
+
+	d, err := bitextract.NewNamedBitExploder(fields)
+	if err != nil {
+		return btd, err
+	}
+	btd.BitExploder = d
+
+	return btd, nil
+}
+
 // SetTaggingEntity modifies the URI prefix the Decoder attaches to BitTags that
 // it decodes. It does not affect existing BitTags that this Decoder previously
 // decoded.
@@ -125,10 +402,16 @@ func NewDecoder(authority, date string, widths []int) (Decoder, error) {
 // RFC 4151. Specifically, the authority can only use a-z, 0-9, '.', and '-',
 // must be <= 255 characters, and each part must have <= 63 characters; it should
 // be a fully-qualified domain name (hence the above restrictions), but this
-// doesn't reach out to a domain server to verify that. The date format must
-// be of the form yyyy-MM-dd and should be a date on which the FQDN was owned by
-// the tagging authority; again, this isn't verified, but not adhearing to it
-// violates the RFC and may result in non-unique URIs.
+// doesn't reach out to a domain server to verify that. The authority may also
+// be an email address, such as "tags@example.com", whose part before the '@'
+// uses only A-Z, a-z, 0-9, '.', '_', and '-', and whose domain name has the
+// restrictions above.
+//
+// The date format must be of the form yyyy-MM-dd, yyyy-MM, or yyyy, and should
+// be a date on which the FQDN or email address was owned by the tagging
+// authority; again, this isn't verified, but not adhearing to it violates the
+// RFC and may result in non-unique URIs. Per the RFC, a year or month without a
+// day means the first day of it.
 func (btd *Decoder) SetTaggingEntity(authority string, date string) error {
 	if authority == "" {
 		return errors.New("missing tagging entity authority")
@@ -139,7 +422,17 @@ func (btd *Decoder) SetTaggingEntity(authority string, date string) error {
 
 	// Although we could silently "fix" some problems for the user, it would
 	// likely lead to more confusion, so instead, reject it bad config values.
-	if len(authority) > 255 || !authorityRegex.MatchString(authority) {
+	domain := authority
+	if at := strings.LastIndexByte(authority, '@'); at >= 0 {
+		if !emailLocalRegex.MatchString(authority[:at]) {
+			return errors.Errorf("bad authority '%s': "+
+				"the part of an email address before the '@' must be "+
+				"non-empty and use only a-z, A-Z, digits 0-9, periods ('.'), "+
+				"underscores ('_'), and hyphens ('-').", authority)
+		}
+		domain = authority[at+1:]
+	}
+	if len(domain) > 255 || !authorityRegex.MatchString(domain) {
 		return errors.Errorf("bad authority '%s': "+
 			"authority must be a fully-qualified domain name, "+
 			"using only lower-case a-z, digits 0-9, periods ('.') and hyphens ('-'), "+
@@ -147,14 +440,167 @@ func (btd *Decoder) SetTaggingEntity(authority string, date string) error {
 			"(separated by '.') containing 63 characters or fewer.", authority)
 	}
 
-	if _, err := time.Parse(referenceYear, date); err != nil {
-		return errors.Wrapf(err, "invalid authority date")
+	validDate := false
+	for _, layout := range dateLayouts {
+		if _, err := time.Parse(layout, date); err == nil {
+			validDate = true
+			break
+		}
+	}
+	if !validDate {
+		return errors.Errorf("invalid authority date '%s': it must be a "+
+			"valid date of the form yyyy-MM-dd, yyyy-MM, or yyyy", date)
 	}
 
 	btd.uriPrefix = fmt.Sprintf("tag:%s,%s", authority, date)
 	return nil
 }
 
+// decoderJSON is the JSON representation of a Decoder.
+type decoderJSON struct {
+	Authority  string                 `json:"authority"`
+	Date       string                 `json:"date"`
+	Layout     bitextract.BitExploder `json:"layout"`
+	Formats    []string               `json:"formats,omitempty"`
+	Padding    []int                  `json:"padding,omitempty"`
+	Delimiter  string                 `json:"delimiter,omitempty"`
+	Checksums  []checksumJSON         `json:"checksums,omitempty"`
+	Validators []validatorJSON        `json:"validators,omitempty"`
+}
+
+// validatorJSON is the JSON representation of a field's Validator.
+type validatorJSON struct {
+	Field int `json:"field"`
+	Validator
+}
+
+// checksumJSON is the JSON representation of a checksum field.
+type checksumJSON struct {
+	Field    int    `json:"field"`
+	Checksum string `json:"checksum"`
+}
+
+// MarshalJSON encodes the Decoder's tagging entity and the configuration of its
+// BitExploder as JSON, of the form:
+//     {"authority": "example.com", "date": "2019-01-01", "layout": {...}}
+// in which the layout is as described by bitextract.BitExploder's MarshalJSON.
+// If the Decoder has field formats, "formats" lists each field's, by the
+// names FieldFormat's String returns, such as ["decimal", "hex"]; if any field
+// has padding set by SetFieldPadding, "padding" lists each field's, or 0 for
+// the default; and a delimiter other than "." is the "delimiter". If it has
+// checksum fields, "checksums" lists them, such as
+//     [{"field": 2, "checksum": "crc16"}]
+// and if it has Validators, "validators" lists them, such as
+//     [{"field": 0, "min": 1, "max": 4}]
+func (btd Decoder) MarshalJSON() ([]byte, error) {
+	// the prefix is "tag:" + authority + "," + date, and authorities have no ','
+	entity := strings.TrimPrefix(btd.uriPrefix, "tag:")
+	sep := strings.IndexByte(entity, ',')
+	if sep < 0 {
+		return nil, errors.New("the Decoder has no tagging entity")
+	}
+	var formats []string
+	var padding []int
+	for i, ff := range btd.formats {
+		formats = append(formats, ff.format.String())
+		if ff.pad > 0 && padding == nil {
+			padding = make([]int, len(btd.formats))
+		}
+		if padding != nil {
+			padding[i] = ff.pad
+		}
+	}
+	var checksums []checksumJSON
+	for _, cf := range btd.checksums {
+		checksums = append(checksums, checksumJSON{
+			Field:    cf.field,
+			Checksum: cf.checksum.String(),
+		})
+	}
+	var validators []validatorJSON
+	for _, fv := range btd.validators {
+		validators = append(validators, validatorJSON{
+			Field:     fv.field,
+			Validator: fv.validator,
+		})
+	}
+	return json.Marshal(decoderJSON{
+		Authority:  entity[:sep],
+		Date:       entity[sep+1:],
+		Layout:     btd.BitExploder,
+		Formats:    formats,
+		Padding:    padding,
+		Delimiter:  btd.delim,
+		Checksums:  checksums,
+		Validators: validators,
+	})
+}
+
+// UnmarshalJSON replaces the Decoder's configuration with one encoded by
+// MarshalJSON, or returns an error if it's invalid, in which case the Decoder
+// is unchanged. The tagging entity has the same restrictions as those given to
+// SetTaggingEntity.
+func (btd *Decoder) UnmarshalJSON(data []byte) error {
+	var dj decoderJSON
+	if err := json.Unmarshal(data, &dj); err != nil {
+		return errors.Wrap(err, "invalid Decoder JSON")
+	}
+	if dj.Layout.NumFields() == 0 {
+		return errors.New("Decoder JSON is missing its layout")
+	}
+
+	d := Decoder{BitExploder: dj.Layout}
+	if err := d.SetTaggingEntity(dj.Authority, dj.Date); err != nil {
+		return err
+	}
+	if dj.Formats != nil && len(dj.Formats) != d.NumFields() {
+		return errors.Errorf("Decoder JSON has %d formats, but %d fields",
+			len(dj.Formats), d.NumFields())
+	}
+	for i, name := range dj.Formats {
+		format, err := parseFieldFormat(name)
+		if err != nil {
+			return errors.Wrapf(err, "invalid format for field %d", i)
+		}
+		if err := d.SetFieldFormat(i, format); err != nil {
+			return err
+		}
+	}
+	if dj.Padding != nil && len(dj.Padding) != d.NumFields() {
+		return errors.Errorf("Decoder JSON has %d paddings, but %d fields",
+			len(dj.Padding), d.NumFields())
+	}
+	for i, digits := range dj.Padding {
+		if digits == 0 {
+			continue
+		}
+		if err := d.SetFieldPadding(i, digits); err != nil {
+			return err
+		}
+	}
+	if dj.Delimiter != "" {
+		if err := d.SetDelimiter(dj.Delimiter); err != nil {
+			return err
+		}
+	}
+	for i, cj := range dj.Checksums {
+		c, err := parseChecksum(cj.Checksum)
+		if err != nil {
+			return errors.Wrapf(err, "invalid checksum %d", i)
+		}
+		if err := d.SetChecksum(cj.Field, c); err != nil {
+			return errors.Wrapf(err, "invalid checksum %d", i)
+		}
+	}
+	for i, vj := range dj.Validators {
+		if err := d.AddValidator(vj.Field, vj.Validator); err != nil {
+			return errors.Wrapf(err, "invalid validator %d", i)
+		}
+	}
+	*btd = d
+	return nil
+}
+
 // DecodeString is a convenience method that decodes hex-encoded byte data.
 func (btd Decoder) DecodeString(data string) (bt BitTag, err error) {
 	byteData, err := hex.DecodeString(data)
@@ -165,7 +611,9 @@ func (btd Decoder) DecodeString(data string) (bt BitTag, err error) {
 	return btd.Decode(byteData)
 }
 
-// Decode decodes BitTags from a byte slices.
+// Decode decodes BitTags from a byte slices. If the Decoder has checksum fields
+// that don't match the data, it returns a *ChecksumError, and if a field doesn't
+// meet one of its Validators, it returns a *ValidationError.
 func (btd Decoder) Decode(data []byte) (bt BitTag, err error) {
 	if len(data)*8 < btd.BitLength() {
 		err = errors.Errorf("invalid data length %d; expected %d bits",
@@ -173,16 +621,45 @@ func (btd Decoder) Decode(data []byte) (bt BitTag, err error) {
 		return
 	}
 
+	if btd.formats != nil && len(btd.formats) != btd.NumFields() {
+		err = errors.New("the Decoder's widths changed after its field " +
+			"formats were set")
+		return
+	}
+
+	if err = btd.verifyChecksums(data); err != nil {
+		return
+	}
+
 	fields, err := btd.Explode(data)
 	if err != nil {
 		return
 	}
 
 	bt.uriPrefix = btd.uriPrefix
+	bt.names = btd.FieldNames()
+	bt.formats = btd.formats
+	bt.delim = btd.delim
 	bt.fields = make([]interface{}, btd.NumFields())
+	var ranges []bitextract.FieldRange
+	if btd.formats != nil {
+		ranges = btd.FieldRanges()
+	}
 	buff := make([]byte, 8)
 	for fieldIdx, field := range fields {
-		if len(field) <= 8 {
+		if ranges != nil && btd.formats[fieldIdx].format.charBits() > 0 {
+			width := ranges[fieldIdx].Length
+			if width == bitextract.RestOfData {
+				width = len(data)*8 - btd.BitLength()
+			}
+			var text string
+			text, err = btd.formats[fieldIdx].format.decodeText(field, width)
+			if err != nil {
+				err = errors.Wrapf(err, "invalid field %d", fieldIdx)
+				return
+			}
+			bt.fields[fieldIdx] = text
+		} else if len(field) <= 8 {
 			binary.BigEndian.PutUint64(buff, 0)
 			copy(buff[8-len(field):], field)
 			bt.fields[fieldIdx] = binary.BigEndian.Uint64(buff)
@@ -193,34 +670,151 @@ func (btd Decoder) Decode(data []byte) (bt BitTag, err error) {
 		}
 	}
 
+	if err = btd.validate(bt); err != nil {
+		bt = BitTag{}
+	}
 	return
 }
 
 // Fields returns the URI's fields or an error if the URI is not valid.
 //
 // The URI is valid if it's prefix matches the Decoder's prefix, it has at least
-// as many fields as the decoder, and those fields consist only of digits 0-9.
+// as many fields as the decoder, and those fields consist only of digits 0-9,
+// or for fields with other formats set by SetFieldFormat, those formats' digits.
 func (btd Decoder) Fields(uri string) ([]string, error) {
 	if !strings.HasPrefix(uri, btd.uriPrefix+":") {
 		return nil, errors.Errorf("prefix should be '%s'",
 			btd.uriPrefix)
 	}
 
-	fields := strings.SplitN(uri[(len(btd.uriPrefix)+1):], ".", btd.NumFields())
+	fields := strings.SplitN(uri[(len(btd.uriPrefix)+1):], btd.Delimiter(), btd.NumFields())
 	if len(fields) < btd.NumFields() {
 		return nil, errors.Errorf("missing %d fields", btd.NumFields()-len(fields))
 	}
 
 	for i := range fields {
-		if !fieldsRegex.MatchString(fields[i]) {
+		format := btd.FieldFormat(i)
+		if !formatRegexes[format].MatchString(fields[i]) {
 			return nil, errors.Errorf("field %d is invalid (it's empty "+
-				"or contains non-numeric characters)", i)
+				"or contains characters that aren't %v digits)", i, format)
 		}
 	}
 
 	return fields, nil
 }
 
+// ParseURI parses a URI produced by a BitTag this Decoder decoded, and returns a
+// BitTag equal to that one, so that URIs and binary data can be converted back
+// and forth. It returns an error if the URI isn't valid for the Decoder, as
+// described by Fields, if it has more fields than the Decoder, or if a field's
+// value doesn't fit in that field's bits or meet its Validators.
+//
+// As with Decode, fields 64 bits wide or less have uint64 values, and wider ones
+// have *big.Int values. Since a RestOfData field's width depends on the data,
+// its value is a uint64 if it fits in one. If the Decoder's BitExploder has
+// Transforms that produce values wider than their fields, those fields can't be
+// parsed.
+func (btd Decoder) ParseURI(uri string) (BitTag, error) {
+	fields, err := btd.Fields(uri)
+	if err != nil {
+		return BitTag{}, err
+	}
+
+	ranges := btd.FieldRanges()
+	bt := BitTag{
+		uriPrefix: btd.uriPrefix,
+		fields:    make([]interface{}, len(fields)),
+		names:     btd.FieldNames(),
+		formats:   btd.formats,
+		delim:     btd.delim,
+	}
+	for i, field := range fields {
+		if format := btd.FieldFormat(i); format.charBits() > 0 {
+			text, err := format.parseText(field, ranges[i].Length)
+			if err != nil {
+				return BitTag{}, errors.Wrapf(err, "invalid field %d", i)
+			}
+			bt.fields[i] = text
+			continue
+		}
+
+		v, ok := new(big.Int).SetString(field, formatBases[btd.FieldFormat(i)])
+		if !ok {
+			return BitTag{}, errors.Errorf("field %d isn't a number", i)
+		}
+
+		width := ranges[i].Length
+		if width == bitextract.RestOfData {
+			if v.IsUint64() {
+				bt.fields[i] = v.Uint64()
+			} else {
+				bt.fields[i] = v
+			}
+			continue
+		}
+		if v.BitLen() > width {
+			return BitTag{}, errors.Errorf("field %d's value %s doesn't fit "+
+				"in its %d bits", i, field, width)
+		}
+		if width <= 64 {
+			bt.fields[i] = v.Uint64()
+		} else {
+			bt.fields[i] = v
+		}
+	}
+
+	if err := btd.validate(bt); err != nil {
+		return BitTag{}, err
+	}
+	return bt, nil
+}
+
+// UnmarshalBitTag decodes a BitTag this Decoder decoded from the JSON produced
+// by its MarshalJSON. The BitTag is parsed from the URI, as by ParseURI, and
+// its fields, if present, must match it. It returns an error if the JSON or URI
+// are invalid.
+func (btd Decoder) UnmarshalBitTag(data []byte) (BitTag, error) {
+	var bj bitTagJSON
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&bj); err != nil {
+		return BitTag{}, errors.Wrap(err, "invalid BitTag JSON")
+	}
+
+	bt, err := btd.ParseURI(bj.URI)
+	if err != nil {
+		return BitTag{}, err
+	}
+	if bj.Fields == nil {
+		return bt, nil
+	}
+	if len(bj.Fields) != bt.NumFields() {
+		return BitTag{}, errors.Errorf("BitTag JSON has %d fields, but its "+
+			"URI has %d", len(bj.Fields), bt.NumFields())
+	}
+	for i, f := range bj.Fields {
+		var value string
+		switch f := f.(type) {
+		case json.Number:
+			value = f.String()
+		case string:
+			value = f
+		default:
+			return BitTag{}, errors.Errorf("BitTag JSON field %d isn't "+
+				"a number or string", i)
+		}
+		expected := bt.FormatField("%d", i)
+		if text, ok := bt.fields[i].(string); ok {
+			expected = text
+		}
+		if value != expected {
+			return BitTag{}, errors.Errorf("BitTag JSON field %d is %q, "+
+				"but its URI has %q", i, value, expected)
+		}
+	}
+	return bt, nil
+}
+
 // Field returns a specific field of the URI or an error if the URI is not valid.
 //
 // Note that this method validates the entire URI - if you're using the field
@@ -236,3 +830,13 @@ func (btd Decoder) Field(URI string, idx int) (string, error) {
 	}
 	return fields[idx], nil
 }
+
+// FieldByName is like Field, but returns the field of the URI with the given
+// name, or an error if the URI is invalid or the Decoder has no such field.
+func (btd Decoder) FieldByName(URI string, name string) (string, error) {
+	idx, ok := btd.FieldIndex(name)
+	if !ok {
+		return "", errors.Errorf("no field is named %q", name)
+	}
+	return btd.Field(URI, idx)
+}
@@ -7,7 +7,10 @@
 package bittag
 
 import (
+	"encoding/json"
 	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"math/big"
 	"testing"
 )
 
@@ -27,3 +30,371 @@ func TestNewBitTagDecoder(t *testing.T) {
 	decID := w.ShouldHaveResult(decoder.Field(URI, 2)).(string)
 	w.As("productID from URI").ShouldBeEqual(decID, "5330")
 }
+
+func TestDecoder_SetTaggingEntity(t *testing.T) {
+	w := expect.WrapT(t)
+
+	testCases := []struct {
+		authority, date string
+		valid           bool
+	}{
+		{"test.com", "2019-01-01", true},
+		{"test.com", "2019-01", true},
+		{"test.com", "2019", true},
+		{"tags@test.com", "2019-01-01", true},
+		{"Tag.Team_1-a@sub.test.com", "2019", true},
+		{"Test.com", "2019-01-01", false},
+		{"test.com", "2019-13", false},
+		{"test.com", "2019-02-30", false},
+		{"test.com", "19", false},
+		{"test.com", "2019-1", false},
+		{"test.com", "2019-01-01T00:00", false},
+		{"@test.com", "2019", false},
+		{"tags@", "2019", false},
+		{"tag s@test.com", "2019", false},
+		{"a@b@test.com", "2019", false},
+		{"tags@Test.com", "2019", false},
+		{"", "2019", false},
+		{"test.com", "", false},
+	}
+
+	for _, tc := range testCases {
+		var d Decoder
+		err := d.SetTaggingEntity(tc.authority, tc.date)
+		if tc.valid {
+			w.As(tc).ShouldSucceed(err)
+			w.As(tc).ShouldBeEqual(d.Prefix(), "tag:"+tc.authority+","+tc.date)
+		} else {
+			w.As(tc).ShouldFail(err)
+		}
+	}
+
+	// email authorities survive a JSON round trip
+	decoder := w.ShouldHaveResult(NewDecoder("tags@test.com", "2019", []int{8})).(Decoder)
+	data := w.ShouldHaveResult(json.Marshal(decoder)).([]byte)
+	var decoded Decoder
+	w.ShouldSucceed(json.Unmarshal(data, &decoded))
+	w.ShouldBeEqual(decoded.Prefix(), "tag:tags@test.com,2019")
+	bitTag := w.ShouldHaveResult(decoded.DecodeString("0F")).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:tags@test.com,2019:15")
+	w.ShouldHaveResult(decoded.ParseURI(bitTag.URI()))
+}
+
+func TestDecoder_skippedBits(t *testing.T) {
+	w := expect.WrapT(t)
+
+	widths := w.ShouldHaveResult(bitextract.SplitWidths("8.-48.40", ".")).([]int)
+	decoder := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", widths)).(Decoder)
+	w.ShouldBeEqual(decoder.NumFields(), 2)
+	w.ShouldBeEqual(decoder.BitLength(), 96)
+
+	bitTag := w.ShouldHaveResult(decoder.DecodeString("0F00000000000C00000014D2")).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:15.5330")
+	w.ShouldBeEqual(w.ShouldHaveResult(decoder.Field(bitTag.URI(), 1)), "5330")
+	w.ShouldFail(decoder.DecodeString("0F00000000000C00000014"))
+}
+
+func TestDecoder_reservedRegions(t *testing.T) {
+	w := expect.WrapT(t)
+
+	// reserved bits don't appear in URIs; a later version of the layout that
+	// assigns some of them to a field adds it without changing the others
+	v1 := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, -48, 40})).(Decoder)
+	v2 := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, -24, 24, 40})).(Decoder)
+
+	data := "0F00000000000C00000014D2"
+	bt1 := w.ShouldHaveResult(v1.DecodeString(data)).(BitTag)
+	bt2 := w.ShouldHaveResult(v2.DecodeString(data)).(BitTag)
+	w.ShouldBeEqual(bt1.URI(), "tag:test.com,2019-01-01:15.5330")
+	w.ShouldBeEqual(bt2.URI(), "tag:test.com,2019-01-01:15.12.5330")
+	w.ShouldBeEqual(bt1.NumFields(), 2)
+	w.ShouldBeEqual(w.ShouldHaveResult(v1.Field(bt1.URI(), 1)), "5330")
+
+	// reserved bits need not be zero
+	bt1 = w.ShouldHaveResult(v1.DecodeString("0FFFFFFFFFFFFF00000014D2")).(BitTag)
+	w.ShouldBeEqual(bt1.URI(), "tag:test.com,2019-01-01:15.5330")
+}
+
+func TestDecoder_JSON(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, -48, 40})).(Decoder)
+	data := w.ShouldHaveResult(json.Marshal(decoder)).([]byte)
+	w.ShouldBeEqual(string(data), `{"authority":"test.com","date":"2019-01-01",`+
+		`"layout":{"fields":[{"width":8},{"skip":48},{"width":40}]}}`)
+
+	var decoded Decoder
+	w.ShouldSucceed(json.Unmarshal(data, &decoded))
+	w.ShouldBeEqual(decoded.Prefix(), decoder.Prefix())
+	bitTag := w.ShouldHaveResult(decoded.DecodeString("0F00000000000C00000014D2")).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:15.5330")
+
+	for _, invalid := range []string{
+		`{"authority":"test.com","date":"2019-01-01"}`,
+		`{"authority":"Test.com","date":"2019-01-01","layout":{"fields":[{"width":8}]}}`,
+		`{"authority":"test.com","date":"2019-13","layout":{"fields":[{"width":8}]}}`,
+		`{"authority":"test.com","date":"2019-01-01","layout":{"fields":[]}}`,
+	} {
+		w.As(invalid).ShouldFail(json.Unmarshal([]byte(invalid), &decoded))
+	}
+	w.ShouldBeEqual(decoded.Prefix(), decoder.Prefix())
+
+	w.ShouldHaveError(json.Marshal(Decoder{}))
+}
+
+func TestDecoder_ParseURI(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, -8, 72, 8})).(Decoder)
+
+	for _, data := range []string{
+		"0F00000000000C00000014D2",
+		"FFFFFFFFFFFFFFFFFFFFFFFF",
+		"000000000000000000000000",
+	} {
+		bitTag := w.ShouldHaveResult(decoder.DecodeString(data)).(BitTag)
+		parsed := w.As(data).ShouldHaveResult(decoder.ParseURI(bitTag.URI())).(BitTag)
+		shouldBeSameTag(w.As(data), parsed, bitTag)
+	}
+
+	for _, invalid := range []string{
+		"tag:test.com,2019-01-02:15.12.210",
+		"tag:test.com,2019-01-01:15.12",
+		"tag:test.com,2019-01-01:15.12.210.1",
+		"tag:test.com,2019-01-01:15.x.210",
+		"tag:test.com,2019-01-01:256.12.210",
+		"tag:test.com,2019-01-01:15.4722366482869645213696.210",
+	} {
+		w.As(invalid).ShouldFail(decoder.ParseURI(invalid))
+	}
+
+	rest := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, bitextract.RestOfData})).(Decoder)
+	bitTag := w.ShouldHaveResult(rest.DecodeString("0F14D2")).(BitTag)
+	parsed := w.ShouldHaveResult(rest.ParseURI(bitTag.URI())).(BitTag)
+	shouldBeSameTag(w, parsed, bitTag)
+
+	// a RestOfData field's width isn't known, so small values are uint64s
+	bitTag = w.ShouldHaveResult(rest.DecodeString("0F00000000000C00000014D2")).(BitTag)
+	parsed = w.ShouldHaveResult(rest.ParseURI(bitTag.URI())).(BitTag)
+	w.ShouldBeEqual(parsed.URI(), bitTag.URI())
+	w.ShouldBeEqual(parsed.FormatField("%T", 1), "uint64")
+}
+
+// shouldBeSameTag checks that the BitTags have the same prefix and fields, with
+// the same types. *big.Int fields are compared by value, since equal values may
+// have different internal representations.
+func shouldBeSameTag(w *expect.TWrapper, actual, expected BitTag) {
+	w.ShouldBeEqual(actual.URI(), expected.URI())
+	w.StopOnMismatch().ShouldBeEqual(actual.NumFields(), expected.NumFields())
+	for i := 0; i < expected.NumFields(); i++ {
+		w.As(i).ShouldBeEqual(actual.FormatField("%T", i), expected.FormatField("%T", i))
+	}
+}
+
+func TestNewNamedDecoder(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewNamedDecoder("test.com", "2019-01-01",
+		[]bitextract.Field{
+			{Name: "version", Width: 8}, {Width: -48}, {Name: "productID", Width: 40},
+		})).(Decoder)
+	w.ShouldBeEqual(decoder.FieldNames(), []string{"version", "productID"})
+
+	bitTag := w.ShouldHaveResult(decoder.DecodeString("0F00000000000C00000014D2")).(BitTag)
+	w.ShouldBeEqual(bitTag.FieldNames(), []string{"version", "productID"})
+	w.ShouldBeEqual(w.ShouldHaveResult(bitTag.Field("productID")), uint64(0x14D2))
+	w.ShouldBeEqual(w.ShouldHaveResult(bitTag.HexFieldByName("productID", 6)), "0014D2")
+	w.ShouldBeEqual(w.ShouldHaveResult(bitTag.FormatFieldByName("%03d", "version")), "015")
+	w.ShouldHaveError(bitTag.Field("serial"))
+	w.ShouldHaveError(bitTag.HexFieldByName("serial", 6))
+	w.ShouldHaveError(bitTag.FormatFieldByName("%d", "serial"))
+
+	URI := bitTag.URI()
+	w.ShouldBeEqual(URI, "tag:test.com,2019-01-01:15.5330")
+	w.ShouldBeEqual(w.ShouldHaveResult(decoder.FieldByName(URI, "productID")), "5330")
+	w.ShouldHaveError(decoder.FieldByName(URI, "serial"))
+	w.ShouldHaveError(decoder.FieldByName("tag:test.com,2019-01-01:15", "version"))
+
+	parsed := w.ShouldHaveResult(decoder.ParseURI(URI)).(BitTag)
+	w.ShouldBeEqual(w.ShouldHaveResult(parsed.Field("version")), uint64(15))
+
+	// unnamed Decoders' BitTags have no names
+	unnamed := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, -48, 40})).(Decoder)
+	bitTag = w.ShouldHaveResult(unnamed.DecodeString("0F00000000000C00000014D2")).(BitTag)
+	w.ShouldBeEqual(bitTag.FieldNames(), []string(nil))
+	w.ShouldHaveError(bitTag.Field("productID"))
+
+	w.ShouldFail(NewNamedDecoder("test.com", "2019-01-01",
+		[]bitextract.Field{{Name: "version", Width: 8}, {Name: "version", Width: 8}}))
+	w.ShouldFail(NewNamedDecoder("test.com", "2019-01-01",
+		[]bitextract.Field{{Name: "version", Width: 8}, {Width: 8}}))
+}
+
+func TestBitTag_JSON(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, 72, 16})).(Decoder)
+	bitTag := w.ShouldHaveResult(decoder.DecodeString("0FFF000000000000000114D2")).(BitTag)
+	data := w.ShouldHaveResult(json.Marshal(bitTag)).([]byte)
+	w.ShouldBeEqual(string(data), `{"uri":"tag:test.com,2019-01-01:`+
+		`15.4703919738795935662081.5330","fields":[15,"4703919738795935662081",5330]}`)
+
+	decoded := w.ShouldHaveResult(decoder.UnmarshalBitTag(data)).(BitTag)
+	shouldBeSameTag(w, decoded, bitTag)
+
+	// the fields are optional
+	decoded = w.ShouldHaveResult(decoder.UnmarshalBitTag(
+		[]byte(`{"uri":"tag:test.com,2019-01-01:15.4703919738795935662081.5330"}`))).(BitTag)
+	shouldBeSameTag(w, decoded, bitTag)
+
+	for _, invalid := range []string{
+		`{"uri":"tag:test.com,2019-01-01:15.1.5330","fields":[15,1]}`,
+		`{"uri":"tag:test.com,2019-01-01:15.1.5330","fields":[15,2,5330]}`,
+		`{"uri":"tag:test.com,2019-01-01:15.1.5330","fields":[15,"1",true]}`,
+		`{"uri":"tag:test.com,2019-01-01:15.1","fields":[15,1]}`,
+		`{"uri":"tag:test.com,2019-01-01:15.1.5330","fields":[15,1,5330.0]}`,
+		`{"fields":[15,1,5330]}`,
+		`[15,1,5330]`,
+	} {
+		w.As(invalid).ShouldFail(decoder.UnmarshalBitTag([]byte(invalid)))
+	}
+}
+
+func TestBitTag_typedFields(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, 72, 16})).(Decoder)
+	bitTag := w.ShouldHaveResult(decoder.DecodeString("0FFF000000000000000114D2")).(BitTag)
+
+	w.ShouldBeEqual(w.ShouldHaveResult(bitTag.Uint64Field(0)), uint64(15))
+	w.ShouldBeEqual(w.ShouldHaveResult(bitTag.Uint64Field(2)), uint64(5330))
+	w.ShouldHaveError(bitTag.Uint64Field(1))
+	w.ShouldHaveError(bitTag.Uint64Field(3))
+	w.ShouldHaveError(bitTag.Uint64Field(-1))
+
+	expected, _ := new(big.Int).SetString("4703919738795935662081", 10)
+	w.ShouldBeEqual(w.ShouldHaveResult(bitTag.BigIntField(1)), expected)
+	w.ShouldBeEqual(w.ShouldHaveResult(bitTag.BigIntField(0)), big.NewInt(15))
+	w.ShouldHaveError(bitTag.BigIntField(3))
+
+	// the results are copies
+	w.ShouldHaveResult(bitTag.BigIntField(1)).(*big.Int).SetInt64(0)
+	fields := bitTag.Fields()
+	w.ShouldBeEqual(fields[0], uint64(15))
+	w.ShouldBeEqual(fields[1], expected)
+	fields[1].(*big.Int).SetInt64(0)
+	fields[0] = uint64(0)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:15.4703919738795935662081.5330")
+
+	// big fields that fit in a uint64, and text fields
+	small := w.ShouldHaveResult(decoder.DecodeString("0F00000000000000000114D2")).(BitTag)
+	w.ShouldBeEqual(w.ShouldHaveResult(small.Uint64Field(1)), uint64(1))
+	w.ShouldSucceed(decoder.SetFieldFormat(2, ASCII8))
+	text := w.ShouldHaveResult(decoder.DecodeString("0F0000000000000000014869")).(BitTag)
+	w.ShouldBeEqual(text.Fields()[2], "Hi")
+	w.ShouldHaveError(text.Uint64Field(2))
+	w.ShouldHaveError(text.BigIntField(2))
+}
+
+func TestBitTag_AppendURI(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, 10, 30, 24, 24})).(Decoder)
+	w.ShouldSucceed(decoder.SetFieldFormat(2, Hex))
+	w.ShouldSucceed(decoder.SetFieldPadding(3, 10))
+	w.ShouldSucceed(decoder.SetDelimiter(":"))
+	bitTag := w.ShouldHaveResult(decoder.DecodeString("0F00000000000C00000014D2")).(BitTag)
+
+	uri := "tag:test.com,2019-01-01:15:0:00000000:0000786432:5330"
+	w.ShouldBeEqual(bitTag.URI(), uri)
+	w.ShouldBeEqual(string(bitTag.AppendURI([]byte("uri: "))), "uri: "+uri)
+
+	buf := make([]byte, 0, 64)
+	w.ShouldBeEqual(testing.AllocsPerRun(100, func() {
+		buf = bitTag.AppendURI(buf[:0])
+	}), float64(0))
+}
+
+func BenchmarkBitTag_AppendURI(b *testing.B) {
+	decoder, _ := NewDecoder("test.com", "2019-01-01", []int{8, 48, 24, 16})
+	bitTag, _ := decoder.DecodeString("0F00000000000C00000014D2")
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = bitTag.AppendURI(buf[:0])
+	}
+}
+
+func BenchmarkBitTag_URI(b *testing.B) {
+	decoder, _ := NewDecoder("test.com", "2019-01-01", []int{8, 48, 24, 16})
+	bitTag, _ := decoder.DecodeString("0F00000000000C00000014D2")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = bitTag.URI()
+	}
+}
+
+func TestBitTag_Equal(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, 72, 16})).(Decoder)
+	formatted := decoder
+	w.ShouldSucceed(formatted.SetFieldFormat(1, Hex))
+	w.ShouldSucceed(formatted.SetFieldPadding(2, 8))
+	w.ShouldSucceed(formatted.SetDelimiter(":"))
+
+	data := "0F00000000000000000114D2"
+	bitTag := w.ShouldHaveResult(decoder.DecodeString(data)).(BitTag)
+	other := w.ShouldHaveResult(formatted.DecodeString(data)).(BitTag)
+	w.ShouldBeTrue(bitTag.Equal(other))
+	w.ShouldBeTrue(other.Equal(bitTag))
+	w.ShouldBeFalse(bitTag.URI() == other.URI())
+
+	// decoding 9 bytes of a rest field holds it as a *big.Int, but parsing
+	// holds it as a uint64, if it fits
+	rest := w.ShouldHaveResult(NewDecoder("test.com", "2019-01-01",
+		[]int{8, bitextract.RestOfData})).(Decoder)
+	decoded := w.ShouldHaveResult(rest.DecodeString("0F000000000000000001")).(BitTag)
+	parsed := w.ShouldHaveResult(rest.ParseURI(decoded.URI())).(BitTag)
+	w.ShouldBeEqual(parsed.fields[1], uint64(1))
+	w.ShouldBeTrue(decoded.Equal(parsed))
+	w.ShouldBeTrue(parsed.Equal(decoded))
+	w.ShouldBeFalse(bitTag.Equal(parsed))
+
+	different := w.ShouldHaveResult(decoder.DecodeString("0F00000000000000000214D2")).(BitTag)
+	w.ShouldBeFalse(bitTag.Equal(different))
+	wide := w.ShouldHaveResult(decoder.DecodeString("0FFF000000000000000114D2")).(BitTag)
+	w.ShouldBeFalse(bitTag.Equal(wide))
+	w.ShouldBeFalse(wide.Equal(bitTag))
+	w.ShouldBeTrue(wide.Equal(wide))
+
+	otherEntity := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01", []int{8, 72, 16})).(Decoder)
+	w.ShouldBeFalse(bitTag.Equal(w.ShouldHaveResult(otherEntity.DecodeString(data)).(BitTag)))
+	fewer := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, 72})).(Decoder)
+	w.ShouldBeFalse(bitTag.Equal(w.ShouldHaveResult(fewer.DecodeString(data[:20])).(BitTag)))
+
+	// text fields only equal the same text
+	text := decoder
+	w.ShouldSucceed(text.SetFieldFormat(2, ASCII8))
+	textTag := w.ShouldHaveResult(text.DecodeString("0F0000000000000000014869")).(BitTag)
+	w.ShouldBeTrue(textTag.Equal(w.ShouldHaveResult(text.ParseURI(textTag.URI())).(BitTag)))
+	w.ShouldBeFalse(textTag.Equal(w.ShouldHaveResult(decoder.DecodeString("0F0000000000000000014869")).(BitTag)))
+	w.ShouldBeTrue(BitTag{}.Equal(BitTag{}))
+}
@@ -0,0 +1,65 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// Chain is a series of Decoders for different formats, such as the proprietary
+// formats of different tag generations, which decodes data with the first that
+// accepts it. To tell formats apart, give their Decoders different lengths or
+// Validators, such as a Constant for a leading version field.
+type Chain []Decoder
+
+// CanDecode returns true if data has exactly as many bytes as needed to hold the
+// Decoder's BitLength, or at least that many if it has a RestOfData field. It
+// doesn't check the data's checksums or Validators, so Decode may still reject
+// it.
+func (btd Decoder) CanDecode(data []byte) bool {
+	byteLen := (btd.BitLength() + 7) / 8
+	return len(data) == byteLen || (len(data) > byteLen && btd.HasRestOfData())
+}
+
+// Decode decodes data with the first of the Chain's Decoders that accepts it,
+// and returns the resulting BitTag along with the index of that Decoder.
+//
+// A Decoder accepts data if its CanDecode method returns true for it, and it
+// decodes the data without error, so its checksums and Validators must match.
+// If none accept it, Decode returns an error that includes each Decoder's
+// reason for rejecting it.
+func (c Chain) Decode(data []byte) (BitTag, int, error) {
+	var reasons []string
+	for i, btd := range c {
+		if !btd.CanDecode(data) {
+			reasons = append(reasons, fmt.Sprintf("decoder %d: needs %d "+
+				"bytes, but data has %d", i, (btd.BitLength()+7)/8, len(data)))
+			continue
+		}
+
+		bt, err := btd.Decode(data)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("decoder %d: %v", i, err))
+			continue
+		}
+		return bt, i, nil
+	}
+	return BitTag{}, -1, errors.Errorf("no decoder in the chain accepts the "+
+		"data: %s", strings.Join(reasons, "; "))
+}
+
+// DecodeString is a convenience method that decodes hex-encoded byte data.
+func (c Chain) DecodeString(data string) (BitTag, int, error) {
+	byteData, err := hex.DecodeString(data)
+	if err != nil {
+		return BitTag{}, -1, errors.Wrapf(err, "unable to decode tag data as hex")
+	}
+	return c.Decode(byteData)
+}
@@ -0,0 +1,80 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"testing"
+)
+
+func TestChain_Decode(t *testing.T) {
+	w := expect.WrapT(t)
+
+	newDecoder := func(version uint64, widths ...int) Decoder {
+		d := w.ShouldHaveResult(NewDecoder("test.com", "2019-01-01", widths)).(Decoder)
+		w.ShouldSucceed(d.AddValidator(0, Constant(version)))
+		return d
+	}
+	chain := Chain{
+		newDecoder(1, 8, 56, 32),
+		newDecoder(2, 8, 56, 32),
+		newDecoder(1, 8, 24),
+		newDecoder(3, 8, bitextract.RestOfData),
+	}
+
+	testCases := []struct {
+		data    string
+		decoder int
+		uri     string
+	}{
+		{"0100000000000000000014D2", 0, "tag:test.com,2019-01-01:1.0.5330"},
+		{"0200000000000000000014D2", 1, "tag:test.com,2019-01-01:2.0.5330"},
+		{"0100000C", 2, "tag:test.com,2019-01-01:1.12"},
+		{"0300000C", 3, "tag:test.com,2019-01-01:3.12"},
+		{"0300000000000000000014D2", 3, "tag:test.com,2019-01-01:3.5330"},
+	}
+
+	for _, tc := range testCases {
+		bt, idx, err := chain.DecodeString(tc.data)
+		w.As(tc.data).ShouldSucceed(err)
+		w.As(tc.data).ShouldBeEqual(idx, tc.decoder)
+		w.As(tc.data).ShouldBeEqual(bt.URI(), tc.uri)
+	}
+
+	for _, invalid := range []string{
+		"0200000C",
+		"0100000000000000000014D200",
+		"01",
+		"0Z",
+	} {
+		_, idx, err := chain.DecodeString(invalid)
+		w.As(invalid).ShouldFail(err)
+		w.As(invalid).ShouldBeEqual(idx, -1)
+	}
+
+	_, _, err := chain.DecodeString("0200000C")
+	w.ShouldContainStr(err.Error(), "decoder 2")
+
+	_, _, err = Chain{}.DecodeString("01")
+	w.ShouldFail(err)
+}
+
+func TestDecoder_CanDecode(t *testing.T) {
+	w := expect.WrapT(t)
+
+	fixed := w.ShouldHaveResult(NewDecoder("test.com", "2019-01-01", []int{8, 12})).(Decoder)
+	w.ShouldBeFalse(fixed.CanDecode([]byte{1, 2}))
+	w.ShouldBeTrue(fixed.CanDecode([]byte{1, 2, 3}))
+	w.ShouldBeFalse(fixed.CanDecode([]byte{1, 2, 3, 4}))
+
+	rest := w.ShouldHaveResult(NewDecoder("test.com", "2019-01-01",
+		[]int{8, bitextract.RestOfData})).(Decoder)
+	w.ShouldBeFalse(rest.CanDecode(nil))
+	w.ShouldBeTrue(rest.CanDecode([]byte{1}))
+	w.ShouldBeTrue(rest.CanDecode([]byte{1, 2, 3, 4}))
+}
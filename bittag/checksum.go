@@ -0,0 +1,217 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+// Checksum is a way of computing a check value over the bits preceding a
+// checksum field, which Decode verifies against the field's value.
+type Checksum uint8
+
+const (
+	// Mod10 is a GS1 style check digit: the preceding bits are treated as an
+	// unsigned integer, and its decimal digits are weighted by 3 and 1,
+	// alternating from the rightmost digit, summed, and subtracted from the
+	// next multiple of 10. The field must be 4 to 64 bits wide.
+	Mod10 = Checksum(iota)
+	// XOR is the exclusive-or of the preceding bits split into chunks as wide
+	// as the field, starting from the first bit; if the last chunk is shorter,
+	// it's padded with 0s on the right. The field may be up to 64 bits wide.
+	XOR
+	// CRC8 is the CRC-8 of the preceding bits, most significant first, using
+	// the polynomial 0x07 and an initial value of 0, without reflection or a
+	// final XOR (CRC-8/SMBUS). The field must be 8 bits wide.
+	CRC8
+	// CRC16 is the CRC-16 of the preceding bits, most significant first, using
+	// the polynomial 0x1021 and an initial value of 0xFFFF, without reflection
+	// or a final XOR (CRC-16/CCITT-FALSE). The field must be 16 bits wide.
+	CRC16
+)
+
+var checksumNames = [...]string{
+	Mod10: "mod10",
+	XOR:   "xor",
+	CRC8:  "crc8",
+	CRC16: "crc16",
+}
+
+func (c Checksum) String() string {
+	if int(c) < len(checksumNames) {
+		return checksumNames[c]
+	}
+	return "Unknown checksum"
+}
+
+// parseChecksum returns the Checksum with the given name.
+func parseChecksum(name string) (Checksum, error) {
+	for c, n := range checksumNames {
+		if n == name {
+			return Checksum(c), nil
+		}
+	}
+	return 0, errors.Errorf("unknown checksum %q", name)
+}
+
+// ChecksumError is returned by Decode when a checksum field's value doesn't
+// match the checksum of the bits preceding it.
+type ChecksumError struct {
+	Field    int      // the index of the checksum field
+	Checksum Checksum // the kind of checksum
+	Expected uint64   // the checksum computed from the preceding bits
+	Actual   uint64   // the checksum field's value
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("field %d's %v checksum is %#x, but should be %#x",
+		e.Field, e.Checksum, e.Actual, e.Expected)
+}
+
+// checksumField is a field whose value is a Checksum of the preceding bits.
+type checksumField struct {
+	field    int
+	checksum Checksum
+}
+
+// checkWidth returns an error if the Checksum can't be held by a field of the
+// given width.
+func (c Checksum) checkWidth(width int) error {
+	var ok bool
+	switch c {
+	case Mod10:
+		ok = width >= 4 && width <= 64
+	case XOR:
+		ok = width > 0 && width <= 64
+	case CRC8:
+		ok = width == 8
+	case CRC16:
+		ok = width == 16
+	default:
+		return errors.Errorf("invalid checksum %d", c)
+	}
+	if !ok {
+		return errors.Errorf("a %v checksum can't be held by a %d bit field",
+			c, width)
+	}
+	return nil
+}
+
+// SetChecksum declares that the field at the given index holds a Checksum of all
+// the bits preceding it, including any skipped bits. Decode verifies it, and
+// returns a *ChecksumError if it doesn't match. It returns an error if the index
+// is out of range, if the field is a RestOfData field or first, or if the
+// Checksum can't be held by a field of its width.
+//
+// Like formats, checksums should be set after the Decoder's widths.
+func (btd *Decoder) SetChecksum(idx int, c Checksum) error {
+	ranges := btd.FieldRanges()
+	if idx < 0 || idx >= len(ranges) {
+		return errors.Errorf("field index %d is out of range [0, %d)",
+			idx, len(ranges))
+	}
+	if ranges[idx].Start == 0 {
+		return errors.Errorf("field %d has no preceding bits to check", idx)
+	}
+	if err := c.checkWidth(ranges[idx].Length); err != nil {
+		return errors.Wrapf(err, "invalid checksum field %d", idx)
+	}
+
+	// copy them, since copies of the Decoder share them
+	checksums := make([]checksumField, len(btd.checksums), len(btd.checksums)+1)
+	copy(checksums, btd.checksums)
+	btd.checksums = append(checksums, checksumField{field: idx, checksum: c})
+	return nil
+}
+
+// verifyChecksums returns an error if any of the Decoder's checksum fields don't
+// match data, which must have at least BitLength bits.
+func (btd Decoder) verifyChecksums(data []byte) error {
+	if btd.checksums == nil {
+		return nil
+	}
+
+	ranges := btd.FieldRanges()
+	for _, cf := range btd.checksums {
+		if cf.field >= len(ranges) || cf.checksum.checkWidth(ranges[cf.field].Length) != nil {
+			return errors.New("the Decoder's widths changed after its " +
+				"checksums were set")
+		}
+
+		r := ranges[cf.field]
+		actual := bitextract.New(r.Start, r.Length).ExtractUInt64(data)
+		expected := cf.checksum.compute(data, r.Start, r.Length)
+		if actual != expected {
+			return &ChecksumError{
+				Field:    cf.field,
+				Checksum: cf.checksum,
+				Expected: expected,
+				Actual:   actual,
+			}
+		}
+	}
+	return nil
+}
+
+// compute returns the Checksum of data's first nBits bits, for a field of the
+// given width.
+func (c Checksum) compute(data []byte, nBits, width int) uint64 {
+	switch c {
+	case Mod10:
+		return mod10(bitextract.New(0, nBits).ExtractBigInt(data))
+	case XOR:
+		var x uint64
+		for start := 0; start < nBits; start += width {
+			n := width
+			if start+n > nBits {
+				n = nBits - start
+			}
+			x ^= bitextract.New(start, n).ExtractUInt64(data) << uint(width-n)
+		}
+		return x
+	case CRC8:
+		return uint64(crc(data, nBits, 0x07, 0, 8))
+	case CRC16:
+		return uint64(crc(data, nBits, 0x1021, 0xFFFF, 16))
+	}
+	return 0
+}
+
+// mod10 returns the GS1 check digit of v's decimal digits.
+func mod10(v *big.Int) uint64 {
+	digits := v.String()
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i -= 2 {
+		sum += 3 * int(digits[i]-'0')
+		if i > 0 {
+			sum += int(digits[i-1] - '0')
+		}
+	}
+	return uint64((10 - sum%10) % 10)
+}
+
+// crc returns the non-reflected CRC of data's first nBits bits, most significant
+// first, using the polynomial poly and initial value init, for a CRC of the
+// given width.
+func crc(data []byte, nBits int, poly, init uint16, width uint) uint16 {
+	top := uint16(1) << (width - 1)
+	mask := uint16(1<<width - 1)
+	reg := init
+	for i := 0; i < nBits; i++ {
+		bit := uint16(data[i/8]>>uint(7-i%8)) & 1
+		if (reg&top != 0) != (bit != 0) {
+			reg = (reg << 1) ^ poly
+		} else {
+			reg <<= 1
+		}
+		reg &= mask
+	}
+	return reg
+}
@@ -0,0 +1,109 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"encoding/json"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"math/big"
+	"testing"
+)
+
+func TestChecksum_checkValues(t *testing.T) {
+	w := expect.WrapT(t)
+
+	// the standard check values, computed over "123456789"
+	data := []byte("123456789")
+	w.ShouldBeEqual(crc(data, 72, 0x07, 0, 8), uint16(0xF4))
+	w.ShouldBeEqual(crc(data, 72, 0x1021, 0xFFFF, 16), uint16(0x29B1))
+
+	// GTIN-13 4006381333931
+	w.ShouldBeEqual(mod10(big.NewInt(400638133393)), uint64(1))
+	w.ShouldBeEqual(mod10(big.NewInt(0)), uint64(0))
+}
+
+func TestDecoder_SetChecksum(t *testing.T) {
+	w := expect.WrapT(t)
+
+	testCases := []struct {
+		checksum   Checksum
+		widths     []int
+		valid, bad string
+		expected   uint64
+	}{
+		{CRC16, []int{8, 64, 16}, "0F00000000000C0000F547", "0F00000000000C0000F548", 0xF547},
+		{CRC8, []int{72, 8}, "0F00000000000C000053", "0F00000000000C000153", 0x54},
+		{XOR, []int{72, 8}, "0F00000000000C000003", "0F00000000000C000000", 0x03},
+		{XOR, []int{12, 8}, "0F00F0", "0F0100", 0x0F},
+		{Mod10, []int{72, 4}, "0F00000000000C000000", "0F00000000000C000010", 0x00},
+	}
+
+	for i, tc := range testCases {
+		w := w.As(i)
+		decoder := w.ShouldHaveResult(NewDecoder(
+			"test.com", "2019-01-01", tc.widths)).(Decoder)
+		unchecked := decoder
+		last := len(tc.widths) - 1
+		w.ShouldSucceed(decoder.SetChecksum(last, tc.checksum))
+
+		w.ShouldHaveResult(decoder.DecodeString(tc.valid))
+		w.ShouldHaveResult(unchecked.DecodeString(tc.bad))
+		_, err := decoder.DecodeString(tc.bad)
+		csErr, ok := err.(*ChecksumError)
+		w.StopOnMismatch().ShouldBeTrue(ok)
+		w.ShouldBeEqual(csErr.Field, last)
+		w.ShouldBeEqual(csErr.Checksum, tc.checksum)
+		w.ShouldBeEqual(csErr.Expected, tc.expected)
+		w.ShouldContainStr(csErr.Error(), tc.checksum.String())
+	}
+
+	decoder := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, 8, 16, 72, 2})).(Decoder)
+	w.ShouldFail(decoder.SetChecksum(0, XOR))
+	w.ShouldFail(decoder.SetChecksum(5, XOR))
+	w.ShouldFail(decoder.SetChecksum(1, CRC16))
+	w.ShouldFail(decoder.SetChecksum(2, CRC8))
+	w.ShouldFail(decoder.SetChecksum(3, XOR))
+	w.ShouldFail(decoder.SetChecksum(4, Mod10))
+	w.ShouldFail(decoder.SetChecksum(1, Checksum(4)))
+
+	rest := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, -1 << 31})).(Decoder)
+	w.ShouldFail(rest.SetChecksum(1, XOR))
+}
+
+func TestDecoder_checksumConfig(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoderFromConfig([]byte(`{
+		"authority": "test.com",
+		"date": "2019-01-01",
+		"fields": [
+			{"name": "version", "width": 8},
+			{"name": "productID", "width": 64},
+			{"name": "crc", "width": 16, "checksum": "crc16", "format": "hex"}
+		]
+	}`))).(Decoder)
+	bitTag := w.ShouldHaveResult(decoder.DecodeString("0F00000000000C0000F547")).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:15.786432.F547")
+	w.ShouldFail(decoder.DecodeString("0F00000000000C0000F548"))
+
+	w.ShouldFail(NewDecoderFromConfig([]byte(`{"authority": "test.com",
+		"date": "2019-01-01", "fields": [{"width": 8}, {"width": 8, "checksum": "md5"}]}`)))
+	w.ShouldFail(NewDecoderFromConfig([]byte(`{"authority": "test.com",
+		"date": "2019-01-01", "fields": [{"width": 8}, {"skip": 8, "checksum": "xor"}]}`)))
+
+	data := w.ShouldHaveResult(json.Marshal(decoder)).([]byte)
+	w.ShouldContainStr(string(data), `"checksums":[{"field":2,"checksum":"crc16"}]`)
+	var decoded Decoder
+	w.ShouldSucceed(json.Unmarshal(data, &decoded))
+	w.ShouldFail(decoded.DecodeString("0F00000000000C0000F548"))
+
+	w.ShouldFail(json.Unmarshal([]byte(`{"authority":"test.com","date":"2019-01-01",`+
+		`"layout":{"fields":[{"width":8},{"width":8}]},`+
+		`"checksums":[{"field":1,"checksum":"crc16"}]}`), &decoded))
+}
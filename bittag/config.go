@@ -0,0 +1,192 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"github.com/pkg/errors"
+)
+
+// DecoderConfig describes a Decoder in a form suitable for configuration files.
+// Its fields have both json and yaml tags, so it may be embedded in a service's
+// own configuration and loaded along with it. For example, in JSON:
+//     {
+//       "authority": "example.com",
+//       "date": "2019-01-01",
+//       "fields": [
+//         {"name": "version", "width": 8},
+//         {"skip": 48},
+//         {"name": "productID", "width": 40, "format": "hex"}
+//       ]
+//     }
+//
+// Delimiter separates the fields of URIs, as set by SetDelimiter; if it's
+// empty, it's ".".
+type DecoderConfig struct {
+	Authority string        `json:"authority" yaml:"authority"`
+	Date      string        `json:"date" yaml:"date"`
+	Fields    []FieldConfig `json:"fields" yaml:"fields"`
+	Delimiter string        `json:"delimiter,omitempty" yaml:"delimiter,omitempty"`
+}
+
+// FieldConfig describes one of a DecoderConfig's fields. Exactly one of Width,
+// Skip, or Rest must be set: Width declares a field of that many bits, Skip
+// ignores that many reserved bits, and Rest declares a final field of the rest
+// of the data, as bitextract.RestOfData does.
+//
+// Either all of the fields must have names, or none of them; skipped bits never
+// do. Format is the name of the field's FieldFormat, as returned by its String
+// method, such as "hex"; if it's empty, the field is Decimal. Checksum is
+// similarly the name of a Checksum, such as "crc16", if the field holds one.
+// Padding is the number of digits to which the field is padded, as set by
+// SetFieldPadding, if it's not 0. Validators restrict the field's values, as if
+// added with AddValidator.
+type FieldConfig struct {
+	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
+	Width    int    `json:"width,omitempty" yaml:"width,omitempty"`
+	Skip     int    `json:"skip,omitempty" yaml:"skip,omitempty"`
+	Rest     bool   `json:"rest,omitempty" yaml:"rest,omitempty"`
+	Format   string `json:"format,omitempty" yaml:"format,omitempty"`
+	Checksum string `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+	Padding  int    `json:"padding,omitempty" yaml:"padding,omitempty"`
+
+	Validators []Validator `json:"validators,omitempty" yaml:"validators,omitempty"`
+}
+
+// NewDecoderFromConfig returns a new Decoder from a JSON encoded DecoderConfig,
+// or an error if the JSON has unknown keys or the config is invalid.
+func NewDecoderFromConfig(data []byte) (Decoder, error) {
+	var conf DecoderConfig
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&conf); err != nil {
+		return Decoder{}, errors.Wrap(err, "invalid Decoder config")
+	}
+	return conf.NewDecoder()
+}
+
+// NewDecoder returns a new Decoder as described by the config, or an error if
+// the config is invalid.
+func (conf DecoderConfig) NewDecoder() (Decoder, error) {
+	var fields []bitextract.Field
+	var formats []FieldFormat
+	var checksums []checksumField
+	var validators []fieldValidator
+	var padding []int
+	named := false
+	for i, fc := range conf.Fields {
+		set := 0
+		for _, isSet := range []bool{fc.Width != 0, fc.Skip != 0, fc.Rest} {
+			if isSet {
+				set++
+			}
+		}
+		if set != 1 || fc.Width < 0 || fc.Skip < 0 {
+			return Decoder{}, errors.Errorf("field %d must have exactly one "+
+				"of a positive width, a positive skip, or rest", i)
+		}
+
+		if fc.Skip > 0 {
+			if fc.Name != "" || fc.Format != "" || fc.Checksum != "" ||
+				fc.Padding != 0 || fc.Validators != nil {
+				return Decoder{}, errors.Errorf("skipped bits can't have a "+
+					"name, format, checksum, padding, or validators, but "+
+					"field %d does", i)
+			}
+			fields = append(fields, bitextract.Field{Width: -fc.Skip})
+			continue
+		}
+
+		width := fc.Width
+		if fc.Rest {
+			width = bitextract.RestOfData
+		}
+		if len(formats) == 0 {
+			named = fc.Name != ""
+		} else if named != (fc.Name != "") {
+			return Decoder{}, errors.Errorf("either all fields must have "+
+				"names, or none of them, but field %d differs", i)
+		}
+		fields = append(fields, bitextract.Field{Name: fc.Name, Width: width})
+
+		format := Decimal
+		if fc.Format != "" {
+			var err error
+			if format, err = parseFieldFormat(fc.Format); err != nil {
+				return Decoder{}, errors.Wrapf(err, "invalid field %d", i)
+			}
+		}
+		if fc.Checksum != "" {
+			c, err := parseChecksum(fc.Checksum)
+			if err != nil {
+				return Decoder{}, errors.Wrapf(err, "invalid field %d", i)
+			}
+			checksums = append(checksums, checksumField{
+				field:    len(formats),
+				checksum: c,
+			})
+		}
+		for _, v := range fc.Validators {
+			validators = append(validators, fieldValidator{
+				field:     len(formats),
+				validator: v,
+			})
+		}
+		formats = append(formats, format)
+		padding = append(padding, fc.Padding)
+	}
+
+	var btd Decoder
+	var err error
+	if named {
+		btd, err = NewNamedDecoder(conf.Authority, conf.Date, fields)
+	} else {
+		widths := make([]int, len(fields))
+		for i, f := range fields {
+			widths[i] = f.Width
+		}
+		btd, err = NewDecoder(conf.Authority, conf.Date, widths)
+	}
+	if err != nil {
+		return Decoder{}, err
+	}
+
+	for idx, format := range formats {
+		if format == Decimal {
+			continue
+		}
+		if err := btd.SetFieldFormat(idx, format); err != nil {
+			return Decoder{}, err
+		}
+	}
+	for idx, digits := range padding {
+		if digits == 0 {
+			continue
+		}
+		if err := btd.SetFieldPadding(idx, digits); err != nil {
+			return Decoder{}, err
+		}
+	}
+	if conf.Delimiter != "" {
+		if err := btd.SetDelimiter(conf.Delimiter); err != nil {
+			return Decoder{}, err
+		}
+	}
+	for _, cf := range checksums {
+		if err := btd.SetChecksum(cf.field, cf.checksum); err != nil {
+			return Decoder{}, err
+		}
+	}
+	for _, fv := range validators {
+		if err := btd.AddValidator(fv.field, fv.validator); err != nil {
+			return Decoder{}, err
+		}
+	}
+	return btd, nil
+}
@@ -0,0 +1,59 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestNewDecoderFromConfig(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoderFromConfig([]byte(`{
+		"authority": "test.com",
+		"date": "2019-01-01",
+		"fields": [
+			{"name": "version", "width": 8},
+			{"skip": 48},
+			{"name": "productID", "width": 24, "format": "hex"},
+			{"name": "serial", "rest": true}
+		]
+	}`))).(Decoder)
+	w.ShouldBeEqual(decoder.Prefix(), "tag:test.com,2019-01-01")
+	w.ShouldBeEqual(decoder.FieldNames(), []string{"version", "productID", "serial"})
+	w.ShouldBeEqual(decoder.FieldFormat(1), Hex)
+	w.ShouldBeTrue(decoder.HasRestOfData())
+
+	bitTag := w.ShouldHaveResult(decoder.DecodeString("0F00000000000C00000014D2")).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:15.000000.5330")
+	w.ShouldBeEqual(w.ShouldHaveResult(bitTag.Field("serial")), uint64(5330))
+
+	unnamed := w.ShouldHaveResult(NewDecoderFromConfig([]byte(`{
+		"authority": "test.com",
+		"date": "2019-01-01",
+		"fields": [{"width": 8}, {"skip": 48}, {"width": 40}]
+	}`))).(Decoder)
+	w.ShouldBeEqual(unnamed.FieldNames(), []string(nil))
+	bitTag = w.ShouldHaveResult(unnamed.DecodeString("0F00000000000C00000014D2")).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:15.5330")
+
+	for _, invalid := range []string{
+		`{"authority": "test.com", "date": "2019-01-01", "fields": []}`,
+		`{"authority": "test.com", "date": "2019-01-01", "fields": [{"width": 8}], "extra": 1}`,
+		`{"authority": "test.com", "date": "2019-13", "fields": [{"width": 8}]}`,
+		`{"authority": "test.com", "date": "2019-01-01", "fields": [{"width": -8}]}`,
+		`{"authority": "test.com", "date": "2019-01-01", "fields": [{"width": 8, "skip": 8}]}`,
+		`{"authority": "test.com", "date": "2019-01-01", "fields": [{"width": 8}, {"skip": 8, "name": "x"}]}`,
+		`{"authority": "test.com", "date": "2019-01-01", "fields": [{"width": 8}, {"name": "x", "width": 8}]}`,
+		`{"authority": "test.com", "date": "2019-01-01", "fields": [{"width": 8, "format": "octal"}]}`,
+		`{"authority": "test.com", "date": "2019-01-01", "fields": [{"rest": true}, {"width": 8}]}`,
+		`{"authority": "test.com", "date": "2019-01-01", "fields": [{"width": 8}]`,
+	} {
+		w.As(invalid).ShouldFail(NewDecoderFromConfig([]byte(invalid)))
+	}
+}
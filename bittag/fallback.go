@@ -0,0 +1,32 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// FallbackURI returns a tag URI identifying arbitrary tag data by its SHA-256
+// digest, for data that doesn't decode under any known scheme. Its format is:
+//     tag:taggingEntity:sha256:hex-digest
+// where the digest is of the raw data bytes, as 64 lower-case hex characters.
+// The URI is deterministic, so every service that uses the same tagging entity
+// produces the same URI for the same data.
+//
+// Per RFC 4151, the tagging entity is an authority name and a date on which it
+// was owned by the tagging authority, separated by a comma, such as
+// "example.com,2019-01-01"; it should meet the restrictions described by
+// Decoder.SetTaggingEntity, but isn't validated.
+//
+// Unlike BitTag URIs, the data can't be recovered from the URI, and since the
+// bytes are hashed, data of different bit lengths should be padded the same way
+// before being passed in.
+func FallbackURI(taggingEntity string, data []byte) string {
+	digest := sha256.Sum256(data)
+	return "tag:" + taggingEntity + ":sha256:" + hex.EncodeToString(digest[:])
+}
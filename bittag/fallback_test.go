@@ -0,0 +1,29 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestFallbackURI(t *testing.T) {
+	w := expect.WrapT(t)
+
+	w.ShouldBeEqual(FallbackURI("test.com,2019-01-01", nil),
+		"tag:test.com,2019-01-01:sha256:"+
+			"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	w.ShouldBeEqual(FallbackURI("test.com,2019-01-01", []byte("abc")),
+		"tag:test.com,2019-01-01:sha256:"+
+			"ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad")
+
+	data := []byte{0x0F, 0x00, 0x0C, 0x14, 0xD2}
+	uri := FallbackURI("test.com,2019-01-01", data)
+	w.ShouldBeEqual(FallbackURI("test.com,2019-01-01", data), uri)
+	w.ShouldBeFalse(FallbackURI("test.com,2019-01-01", data[:4]) == uri)
+	w.ShouldBeFalse(FallbackURI("example.com,2019-01-01", data) == uri)
+}
@@ -0,0 +1,355 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"github.com/pkg/errors"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FieldFormat determines how a field is written in a BitTag's URI and String.
+type FieldFormat uint8
+
+const (
+	// Decimal writes the field as a base-10 number. This is the default.
+	Decimal = FieldFormat(iota)
+	// PaddedDecimal writes the field as a base-10 number, left-padded with
+	// '0's to the number of digits in the field's largest value.
+	PaddedDecimal
+	// Hex writes the field as upper-case hex characters, left-padded with
+	// '0's to one character per 4 bits of the field.
+	Hex
+	// Base36 writes the field as a base-36 number, using the digits 0-9 and
+	// the lower-case letters a-z.
+	Base36
+	// ASCII7 decodes the field as packed 7-bit ASCII characters, without any
+	// trailing null characters, so the field's value is a string rather than
+	// a number. The field's width must be a multiple of 7. In URIs, characters
+	// other than letters, digits, '-', '_', and '~' are percent-encoded.
+	ASCII7
+	// ASCII8 is like ASCII7, but for 8-bit characters, so the field's width
+	// must be a multiple of 8.
+	ASCII8
+)
+
+var formatNames = [...]string{
+	Decimal:       "decimal",
+	PaddedDecimal: "paddedDecimal",
+	Hex:           "hex",
+	Base36:        "base36",
+	ASCII7:        "ascii7",
+	ASCII8:        "ascii8",
+}
+
+func (f FieldFormat) String() string {
+	if int(f) < len(formatNames) {
+		return formatNames[f]
+	}
+	return "Unknown field format"
+}
+
+// parseFieldFormat returns the FieldFormat with the given name.
+func parseFieldFormat(name string) (FieldFormat, error) {
+	for f, n := range formatNames {
+		if n == name {
+			return FieldFormat(f), nil
+		}
+	}
+	return 0, errors.Errorf("unknown field format %q", name)
+}
+
+// formatRegexes validate fields written in each FieldFormat.
+var formatRegexes = [...]*regexp.Regexp{
+	Decimal:       fieldsRegex,
+	PaddedDecimal: fieldsRegex,
+	Hex:           regexp.MustCompile(`^[0-9A-F]+$`),
+	Base36:        regexp.MustCompile(`^[0-9a-z]+$`),
+	ASCII7:        textRegex,
+	ASCII8:        textRegex,
+}
+
+// textRegex validates escaped text fields, which may be empty.
+var textRegex = regexp.MustCompile(`^([-0-9A-Za-z_~]|%[0-9A-F]{2})*$`)
+
+// formatBases are the bases in which each numeric FieldFormat writes numbers.
+var formatBases = [...]int{
+	Decimal:       10,
+	PaddedDecimal: 10,
+	Hex:           16,
+	Base36:        36,
+}
+
+// charBits returns the number of bits per character of text formats, or 0 for
+// numeric formats.
+func (f FieldFormat) charBits() int {
+	switch f {
+	case ASCII7:
+		return 7
+	case ASCII8:
+		return 8
+	}
+	return 0
+}
+
+// decodeText returns the text in a field of a text format, given the field as
+// extracted and its width in bits.
+func (f FieldFormat) decodeText(field []byte, width int) (string, error) {
+	if f == ASCII7 {
+		text, err := bitextract.SevenBitASCII(field, width)
+		return string(text), err
+	}
+	if width%8 != 0 {
+		return "", errors.Errorf("8-bit ASCII fields must have a multiple "+
+			"of 8 bits, but this has %d", width)
+	}
+	return strings.TrimRight(string(field), "\x00"), nil
+}
+
+// parseText unescapes a field of a text format written in a URI, and returns an
+// error if its characters don't fit in the field's width or format.
+func (f FieldFormat) parseText(field string, width int) (string, error) {
+	b := &strings.Builder{}
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+		if c == '%' {
+			v, err := strconv.ParseUint(field[i+1:i+3], 16, 8)
+			if err != nil {
+				return "", errors.Wrap(err, "invalid escape")
+			}
+			c = byte(v)
+			i += 2
+		}
+		if f == ASCII7 && c >= 0x80 {
+			return "", errors.Errorf("%#x isn't a 7-bit character", c)
+		}
+		b.WriteByte(c)
+	}
+
+	text := b.String()
+	if width != bitextract.RestOfData && len(text) > width/f.charBits() {
+		return "", errors.Errorf("%d characters don't fit in %d bits",
+			len(text), width)
+	}
+	return text, nil
+}
+
+// upperHex are the digits with which text is percent-encoded and Hex fields are
+// written.
+const upperHex = "0123456789ABCDEF"
+
+// appendEscaped appends text to dst, percent-encoding the characters other than
+// letters, digits, '-', '_', and '~', as well as any in the delimiter, so that it
+// can be written in a URI's fields.
+func appendEscaped(dst []byte, text, delim string) []byte {
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case strings.IndexByte(delim, c) >= 0:
+			dst = append(dst, '%', upperHex[c>>4], upperHex[c&0xF])
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '~':
+			dst = append(dst, c)
+		default:
+			dst = append(dst, '%', upperHex[c>>4], upperHex[c&0xF])
+		}
+	}
+	return dst
+}
+
+// fieldFormat is a FieldFormat along with the number of digits to which it pads
+// a particular field, and any padding set by SetFieldPadding, which overrides
+// it.
+type fieldFormat struct {
+	format FieldFormat
+	digits int
+	pad    int
+}
+
+// newFieldFormat returns a fieldFormat for a field of the given width, which may
+// be bitextract.RestOfData, in which case it isn't padded.
+func newFieldFormat(format FieldFormat, width int) fieldFormat {
+	ff := fieldFormat{format: format}
+	if width == bitextract.RestOfData {
+		return ff
+	}
+	switch format {
+	case PaddedDecimal:
+		max := new(big.Int).Lsh(big.NewInt(1), uint(width))
+		ff.digits = len(max.Sub(max, big.NewInt(1)).String())
+	case Hex:
+		ff.digits = (width + 3) / 4
+	}
+	return ff
+}
+
+// append appends a field value, which is a uint64 or *big.Int, or a string for
+// text formats, which escape any characters in the delimiter, to dst.
+func (ff fieldFormat) append(dst []byte, value interface{}, delim string) []byte {
+	if ff.format.charBits() > 0 {
+		return appendEscaped(dst, value.(string), delim)
+	}
+
+	digits := ff.digits
+	if ff.pad > 0 {
+		digits = ff.pad
+	}
+	start := len(dst)
+	switch v := value.(type) {
+	case uint64:
+		dst = strconv.AppendUint(dst, v, formatBases[ff.format])
+	case *big.Int:
+		dst = v.Append(dst, formatBases[ff.format])
+	}
+	if ff.format == Hex {
+		for i := start; i < len(dst); i++ {
+			if 'a' <= dst[i] && dst[i] <= 'f' {
+				dst[i] -= 'a' - 'A'
+			}
+		}
+	}
+
+	// shift the digits right to make room for the padding
+	if n := len(dst) - start; n < digits {
+		for i := n; i < digits; i++ {
+			dst = append(dst, '0')
+		}
+		copy(dst[start+digits-n:], dst[start:start+n])
+		for i := start; i < start+digits-n; i++ {
+			dst[i] = '0'
+		}
+	}
+	return dst
+}
+
+// SetFieldFormat sets the format in which the field at the given index is
+// written in the URIs of BitTags the Decoder decodes, so that they can match
+// existing identifier conventions. Fields and ParseURI expect URIs to use the
+// same formats. It returns an error if the index is out of range, the format is
+// unknown, or the field's width isn't valid for the format.
+//
+// Padded formats pad to the width of the field, so they don't pad RestOfData
+// fields, whose width depends on the data. Formats should be set after the
+// Decoder's widths, since changing those doesn't update them.
+func (btd *Decoder) SetFieldFormat(idx int, format FieldFormat) error {
+	ranges := btd.FieldRanges()
+	if idx < 0 || idx >= len(ranges) {
+		return errors.Errorf("field index %d is out of range [0, %d)",
+			idx, len(ranges))
+	}
+	if int(format) >= len(formatNames) {
+		return errors.Errorf("invalid field format %d", format)
+	}
+	width := ranges[idx].Length
+	if n := format.charBits(); n > 0 && width != bitextract.RestOfData && width%n != 0 {
+		return errors.Errorf("%v fields must have a multiple of %d bits, "+
+			"but field %d has %d", format, n, idx, width)
+	}
+
+	// copy them, since copies of the Decoder and its BitTags share them
+	formats := make([]fieldFormat, len(ranges))
+	copy(formats, btd.formats)
+	for i := len(btd.formats); i < len(formats); i++ {
+		formats[i] = newFieldFormat(Decimal, ranges[i].Length)
+	}
+	if pad := formats[idx].pad; pad > 0 {
+		if format.charBits() > 0 {
+			return errors.Errorf("field %d is padded, so it can't have "+
+				"the %v format", idx, format)
+		}
+		formats[idx] = newFieldFormat(format, width)
+		formats[idx].pad = pad
+	} else {
+		formats[idx] = newFieldFormat(format, width)
+	}
+	btd.formats = formats
+	return nil
+}
+
+// FieldFormat returns the format in which the field at the given index is
+// written in URIs. It panics if the index is out of range.
+func (btd Decoder) FieldFormat(idx int) FieldFormat {
+	if idx < 0 || idx >= btd.NumFields() {
+		panic(fmt.Sprintf("field index %d is out of range [0, %d)",
+			idx, btd.NumFields()))
+	}
+	if idx < len(btd.formats) {
+		return btd.formats[idx].format
+	}
+	return Decimal
+}
+
+// SetFieldPadding sets the number of digits to which the field at the given
+// index is left-padded with '0's in URIs, overriding any padding of its format,
+// so that URIs can match legacy identifiers exactly. Values with more digits
+// aren't truncated. It returns an error if the index is out of range, the
+// number of digits isn't positive, or the field has a text format.
+//
+// The padding is kept if the field's format is changed, and like formats,
+// should be set after the Decoder's widths.
+func (btd *Decoder) SetFieldPadding(idx int, digits int) error {
+	if idx < 0 || idx >= btd.NumFields() {
+		return errors.Errorf("field index %d is out of range [0, %d)",
+			idx, btd.NumFields())
+	}
+	if digits <= 0 {
+		return errors.Errorf("padding must be positive, not %d", digits)
+	}
+	format := btd.FieldFormat(idx)
+	if format.charBits() > 0 {
+		return errors.Errorf("field %d has the %v format, so it can't be "+
+			"padded", idx, format)
+	}
+
+	// this copies the formats, so the padding can be set in place
+	if err := btd.SetFieldFormat(idx, format); err != nil {
+		return err
+	}
+	btd.formats[idx].pad = digits
+	return nil
+}
+
+// defaultDelimiter separates the fields of URIs by default.
+const defaultDelimiter = "."
+
+// delimiterChars are the characters delimiters may use. They're those allowed
+// in the specific part of tag URIs that aren't in the fields of any format.
+const delimiterChars = ".:;/!$&'()*+,=@"
+
+// SetDelimiter sets the string that separates the fields of URIs of BitTags the
+// Decoder decodes, which is "." by default, so that URIs can match legacy
+// identifiers. Fields and ParseURI expect URIs to use the same delimiter. It
+// returns an error if the delimiter is empty, or uses characters other than
+// those in ".:;/!$&'()*+,=@", which can't appear in fields.
+func (btd *Decoder) SetDelimiter(delim string) error {
+	if delim == "" {
+		return errors.New("the delimiter can't be empty")
+	}
+	for i := 0; i < len(delim); i++ {
+		if strings.IndexByte(delimiterChars, delim[i]) < 0 {
+			return errors.Errorf("the delimiter %q may only use the "+
+				"characters %q", delim, delimiterChars)
+		}
+	}
+	if delim == defaultDelimiter {
+		delim = ""
+	}
+	btd.delim = delim
+	return nil
+}
+
+// Delimiter returns the string that separates the fields of URIs.
+func (btd Decoder) Delimiter() string {
+	if btd.delim == "" {
+		return defaultDelimiter
+	}
+	return btd.delim
+}
@@ -0,0 +1,270 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"math/big"
+	"testing"
+)
+
+func TestDecoder_SetFieldFormat(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, 10, 30, 24, 24})).(Decoder)
+	plain := decoder
+	w.ShouldSucceed(decoder.SetFieldFormat(1, PaddedDecimal))
+	w.ShouldSucceed(decoder.SetFieldFormat(2, Hex))
+	w.ShouldSucceed(decoder.SetFieldFormat(3, Base36))
+	w.ShouldBeEqual(decoder.FieldFormat(0), Decimal)
+	w.ShouldBeEqual(decoder.FieldFormat(2), Hex)
+	w.ShouldBeEqual(plain.FieldFormat(2), Decimal)
+
+	w.ShouldFail(decoder.SetFieldFormat(5, Hex))
+	w.ShouldFail(decoder.SetFieldFormat(-1, Hex))
+	w.ShouldFail(decoder.SetFieldFormat(0, FieldFormat(4)))
+
+	data := "0F00000000000C00000014D2"
+	bitTag := w.ShouldHaveResult(decoder.DecodeString(data)).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:15.0000.00000000.gutc.5330")
+	w.ShouldBeEqual(w.ShouldHaveResult(plain.DecodeString(data)).(BitTag).URI(),
+		"tag:test.com,2019-01-01:15.0.0.786432.5330")
+
+	w.ShouldBeEqual(w.ShouldHaveResult(decoder.Field(bitTag.URI(), 3)), "gutc")
+	parsed := w.ShouldHaveResult(decoder.ParseURI(bitTag.URI())).(BitTag)
+	w.ShouldBeEqual(parsed.URI(), bitTag.URI())
+	w.ShouldBeEqual(parsed.FormatField("%d", 3), "786432")
+
+	for _, invalid := range []string{
+		"tag:test.com,2019-01-01:15.0000.0000000a.gutc.5330",
+		"tag:test.com,2019-01-01:15.0000.00000000.GUTC.5330",
+		"tag:test.com,2019-01-01:15.0000.00000000.zzzzz.5330",
+	} {
+		w.As(invalid).ShouldFail(decoder.ParseURI(invalid))
+	}
+
+	// changing the widths after setting formats is an error
+	changed := decoder
+	w.ShouldSucceed(changed.SetWidths([]int{8, 88}))
+	w.ShouldFail(changed.DecodeString(data))
+}
+
+func TestFieldFormat_wide(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{16, 80, bitextract.RestOfData})).(Decoder)
+	for idx, format := range []FieldFormat{PaddedDecimal, Hex, Base36} {
+		w.ShouldSucceed(decoder.SetFieldFormat(idx, format))
+	}
+
+	bitTag := w.ShouldHaveResult(decoder.DecodeString(
+		"0001FFFFFFFFFFFFFFFFFFFF0123")).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(),
+		"tag:test.com,2019-01-01:00001.FFFFFFFFFFFFFFFFFFFF.83")
+	parsed := w.ShouldHaveResult(decoder.ParseURI(bitTag.URI())).(BitTag)
+	shouldBeSameTag(w, parsed, bitTag)
+}
+
+func TestDecoder_JSON_formats(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, 40})).(Decoder)
+	w.ShouldSucceed(decoder.SetFieldFormat(1, Hex))
+	data := w.ShouldHaveResult(json.Marshal(decoder)).([]byte)
+	w.ShouldBeEqual(string(data), `{"authority":"test.com","date":"2019-01-01",`+
+		`"layout":{"fields":[{"width":8},{"width":40}]},"formats":["decimal","hex"]}`)
+
+	var decoded Decoder
+	w.ShouldSucceed(json.Unmarshal(data, &decoded))
+	w.ShouldBeEqual(decoded.FieldFormat(1), Hex)
+
+	for _, invalid := range []string{
+		`{"authority":"test.com","date":"2019-01-01",` +
+			`"layout":{"fields":[{"width":8},{"width":40}]},"formats":["hex"]}`,
+		`{"authority":"test.com","date":"2019-01-01",` +
+			`"layout":{"fields":[{"width":8},{"width":40}]},"formats":["hex","octal"]}`,
+	} {
+		w.As(invalid).ShouldFail(json.Unmarshal([]byte(invalid), &decoded))
+	}
+
+	w.ShouldBeEqual(Base36.String(), "base36")
+	w.ShouldBeEqual(FieldFormat(9).String(), "Unknown field format")
+}
+
+func TestFieldFormat_ASCII(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewNamedDecoder("test.com", "2019-01-01",
+		[]bitextract.Field{
+			{Name: "version", Width: 8},
+			{Name: "lot", Width: 42},
+			{Name: "code", Width: 24},
+		})).(Decoder)
+	w.ShouldSucceed(decoder.SetFieldFormat(1, ASCII7))
+	w.ShouldSucceed(decoder.SetFieldFormat(2, ASCII8))
+	w.ShouldFail(decoder.SetFieldFormat(0, ASCII7))
+	w.ShouldFail(decoder.SetFieldFormat(1, ASCII8))
+
+	bitTag := w.ShouldHaveResult(decoder.DecodeString("0F993EA2D620104B9080")).(BitTag)
+	w.ShouldBeEqual(w.ShouldHaveResult(bitTag.Field("lot")), "LOT-1")
+	w.ShouldBeEqual(w.ShouldHaveResult(bitTag.Field("code")), "A.B")
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:15.LOT-1.A%2EB")
+	w.ShouldBeEqual(w.ShouldHaveResult(decoder.FieldByName(bitTag.URI(), "code")), "A%2EB")
+
+	parsed := w.ShouldHaveResult(decoder.ParseURI(bitTag.URI())).(BitTag)
+	w.ShouldBeEqual(parsed, bitTag)
+
+	data := w.ShouldHaveResult(json.Marshal(bitTag)).([]byte)
+	w.ShouldBeEqual(string(data), `{"uri":"tag:test.com,2019-01-01:15.LOT-1.A%2EB",`+
+		`"fields":[15,"LOT-1","A.B"]}`)
+	w.ShouldBeEqual(w.ShouldHaveResult(decoder.UnmarshalBitTag(data)), bitTag)
+
+	for _, invalid := range []string{
+		"tag:test.com,2019-01-01:15.LOT-1.A.B",
+		"tag:test.com,2019-01-01:15.LOT 1.AB",
+		"tag:test.com,2019-01-01:15.LOTLOT1.AB",
+		"tag:test.com,2019-01-01:15.LOT%801.AB",
+		"tag:test.com,2019-01-01:15.LOT-1.ABCD",
+		"tag:test.com,2019-01-01:15.LOT-1.A%2eB",
+	} {
+		w.As(invalid).ShouldFail(decoder.ParseURI(invalid))
+	}
+
+	// empty text is valid
+	parsed = w.ShouldHaveResult(decoder.ParseURI("tag:test.com,2019-01-01:15..AB")).(BitTag)
+	w.ShouldBeEqual(w.ShouldHaveResult(parsed.Field("lot")), "")
+
+	// RestOfData fields use the rest of the data's width
+	rest := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, bitextract.RestOfData})).(Decoder)
+	w.ShouldSucceed(rest.SetFieldFormat(1, ASCII7))
+	bitTag = w.ShouldHaveResult(rest.DecodeString("0F91A50800000000")).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:15.Hi%21")
+	w.ShouldFail(rest.DecodeString("0F91A508"))
+}
+
+func TestDecoder_SetDelimiter(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, 48, 40})).(Decoder)
+	w.ShouldBeEqual(decoder.Delimiter(), ".")
+	w.ShouldSucceed(decoder.SetDelimiter("/"))
+	w.ShouldBeEqual(decoder.Delimiter(), "/")
+
+	data := "0F00000000000C00000014D2"
+	bitTag := w.ShouldHaveResult(decoder.DecodeString(data)).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:15/12/5330")
+	w.ShouldBeEqual(w.ShouldHaveResult(decoder.Field(bitTag.URI(), 2)), "5330")
+	shouldBeSameTag(w, w.ShouldHaveResult(decoder.ParseURI(bitTag.URI())).(BitTag), bitTag)
+	w.ShouldFail(decoder.ParseURI("tag:test.com,2019-01-01:15.12.5330"))
+
+	for _, invalid := range []string{"", "-", "a", "1", "%", "#", "./-"} {
+		w.As(invalid).ShouldFail(decoder.SetDelimiter(invalid))
+	}
+	w.ShouldBeEqual(decoder.Delimiter(), "/")
+
+	// delimiter characters are escaped in text fields
+	text := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, 24})).(Decoder)
+	w.ShouldSucceed(text.SetFieldFormat(1, ASCII8))
+	w.ShouldSucceed(text.SetDelimiter("::"))
+	bitTag = w.ShouldHaveResult(text.DecodeString("0F413A42")).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:15::A%3AB")
+	parsed := w.ShouldHaveResult(text.ParseURI(bitTag.URI())).(BitTag)
+	w.ShouldBeEqual(parsed.Fields()[1], "A:B")
+
+	w.ShouldSucceed(text.SetDelimiter("."))
+	w.ShouldBeEqual(w.ShouldHaveResult(text.DecodeString("0F413A42")).(BitTag).URI(),
+		"tag:test.com,2019-01-01:15.A%3AB")
+}
+
+func TestDecoder_SetFieldPadding(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, 48, 40, 16})).(Decoder)
+	w.ShouldSucceed(decoder.SetFieldPadding(0, 3))
+	w.ShouldSucceed(decoder.SetFieldFormat(1, Hex))
+	w.ShouldSucceed(decoder.SetFieldPadding(1, 4))
+	w.ShouldSucceed(decoder.SetFieldPadding(2, 2))
+	w.ShouldSucceed(decoder.SetFieldFormat(3, Base36))
+	w.ShouldSucceed(decoder.SetFieldPadding(3, 6))
+	w.ShouldFail(decoder.SetFieldPadding(4, 3))
+	w.ShouldFail(decoder.SetFieldPadding(0, 0))
+
+	data := "0F00000000000C00000014D20010"
+	bitTag := w.ShouldHaveResult(decoder.DecodeString(data)).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:015.000C.5330.00000g")
+	shouldBeSameTag(w, w.ShouldHaveResult(decoder.ParseURI(bitTag.URI())).(BitTag), bitTag)
+
+	// padding is kept when the format changes
+	w.ShouldSucceed(decoder.SetFieldFormat(0, Hex))
+	bitTag = w.ShouldHaveResult(decoder.DecodeString(data)).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:00F.000C.5330.00000g")
+	w.ShouldFail(decoder.SetFieldFormat(0, ASCII8))
+
+	text := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, 24})).(Decoder)
+	w.ShouldSucceed(text.SetFieldFormat(1, ASCII8))
+	w.ShouldFail(text.SetFieldPadding(1, 4))
+
+	// padding and delimiters are configurable, and survive JSON round trips
+	configured := w.ShouldHaveResult(NewDecoderFromConfig([]byte(`{
+		"authority": "test.com",
+		"date": "2019-01-01",
+		"delimiter": ":",
+		"fields": [{"width": 8, "padding": 3}, {"width": 48}]
+	}`))).(Decoder)
+	bitTag = w.ShouldHaveResult(configured.DecodeString("0F00000000000C")).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:015:12")
+
+	encoded := w.ShouldHaveResult(json.Marshal(configured)).([]byte)
+	w.ShouldContainStr(string(encoded), `"padding":[3,0],"delimiter":":"`)
+	var decoded Decoder
+	w.ShouldSucceed(json.Unmarshal(encoded, &decoded))
+	bitTag = w.ShouldHaveResult(decoded.DecodeString("0F00000000000C")).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:015:12")
+
+	w.ShouldFail(NewDecoderFromConfig([]byte(`{"authority": "test.com",
+		"date": "2019-01-01", "delimiter": "-", "fields": [{"width": 8}]}`)))
+	w.ShouldFail(NewDecoderFromConfig([]byte(`{"authority": "test.com",
+		"date": "2019-01-01", "fields": [{"width": 8}, {"skip": 8, "padding": 2}]}`)))
+}
+
+func TestFieldFormat_append(t *testing.T) {
+	w := expect.WrapT(t)
+
+	wide := new(big.Int).Lsh(big.NewInt(0xABC), 70)
+	for _, tc := range []struct {
+		ff       fieldFormat
+		value    interface{}
+		expected string
+	}{
+		{fieldFormat{}, uint64(0), "0"},
+		{fieldFormat{}, uint64(18446744073709551615), "18446744073709551615"},
+		{fieldFormat{}, wide, fmt.Sprintf("%d", wide)},
+		{fieldFormat{format: PaddedDecimal, digits: 4}, uint64(12), "0012"},
+		{fieldFormat{format: PaddedDecimal, digits: 4}, uint64(123456), "123456"},
+		{fieldFormat{format: PaddedDecimal, digits: 30}, wide, fmt.Sprintf("%030d", wide)},
+		{fieldFormat{format: Hex, digits: 6}, uint64(0xabc), "000ABC"},
+		{fieldFormat{format: Hex, digits: 6, pad: 2}, uint64(0xabc), "ABC"},
+		{fieldFormat{format: Hex, digits: 24}, wide, fmt.Sprintf("%024X", wide)},
+		{fieldFormat{format: Base36, digits: 0, pad: 6}, uint64(786432), "00gutc"},
+		{fieldFormat{format: Decimal, pad: 3}, uint64(7), "007"},
+		{fieldFormat{format: ASCII8}, "A:B.c", "A%3AB%2Ec"},
+	} {
+		actual := string(tc.ff.append([]byte("x"), tc.value, "."))
+		w.As(tc).ShouldBeEqual(actual, "x"+tc.expected)
+	}
+}
@@ -0,0 +1,117 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"github.com/pkg/errors"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+
+// DecodeInto decodes data as Decode does, and stores the values of the named
+// fields in the struct v points to, based on the "bittag" tags of its fields,
+// which hold the names of the Decoder's fields. For example, for a Decoder with
+// fields named "version", "productID", and "serial":
+//     type Product struct {
+//         ProductID uint32 `bittag:"productID"`
+//         Serial    uint64 `bittag:"serial"`
+//     }
+//
+// Struct fields without a "bittag" tag, or with the tag "-", are ignored, as are
+// the Decoder's fields without a tagged struct field. Tagged fields may be
+// unsigned or signed integers, which must be large enough for the field's value,
+// *big.Int, which is set to a new copy of any numeric field's value, or string,
+// for fields with the ASCII7 or ASCII8 formats.
+//
+// DecodeInto returns an error if v isn't a non-nil pointer to a struct, the
+// Decoder doesn't name its fields, a tag names a field the Decoder doesn't
+// have, the data can't be decoded, or a value doesn't fit its struct field. In
+// that case, the struct may have been partially set.
+func (btd Decoder) DecodeInto(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("DecodeInto requires a non-nil pointer to a "+
+			"struct, not %T", v)
+	}
+	rv = rv.Elem()
+	if btd.FieldNames() == nil {
+		return errors.New("DecodeInto requires a Decoder with named fields")
+	}
+
+	bt, err := btd.Decode(data)
+	if err != nil {
+		return err
+	}
+
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, ok := sf.Tag.Lookup("bittag")
+		if !ok || name == "-" {
+			continue
+		}
+		if sf.PkgPath != "" {
+			return errors.Errorf("field %s has a bittag tag, but is "+
+				"unexported", sf.Name)
+		}
+		idx, err := bt.fieldIndex(name)
+		if err != nil {
+			return errors.Wrapf(err, "invalid bittag tag for field %s", sf.Name)
+		}
+		if err := bt.storeField(idx, rv.Field(i)); err != nil {
+			return errors.Wrapf(err, "unable to store %q in field %s",
+				name, sf.Name)
+		}
+	}
+	return nil
+}
+
+// storeField sets fv to the value of the field at the given index.
+func (bt BitTag) storeField(idx int, fv reflect.Value) error {
+	switch fv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := bt.Uint64Field(idx)
+		if err != nil {
+			return err
+		}
+		if fv.OverflowUint(v) {
+			return errors.Errorf("%d doesn't fit in a %s", v, fv.Type())
+		}
+		fv.SetUint(v)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := bt.Uint64Field(idx)
+		if err != nil {
+			return err
+		}
+		if v > math.MaxInt64 || fv.OverflowInt(int64(v)) {
+			return errors.Errorf("%d doesn't fit in a %s", v, fv.Type())
+		}
+		fv.SetInt(int64(v))
+		return nil
+	case reflect.String:
+		s, ok := bt.fields[idx].(string)
+		if !ok {
+			return errors.Errorf("field %d isn't text", idx)
+		}
+		fv.SetString(s)
+		return nil
+	}
+
+	if fv.Type() == bigIntType {
+		v, err := bt.BigIntField(idx)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(v))
+		return nil
+	}
+	return errors.Errorf("unsupported type %s", fv.Type())
+}
@@ -0,0 +1,102 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"math/big"
+	"testing"
+)
+
+func TestDecoder_DecodeInto(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewNamedDecoder("test.com", "2019-01-01",
+		[]bitextract.Field{
+			{Name: "version", Width: 8},
+			{Name: "productID", Width: 24},
+			{Name: "serial", Width: 40},
+			{Name: "lot", Width: 72},
+			{Name: "code", Width: 16},
+		})).(Decoder)
+	w.ShouldSucceed(decoder.SetFieldFormat(4, ASCII8))
+	data := w.ShouldHaveResult(hex.DecodeString(
+		"0F" + "0C0000" + "00000014D2" + "FF0000000000000001" + "4869")).([]byte)
+
+	type product struct {
+		Version   int8     `bittag:"version"`
+		ProductID uint32   `bittag:"productID"`
+		Serial    uint64   `bittag:"serial"`
+		Lot       *big.Int `bittag:"lot"`
+		Code      string   `bittag:"code"`
+		Ignored   float64  `bittag:"-"`
+		Untagged  string
+	}
+	var p product
+	w.ShouldSucceed(decoder.DecodeInto(data, &p))
+	lot, _ := new(big.Int).SetString("FF0000000000000001", 16)
+	w.ShouldBeEqual(p, product{
+		Version:   15,
+		ProductID: 786432,
+		Serial:    5330,
+		Lot:       lot,
+		Code:      "Hi",
+	})
+
+	// only tagged fields are needed
+	var serial struct {
+		Serial uint `bittag:"serial"`
+	}
+	w.ShouldSucceed(decoder.DecodeInto(data, &serial))
+	w.ShouldBeEqual(serial.Serial, uint(5330))
+
+	for _, tc := range []struct {
+		name string
+		v    interface{}
+	}{
+		{"not a pointer", p},
+		{"nil pointer", (*product)(nil)},
+		{"not a struct", new(int)},
+		{"unknown name", &struct {
+			X uint64 `bittag:"x"`
+		}{}},
+		{"overflow", &struct {
+			ProductID uint16 `bittag:"productID"`
+		}{}},
+		{"signed overflow", &struct {
+			Serial int8 `bittag:"serial"`
+		}{}},
+		{"too wide", &struct {
+			Lot uint64 `bittag:"lot"`
+		}{}},
+		{"text as a number", &struct {
+			Code uint64 `bittag:"code"`
+		}{}},
+		{"number as text", &struct {
+			Serial string `bittag:"serial"`
+		}{}},
+		{"unsupported type", &struct {
+			Serial float64 `bittag:"serial"`
+		}{}},
+		{"unexported", &struct {
+			serial uint64 `bittag:"serial"`
+		}{}},
+	} {
+		w.As(tc.name).ShouldFail(decoder.DecodeInto(data, tc.v))
+	}
+
+	// errors decoding the data
+	w.ShouldFail(decoder.DecodeInto(data[:10], &p))
+	w.ShouldSucceed(decoder.AddValidator(0, Constant(1)))
+	w.ShouldFail(decoder.DecodeInto(data, &p))
+
+	unnamed := w.ShouldHaveResult(NewDecoder(
+		"test.com", "2019-01-01", []int{8, 24})).(Decoder)
+	w.ShouldFail(unnamed.DecodeInto(data, &serial))
+}
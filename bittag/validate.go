@@ -0,0 +1,170 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"math/big"
+	"strings"
+)
+
+// Validator restricts the values of a numeric field. Each of its restrictions
+// that's set must hold: the value must be at least Min and at most Max, one of
+// OneOf, and equal to Const. InRange, OneOf, and Constant return Validators
+// with a single restriction. Its fields have json and yaml tags, so that it may
+// be used in configuration, such as a DecoderConfig.
+type Validator struct {
+	Min   *uint64  `json:"min,omitempty" yaml:"min,omitempty"`
+	Max   *uint64  `json:"max,omitempty" yaml:"max,omitempty"`
+	OneOf []uint64 `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
+	Const *uint64  `json:"const,omitempty" yaml:"const,omitempty"`
+}
+
+// InRange returns a Validator that requires values to be from min to max,
+// inclusive.
+func InRange(min, max uint64) Validator {
+	return Validator{Min: &min, Max: &max}
+}
+
+// OneOf returns a Validator that requires values to be one of the given values.
+func OneOf(values ...uint64) Validator {
+	return Validator{OneOf: append([]uint64(nil), values...)}
+}
+
+// Constant returns a Validator that requires values to equal the given value,
+// such as for a field that marks a tag's format.
+func Constant(value uint64) Validator {
+	return Validator{Const: &value}
+}
+
+// String describes the Validator's restrictions, such as "at least 1, one of
+// [1 2 3]".
+func (v Validator) String() string {
+	var parts []string
+	if v.Min != nil {
+		parts = append(parts, fmt.Sprintf("at least %d", *v.Min))
+	}
+	if v.Max != nil {
+		parts = append(parts, fmt.Sprintf("at most %d", *v.Max))
+	}
+	if v.OneOf != nil {
+		parts = append(parts, fmt.Sprintf("one of %v", v.OneOf))
+	}
+	if v.Const != nil {
+		parts = append(parts, fmt.Sprintf("equal to %d", *v.Const))
+	}
+	if parts == nil {
+		return "unrestricted"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Validate returns an error if the value, which is a uint64 or *big.Int as held
+// by a BitTag's fields, doesn't meet the Validator's restrictions. Text values
+// never do.
+func (v Validator) Validate(value interface{}) error {
+	var n uint64
+	switch value := value.(type) {
+	case uint64:
+		n = value
+	case *big.Int:
+		if !value.IsUint64() {
+			// it's larger than any restriction allows
+			if v.Max != nil || v.OneOf != nil || v.Const != nil {
+				return errors.Errorf("%v isn't %v", value, v)
+			}
+			return nil
+		}
+		n = value.Uint64()
+	default:
+		return errors.Errorf("%v isn't numeric, so it can't be %v", value, v)
+	}
+
+	ok := (v.Min == nil || n >= *v.Min) &&
+		(v.Max == nil || n <= *v.Max) &&
+		(v.Const == nil || n == *v.Const)
+	if ok && v.OneOf != nil {
+		ok = false
+		for _, allowed := range v.OneOf {
+			if n == allowed {
+				ok = true
+				break
+			}
+		}
+	}
+	if !ok {
+		return errors.Errorf("%d isn't %v", n, v)
+	}
+	return nil
+}
+
+// ValidationError is returned by Decode when a field's value doesn't meet the
+// restrictions of a Validator added with AddValidator.
+type ValidationError struct {
+	Field     int         // the index of the field
+	Name      string      // the name of the field, if the Decoder names them
+	Value     interface{} // the field's value, as a BitTag would hold it
+	Validator Validator   // the Validator the value doesn't meet
+}
+
+func (e *ValidationError) Error() string {
+	field := fmt.Sprintf("field %d", e.Field)
+	if e.Name != "" {
+		field = fmt.Sprintf("field %d (%s)", e.Field, e.Name)
+	}
+	return fmt.Sprintf("%s is %v, but must be %v", field, e.Value, e.Validator)
+}
+
+// fieldValidator is a Validator for a particular field.
+type fieldValidator struct {
+	field     int
+	validator Validator
+}
+
+// AddValidator adds a Validator to the field at the given index, so that Decode
+// returns a *ValidationError if the field's value doesn't meet it. Fields may
+// have more than one Validator, in which case values must meet all of them. It
+// returns an error if the index is out of range.
+//
+// Like formats, validators should be added after the Decoder's widths.
+func (btd *Decoder) AddValidator(idx int, v Validator) error {
+	if idx < 0 || idx >= btd.NumFields() {
+		return errors.Errorf("field index %d is out of range [0, %d)",
+			idx, btd.NumFields())
+	}
+
+	// copy them, since copies of the Decoder share them
+	validators := make([]fieldValidator, len(btd.validators), len(btd.validators)+1)
+	copy(validators, btd.validators)
+	btd.validators = append(validators, fieldValidator{field: idx, validator: v})
+	return nil
+}
+
+// validate returns a *ValidationError if any of the BitTag's fields don't meet
+// the Decoder's Validators.
+func (btd Decoder) validate(bt BitTag) error {
+	for _, fv := range btd.validators {
+		if fv.field >= len(bt.fields) {
+			return errors.New("the Decoder's widths changed after its " +
+				"validators were added")
+		}
+		value := bt.fields[fv.field]
+		if fv.validator.Validate(value) != nil {
+			ve := &ValidationError{
+				Field:     fv.field,
+				Value:     value,
+				Validator: fv.validator,
+			}
+			if bt.names != nil {
+				ve.Name = bt.names[fv.field]
+			}
+			return ve
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bittag
+
+import (
+	"encoding/json"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"math/big"
+	"testing"
+)
+
+func TestValidator_Validate(t *testing.T) {
+	w := expect.WrapT(t)
+
+	huge := new(big.Int).Lsh(big.NewInt(1), 80)
+	min := uint64(3)
+	testCases := []struct {
+		v     Validator
+		value interface{}
+		valid bool
+	}{
+		{InRange(1, 5), uint64(1), true},
+		{InRange(1, 5), uint64(5), true},
+		{InRange(1, 5), uint64(0), false},
+		{InRange(1, 5), uint64(6), false},
+		{OneOf(2, 4, 8), uint64(4), true},
+		{OneOf(2, 4, 8), uint64(5), false},
+		{Constant(7), uint64(7), true},
+		{Constant(7), uint64(8), false},
+		{Constant(7), big.NewInt(7), true},
+		{Constant(7), huge, false},
+		{InRange(1, 5), huge, false},
+		{Validator{Min: &min}, huge, true},
+		{Validator{}, uint64(9), true},
+		{Constant(7), "7", false},
+	}
+
+	for i, tc := range testCases {
+		err := tc.v.Validate(tc.value)
+		if tc.valid {
+			w.As(i).ShouldSucceed(err)
+		} else {
+			w.As(i).ShouldFail(err)
+		}
+	}
+
+	w.ShouldBeEqual(InRange(1, 5).String(), "at least 1, at most 5")
+	w.ShouldBeEqual(OneOf(2, 4).String(), "one of [2 4]")
+	w.ShouldBeEqual(Constant(7).String(), "equal to 7")
+	w.ShouldBeEqual(Validator{}.String(), "unrestricted")
+}
+
+func TestDecoder_AddValidator(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewNamedDecoder("test.com", "2019-01-01",
+		[]bitextract.Field{
+			{Name: "version", Width: 8},
+			{Width: -48},
+			{Name: "productID", Width: 40},
+		})).(Decoder)
+	unvalidated := decoder
+	w.ShouldSucceed(decoder.AddValidator(0, Constant(15)))
+	w.ShouldSucceed(decoder.AddValidator(1, InRange(1, 9999)))
+	w.ShouldSucceed(decoder.AddValidator(1, OneOf(5330, 5331)))
+	w.ShouldFail(decoder.AddValidator(2, Constant(0)))
+	w.ShouldFail(decoder.AddValidator(-1, Constant(0)))
+
+	bitTag := w.ShouldHaveResult(decoder.DecodeString("0F00000000000C00000014D2")).(BitTag)
+	w.ShouldBeEqual(bitTag.URI(), "tag:test.com,2019-01-01:15.5330")
+	w.ShouldHaveResult(decoder.ParseURI(bitTag.URI()))
+
+	_, err := decoder.DecodeString("0E00000000000C00000014D2")
+	ve, ok := err.(*ValidationError)
+	w.StopOnMismatch().ShouldBeTrue(ok)
+	w.ShouldBeEqual(ve.Field, 0)
+	w.ShouldBeEqual(ve.Name, "version")
+	w.ShouldBeEqual(ve.Value, uint64(14))
+	w.ShouldBeEqual(ve.Error(), "field 0 (version) is 14, but must be equal to 15")
+
+	_, err = decoder.DecodeString("0F00000000000C00000014D4")
+	ve, ok = err.(*ValidationError)
+	w.StopOnMismatch().ShouldBeTrue(ok)
+	w.ShouldBeEqual(ve.Name, "productID")
+	w.ShouldBeEqual(ve.Validator, OneOf(5330, 5331))
+
+	w.ShouldFail(decoder.ParseURI("tag:test.com,2019-01-01:15.5332"))
+	w.ShouldHaveResult(unvalidated.DecodeString("0F00000000000C00000014D4"))
+
+	data := w.ShouldHaveResult(json.Marshal(decoder)).([]byte)
+	w.ShouldContainStr(string(data), `"validators":[{"field":0,"const":15},`+
+		`{"field":1,"min":1,"max":9999},{"field":1,"oneOf":[5330,5331]}]`)
+	var decoded Decoder
+	w.ShouldSucceed(json.Unmarshal(data, &decoded))
+	w.ShouldFail(decoded.DecodeString("0E00000000000C00000014D2"))
+}
+
+func TestDecoder_validatorConfig(t *testing.T) {
+	w := expect.WrapT(t)
+
+	decoder := w.ShouldHaveResult(NewDecoderFromConfig([]byte(`{
+		"authority": "test.com",
+		"date": "2019-01-01",
+		"fields": [
+			{"width": 8, "validators": [{"const": 15}]},
+			{"skip": 48},
+			{"width": 40, "validators": [{"min": 1, "max": 9999}]}
+		]
+	}`))).(Decoder)
+	w.ShouldHaveResult(decoder.DecodeString("0F00000000000C00000014D2"))
+	_, err := decoder.DecodeString("0F00000000000C00000FFFFF")
+	ve, ok := err.(*ValidationError)
+	w.StopOnMismatch().ShouldBeTrue(ok)
+	w.ShouldBeEqual(ve.Field, 1)
+	w.ShouldBeEqual(ve.Name, "")
+
+	w.ShouldFail(NewDecoderFromConfig([]byte(`{"authority": "test.com",
+		"date": "2019-01-01", "fields": [{"width": 8},
+		{"skip": 8, "validators": [{"const": 0}]}]}`)))
+}
@@ -0,0 +1,77 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"container/list"
+	"sync"
+)
+
+// cacheEntry is the result of decoding some tag data.
+type cacheEntry struct {
+	key       string // the tag data
+	uri, name string
+	err       error
+
+	scheme   string
+	value    interface{}
+	warnings []string
+}
+
+// lruCache is a concurrency-safe cache of the most recently used cacheEntries.
+type lruCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of *cacheEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns the entry for the data, if it's cached, and marks it as the most
+// recently used.
+func (c *lruCache) get(data []byte) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[string(data)]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	c.order.MoveToFront(elem)
+	return *elem.Value.(*cacheEntry), true
+}
+
+// add caches the entry, evicting the least recently used entry if the cache is
+// full.
+func (c *lruCache) add(e cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[e.key]; ok {
+		// another goroutine decoded the same data
+		c.order.MoveToFront(elem)
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+	c.entries[e.key] = c.order.PushFront(&e)
+}
+
+// len returns the number of cached entries.
+func (c *lruCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
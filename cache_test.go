@@ -0,0 +1,85 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestLRUCache(t *testing.T) {
+	w := expect.WrapT(t)
+
+	c := newLRUCache(2)
+	c.add(cacheEntry{key: "a", uri: "A"})
+	c.add(cacheEntry{key: "b", uri: "B"})
+	e, ok := c.get([]byte("a"))
+	w.ShouldBeTrue(ok)
+	w.ShouldBeEqual(e.uri, "A")
+
+	// "b" is the least recently used
+	c.add(cacheEntry{key: "c", uri: "C"})
+	_, ok = c.get([]byte("b"))
+	w.ShouldBeFalse(ok)
+	_, ok = c.get([]byte("a"))
+	w.ShouldBeTrue(ok)
+	_, ok = c.get([]byte("c"))
+	w.ShouldBeTrue(ok)
+
+	// adding an entry again doesn't replace it
+	c.add(cacheEntry{key: "c", uri: "D"})
+	e, _ = c.get([]byte("c"))
+	w.ShouldBeEqual(e.uri, "C")
+	w.ShouldBeEqual(c.len(), 2)
+}
+
+func TestDecoderChain_WithCache(t *testing.T) {
+	w := expect.WrapT(t)
+
+	cm := &countMetrics{counts: map[string]int{}}
+	chain := w.ShouldHaveResult(NewDecoderChain([]string{
+		"sgtin", "bittag:test.com,2019-01-01:8.48.40"})).(DecoderChain)
+	cached := chain.WithCache(100).WithMetrics(cm.funcs())
+
+	data := batchData(w, 300)
+	for i := 0; i < 3; i++ {
+		for _, d := range data[:150] {
+			uri, name, err := chain.Decode(d)
+			cachedURI, cachedName, cachedErr := cached.Decode(d)
+			w.ShouldBeEqual(cachedURI, uri)
+			w.ShouldBeEqual(cachedName, name)
+			w.ShouldBeEqual(cachedErr != nil, err != nil)
+			if err != nil {
+				w.ShouldBeEqual(cachedErr.Error(), err.Error())
+			}
+		}
+	}
+	// cycling through more tags than the cache holds always misses
+	w.ShouldBeEqual(cm.counts["cache hit"], 0)
+	w.ShouldBeEqual(cm.counts["cache miss"], 450)
+	w.ShouldBeEqual(cached.cache.len(), 100)
+
+	cm.counts = map[string]int{}
+	for i := 0; i < 3; i++ {
+		for _, d := range data[:50] {
+			cached.Decode(d)
+		}
+	}
+	w.ShouldBeEqual(cm.counts["cache miss"], 50)
+	w.ShouldBeEqual(cm.counts["cache hit"], 100)
+	w.ShouldBeEqual(cm.counts["no match"], 3*16)
+	w.ShouldBeEqual(cm.counts["attempt sgtin"], 17)
+
+	// concurrent use
+	results := DecodeBatch(cached, data)
+	for i, r := range results {
+		uri, _, _ := chain.Decode(data[i])
+		w.ShouldBeEqual(r.URI, uri)
+	}
+
+	w.ShouldBeTrue(cached.WithCache(0).cache == nil)
+}
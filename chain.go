@@ -0,0 +1,326 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bittag"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+)
+
+// bitTagConfigPrefix starts DecoderChain config entries for bittag Decoders.
+const bitTagConfigPrefix = "bittag:"
+
+// DecoderChain decodes tag data with the first of a series of TagDecoders that
+// accepts it, so that services that read tags of several formats agree on the
+// URI of each.
+type DecoderChain struct {
+	names    []string
+	decoders []TagDecoder
+	metrics  Metrics   // nil for NopMetrics
+	cache    *lruCache // nil unless set by WithCache
+	fallback string    // the tagging entity of fallback URIs; "" if strict
+	workers  int       // for DecodeBatch and Streams; 0 for GOMAXPROCS
+}
+
+// NewDecoderChain returns a DecoderChain of the decoders in the config, in
+// order, which is in the form of the inventory suite's tag decoder config:
+//     ["sgtin", "bittag:test.com,2019-01-01:8.48.40"]
+//
+// Entries are either the names of registered TagDecoders, such as "sgtin", or
+// bittag Decoders of the form "bittag:authority,date:widths", where the widths
+// are as parsed by bitextract.SplitWidths with "." as the delimiter, so they may
+// skip bits with negative widths or end with a "*" field of the rest of the
+// data. An entry "namespace/*" stands for all the decoders registered in that
+// namespace with RegisterDecoders, in order of priority; their names are their
+// full names, such as "acme/gen2".
+//
+// The chain's settings are the defaults set by Configure, overridden by the
+// given Options. It returns an error if the config is empty, an entry is invalid
+// or isn't registered, or the settings are invalid.
+func NewDecoderChain(config []string, opts ...Option) (DecoderChain, error) {
+	if len(config) == 0 {
+		return DecoderChain{}, errors.New("the decoder chain config is empty")
+	}
+	o := defaultOptions()
+	o.apply(opts)
+	if err := o.check(); err != nil {
+		return DecoderChain{}, err
+	}
+
+	dc := DecoderChain{fallback: o.fallbackEntity(), workers: o.workers}
+	if o.cacheSize > 0 {
+		dc.cache = newLRUCache(o.cacheSize)
+	}
+	for i, entry := range config {
+		entry = strings.TrimSpace(entry)
+		if strings.HasPrefix(entry, bitTagConfigPrefix) {
+			btd, err := parseBitTagConfig(entry[len(bitTagConfigPrefix):])
+			if err != nil {
+				return DecoderChain{}, errors.Wrapf(err, "invalid decoder %d", i)
+			}
+			dc.names = append(dc.names, entry)
+			dc.decoders = append(dc.decoders, BitTagDecoder{btd})
+			continue
+		}
+
+		names := []string{entry}
+		if strings.HasSuffix(entry, "/*") {
+			names = registeredWithPrefix(entry[:len(entry)-1])
+			if len(names) == 0 {
+				return DecoderChain{}, errors.Errorf("decoder %d, %q, "+
+					"matches no registered decoders", i, entry)
+			}
+		}
+		for _, name := range names {
+			d, ok := Lookup(name)
+			if !ok {
+				return DecoderChain{}, errors.Errorf("decoder %d, %q, isn't "+
+					"registered", i, name)
+			}
+			dc.names = append(dc.names, name)
+			dc.decoders = append(dc.decoders, d)
+		}
+	}
+	return dc, nil
+}
+
+// parseBitTagConfig returns the bittag Decoder for an "authority,date:widths"
+// config entry.
+func parseBitTagConfig(conf string) (bittag.Decoder, error) {
+	sep := strings.LastIndexByte(conf, ':')
+	if sep < 0 {
+		return bittag.Decoder{}, errors.Errorf("bittag decoders must have the "+
+			"form \"bittag:authority,date:widths\", not %q", bitTagConfigPrefix+conf)
+	}
+	entity, widthConf := conf[:sep], conf[sep+1:]
+
+	comma := strings.IndexByte(entity, ',')
+	if comma < 0 {
+		return bittag.Decoder{}, errors.Errorf("the tagging entity %q must "+
+			"have the form \"authority,date\"", entity)
+	}
+	widths, err := bitextract.SplitWidths(widthConf, ".")
+	if err != nil {
+		return bittag.Decoder{}, err
+	}
+	return bittag.NewDecoder(entity[:comma], entity[comma+1:], widths)
+}
+
+// WithMetrics returns a copy of the chain that reports measurements of its
+// decoding to m. If m is nil, it uses NopMetrics.
+func (dc DecoderChain) WithMetrics(m Metrics) DecoderChain {
+	if _, nop := m.(NopMetrics); nop {
+		m = nil
+	}
+	dc.metrics = m
+	return dc
+}
+
+// WithCache returns a copy of the chain that caches the results of decoding the
+// given number of the most recently decoded tags, keyed by their data, so that
+// tags that are read repeatedly are only decoded once. Failures are cached too,
+// since decoding is deterministic. The cache is safe for concurrent use, and is
+// shared by copies of the returned chain; if size isn't positive, the chain
+// doesn't cache results.
+//
+// The chain's Metrics are told of cache hits and misses, but only decoding on
+// misses is measured, although cached failures are still reported as NoMatch.
+func (dc DecoderChain) WithCache(size int) DecoderChain {
+	dc.cache = nil
+	if size > 0 {
+		dc.cache = newLRUCache(size)
+	}
+	return dc
+}
+
+// Names returns the names of the chain's decoders, in order, which are their
+// config entries, apart from those of namespace wildcards.
+func (dc DecoderChain) Names() []string {
+	return append([]string(nil), dc.names...)
+}
+
+// Result is the outcome of decoding one tag's data with a DecoderChain, with
+// the context needed for diagnostics and analytics.
+type Result struct {
+	Data    []byte // the tag's data
+	URI     string // the canonical URI, if Err is nil
+	Decoder string // the config entry of the decoder that decoded it
+	// Scheme is the scheme of the URI, such as "sgtin", or "tag" for tag
+	// URIs, including those of bittag Decoders and fallback URIs.
+	Scheme string
+	// Value is the structured value the decoder decoded, such as an
+	// epc.SGTIN, epc.EPC, or bittag.BitTag, if the decoder is a
+	// ValueDecoder.
+	Value interface{}
+	// Warnings are the reasons the decoders that could decode the data, but
+	// came before the one that did, didn't, so they're also in Err if none
+	// did.
+	Warnings []string
+	// Duration is how long decoding took, including looking it up in the
+	// chain's cache.
+	Duration time.Duration
+	Err      error // why no decoder accepted the data
+}
+
+// Decode decodes data with the first of the chain's decoders that accepts it,
+// and returns its canonical URI along with the config entry of that decoder,
+// such as "sgtin". DecodeResult returns more details about the decoding.
+//
+// A decoder accepts data if its CanDecode method returns true for it, and it
+// decodes the data without error. If none accept it, Decode returns an error
+// that includes the reason each decoder that could decode it didn't, unless the
+// chain isn't strict, in which case it returns the data's fallback URI and
+// FallbackName; see WithStrict.
+func (dc DecoderChain) Decode(data []byte) (uri, name string, err error) {
+	r := dc.result(data)
+	return r.URI, r.Decoder, r.Err
+}
+
+// DecodeResult decodes data as Decode does, and returns the Result, including
+// the decoded value, the reasons any decoders before the matching one didn't
+// accept the data, and how long decoding took. Values and Warnings may be
+// shared by the Results of the same data from a chain with a cache, so they
+// mustn't be modified.
+func (dc DecoderChain) DecodeResult(data []byte) Result {
+	start := time.Now()
+	r := dc.result(data)
+	r.Duration = time.Since(start)
+	return r
+}
+
+// result decodes data as DecodeResult does, without measuring the Duration.
+func (dc DecoderChain) result(data []byte) Result {
+	if dc.cache == nil {
+		r := dc.decodeUncached(data)
+		r.Data = data
+		return r
+	}
+
+	if e, ok := dc.cache.get(data); ok {
+		if dc.metrics != nil {
+			dc.metrics.CacheHit()
+			if e.err != nil || e.name == FallbackName {
+				dc.metrics.NoMatch()
+			}
+		}
+		return Result{
+			Data:     data,
+			URI:      e.uri,
+			Decoder:  e.name,
+			Scheme:   e.scheme,
+			Value:    e.value,
+			Warnings: e.warnings,
+			Err:      e.err,
+		}
+	}
+	if dc.metrics != nil {
+		dc.metrics.CacheMiss()
+	}
+	r := dc.decodeUncached(data)
+	r.Data = data
+	dc.cache.add(cacheEntry{
+		key:      string(data),
+		uri:      r.URI,
+		name:     r.Decoder,
+		err:      r.Err,
+		scheme:   r.Scheme,
+		value:    r.Value,
+		warnings: r.Warnings,
+	})
+	return r
+}
+
+// decodeUncached decodes data as Decode does, without the chain's cache, and
+// returns its Result, without its Data or Duration.
+func (dc DecoderChain) decodeUncached(data []byte) Result {
+	var r Result
+	for i, d := range dc.decoders {
+		if !d.CanDecode(data) {
+			continue
+		}
+		value, uri, err := dc.decode(i, d, data)
+		if err != nil {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s: %v", dc.names[i], err))
+			continue
+		}
+		r.URI, r.Decoder, r.Scheme, r.Value = uri, dc.names[i], uriScheme(uri), value
+		return r
+	}
+	if dc.metrics != nil {
+		dc.metrics.NoMatch()
+	}
+	if dc.fallback != "" {
+		r.URI, r.Decoder, r.Scheme = bittag.FallbackURI(dc.fallback, data), FallbackName, "tag"
+		return r
+	}
+	if r.Warnings == nil {
+		r.Err = errors.New("no decoder in the chain can decode the data")
+	} else {
+		r.Err = errors.Errorf("no decoder in the chain accepts the data: %s",
+			strings.Join(r.Warnings, "; "))
+	}
+	return r
+}
+
+// decode decodes data with the chain's decoder at index i, reporting it to the
+// chain's Metrics, and returns its value if it's a ValueDecoder, and its URI.
+func (dc DecoderChain) decode(i int, d TagDecoder, data []byte) (interface{}, string, error) {
+	if dc.metrics == nil {
+		return decodeValue(d, data)
+	}
+
+	name := dc.names[i]
+	dc.metrics.DecodeAttempt(name)
+	start := time.Now()
+	value, uri, err := decodeValue(d, data)
+	dc.metrics.DecodeLatency(name, time.Since(start))
+	if err != nil {
+		dc.metrics.DecodeFailure(name, failureReason(err))
+	}
+	return value, uri, err
+}
+
+// decodeValue decodes data with d, and returns its value if it's a
+// ValueDecoder, and its URI.
+func decodeValue(d TagDecoder, data []byte) (interface{}, string, error) {
+	if vd, ok := d.(ValueDecoder); ok {
+		return vd.DecodeValue(data)
+	}
+	uri, err := d.Decode(data)
+	return nil, uri, err
+}
+
+// uriScheme returns the scheme of an EPC Pure Identity URI, such as "sgtin",
+// "tag" for tag URIs, or "" for other URIs.
+func uriScheme(uri string) string {
+	if strings.HasPrefix(uri, "tag:") {
+		return "tag"
+	}
+	if !strings.HasPrefix(uri, epc.PureIdentityURIPrefix) {
+		return ""
+	}
+	scheme := uri[len(epc.PureIdentityURIPrefix):]
+	if end := strings.IndexByte(scheme, ':'); end >= 0 {
+		return scheme[:end]
+	}
+	return ""
+}
+
+// DecodeString is a convenience method that decodes hex-encoded byte data.
+func (dc DecoderChain) DecodeString(data string) (uri, name string, err error) {
+	byteData, err := hex.DecodeString(data)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "unable to decode tag data as hex")
+	}
+	return dc.Decode(byteData)
+}
@@ -0,0 +1,135 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bittag"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"testing"
+)
+
+func TestNewDecoderChain(t *testing.T) {
+	w := expect.WrapT(t)
+
+	config := []string{
+		"sgtin",
+		" epc ",
+		"bittag:test.com,2019-01-01:8.48.40",
+		"bittag:user@test.com,2019:8.-16.*",
+	}
+	chain := w.ShouldHaveResult(NewDecoderChain(config)).(DecoderChain)
+	w.ShouldBeEqual(chain.Names(), []string{"sgtin", "epc",
+		"bittag:test.com,2019-01-01:8.48.40", "bittag:user@test.com,2019:8.-16.*"})
+
+	testCases := []struct {
+		data string
+		uri  string
+		name string
+	}{
+		{"3034257BF7194E4000001A85", "urn:epc:id:sgtin:0614141.812345.6789", "sgtin"},
+		{"3134257BF4499602D2000000", "urn:epc:id:sscc:0614141.1234567890", "epc"},
+		// not a valid SGTIN, so the bittag decoder is used instead
+		{"301000181C7FFFD3A8B43711", "tag:test.com,2019-01-01:48.17592590565375.909068482321",
+			"bittag:test.com,2019-01-01:8.48.40"},
+		{"0F00000000000C00000014D2", "tag:test.com,2019-01-01:15.12.5330",
+			"bittag:test.com,2019-01-01:8.48.40"},
+		{"0F0000FF", "tag:user@test.com,2019:15.255", "bittag:user@test.com,2019:8.-16.*"},
+	}
+	for _, tc := range testCases {
+		w := w.As(tc.data)
+		uri, name, err := chain.DecodeString(tc.data)
+		w.ShouldSucceed(err)
+		w.ShouldBeEqual(uri, tc.uri)
+		w.ShouldBeEqual(name, tc.name)
+	}
+
+	for _, data := range []string{"", "0F00", "not hex"} {
+		_, _, err := chain.DecodeString(data)
+		w.As(data).ShouldFail(err)
+	}
+
+	sgtinOnly := w.ShouldHaveResult(NewDecoderChain([]string{"sgtin"})).(DecoderChain)
+	_, _, err := sgtinOnly.DecodeString("301000181C7FFFD3A8B43711")
+	w.ShouldFail(err)
+	w.ShouldContainStr(err.Error(), "sgtin: ")
+
+	for _, invalid := range [][]string{
+		nil,
+		{"unknown"},
+		{"sgtin", ""},
+		{"bittag:"},
+		{"bittag:test.com,2019-01-01"},
+		{"bittag:test.com:8.48.40"},
+		{"bittag:test.com,2019-13:8.48.40"},
+		{"bittag:Test.com,2019-01-01:8.48.40"},
+		{"bittag:test.com,2019-01-01:8..40"},
+		{"bittag:test.com,2019-01-01:*.8"},
+	} {
+		w.As(invalid).ShouldFail(NewDecoderChain(invalid))
+	}
+}
+
+func TestDecoderChain_DecodeResult(t *testing.T) {
+	w := expect.WrapT(t)
+
+	chain := w.ShouldHaveResult(NewDecoderChain([]string{
+		"sgtin", "bittag:test.com,2019-01-01:8.48.40"})).(DecoderChain)
+	decode := func(data string) Result {
+		return chain.DecodeResult(w.ShouldHaveResult(hex.DecodeString(data)).([]byte))
+	}
+
+	sgtin := decode("3034257BF7194E4000001A85")
+	w.ShouldSucceed(sgtin.Err)
+	w.ShouldBeEqual(sgtin.Data, []byte{0x30, 0x34, 0x25, 0x7B, 0xF7, 0x19,
+		0x4E, 0x40, 0x00, 0x00, 0x1A, 0x85})
+	w.ShouldBeEqual(sgtin.URI, "urn:epc:id:sgtin:0614141.812345.6789")
+	w.ShouldBeEqual(sgtin.Decoder, "sgtin")
+	w.ShouldBeEqual(sgtin.Scheme, "sgtin")
+	w.ShouldBeEqual(sgtin.Value.(epc.SGTIN).GTIN(), "80614141123458")
+	w.ShouldBeEqual(len(sgtin.Warnings), 0)
+	w.ShouldBeTrue(sgtin.Duration > 0)
+
+	// not a valid SGTIN, so the sgtin decoder's reason is a warning
+	bitTag := decode("301000181C7FFFD3A8B43711")
+	w.ShouldSucceed(bitTag.Err)
+	w.ShouldBeEqual(bitTag.Scheme, "tag")
+	w.ShouldBeEqual(bitTag.Value.(bittag.BitTag).NumFields(), 3)
+	w.ShouldBeEqual(len(bitTag.Warnings), 1)
+	w.ShouldContainStr(bitTag.Warnings[0], "sgtin: invalid SGTIN")
+
+	failed := decode("0F00")
+	w.ShouldFail(failed.Err)
+	w.ShouldBeEqual(failed.URI, "")
+	w.ShouldBeTrue(failed.Value == nil)
+
+	// cached Results have the same details
+	cached := chain.WithCache(10)
+	data := bitTag.Data
+	cached.DecodeResult(data)
+	r := cached.DecodeResult(data)
+	w.ShouldBeEqual(r.URI, bitTag.URI)
+	w.ShouldBeEqual(r.Scheme, bitTag.Scheme)
+	w.ShouldBeEqual(r.Warnings, bitTag.Warnings)
+	w.ShouldBeEqual(r.Value.(bittag.BitTag).URI(), bitTag.URI)
+}
+
+func TestURIScheme(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for uri, scheme := range map[string]string{
+		"urn:epc:id:sgtin:0614141.812345.6789":   "sgtin",
+		"urn:epc:id:sscc:0614141.1234567890":     "sscc",
+		"tag:test.com,2019-01-01:15.12.5330":     "tag",
+		"urn:epc:tag:sgtin-96:1.0614141.812345.1": "",
+		"urn:epc:id:":                            "",
+		"":                                       "",
+	} {
+		w.As(uri).ShouldBeEqual(uriScheme(uri), scheme)
+	}
+}
@@ -0,0 +1,272 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Command tagcode converts tag data between its representations, so that tags
+// can be checked on site. Its subcommands are:
+//     decode   hex tag data or an EPC URI -> all of its representations
+//     encode   an EPC URI, or a GTIN and serial -> hex tag data
+//     explain  hex SGTIN data or URI -> the bit-level layout of its fields
+//
+// Each takes its inputs as arguments, or if there are none, one per line from
+// stdin. GTINs and serials are given as GS1 element strings, such as
+// (01)80614141123458(21)6789, or Digital Link URIs, and require the
+// -prefix-length flag. Run "tagcode <subcommand> -h" for its flags.
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+const usage = `usage: tagcode <decode|encode|explain> [flags] [input...]
+
+Inputs are read one per line from stdin if none are given.
+Run "tagcode <subcommand> -h" for the subcommand's flags.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// decoderList is a flag.Value collecting repeated -decoder flags.
+type decoderList []string
+
+func (dl *decoderList) String() string {
+	return strings.Join(*dl, " ")
+}
+
+func (dl *decoderList) Set(s string) error {
+	*dl = append(*dl, s)
+	return nil
+}
+
+// command is a subcommand, which processes its inputs one at a time.
+type command struct {
+	flags     *flag.FlagSet
+	prefixLen int
+	filter    int
+	bits      int
+	decoders  decoderList
+	chain     tagcode.DecoderChain
+	process   func(c *command, input string, out io.Writer) error
+}
+
+// parseOptions returns the epc.ParseOptions set by the command's flags.
+func (c *command) parseOptions() []epc.ParseOption {
+	opts := []epc.ParseOption{epc.WithGTINFilter(epc.FilterValue(c.filter))}
+	if c.prefixLen > 0 {
+		opts = append(opts, epc.WithPrefixLengths(epc.FixedPrefixLength(c.prefixLen)))
+	}
+	return opts
+}
+
+// run runs the subcommand named by args[0] with the rest of args, and returns
+// the process's exit code: 0 on success, 1 if any input fails, and 2 for usage
+// errors.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	c := &command{flags: flag.NewFlagSet("tagcode "+args[0], flag.ContinueOnError)}
+	c.flags.SetOutput(stderr)
+	c.flags.IntVar(&c.prefixLen, "prefix-length", 0,
+		"the GS1 Company Prefix `length` of GTINs in element strings and Digital Link URIs")
+	switch args[0] {
+	case "decode":
+		c.process = decode
+		c.flags.Var(&c.decoders, "decoder", "a tagcode.DecoderChain config `entry` for "+
+			"data that isn't an EPC, such as bittag:example.com,2019-01-01:8.48.40; "+
+			"may be repeated")
+	case "encode":
+		c.process = encode
+		c.flags.IntVar(&c.filter, "filter", 0, "the filter `value` of SGTINs from GTINs")
+		c.flags.IntVar(&c.bits, "bits", 0, "the bit `length` of the encoding, such as "+
+			"96 or 198; by default, the shortest that can hold the EPC")
+	case "explain":
+		c.process = explain
+		c.flags.IntVar(&c.filter, "filter", 0, "the filter `value` of SGTINs from GTINs")
+		c.flags.IntVar(&c.bits, "bits", 0, "the bit `length` of the encoding of URIs "+
+			"and GTINs, such as 96 or 198; by default, a Tag URI's, or the shortest "+
+			"that can hold the EPC. Hex data is explained as it's encoded")
+	default:
+		fmt.Fprintf(stderr, "unknown subcommand %q\n%s", args[0], usage)
+		return 2
+	}
+	if err := c.flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if len(c.decoders) > 0 {
+		var err error
+		if c.chain, err = tagcode.NewDecoderChain(c.decoders); err != nil {
+			fmt.Fprintf(stderr, "invalid -decoder: %v\n", err)
+			return 2
+		}
+	}
+
+	inputs := c.flags.Args()
+	if len(inputs) == 0 {
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				inputs = append(inputs, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			fmt.Fprintf(stderr, "unable to read stdin: %v\n", err)
+			return 1
+		}
+	}
+
+	code := 0
+	for i, input := range inputs {
+		if i > 0 && args[0] != "encode" {
+			fmt.Fprintln(stdout)
+		}
+		if err := c.process(c, input, stdout); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", input, err)
+			code = 1
+		}
+	}
+	return code
+}
+
+// inputBitLength returns the bit length of the encoding an input explicitly
+// gives, for hex data and Tag URIs, or 0 otherwise.
+func inputBitLength(input string) int {
+	input = strings.TrimSpace(input)
+	if strings.HasPrefix(input, epc.TagURIPrefix) {
+		if _, bits, err := epc.ParseTagURI(input); err == nil {
+			return bits
+		}
+		return 0
+	}
+	if b, ok := decodeHex(input); ok && len(b) > 0 {
+		_, bits, _ := epc.SchemeForHeader(b[0])
+		return bits
+	}
+	return 0
+}
+
+// decodeHex decodes hex input, optionally prefixed with "0x".
+func decodeHex(input string) ([]byte, bool) {
+	input = strings.TrimPrefix(strings.TrimPrefix(input, "0x"), "0X")
+	b, err := hex.DecodeString(input)
+	return b, err == nil
+}
+
+// decode writes all the representations of an EPC, or the URI of other data
+// decoded by the command's -decoder chain.
+func decode(c *command, input string, out io.Writer) error {
+	e, err := epc.ParseAny(input, c.parseOptions()...)
+	if err != nil {
+		data, isHex := decodeHex(input)
+		if !isHex || len(c.decoders) == 0 {
+			return err
+		}
+		uri, name, chainErr := c.chain.Decode(data)
+		if chainErr != nil {
+			return errors.Wrapf(chainErr, "not an EPC (%v), and", err)
+		}
+		tw := tabwriter.NewWriter(out, 0, 4, 1, ' ', 0)
+		fmt.Fprintf(tw, "input:\t%s\n", input)
+		fmt.Fprintf(tw, "decoder:\t%s\n", name)
+		fmt.Fprintf(tw, "uri:\t%s\n", uri)
+		return tw.Flush()
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 4, 1, ' ', 0)
+	fmt.Fprintf(tw, "input:\t%s\n", input)
+	fmt.Fprintf(tw, "scheme:\t%s\n", e.Scheme())
+	fmt.Fprintf(tw, "pure identity uri:\t%s\n", e.URI())
+	rangeErr := e.ValidateRanges()
+	if rangeErr == nil {
+		bits := inputBitLength(input)
+		if tagURI, err := epc.TagURI(e, bits); err == nil {
+			fmt.Fprintf(tw, "tag uri:\t%s\n", tagURI)
+		}
+		if b, err := epc.EncodeEPC(e, bits); err == nil {
+			fmt.Fprintf(tw, "hex:\t%X\n", b)
+		}
+	}
+	switch v := e.(type) {
+	case epc.SGTIN:
+		fmt.Fprintf(tw, "gtin:\t%s\n", v.GTIN())
+		fmt.Fprintf(tw, "serial:\t%s\n", v.Serial())
+	case epc.SSCC:
+		fmt.Fprintf(tw, "sscc:\t%s\n", v.SSCC())
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	return errors.Wrap(rangeErr, "invalid EPC")
+}
+
+// encode writes the hex encoding of an EPC.
+func encode(c *command, input string, out io.Writer) error {
+	e, err := epc.ParseAny(input, c.parseOptions()...)
+	if err != nil {
+		return err
+	}
+	if err := e.ValidateRanges(); err != nil {
+		return errors.Wrap(err, "invalid EPC")
+	}
+	b, err := epc.EncodeEPC(e, c.bits)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%X\n", b)
+	return err
+}
+
+// explain writes the bit-level layout of an SGTIN's encoding: that of the input
+// if it's hex data, or else the one with the command's -bits, or the bit length
+// its input gives, as decode uses.
+func explain(c *command, input string, out io.Writer) error {
+	e, err := epc.ParseAny(input, c.parseOptions()...)
+	if err != nil {
+		return err
+	}
+	if _, ok := e.(epc.SGTIN); !ok {
+		return errors.Errorf("explain only supports SGTINs, but this is %s",
+			e.Scheme())
+	}
+	if err := e.ValidateRanges(); err != nil {
+		return errors.Wrap(err, "invalid EPC")
+	}
+	b, ok := decodeHex(strings.TrimSpace(input))
+	if !ok {
+		bits := c.bits
+		if bits == 0 {
+			bits = inputBitLength(input)
+		}
+		if b, err = epc.EncodeEPC(e, bits); err != nil {
+			return err
+		}
+	}
+	fields, err := epc.DescribeSGTIN(b)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "BITS\tFIELD\tVALUE\tBINARY\n")
+	for _, f := range fields {
+		fmt.Fprintf(tw, "%d-%d\t%s\t%s\t%s\n", f.StartBit,
+			f.StartBit+f.BitLength-1, f.Name, f.Value, f.Bits)
+	}
+	return tw.Flush()
+}
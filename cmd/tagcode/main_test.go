@@ -0,0 +1,145 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bytes"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"strings"
+	"testing"
+)
+
+// runWith runs the command with the given arguments and stdin, and returns its
+// exit code, stdout, and stderr.
+func runWith(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_decode(t *testing.T) {
+	w := expect.WrapT(t)
+
+	code, out, _ := runWith("", "decode", "3034257BF7194E4000001A85")
+	w.ShouldBeEqual(code, 0)
+	w.ShouldBeEqual(out, ""+
+		"input:             3034257BF7194E4000001A85\n"+
+		"scheme:            sgtin\n"+
+		"pure identity uri: urn:epc:id:sgtin:0614141.812345.6789\n"+
+		"tag uri:           urn:epc:tag:sgtin-96:1.0614141.812345.6789\n"+
+		"hex:               3034257BF7194E4000001A85\n"+
+		"gtin:              80614141123458\n"+
+		"serial:            6789\n")
+
+	// SGTIN-198 keeps its encoding, even though the serial fits SGTIN-96
+	code, out, _ = runWith("", "decode", "urn:epc:tag:sgtin-198:1.0614141.812345.6789")
+	w.ShouldBeEqual(code, 0)
+	w.ShouldContainStr(out, "hex:               3634257BF7194E5B3770E40000000000000000000000000000\n")
+
+	code, out, _ = runWith("urn:epc:id:sscc:0614141.1234567890\n\n0x3034257BF7194E4000001A85\n", "decode")
+	w.ShouldBeEqual(code, 0)
+	w.ShouldContainStr(out, "sscc:              106141412345678908\n\ninput:")
+	w.ShouldContainStr(out, "gtin:              80614141123458\n")
+
+	code, out, _ = runWith("", "decode", "-prefix-length", "7", "(01)80614141123458(21)6789")
+	w.ShouldBeEqual(code, 0)
+	w.ShouldContainStr(out, "pure identity uri: urn:epc:id:sgtin:0614141.812345.6789\n")
+
+	code, out, _ = runWith("", "decode",
+		"-decoder", "sgtin", "-decoder", "bittag:test.com,2019-01-01:8.48.40",
+		"0F00000000000C00000014D2")
+	w.ShouldBeEqual(code, 0)
+	w.ShouldBeEqual(out, ""+
+		"input:   0F00000000000C00000014D2\n"+
+		"decoder: bittag:test.com,2019-01-01:8.48.40\n"+
+		"uri:     tag:test.com,2019-01-01:15.12.5330\n")
+
+	// out of range values are shown, but fail
+	code, out, errOut := runWith("", "decode", "301000181C7FFFD3A8B43711")
+	w.ShouldBeEqual(code, 1)
+	w.ShouldContainStr(out, "scheme:            sgtin\n")
+	w.ShouldContainStr(errOut, "invalid EPC")
+
+	code, _, errOut = runWith("", "decode", "0F00000000000C00000014D2", "not hex")
+	w.ShouldBeEqual(code, 1)
+	w.ShouldContainStr(errOut, "0F00000000000C00000014D2: ")
+	w.ShouldContainStr(errOut, "not hex: ")
+
+	code, _, _ = runWith("", "decode", "-decoder", "bittag:nope", "00")
+	w.ShouldBeEqual(code, 2)
+}
+
+func TestRun_encode(t *testing.T) {
+	w := expect.WrapT(t)
+
+	code, out, _ := runWith("", "encode", "-prefix-length", "7", "-filter", "1",
+		"(01)80614141123458(21)6789", "https://id.gs1.org/01/80614141123458/21/6789")
+	w.ShouldBeEqual(code, 0)
+	w.ShouldBeEqual(out, "3034257BF7194E4000001A85\n3034257BF7194E4000001A85\n")
+
+	code, out, _ = runWith("urn:epc:id:sgtin:0614141.812345.6789\n", "encode", "-bits", "198")
+	w.ShouldBeEqual(code, 0)
+	w.ShouldBeEqual(out, "3614257BF7194E5B3770E40000000000000000000000000000\n")
+
+	code, _, _ = runWith("", "encode", "(01)80614141123458(21)6789")
+	w.ShouldBeEqual(code, 1)
+	code, _, _ = runWith("", "encode", "-bits", "64", "urn:epc:id:sgtin:0614141.812345.6789")
+	w.ShouldBeEqual(code, 1)
+}
+
+func TestRun_explain(t *testing.T) {
+	w := expect.WrapT(t)
+
+	code, out, _ := runWith("", "explain", "3034257BF7194E4000001A85")
+	w.ShouldBeEqual(code, 0)
+	w.ShouldBeEqual(out, ""+
+		"BITS   FIELD                     VALUE    BINARY\n"+
+		"0-7    Header                    0X30     00110000\n"+
+		"8-10   Filter                    1        001\n"+
+		"11-13  Partition                 5        101\n"+
+		"14-37  GS1 Company Prefix        0614141  000010010101111011111101\n"+
+		"38-57  Indicator/Item Reference  812345   11000110010100111001\n"+
+		"58-95  Serial                    6789     00000000000000000000000001101010000101\n")
+
+	// hex data is explained as it's encoded, even if SGTIN-96 could hold it
+	sgtin198 := "" +
+		"BITS    FIELD                     VALUE    BINARY\n" +
+		"0-7     Header                    0X36     00110110\n" +
+		"8-10    Filter                    1        001\n" +
+		"11-13   Partition                 5        101\n" +
+		"14-37   GS1 Company Prefix        0614141  000010010101111011111101\n" +
+		"38-57   Indicator/Item Reference  812345   11000110010100111001\n" +
+		"58-197  Serial                    6789     0110110011011101110000111001" +
+		strings.Repeat("0", 112) + "\n"
+	code, out, _ = runWith("", "explain", "3634257BF7194E5B3770E40000000000000000000000000000")
+	w.ShouldBeEqual(code, 0)
+	w.ShouldBeEqual(out, sgtin198)
+	// as are Tag URIs, and other URIs with -bits
+	code, out, _ = runWith("", "explain", "urn:epc:tag:sgtin-198:1.0614141.812345.6789")
+	w.ShouldBeEqual(code, 0)
+	w.ShouldBeEqual(out, sgtin198)
+	code, out, _ = runWith("", "explain", "-bits", "198", "-filter", "1",
+		"-prefix-length", "7", "(01)80614141123458(21)6789")
+	w.ShouldBeEqual(code, 0)
+	w.ShouldBeEqual(out, sgtin198)
+	code, _, _ = runWith("", "explain", "-bits", "64", "urn:epc:id:sgtin:0614141.812345.6789")
+	w.ShouldBeEqual(code, 1)
+
+	code, _, errOut := runWith("", "explain", "urn:epc:id:sscc:0614141.1234567890")
+	w.ShouldBeEqual(code, 1)
+	w.ShouldContainStr(errOut, "only supports SGTINs")
+}
+
+func TestRun_usage(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for _, args := range [][]string{nil, {"unknown"}, {"encode", "-unknown"}} {
+		code, _, errOut := runWith("", args...)
+		w.As(args).ShouldBeEqual(code, 2)
+		w.As(args).ShouldContainStr(strings.ToLower(errOut), "usage")
+	}
+}
@@ -0,0 +1,81 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+)
+
+// DecodedTag describes a decoded tag for components outside this module. It
+// mirrors the DecodedTag message of proto/tagcode.proto, and its JSON encoding
+// matches that message's proto3 JSON mapping, so it can be served to them
+// before code is generated from the schema.
+type DecodedTag struct {
+	Raw      []byte   `json:"raw,omitempty"`
+	Scheme   string   `json:"scheme,omitempty"`
+	URI      string   `json:"uri,omitempty"`
+	Decoder  string   `json:"decoder,omitempty"`
+	GTIN     string   `json:"gtin,omitempty"`
+	Fields   []Field  `json:"fields,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Field is one field of a tag's binary encoding, mirroring the Field message of
+// proto/tagcode.proto.
+type Field struct {
+	Name      string `json:"name,omitempty"`
+	Value     string `json:"value,omitempty"`
+	StartBit  int    `json:"startBit,omitempty"`
+	BitLength int    `json:"bitLength,omitempty"`
+}
+
+// NewDecodedTag returns the DecodedTag of a Result. Its Scheme is the Result's,
+// or if that's not set, the scheme of its URI. For SGTINs, it has the GTIN, and
+// if the Result's Data is an SGTIN-96 or SGTIN-198, the fields of that encoding.
+func NewDecodedTag(r Result) DecodedTag {
+	dt := DecodedTag{Raw: r.Data, URI: r.URI, Decoder: r.Decoder, Warnings: r.Warnings}
+	if r.Err != nil {
+		dt.Error = r.Err.Error()
+		return dt
+	}
+	dt.Scheme = r.Scheme
+	if dt.Scheme == "" {
+		dt.Scheme = uriScheme(r.URI)
+	}
+	if dt.Scheme != "sgtin" {
+		return dt
+	}
+
+	s, ok := r.Value.(epc.SGTIN)
+	if !ok {
+		// decode it again, or parse its URI, for its GTIN
+		var err error
+		if s, err = epc.DecodeSGTIN(r.Data); err != nil {
+			e, err := epc.ParsePureIdentityURI(r.URI)
+			if err != nil {
+				return dt
+			}
+			s = e.(epc.SGTIN)
+		}
+	}
+	dt.GTIN = s.GTIN()
+	fields, err := epc.DescribeSGTIN(r.Data)
+	if err != nil {
+		return dt
+	}
+	dt.Fields = make([]Field, len(fields))
+	for i, f := range fields {
+		dt.Fields[i] = Field{
+			Name:      f.Name,
+			Value:     f.Value,
+			StartBit:  f.StartBit,
+			BitLength: f.BitLength,
+		}
+	}
+	return dt
+}
@@ -0,0 +1,61 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestNewDecodedTag(t *testing.T) {
+	w := expect.WrapT(t)
+
+	chain := w.ShouldHaveResult(NewDecoderChain([]string{
+		"epc", "bittag:test.com,2019-01-01:8.48.40"})).(DecoderChain)
+	decode := func(data string) DecodedTag {
+		b := w.ShouldHaveResult(hex.DecodeString(data)).([]byte)
+		return NewDecodedTag(DecodeBatch(chain, [][]byte{b})[0])
+	}
+
+	sgtin := decode("3034257BF7194E4000001A85")
+	w.ShouldBeEqual(sgtin.Scheme, "sgtin")
+	w.ShouldBeEqual(sgtin.URI, "urn:epc:id:sgtin:0614141.812345.6789")
+	w.ShouldBeEqual(sgtin.Decoder, "epc")
+	w.ShouldBeEqual(sgtin.GTIN, "80614141123458")
+	w.ShouldBeEqual(len(sgtin.Fields), 6)
+	w.ShouldBeEqual(sgtin.Fields[1], Field{Name: "Filter", Value: "1", StartBit: 8, BitLength: 3})
+
+	// the fields are those of the data's encoding, not the one Encode chooses
+	sgtin198 := decode("3634257BF7194E5B3770E40000000000000000000000000000")
+	w.ShouldBeEqual(sgtin198.GTIN, "80614141123458")
+	w.ShouldBeEqual(len(sgtin198.Fields), 6)
+	w.ShouldBeEqual(sgtin198.Fields[0].Value, "0X36")
+	w.ShouldBeEqual(sgtin198.Fields[5].BitLength, 140)
+
+	sscc := decode("3134257BF4499602D2000000")
+	w.ShouldBeEqual(sscc.Scheme, "sscc")
+	w.ShouldBeEqual(sscc.GTIN, "")
+
+	warned := decode("301000181C7FFFD3A8B43711")
+	w.ShouldBeEqual(warned.Scheme, "tag")
+	w.ShouldBeEqual(len(warned.Warnings), 1)
+
+	bitTag := decode("0F00000000000C00000014D2")
+	w.ShouldBeEqual(bitTag.Scheme, "tag")
+	w.ShouldBeEqual(bitTag.URI, "tag:test.com,2019-01-01:15.12.5330")
+
+	failed := decode("0F00")
+	w.ShouldBeEqual(failed.URI, "")
+	w.ShouldContainStr(failed.Error, "no decoder")
+
+	// the JSON matches the proto3 JSON mapping of the schema
+	data := w.ShouldHaveResult(json.Marshal(sscc)).([]byte)
+	w.ShouldBeEqual(string(data), `{"raw":"MTQle/RJlgLSAAAA","scheme":"sscc",`+
+		`"uri":"urn:epc:id:sscc:0614141.1234567890","decoder":"epc"}`)
+}
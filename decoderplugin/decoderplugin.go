@@ -0,0 +1,83 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package decoderplugin loads customer-specific tag decoders from Go plugins at
+// deployment time, so they can be added to decoder chains without rebuilding
+// the services that use them. It's separate from package tagcode since
+// importing the standard library's plugin package affects how programs are
+// linked; programs that don't load plugins needn't import it.
+//
+// A plugin is a main package built with "go build -buildmode=plugin" that
+// exports the namespace of its decoders and a function returning them:
+//     var Namespace = "acme"
+//
+//     func Decoders() []tagcode.Registration {
+//         return []tagcode.Registration{
+//             {Name: "gen2", Decoder: gen2Decoder{}, Priority: 10},
+//         }
+//     }
+//
+// As with all Go plugins, it must be built with the same version of Go and of
+// this module as the program that loads it.
+package decoderplugin
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode"
+	"github.com/pkg/errors"
+	"plugin"
+)
+
+// Load opens the Go plugin at path and registers its decoders under its
+// namespace with tagcode.RegisterDecoders, and returns their full names, such
+// as "acme/gen2". It returns an error if the plugin can't be opened, doesn't
+// export Namespace and Decoders as described in the package documentation, or
+// its decoders can't be registered, in which case none of them are.
+func Load(path string) ([]string, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open decoder plugin %q", path)
+	}
+	names, err := register(p.Lookup)
+	return names, errors.Wrapf(err, "invalid decoder plugin %q", path)
+}
+
+// LoadAll loads each of the plugins at the given paths, in order, and returns
+// the full names of all their decoders. It stops at the first that fails.
+func LoadAll(paths []string) ([]string, error) {
+	var all []string
+	for _, path := range paths {
+		names, err := Load(path)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, names...)
+	}
+	return all, nil
+}
+
+// register registers the decoders of a plugin whose symbols are found with
+// lookup.
+func register(lookup func(string) (plugin.Symbol, error)) ([]string, error) {
+	nsSym, err := lookup("Namespace")
+	if err != nil {
+		return nil, err
+	}
+	namespace, ok := nsSym.(*string)
+	if !ok {
+		return nil, errors.Errorf("Namespace is a %T, not a string", nsSym)
+	}
+
+	decodersSym, err := lookup("Decoders")
+	if err != nil {
+		return nil, err
+	}
+	decoders, ok := decodersSym.(func() []tagcode.Registration)
+	if !ok {
+		return nil, errors.Errorf("Decoders is a %T, not a "+
+			"func() []tagcode.Registration", decodersSym)
+	}
+	return tagcode.RegisterDecoders(*namespace, decoders())
+}
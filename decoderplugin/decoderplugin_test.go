@@ -0,0 +1,89 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package decoderplugin
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode"
+	"github.com/pkg/errors"
+	"plugin"
+	"sync"
+	"testing"
+)
+
+// testDecoder is a TagDecoder a test plugin provides.
+type testDecoder string
+
+func (d testDecoder) Decode(data []byte) (string, error) {
+	return "urn:example:" + string(d), nil
+}
+
+func (d testDecoder) CanDecode(data []byte) bool {
+	return len(data) > 0 && data[0] == d[0]
+}
+
+// symbols returns a lookup function for a plugin with the given symbols.
+func symbols(syms map[string]plugin.Symbol) func(string) (plugin.Symbol, error) {
+	return func(name string) (plugin.Symbol, error) {
+		if sym, ok := syms[name]; ok {
+			return sym, nil
+		}
+		return nil, errors.Errorf("symbol %s not found", name)
+	}
+}
+
+// registerTestPlugin registers the test plugin's decoders once, even if tests
+// are repeated.
+var registerTestPlugin sync.Once
+
+func TestRegister(t *testing.T) {
+	w := expect.WrapT(t)
+
+	namespace := "test-plugin"
+	decoders := func() []tagcode.Registration {
+		return []tagcode.Registration{
+			{Name: "a", Decoder: testDecoder("a")},
+			{Name: "b", Decoder: testDecoder("b"), Priority: 1},
+		}
+	}
+	registerTestPlugin.Do(func() {
+		names := w.ShouldHaveResult(register(symbols(map[string]plugin.Symbol{
+			"Namespace": &namespace,
+			"Decoders":  decoders,
+		}))).([]string)
+		w.ShouldBeEqual(names, []string{"test-plugin/a", "test-plugin/b"})
+	})
+
+	chain := w.ShouldHaveResult(tagcode.NewDecoderChain(
+		[]string{"sgtin", "test-plugin/*"})).(tagcode.DecoderChain)
+	w.ShouldBeEqual(chain.Names(), []string{"sgtin", "test-plugin/b", "test-plugin/a"})
+	uri, name, err := chain.Decode([]byte("a"))
+	w.ShouldSucceed(err)
+	w.ShouldBeEqual(uri, "urn:example:a")
+	w.ShouldBeEqual(name, "test-plugin/a")
+
+	// registering them again fails
+	w.ShouldHaveError(register(symbols(map[string]plugin.Symbol{
+		"Namespace": &namespace,
+		"Decoders":  decoders,
+	})))
+
+	other := "other"
+	for _, syms := range []map[string]plugin.Symbol{
+		{},
+		{"Namespace": &other},
+		{"Namespace": other, "Decoders": decoders},
+		{"Namespace": &other, "Decoders": func() []tagcode.TagDecoder { return nil }},
+	} {
+		w.As(syms).ShouldHaveError(register(symbols(syms)))
+	}
+	_, ok := tagcode.Lookup("other/a")
+	w.ShouldBeFalse(ok)
+
+	w.ShouldHaveError(Load("testdata/missing.so"))
+	w.ShouldHaveError(LoadAll([]string{"testdata/missing.so"}))
+}
@@ -8,22 +8,11 @@ package epc
 
 import (
 	"fmt"
-	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"github.com/pkg/errors"
 	"strings"
 )
 
 var (
-	asciiExtracts = []bitextract.BitExtractor{
-		bitextract.New(0, 7),
-		bitextract.New(7, 7),
-		bitextract.New(6, 7),
-		bitextract.New(5, 7),
-		bitextract.New(4, 7),
-		bitextract.New(3, 7),
-		bitextract.New(2, 7),
-		bitextract.New(1, 7),
-	}
-
 	gs1Escaper = strings.NewReplacer(
 		`"`, "%22",
 		`#`, "%23",
@@ -105,12 +94,22 @@ func DecodeASCIIAt(data []byte, offset int) (out string, nullTerm int, extra boo
 	}
 
 	nullTerm = -1
-	ext := (8 - offset) % 8
 	outdata := make([]byte, outbyteLen)
+
+	// Rather than extracting each character separately, shift the input into
+	// a word a byte at a time, and take characters from the top of the bits
+	// that haven't been used yet; the bits above those are shifted out.
+	acc := uint64(data[0] & (0xFF >> uint(offset)))
+	nbits := uint(8 - offset)
+	next := 1
 	for i := 0; i < len(outdata); i++ {
-		inbyte := i - ((i + 7 - offset) / 8)
-		asciiExtracts[ext%8].ExtractTo(outdata[i:], data[inbyte:])
-		ext++
+		if nbits < 7 {
+			acc = acc<<8 | uint64(data[next])
+			next++
+			nbits += 8
+		}
+		nbits -= 7
+		outdata[i] = byte(acc>>nbits) & 0x7F
 
 		if outdata[i] == nullASCII {
 			if nullTerm == -1 {
@@ -127,6 +126,94 @@ func DecodeASCIIAt(data []byte, offset int) (out string, nullTerm int, extra boo
 	return
 }
 
+// encodeASCIIAt packs the characters of s as 7-bit ISO-646 values into dst,
+// starting at the given bit. It's the inverse of DecodeASCIIAt: characters are
+// written consecutively, and any space remaining in dst is left unchanged.
+func encodeASCIIAt(dst []byte, start int, s string) {
+	for i := 0; i < len(s); i++ {
+		putBits(dst, start+(i*7), 7, uint64(s[i]&0x7F))
+	}
+}
+
+// Decode6BitAt decodes packed 6-bit characters, as used by ADI EPCs, US DoD
+// constructs, and ISO/IEC 15434 payloads, starting at the given bit offset, as
+// DecodeASCIIAt does for 7-bit ASCII. Each character is the least significant 6
+// bits of an ASCII character from 0x20 (space) to 0x5F ('_'), so the values
+// 000000b-011111b are '@' through '_', and 100000b-111111b are ' ' through '?';
+// that covers uppercase letters, digits, and some punctuation.
+//
+// Since 6-bit strings, such as ADI's, are terminated by a 000000b character,
+// the returned values are as for DecodeASCIIAt, with '@' in place of a null
+// byte: the string, which includes any terminator and characters after it, the
+// number of characters before the first terminator, and whether there are any
+// non-terminator characters after it.
+//
+// If the incoming data isn't a multiple of 6 bits, the final bits are ignored,
+// and the string will have floor((len(data)*8-offset)/6) characters.
+//
+// The function panics if the offset isn't in [0, 7].
+func Decode6BitAt(data []byte, offset int) (out string, nullTerm int, extra bool) {
+	if offset < 0 || offset > 7 {
+		panic(fmt.Errorf("invalid offset %d", offset))
+	}
+
+	outbyteLen := ((len(data) * 8) - offset) / 6
+	if outbyteLen <= 0 {
+		return "", 0, false
+	}
+
+	nullTerm = -1
+	outdata := make([]byte, outbyteLen)
+	acc := uint64(data[0] & (0xFF >> uint(offset)))
+	nbits := uint(8 - offset)
+	next := 1
+	for i := 0; i < len(outdata); i++ {
+		if nbits < 6 {
+			acc = acc<<8 | uint64(data[next])
+			next++
+			nbits += 8
+		}
+		nbits -= 6
+		c := byte(acc>>nbits) & 0x3F
+
+		if c == 0 {
+			if nullTerm == -1 {
+				nullTerm = i
+			}
+		} else if nullTerm != -1 {
+			extra = true
+		}
+		if c&0x20 == 0 {
+			c |= 0x40
+		}
+		outdata[i] = c
+	}
+	out = string(outdata)
+	if nullTerm == -1 {
+		nullTerm = len(out)
+	}
+	return
+}
+
+// Encode6Bit packs the characters of s as 6-bit values, as Decode6BitAt
+// decodes them, padding the last byte with 0 bits. It doesn't add a 000000b
+// terminator; callers encoding terminated strings, such as ADI's, can append
+// '@' to s. It returns an error if s has characters outside 0x20 to 0x5F,
+// such as lowercase letters.
+func Encode6Bit(s string) ([]byte, error) {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x5F {
+			return nil, errors.Errorf("%q at index %d can't be encoded as a "+
+				"6-bit character", s[i], i)
+		}
+	}
+	b := make([]byte, (len(s)*6+7)/8)
+	for i := 0; i < len(s); i++ {
+		putBits(b, i*6, 6, uint64(s[i]&0x3F))
+	}
+	return b, nil
+}
+
 // EscapeGS1 returns s with the following characters replaced by their GS1
 // escape sequences:
 // - `"` -> "%22"
@@ -145,6 +232,21 @@ func EscapeGS1(s string) string {
 	return gs1Escaper.Replace(s)
 }
 
+// appendGS1Escaped appends s to dst, escaped as EscapeGS1 does.
+func appendGS1Escaped(dst []byte, s string) []byte {
+	const hexDigits = "0123456789ABCDEF"
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '#', '%', '&', '/', '<', '>', '?':
+			dst = append(dst, '%', hexDigits[c>>4], hexDigits[c&0x0F])
+		case 0:
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
 // UnescapeGS1 returns s with the following escape sequences replaced by their
 // GS1 character equivalents.
 // - `"` -> "%22"
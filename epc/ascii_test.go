@@ -295,3 +295,84 @@ func TestIsGS1CompPartEncable(t *testing.T) {
 		})
 	}
 }
+
+// getSixBit returns s as 6-bit characters, preceded by offset 1 bits and padded
+// with 0s to a whole byte, as getASCII does for 7-bit characters.
+func getSixBit(s string, offset int) string {
+	bitStr := "1111111"[7-offset:]
+	for i := 0; i < len(s); i++ {
+		bitStr += fmt.Sprintf("%06b", s[i]&0x3F)
+	}
+	if len(bitStr)%8 != 0 {
+		bitStr += "00000000"[len(bitStr)%8:]
+	}
+	b := make([]byte, len(bitStr)/8)
+	for i := range b {
+		for _, c := range bitStr[i*8 : i*8+8] {
+			b[i] = b[i]<<1 | byte(c-'0')
+		}
+	}
+	return string(b)
+}
+
+func TestDecode6BitAt(t *testing.T) {
+	w := expect.WrapT(t)
+
+	out, n, extra := Decode6BitAt([]byte{0x20, 0x90}, 0)
+	w.ShouldBeEqual(out, "HI")
+	w.ShouldBeEqual(n, 2)
+	w.ShouldBeFalse(extra)
+	out, _, _ = Decode6BitAt([]byte{0x07, 0x1B, 0x42}, 0)
+	w.ShouldBeEqual(out, "A1-B")
+
+	for _, s := range []string{
+		"A", "AB", "ABC", "ABCD", "ABCDE", "0123456789", " !\"#$%&'()*+,-./:;<=>?",
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_", "1234ABCD-/#",
+	} {
+		for offset := 0; offset < 8; offset++ {
+			w := w.As(fmt.Sprintf("%d_%q", offset, s))
+			data := []byte(getSixBit(s, offset))
+			out, n, extra := Decode6BitAt(data, offset)
+			w.ShouldBeEqual(out[:n], s)
+			w.ShouldBeFalse(extra)
+			if offset == 0 {
+				w.ShouldBeEqual(w.ShouldHaveResult(Encode6Bit(s)), data)
+			}
+		}
+	}
+
+	// ADI-style terminators
+	out, n, extra = Decode6BitAt([]byte(getSixBit("ABC@", 2)), 2)
+	// the padding after the terminator is another 0 character
+	w.ShouldBeEqual(out, "ABC@@")
+	w.ShouldBeEqual(n, 3)
+	w.ShouldBeFalse(extra)
+	out, n, extra = Decode6BitAt([]byte(getSixBit("AB@C", 0)), 0)
+	w.ShouldBeEqual(out, "AB@C")
+	w.ShouldBeEqual(n, 2)
+	w.ShouldBeTrue(extra)
+
+	out, n, extra = Decode6BitAt(nil, 0)
+	w.ShouldBeEqual(out, "")
+	w.ShouldBeEqual(n, 0)
+	w.ShouldBeFalse(extra)
+	out, _, _ = Decode6BitAt([]byte{0xFF}, 3)
+	w.ShouldBeEqual(out, "")
+
+	panicked := func() (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		Decode6BitAt([]byte{0}, 8)
+		return
+	}()
+	w.ShouldBeTrue(panicked)
+}
+
+func TestEncode6Bit(t *testing.T) {
+	w := expect.WrapT(t)
+
+	w.ShouldBeEqual(w.ShouldHaveResult(Encode6Bit("HI")), []byte{0x20, 0x90})
+	w.ShouldBeEqual(w.ShouldHaveResult(Encode6Bit("")), []byte{})
+	for _, s := range []string{"abc", "A\x00", "\x7F", "Ä"} {
+		w.As(s).ShouldHaveError(Encode6Bit(s))
+	}
+}
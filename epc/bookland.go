@@ -0,0 +1,253 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+// BooklandKind identifies the bibliographic identifier, if any, from which a
+// GTIN was produced. GS1 reserves several prefixes (collectively known as
+// "Bookland") for GTINs that are formed from other numbering systems:
+//
+//   - 978 and 979 hold ISBNs (International Standard Book Numbers), except for
+//     9790, which holds ISMNs (International Standard Music Numbers)
+//   - 977 holds ISSNs (International Standard Serial Numbers) of periodicals
+//
+// Like all GTINs, these may be encoded as SGTINs.
+type BooklandKind int
+
+const (
+	NotBookland = BooklandKind(iota)
+	BooklandISBN
+	BooklandISSN
+	BooklandISMN
+)
+
+func (bk BooklandKind) String() string {
+	switch bk {
+	case NotBookland:
+		return "Not Bookland"
+	case BooklandISBN:
+		return "ISBN"
+	case BooklandISSN:
+		return "ISSN"
+	case BooklandISMN:
+		return "ISMN"
+	}
+	return "Unknown Bookland kind: " + strconv.Itoa(int(bk))
+}
+
+// Bookland returns the kind of bibliographic identifier from which the SGTIN's
+// GTIN was produced, if any. Like Classify, this ignores the indicator digit.
+func (s SGTIN) Bookland() BooklandKind {
+	gtin := s.GTIN()
+	if len(gtin) != 14 {
+		return NotBookland
+	}
+	switch {
+	case gtin[1:5] == "9790":
+		return BooklandISMN
+	case gtin[1:4] == "978", gtin[1:4] == "979":
+		return BooklandISBN
+	case gtin[1:4] == "977":
+		return BooklandISSN
+	}
+	return NotBookland
+}
+
+// ISBN returns the 13 digit ISBN from which the SGTIN's GTIN was produced, or an
+// error if it isn't an ISBN or has a non-zero indicator digit. The ISBN-13 is
+// identical to the GTIN-13.
+func (s SGTIN) ISBN() (string, error) {
+	return s.bookland(BooklandISBN)
+}
+
+// ISBN10 returns the 10 character ISBN from which the SGTIN's GTIN was produced,
+// or an error if it isn't an ISBN or has no 10 character form. Only ISBNs with
+// prefix 978 have a 10 character form, which drops that prefix and replaces the
+// check digit with a mod 11 check character (see ISBN10CheckChar).
+func (s SGTIN) ISBN10() (string, error) {
+	isbn, err := s.ISBN()
+	if err != nil {
+		return "", err
+	}
+	if isbn[:3] != "978" {
+		return "", errors.Errorf("ISBN %s has no 10 character form", isbn)
+	}
+	cc, _ := ISBN10CheckChar(isbn[3:12])
+	return isbn[3:12] + string(cc), nil
+}
+
+// ISMN returns the 13 digit ISMN from which the SGTIN's GTIN was produced, or an
+// error if it isn't an ISMN or has a non-zero indicator digit. The ISMN-13 is
+// identical to the GTIN-13.
+func (s SGTIN) ISMN() (string, error) {
+	return s.bookland(BooklandISMN)
+}
+
+// ISSN returns the ISSN, in the form NNNN-NNNC, from which the SGTIN's GTIN was
+// produced, or an error if it isn't an ISSN or has a non-zero indicator digit.
+//
+// The GTIN-13 of a periodical holds the prefix 977, the first 7 digits of the
+// ISSN, a 2 digit variant (often indicating the price or issue), and a GS1
+// check digit; the ISSN's own check character is recalculated from its digits.
+func (s SGTIN) ISSN() (string, error) {
+	gtin, err := s.bookland(BooklandISSN)
+	if err != nil {
+		return "", err
+	}
+	cc, _ := ISSNCheckChar(gtin[3:10])
+	return gtin[3:7] + "-" + gtin[7:10] + string(cc), nil
+}
+
+// bookland returns the SGTIN's GTIN-13 if its Bookland kind matches bk.
+func (s SGTIN) bookland(bk BooklandKind) (string, error) {
+	if actual := s.Bookland(); actual != bk {
+		return "", errors.Errorf("GTIN %s is not an %s", s.GTIN(), bk)
+	}
+	gtin, err := s.GTIN13()
+	if err != nil {
+		return "", errors.Wrapf(err, "GTIN %s identifies a grouping of "+
+			"an %s, not the publication itself", s.GTIN(), bk)
+	}
+	return gtin, nil
+}
+
+// ISBNToGTIN returns the GTIN-13 for an ISBN-10 or ISBN-13, which may include
+// hyphens or spaces between its parts. It returns an error if the ISBN is
+// malformed or its check character is incorrect, or if it starts with 9790,
+// which holds ISMNs, not ISBNs.
+func ISBNToGTIN(isbn string) (string, error) {
+	digits := stripISBN(isbn)
+	switch len(digits) {
+	case 10:
+		if err := ValidateISBN10(digits); err != nil {
+			return "", err
+		}
+		gtin := "978" + digits[:9]
+		return gtin + strconv.Itoa(gs1CheckDigit(gtin)), nil
+	case 13:
+		gtin14, err := normalizeGTIN(digits)
+		if err != nil {
+			return "", err
+		}
+		if digits[:3] != "978" && digits[:3] != "979" {
+			return "", errors.Errorf("ISBN-13s start with 978 or 979, "+
+				"but %s does not", isbn)
+		}
+		if digits[:4] == "9790" {
+			return "", errors.Errorf("%s starts with 9790, so it's an ISMN, "+
+				"not an ISBN", isbn)
+		}
+		return gtin14[1:], nil
+	}
+	return "", errors.Errorf("ISBNs have 10 or 13 characters, but %q has %d",
+		isbn, len(digits))
+}
+
+// ISSNToGTIN returns the GTIN-13 for an ISSN, in the form NNNN-NNNC or NNNNNNNC,
+// and a variant in [0, 99]. It returns an error if the ISSN is malformed or its
+// check character is incorrect.
+func ISSNToGTIN(issn string, variant int) (string, error) {
+	if err := ValidateISSN(issn); err != nil {
+		return "", err
+	}
+	if variant < 0 || variant > 99 {
+		return "", errors.Errorf("ISSN variant must be in [0, 99], "+
+			"but is %d", variant)
+	}
+	digits := strings.Replace(issn, "-", "", 1)
+	gtin := "977" + digits[:7] + strconv.Itoa(variant/10) + strconv.Itoa(variant%10)
+	return gtin + strconv.Itoa(gs1CheckDigit(gtin)), nil
+}
+
+// ValidateISBN10 returns an error unless isbn is 9 digits followed by their
+// correct ISBN-10 check character.
+func ValidateISBN10(isbn string) error {
+	if len(isbn) != 10 {
+		return errors.Errorf("ISBN-10s have 10 characters, but %q has %d",
+			isbn, len(isbn))
+	}
+	cc, err := ISBN10CheckChar(isbn[:9])
+	if err != nil {
+		return err
+	}
+	if isbn[9] != cc {
+		return errors.Errorf("invalid check character for ISBN %s; "+
+			"it should be %c", isbn, cc)
+	}
+	return nil
+}
+
+// ValidateISSN returns an error unless issn is 7 digits followed by their
+// correct ISSN check character, optionally with a hyphen after the 4th digit.
+func ValidateISSN(issn string) error {
+	digits := issn
+	if len(issn) == 9 && issn[4] == '-' {
+		digits = issn[:4] + issn[5:]
+	}
+	if len(digits) != 8 {
+		return errors.Errorf("ISSNs have 8 characters, but %q has %d",
+			issn, len(digits))
+	}
+	cc, err := ISSNCheckChar(digits[:7])
+	if err != nil {
+		return err
+	}
+	if digits[7] != cc {
+		return errors.Errorf("invalid check character for ISSN %s; "+
+			"it should be %c", issn, cc)
+	}
+	return nil
+}
+
+// ISBN10CheckChar returns the ISBN-10 check character for the first 9 digits of
+// an ISBN-10: '0'-'9', or 'X' for a value of 10.
+func ISBN10CheckChar(digits string) (byte, error) {
+	if len(digits) != 9 {
+		return 0, errors.Errorf("ISBN-10 check characters are calculated "+
+			"from 9 digits, but %q has %d", digits, len(digits))
+	}
+	return mod11CheckChar(digits)
+}
+
+// ISSNCheckChar returns the ISSN check character for the first 7 digits of an
+// ISSN: '0'-'9', or 'X' for a value of 10.
+func ISSNCheckChar(digits string) (byte, error) {
+	if len(digits) != 7 {
+		return 0, errors.Errorf("ISSN check characters are calculated "+
+			"from 7 digits, but %q has %d", digits, len(digits))
+	}
+	return mod11CheckChar(digits)
+}
+
+// mod11CheckChar returns the ISO 2108/3297 mod 11 check character of digits, in
+// which the digits are weighted from len(digits)+1 down to 2.
+func mod11CheckChar(digits string) (byte, error) {
+	if !isDigits(digits) {
+		return 0, errors.Errorf("%q must contain only digits", digits)
+	}
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		sum += int(digits[i]-'0') * (len(digits) + 1 - i)
+	}
+	switch c := (11 - sum%11) % 11; c {
+	case 10:
+		return 'X', nil
+	default:
+		return byte('0' + c), nil
+	}
+}
+
+// stripISBN removes the hyphens and spaces that often separate ISBN parts, and
+// upper-cases a trailing check character of 'x'.
+func stripISBN(isbn string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isbn))
+}
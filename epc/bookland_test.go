@@ -0,0 +1,96 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestSGTIN_Bookland(t *testing.T) {
+	w := expect.WrapT(t)
+	newSGTIN := func(gtin string) SGTIN {
+		s, err := NewSGTINFromGTIN(POS, gtin, FixedPrefixLength(7), "1")
+		if err != nil {
+			t.Fatalf("%s: %+v", gtin, err)
+		}
+		return s
+	}
+	s := newSGTIN("9780306406157")
+	w.ShouldBeEqual(s.Bookland(), BooklandISBN)
+	w.ShouldBeEqual(w.ShouldHaveResult(s.ISBN()), "9780306406157")
+	w.ShouldBeEqual(w.ShouldHaveResult(s.ISBN10()), "0306406152")
+	w.ShouldHaveError(s.ISSN())
+	w.ShouldHaveError(s.ISMN())
+
+	s = newSGTIN("9780804429573")
+	w.ShouldBeEqual(w.ShouldHaveResult(s.ISBN10()), "080442957X")
+
+	s = newSGTIN("9791032500453")
+	w.ShouldBeEqual(s.Bookland(), BooklandISBN)
+	w.ShouldBeEqual(w.ShouldHaveResult(s.ISBN()), "9791032500453")
+	w.ShouldHaveError(s.ISBN10())
+
+	s = newSGTIN("9790260000438")
+	w.ShouldBeEqual(s.Bookland(), BooklandISMN)
+	w.ShouldBeEqual(w.ShouldHaveResult(s.ISMN()), "9790260000438")
+	w.ShouldHaveError(s.ISBN())
+
+	s = newSGTIN("9770317847001")
+	w.ShouldBeEqual(s.Bookland(), BooklandISSN)
+	w.ShouldBeEqual(w.ShouldHaveResult(s.ISSN()), "0317-8471")
+	s = newSGTIN("9772434561006")
+	w.ShouldBeEqual(w.ShouldHaveResult(s.ISSN()), "2434-561X")
+
+	// a case of books isn't the book itself
+	s = newSGTIN("19780306406154")
+	w.ShouldBeEqual(s.Bookland(), BooklandISBN)
+	w.ShouldHaveError(s.ISBN())
+
+	s = newSGTIN("00614141007349")
+	w.ShouldBeEqual(s.Bookland(), NotBookland)
+	w.ShouldHaveError(s.ISBN())
+}
+
+func TestBooklandToGTIN(t *testing.T) {
+	w := expect.WrapT(t)
+	for isbn, gtin := range map[string]string{
+		"0-306-40615-2":     "9780306406157",
+		"0306406152":        "9780306406157",
+		"0-8044-2957-x":     "9780804429573",
+		"978-0-306-40615-7": "9780306406157",
+		"979 10 325 0045 3": "9791032500453",
+	} {
+		w.As(isbn).ShouldBeEqual(w.ShouldHaveResult(ISBNToGTIN(isbn)), gtin)
+	}
+	for _, isbn := range []string{
+		"", "0-306-40615-3", "030640615", "9770317847001", "9780306406158",
+		"X306406152", "979-0-2600-0043-8",
+	} {
+		w.As(isbn).ShouldHaveError(ISBNToGTIN(isbn))
+	}
+
+	w.ShouldBeEqual(w.ShouldHaveResult(ISSNToGTIN("0317-8471", 0)), "9770317847001")
+	w.ShouldBeEqual(w.ShouldHaveResult(ISSNToGTIN("2434561X", 0)), "9772434561006")
+	w.ShouldHaveError(ISSNToGTIN("0317-8472", 0))
+	w.ShouldHaveError(ISSNToGTIN("0317-8471", 100))
+	w.ShouldHaveError(ISSNToGTIN("03178-471", 0))
+	w.ShouldHaveError(ISSNToGTIN("0317A8471", 0))
+}
+
+func TestMod11CheckChars(t *testing.T) {
+	w := expect.WrapT(t)
+	w.ShouldBeEqual(w.ShouldHaveResult(ISBN10CheckChar("030640615")), byte('2'))
+	w.ShouldBeEqual(w.ShouldHaveResult(ISSNCheckChar("2434561")), byte('X'))
+	w.ShouldBeEqual(w.ShouldHaveResult(ISSNCheckChar("0378595")), byte('5'))
+	w.ShouldHaveError(ISBN10CheckChar("03064061"))
+	w.ShouldHaveError(ISSNCheckChar("037859A"))
+	w.ShouldSucceed(ValidateISBN10("080442957X"))
+	w.ShouldFail(ValidateISBN10("0804429570"))
+	w.ShouldSucceed(ValidateISSN("0378-5955"))
+	w.ShouldFail(ValidateISSN("0378-5956"))
+}
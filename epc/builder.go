@@ -0,0 +1,136 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+// ValidationErrors holds every problem found while validating a value, rather
+// than just the first.
+type ValidationErrors []error
+
+func (ve ValidationErrors) Error() string {
+	msgs := make([]string, len(ve))
+	for i, err := range ve {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// SGTINBuilder constructs SGTINs from named values, as an alternative to
+// NewSGTIN's positional arguments. The company prefix and item reference are
+// given as strings of digits, as they appear in the SGTIN's URI, so that their
+// lengths determine the partition and swapping them is an error, rather than a
+// different SGTIN.
+//
+// The zero value is ready to use. Each setter returns a modified copy of the
+// builder, so that calls may be chained:
+//     s, err := SGTINBuilder{}.
+//         CompanyPrefix("0614141").
+//         Indicator(8).
+//         ItemRef("12345").
+//         Serial("6789").
+//         Build()
+// The filter defaults to Other and the indicator defaults to 0.
+type SGTINBuilder struct {
+	filter        FilterValue
+	companyPrefix string
+	indicator     int
+	itemRef       string
+	serial        string
+}
+
+// Filter sets the SGTIN's filter value.
+func (b SGTINBuilder) Filter(filter FilterValue) SGTINBuilder {
+	b.filter = filter
+	return b
+}
+
+// CompanyPrefix sets the SGTIN's GS1 Company Prefix, which must have 6 to 12
+// digits, including any leading '0's.
+func (b SGTINBuilder) CompanyPrefix(prefix string) SGTINBuilder {
+	b.companyPrefix = prefix
+	return b
+}
+
+// Indicator sets the SGTIN's indicator digit.
+func (b SGTINBuilder) Indicator(indicator int) SGTINBuilder {
+	b.indicator = indicator
+	return b
+}
+
+// ItemRef sets the SGTIN's item reference, which must have 12 digits less the
+// length of the company prefix, including any leading '0's. It's empty if the
+// company prefix has 12 digits.
+func (b SGTINBuilder) ItemRef(itemRef string) SGTINBuilder {
+	b.itemRef = itemRef
+	return b
+}
+
+// Serial sets the SGTIN's serial number, which should not be escaped.
+func (b SGTINBuilder) Serial(serial string) SGTINBuilder {
+	b.serial = serial
+	return b
+}
+
+// Build returns the SGTIN with the builder's values. If any are invalid, the
+// error is a ValidationErrors holding an error for each problem.
+func (b SGTINBuilder) Build() (SGTIN, error) {
+	var errs ValidationErrors
+
+	prefixOK := len(b.companyPrefix) >= 6 && len(b.companyPrefix) <= 12 &&
+		isDigits(b.companyPrefix)
+	if !prefixOK {
+		errs = append(errs, errors.Errorf("company prefix %q must have "+
+			"6 to 12 digits", b.companyPrefix))
+	}
+	if b.itemRef != "" && !isDigits(b.itemRef) {
+		errs = append(errs, errors.Errorf("item reference %q must only "+
+			"contain digits", b.itemRef))
+	} else if prefixOK && len(b.companyPrefix)+len(b.itemRef) != 12 {
+		errs = append(errs, errors.Errorf("item reference %q must have "+
+			"%d digits to follow a %d digit company prefix", b.itemRef,
+			12-len(b.companyPrefix), len(b.companyPrefix)))
+	}
+	if b.indicator < 0 || b.indicator > 9 {
+		errs = append(errs, errors.Errorf("indicator must be in [0,9], "+
+			"but is %d", b.indicator))
+	}
+	if !b.filter.IsValid() {
+		errs = append(errs, errors.Errorf("filter %d is invalid or reserved",
+			b.filter))
+	}
+	switch {
+	case b.serial == "":
+		errs = append(errs, errors.New("serial is empty"))
+	case len(b.serial) > 20:
+		errs = append(errs, errors.Errorf("serial must have at most 20 "+
+			"characters, but has %d", len(b.serial)))
+	case !IsGS1AIEncodable(b.serial):
+		errs = append(errs, errors.Errorf("serial %q has characters outside "+
+			"the GS1 AI Encodable Character Set 82", b.serial))
+	}
+	if errs != nil {
+		return SGTIN{}, errs
+	}
+
+	partition := 12 - len(b.companyPrefix)
+	companyPrefix, _ := strconv.Atoi(b.companyPrefix)
+	itemRef := 0
+	if partition > 0 {
+		itemRef, _ = strconv.Atoi(b.itemRef)
+	}
+	s, err := NewSGTIN(b.filter, partition, b.indicator, companyPrefix, itemRef,
+		b.serial)
+	if err != nil {
+		return SGTIN{}, ValidationErrors{err}
+	}
+	return s, nil
+}
@@ -0,0 +1,50 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/pkg/errors"
+	"testing"
+)
+
+func TestSGTINBuilder(t *testing.T) {
+	w := expect.WrapT(t)
+
+	s := w.ShouldHaveResult(SGTINBuilder{}.
+		Filter(POS).
+		CompanyPrefix("0614141").
+		Indicator(8).
+		ItemRef("12345").
+		Serial("6789").
+		Build()).(SGTIN)
+	w.ShouldBeEqual(s.URI(), "urn:epc:id:sgtin:0614141.812345.6789")
+	w.ShouldBeEqual(s.Filter(), POS)
+	w.ShouldBeEqual(s, w.ShouldHaveResult(NewSGTIN(POS, 5, 8, 614141, 12345, "6789")))
+
+	s = w.ShouldHaveResult(SGTINBuilder{}.CompanyPrefix("061414100734").
+		Indicator(1).Serial("a/b").Build()).(SGTIN)
+	w.ShouldBeEqual(s.URI(), "urn:epc:id:sgtin:061414100734.1.a%2Fb")
+
+	// swapped company prefix and item ref
+	_, err := SGTINBuilder{}.CompanyPrefix("12345").ItemRef("0614141").
+		Serial("1").Build()
+	w.ShouldFail(err)
+
+	_, err = SGTINBuilder{}.Filter(3).CompanyPrefix("0614141").ItemRef("1234").
+		Indicator(10).Build()
+	ve, ok := errors.Cause(err).(ValidationErrors)
+	w.ShouldBeTrue(ok)
+	w.ShouldHaveLength(ve, 4)
+	w.ShouldContainStr(err.Error(), "; ")
+
+	_, err = SGTINBuilder{}.CompanyPrefix("0614141").ItemRef("1234A").
+		Serial("~~~~~~~~~~~~~~~~~~~~~").Build()
+	ve, ok = errors.Cause(err).(ValidationErrors)
+	w.ShouldBeTrue(ok)
+	w.ShouldHaveLength(ve, 2)
+}
@@ -0,0 +1,84 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ComputeGS1CheckDigit returns the GS1 mod 10 check digit for a string of
+// digits, which should not include the check digit itself. It's the same
+// calculation for every GS1 key with a check digit (GTINs, SSCCs, GLNs, etc.),
+// or an error if digits is empty or contains anything other than 0-9.
+func ComputeGS1CheckDigit(digits string) (int, error) {
+	if !isDigits(digits) {
+		return 0, errors.Errorf("%q must be a non-empty string of digits", digits)
+	}
+	return gs1CheckDigit(digits), nil
+}
+
+// ValidateGTIN returns an error unless gtin is an 8, 12, 13, or 14 digit GTIN
+// with a correct check digit.
+func ValidateGTIN(gtin string) error {
+	_, err := normalizeGTIN(gtin)
+	return err
+}
+
+// ValidateSSCC returns an error unless sscc is an 18 digit Serial Shipping
+// Container Code with a correct check digit.
+func ValidateSSCC(sscc string) error {
+	return validateGS1Key("SSCC", sscc, 18)
+}
+
+// ValidateGLN returns an error unless gln is a 13 digit Global Location Number
+// with a correct check digit.
+func ValidateGLN(gln string) error {
+	return validateGS1Key("GLN", gln, 13)
+}
+
+// ValidateGRAI returns an error unless grai is the data of a Global Returnable
+// Asset Identifier element string (AI 8003): a '0', a 13 digit GS1 Company
+// Prefix and asset type with a correct check digit, and optionally, a serial
+// component of up to 16 characters from the GS1 AI Encodable Character Set 82.
+func ValidateGRAI(grai string) error {
+	if len(grai) < 14 {
+		return errors.Errorf("GRAIs have at least 14 characters, "+
+			"but %q has %d", grai, len(grai))
+	}
+	if grai[0] != '0' {
+		return errors.Errorf("GRAIs must start with '0', but %q does not", grai)
+	}
+	if err := validateGS1Key("GRAI", grai[1:14], 13); err != nil {
+		return err
+	}
+
+	serial := grai[14:]
+	if len(serial) > 16 {
+		return errors.Errorf("GRAI serials have at most 16 characters, "+
+			"but %q has %d", serial, len(serial))
+	}
+	if serial != "" && !IsGS1AIEncodable(serial) {
+		return errors.Errorf("GRAI serial %q has characters outside the "+
+			"GS1 AI Encodable Character Set 82", serial)
+	}
+	return nil
+}
+
+// validateGS1Key returns an error unless key has the given number of digits,
+// the last of which is the correct GS1 check digit.
+func validateGS1Key(name, key string, length int) error {
+	if len(key) != length || !isDigits(key) {
+		return errors.Errorf("%ss have %d digits, but %q does not",
+			name, length, key)
+	}
+	cd := gs1CheckDigit(key[:length-1])
+	if int(key[length-1]-'0') != cd {
+		return errors.Errorf("invalid check digit for %s %s; "+
+			"it should be %d", name, key, cd)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestComputeGS1CheckDigit(t *testing.T) {
+	w := expect.WrapT(t)
+	w.ShouldBeEqual(w.ShouldHaveResult(ComputeGS1CheckDigit("0061414100734")), 9)
+	w.ShouldBeEqual(w.ShouldHaveResult(ComputeGS1CheckDigit("00614141123456789")), 0)
+	w.ShouldBeEqual(w.ShouldHaveResult(ComputeGS1CheckDigit("7")), 9)
+	w.ShouldHaveError(ComputeGS1CheckDigit(""))
+	w.ShouldHaveError(ComputeGS1CheckDigit("12A"))
+}
+
+func TestValidateGS1Keys(t *testing.T) {
+	w := expect.WrapT(t)
+
+	w.ShouldSucceed(ValidateGTIN("00614141007349"))
+	w.ShouldSucceed(ValidateGTIN("96385074"))
+	w.ShouldFail(ValidateGTIN("00614141007340"))
+	w.ShouldFail(ValidateGTIN("0061414100734"))
+
+	w.ShouldSucceed(ValidateSSCC("006141411234567890"))
+	w.ShouldFail(ValidateSSCC("006141411234567891"))
+	w.ShouldFail(ValidateSSCC("00614141123456789"))
+
+	w.ShouldSucceed(ValidateGLN("0614141000012"))
+	w.ShouldFail(ValidateGLN("0614141000013"))
+	w.ShouldFail(ValidateGLN("061414100001A"))
+
+	w.ShouldSucceed(ValidateGRAI("00614141123452"))
+	w.ShouldSucceed(ValidateGRAI("00614141123452ABC/123"))
+	w.ShouldSucceed(ValidateGRAI("006141411234520123456789ABCDEF"))
+	w.ShouldFail(ValidateGRAI("0061414112345"))
+	w.ShouldFail(ValidateGRAI("10614141123452"))
+	w.ShouldFail(ValidateGRAI("00614141123453"))
+	w.ShouldFail(ValidateGRAI("006141411234520123456789ABCDEFG"))
+	w.ShouldFail(ValidateGRAI("00614141123452~"))
+}
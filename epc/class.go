@@ -0,0 +1,118 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+// GTINClass classifies GTIN-like values by the GS1 Prefix that starts their 13
+// digit form (i.e., the GTIN-14, without its indicator digit).
+//
+// Some GS1 Prefixes are reserved for Restricted Circulation Numbers (RCNs):
+// values that look like GTINs, but which are only meaningful within a region or
+// company. They are often used for variable measure items, such as produce or
+// deli goods, with the price or weight embedded in the "item reference". The
+// EPC Tag Data Standard forbids encoding RCNs as SGTINs.
+type GTINClass int
+
+const (
+	// RegularGTIN is a GTIN that may be encoded as an SGTIN.
+	RegularGTIN = GTINClass(iota)
+	// RegionalRCN is a Restricted Circulation Number with GS1 Prefix 02 or
+	// 20-29, whose meaning is defined by a GS1 Member Organization for its
+	// geographic region. These are typically used for variable measure items.
+	RegionalRCN
+	// CompanyRCN is a Restricted Circulation Number with GS1 Prefix 04, which
+	// is only meaningful within the company that assigned it.
+	CompanyRCN
+	// Coupon is a coupon code, with GS1 Prefix 05 (U.P.C. coupons in North
+	// America), 99, or 981-984 (coupons in common currency areas). Coupon
+	// codes identify offers, not trade items, and so must not be encoded as
+	// SGTINs.
+	Coupon
+)
+
+func (c GTINClass) String() string {
+	switch c {
+	case RegularGTIN:
+		return "GTIN"
+	case RegionalRCN:
+		return "Regional Restricted Circulation Number"
+	case CompanyRCN:
+		return "Company Restricted Circulation Number"
+	case Coupon:
+		return "Coupon"
+	}
+	return "Unknown GTIN class: " + strconv.Itoa(int(c))
+}
+
+// ClassifyGTIN returns the GTINClass of a GTIN-8, -12, -13, or -14, or an error
+// if it is not a valid GTIN (see NewSGTINFromGTIN).
+//
+// GTIN-8s are always classified as RegularGTINs; this doesn't attempt to detect
+// RCN-8s, which are only distinguished from GTIN-8s within their 8 digit form.
+func ClassifyGTIN(gtin string) (GTINClass, error) {
+	gtin14, err := normalizeGTIN(gtin)
+	if err != nil {
+		return RegularGTIN, err
+	}
+	return classifyGTIN14(gtin14), nil
+}
+
+// classifyGTIN14 returns the GTINClass of a valid, 14 digit GTIN.
+func classifyGTIN14(gtin14 string) GTINClass {
+	switch {
+	case gtin14[1:3] == "02", gtin14[1] == '2':
+		return RegionalRCN
+	case gtin14[1:3] == "04":
+		return CompanyRCN
+	case gtin14[1:3] == "05", gtin14[1:3] == "99",
+		gtin14[1:4] >= "981" && gtin14[1:4] <= "984":
+		return Coupon
+	}
+	return RegularGTIN
+}
+
+// Classify returns the GTINClass of the SGTIN's GTIN.
+//
+// ValidateRanges doesn't consider the SGTIN's class, since RCNs fit within the
+// SGTIN's fields; use ValidateClass to exclude them.
+func (s SGTIN) Classify() GTINClass {
+	return classifyGTIN14(s.GTIN())
+}
+
+// ValidateClass returns an error if the SGTIN's GTIN is a Restricted
+// Circulation Number or coupon code, which the EPC Tag Data Standard forbids
+// encoding as an SGTIN. For coupon codes, the error is a *CouponError.
+func (s SGTIN) ValidateClass() error {
+	c := s.Classify()
+	if c == Coupon {
+		return &CouponError{GTIN: s.GTIN()}
+	}
+	if c != RegularGTIN {
+		return errors.Errorf("GTIN %s is a %s, which may not be encoded "+
+			"as an SGTIN", s.GTIN(), c)
+	}
+	return nil
+}
+
+// CouponError indicates a coupon code was used where a GTIN was expected.
+//
+// Coupon barcodes are easily mistaken for product barcodes, but their GS1
+// Prefixes are reserved, so they can be recognized and rejected. Use
+// errors.Cause to check whether an error is a *CouponError.
+type CouponError struct {
+	// GTIN is the 14 digit form of the coupon code.
+	GTIN string
+}
+
+func (e *CouponError) Error() string {
+	return "GTIN " + e.GTIN + " is a coupon code, which may not be encoded " +
+		"as an SGTIN"
+}
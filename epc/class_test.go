@@ -0,0 +1,63 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/pkg/errors"
+	"testing"
+)
+
+func TestClassifyGTIN(t *testing.T) {
+	for gtin, class := range map[string]GTINClass{
+		"00614141007349": RegularGTIN,
+		"614141007349":   RegularGTIN,
+		"3012345678902":  RegularGTIN,
+		"19012345678903": RegularGTIN,
+		"96385074":       RegularGTIN,
+		"212345678909":   RegionalRCN, // UPC number system 2
+		"2012345678903":  RegionalRCN,
+		"12912345678903": RegionalRCN, // indicator is ignored
+		"412345678903":   CompanyRCN,  // UPC number system 4
+		"512345678900":   Coupon,      // UPC number system 5
+		"9912345678909":  Coupon,
+		"9812345678902":  Coupon,
+		"9841234567898":  Coupon,
+		"9801234567892":  RegularGTIN, // just outside 981-984
+		"9851234567897":  RegularGTIN,
+	} {
+		w := expect.WrapT(t).As(gtin)
+		w.ShouldBeEqual(w.ShouldHaveResult(ClassifyGTIN(gtin)), class)
+
+		s, err := NewSGTINFromGTIN(POS, gtin, FixedPrefixLength(7), "1")
+		w.ShouldBeEqual(s.Classify(), class)
+		switch class {
+		case RegularGTIN:
+			w.ShouldSucceed(err)
+			w.ShouldSucceed(s.ValidateClass())
+		case Coupon:
+			couponErr, isCoupon := errors.Cause(err).(*CouponError)
+			w.ShouldBeTrue(isCoupon)
+			if isCoupon {
+				w.ShouldBeEqual(couponErr.GTIN, s.GTIN())
+				w.ShouldContainStr(couponErr.Error(), "coupon")
+			}
+			couponErr, isCoupon = errors.Cause(s.ValidateClass()).(*CouponError)
+			w.ShouldBeTrue(isCoupon)
+			if isCoupon {
+				w.ShouldBeEqual(couponErr.GTIN, s.GTIN())
+			}
+		default:
+			w.ShouldSucceed(err)
+			w.ShouldFail(s.ValidateClass())
+		}
+	}
+
+	w := expect.WrapT(t)
+	w.ShouldHaveError(ClassifyGTIN("2012345678900"))
+	w.ShouldHaveError(ClassifyGTIN("20123"))
+}
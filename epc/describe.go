@@ -0,0 +1,99 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// FieldDescription describes a single field of an EPC's binary encoding.
+type FieldDescription struct {
+	// Name is the field's name, as used by the EPC Tag Data Standard.
+	Name string
+	// Value is the field's value as it appears in the EPC's URI, if it
+	// appears there; otherwise, it's the field's decimal value.
+	Value string
+	// StartBit is the offset of the field's first bit, where bit 0 is the
+	// highest-order bit of the encoding's first byte.
+	StartBit int
+	// BitLength is the number of bits in the field.
+	BitLength int
+	// Bits are the field's bits as a string of '0's and '1's, MSB first.
+	Bits string
+}
+
+// Describe returns a breakdown of the fields in the SGTIN's binary encoding, in
+// the order they appear. The encoding is the one chosen by Encode, and this
+// returns the same errors it does. To describe an SGTIN as it's encoded on a
+// tag, which may be an SGTIN-198 even if its serial is numeric, use
+// DescribeSGTIN.
+func (s SGTIN) Describe() ([]FieldDescription, error) {
+	b, err := s.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return s.describe(b), nil
+}
+
+// DescribeSGTIN returns a breakdown of the fields in b, the binary encoding of
+// an SGTIN-96 or SGTIN-198, in the order they appear. It returns an error if b
+// can't be decoded with DecodeSGTIN.
+func DescribeSGTIN(b []byte) ([]FieldDescription, error) {
+	s, err := DecodeSGTIN(b)
+	if err != nil {
+		return nil, err
+	}
+	return s.describe(b), nil
+}
+
+// describe returns the fields of b, which must be the SGTIN's encoding.
+func (s SGTIN) describe(b []byte) []FieldDescription {
+	serialLen, serial := serial96Len, gs1Escaper.Replace(s.serial)
+	if b[0] == SGTIN198Header {
+		serialLen = serial198Len
+	}
+	iirLen := prefixIIRLen - companyBits[s.partition]
+	iir := strconv.Itoa(s.indicator)
+	if s.partition > 0 {
+		iir += s.ItemReference()
+	}
+
+	fields := []FieldDescription{
+		{Name: "Header", Value: fmt.Sprintf("%#X", b[0]),
+			StartBit: headerStartBit, BitLength: headerLen},
+		{Name: "Filter", Value: strconv.Itoa(int(s.filter)),
+			StartBit: filterStartBit, BitLength: filterLen},
+		{Name: "Partition", Value: strconv.Itoa(s.partition),
+			StartBit: partitionStartBit, BitLength: partitionLen},
+		{Name: "GS1 Company Prefix", Value: s.CompanyPrefix(),
+			StartBit: gcpStartBit, BitLength: companyBits[s.partition]},
+		{Name: "Indicator/Item Reference", Value: iir,
+			StartBit: gcpStartBit + companyBits[s.partition], BitLength: iirLen},
+		{Name: "Serial", Value: serial,
+			StartBit: serialStartBit, BitLength: serialLen},
+	}
+	for i := range fields {
+		fields[i].Bits = bitString(b, fields[i].StartBit, fields[i].BitLength)
+	}
+	return fields
+}
+
+// bitString returns length bits of b as '0's and '1's, starting at the given
+// bit, where bit 0 is the highest-order bit of b[0].
+func bitString(b []byte, start, length int) string {
+	bits := make([]byte, length)
+	for i := range bits {
+		bit := start + i
+		if b[bit/8]&(0x80>>uint(bit%8)) != 0 {
+			bits[i] = '1'
+		} else {
+			bits[i] = '0'
+		}
+	}
+	return string(bits)
+}
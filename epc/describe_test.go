@@ -0,0 +1,63 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestSGTIN_Describe(t *testing.T) {
+	w := expect.WrapT(t)
+	s := w.ShouldHaveResult(DecodeSGTINString("3034257BF7194E4000001A85")).(SGTIN)
+	fields := w.ShouldHaveResult(s.Describe()).([]FieldDescription)
+	w.ShouldBeEqual(fields, []FieldDescription{
+		{"Header", "0X30", 0, 8, "00110000"},
+		{"Filter", "1", 8, 3, "001"},
+		{"Partition", "5", 11, 3, "101"},
+		{"GS1 Company Prefix", "0614141", 14, 24, "000010010101111011111101"},
+		{"Indicator/Item Reference", "812345", 38, 20, "11000110010100111001"},
+		{"Serial", "6789", 58, 38, "00000000000000000000000001101010000101"},
+	})
+
+	s = w.ShouldHaveResult(NewSGTIN(Other, 6, 0, 614141, 812345, "a/b")).(SGTIN)
+	fields = w.ShouldHaveResult(s.Describe()).([]FieldDescription)
+	w.ShouldHaveLength(fields, 6)
+	w.ShouldBeEqual(fields[0].Value, "0X36")
+	w.ShouldBeEqual(fields[3].BitLength, 20)
+	w.ShouldBeEqual(fields[4].Value, "0812345")
+	w.ShouldBeEqual(fields[5].Value, "a%2Fb")
+	w.ShouldBeEqual(fields[5].BitLength, 140)
+	w.ShouldBeEqual(fields[5].Bits[:21], "110000101011111100010")
+
+	w.ShouldHaveError(SGTIN{}.Describe())
+}
+
+func TestDescribeSGTIN(t *testing.T) {
+	w := expect.WrapT(t)
+
+	// an SGTIN-198 with a serial SGTIN-96 could hold
+	b := w.ShouldHaveResult(hex.DecodeString(
+		"3634257BF7194E5B3770E40000000000000000000000000000")).([]byte)
+	fields := w.ShouldHaveResult(DescribeSGTIN(b)).([]FieldDescription)
+	w.ShouldHaveLength(fields, 6)
+	w.ShouldBeEqual(fields[0], FieldDescription{"Header", "0X36", 0, 8, "00110110"})
+	w.ShouldBeEqual(fields[4].Value, "812345")
+	w.ShouldBeEqual(fields[5].Value, "6789")
+	w.ShouldBeEqual(fields[5].StartBit, 58)
+	w.ShouldBeEqual(fields[5].BitLength, 140)
+	w.ShouldBeEqual(fields[5].Bits[:28], "0110110011011101110000111001")
+
+	b = w.ShouldHaveResult(hex.DecodeString("3034257BF7194E4000001A85")).([]byte)
+	s := w.ShouldHaveResult(DecodeSGTIN(b)).(SGTIN)
+	w.ShouldBeEqual(w.ShouldHaveResult(DescribeSGTIN(b)),
+		w.ShouldHaveResult(s.Describe()))
+
+	w.ShouldFail(DescribeSGTIN(b[:11]))
+	w.ShouldFail(DescribeSGTIN(nil))
+}
@@ -32,6 +32,8 @@
 // In other words: convert tag data into a URI as soon as possible, and use that,
 // because it's the least ambiguous, most contextual piece of information you
 // could be using.
+// CanonicalURI does exactly that for EPCs in any of the forms this package
+// parses, including hex-encoded tag data.
 //
 // EPC is confusing because first of all, an "Electronic Product Code" is not
 // electronic, not just for products, and not a code. An EPC is an identifier
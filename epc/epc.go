@@ -0,0 +1,159 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"strings"
+	"sync"
+)
+
+// PureIdentityURIPrefix starts the Pure Identity URIs of all EPC schemes.
+const PureIdentityURIPrefix = "urn:epc:id:"
+
+// EPC is implemented by the identifiers of each EPC scheme this package
+// supports, such as SGTIN, SSCC, and GID.
+type EPC interface {
+	// URI returns the EPC's Pure Identity URI.
+	URI() string
+	// Scheme returns the name of the EPC's scheme, as used in its URI; for
+	// instance, "sgtin" for an SGTIN.
+	Scheme() string
+	// ValidateRanges checks that the EPC's values fit within their fields.
+	ValidateRanges() error
+	// CanonicalKey returns a byte string identifying the EPC that's suitable
+	// as a map or database key. Keys start with the scheme name and a 0 byte,
+	// followed by the EPC's fields, laid out so that sorting keys byte-wise
+	// groups EPCs by their leading fields, such as their company prefix.
+	CanonicalKey() []byte
+}
+
+// DecodeEPC decodes binary EPC data using the decoder for the scheme identified
+// by its header byte, and returns the resulting EPC, or an error if the scheme
+// is unsupported or the data cannot be decoded. In addition to the schemes this
+// package implements, it uses decoders added with RegisterScheme.
+//
+// As with the scheme-specific decoders, the EPC's values are NOT validated; use
+// its ValidateRanges method to do so.
+func DecodeEPC(b []byte) (EPC, error) {
+	if len(b) == 0 {
+		return nil, errors.New("no data provided")
+	}
+
+	schemesMu.RLock()
+	sd, ok := schemes[b[0]]
+	schemesMu.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("unsupported EPC header: %#X", b[0])
+	}
+	if sd.byteLen > 0 && len(b) != sd.byteLen {
+		return nil, errors.Errorf("EPCs with header %#X should have %d bytes, "+
+			"but this has %d bytes", b[0], sd.byteLen, len(b))
+	}
+
+	e, err := sd.decode(b)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// schemeDecoder decodes EPCs of the scheme identified by a header byte.
+type schemeDecoder struct {
+	byteLen int // 0 if the decoder handles any length
+	decode  func([]byte) (EPC, error)
+}
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = map[byte]schemeDecoder{
+		SGTIN96Header:  {SGTIN96NumBytes, decodeSGTINEPC},
+		SGTIN198Header: {SGTIN198NumBytes, decodeSGTINEPC},
+		SSCC96Header:   {SSCC96NumBytes, decodeSSCCEPC},
+		GID96Header:    {GID96NumBytes, decodeGIDEPC},
+	}
+)
+
+// RegisterScheme makes a decoder available to DecodeEPC for EPCs with the given
+// header byte, such as a company's private encoding. If byteLen is positive,
+// DecodeEPC only calls the decoder with data of exactly that many bytes;
+// otherwise, the decoder must check the length itself.
+//
+// The decoder may be called concurrently, and if it returns a nil error, its
+// EPC must be non-nil. RegisterScheme panics if decoder is nil or the header
+// already has a decoder, including those of the schemes this package
+// implements. It's typically called from an init function.
+func RegisterScheme(header byte, byteLen int, decoder func([]byte) (EPC, error)) {
+	if decoder == nil {
+		panic("epc: RegisterScheme decoder is nil")
+	}
+
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	if _, dup := schemes[header]; dup {
+		panic(fmt.Sprintf("epc: RegisterScheme called twice for header %#X", header))
+	}
+	schemes[header] = schemeDecoder{byteLen: byteLen, decode: decoder}
+}
+
+func decodeSGTINEPC(b []byte) (EPC, error) {
+	s, err := DecodeSGTIN(b)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func decodeSSCCEPC(b []byte) (EPC, error) {
+	s, err := DecodeSSCC(b)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func decodeGIDEPC(b []byte) (EPC, error) {
+	g, err := DecodeGID(b)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// ParsePureIdentityURI parses a Pure Identity URI of any scheme this package
+// supports, and returns the EPC it represents, or an error if the URI is
+// malformed, its scheme is unsupported, or its values are out of range.
+//
+// Since Pure Identity URIs don't include filter values, the EPC's filter is 0.
+func ParsePureIdentityURI(uri string) (EPC, error) {
+	if !strings.HasPrefix(uri, PureIdentityURIPrefix) {
+		return nil, errors.Errorf("Pure Identity URIs must start with %q",
+			PureIdentityURIPrefix)
+	}
+	scheme := uri[len(PureIdentityURIPrefix):]
+	if i := strings.IndexByte(scheme, ':'); i >= 0 {
+		scheme = scheme[:i]
+	}
+
+	var e EPC
+	var err error
+	switch scheme {
+	case "sgtin":
+		e, err = ParseSGTINURI(uri)
+	case "sscc":
+		e, err = ParseSSCCURI(uri)
+	case "gid":
+		e, err = ParseGIDURI(uri)
+	default:
+		return nil, errors.Errorf("unsupported EPC scheme: %q", scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,125 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestDecodeEPC(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for epc, uri := range map[string]string{
+		"3034257BF7194E4000001A85": "urn:epc:id:sgtin:0614141.812345.6789",
+		"3134257BF4499602D2000000": "urn:epc:id:sscc:0614141.1234567890",
+		"355AB1C6000303900000018F": "urn:epc:id:gid:95100000.12345.399",
+	} {
+		b := w.ShouldHaveResult(hex.DecodeString(epc)).([]byte)
+		e := w.As(epc).ShouldHaveResult(DecodeEPC(b)).(EPC)
+		w.As(epc).ShouldBeEqual(e.URI(), uri)
+		w.As(epc).ShouldSucceed(e.ValidateRanges())
+
+		parsed := w.As(uri).ShouldHaveResult(ParsePureIdentityURI(uri)).(EPC)
+		w.As(uri).ShouldBeEqual(parsed.URI(), uri)
+		w.As(uri).ShouldBeEqual(parsed.Scheme(), e.Scheme())
+	}
+
+	b := w.ShouldHaveResult(hex.DecodeString("3034257BF7194E4000001A85")).([]byte)
+	e := w.ShouldHaveResult(DecodeEPC(b)).(EPC)
+	w.ShouldBeEqual(e.Scheme(), "sgtin")
+	_, isSGTIN := e.(SGTIN)
+	w.ShouldBeTrue(isSGTIN)
+
+	for _, epc := range []string{"", "00", "E2801160", "3034257BF7194E40"} {
+		b := w.ShouldHaveResult(hex.DecodeString(epc)).([]byte)
+		e, err := DecodeEPC(b)
+		w.As(epc).ShouldFail(err)
+		w.As(epc).ShouldBeTrue(e == nil)
+	}
+}
+
+func TestParsePureIdentityURI(t *testing.T) {
+	w := expect.WrapT(t)
+
+	e := w.ShouldHaveResult(ParsePureIdentityURI("urn:epc:id:sscc:061414112.12345678")).(EPC)
+	sscc, ok := e.(SSCC)
+	w.ShouldBeTrue(ok)
+	w.ShouldBeEqual(sscc.Partition(), 3)
+	w.ShouldBeEqual(sscc.SerialReference(), "12345678")
+
+	for _, uri := range []string{
+		"",
+		"urn:epc:id:",
+		"urn:epc:id:sgln:0614141.12345.400",
+		"urn:epc:tag:sgtin-96:1.0614141.812345.6789",
+		"urn:epc:id:sgtin:0614141.812345",
+		"urn:epc:id:sscc:0614141.123456789",
+		"urn:epc:id:sscc:0614141.12345678901",
+		"urn:epc:id:sscc:0614141.123456789A",
+		"urn:epc:id:sscc:061414.12345678901.1",
+		"urn:epc:id:gid:1.2",
+	} {
+		e, err := ParsePureIdentityURI(uri)
+		w.As(uri).ShouldFail(err)
+		w.As(uri).ShouldBeTrue(e == nil)
+	}
+}
+
+// testPrivateEPC is a made-up, private EPC scheme for testing RegisterScheme.
+type testPrivateEPC struct {
+	id uint16
+}
+
+func (e testPrivateEPC) URI() string {
+	return "urn:example:private:" + strconv.Itoa(int(e.id))
+}
+
+func (e testPrivateEPC) Scheme() string {
+	return "private"
+}
+
+func (e testPrivateEPC) ValidateRanges() error {
+	return nil
+}
+
+func (e testPrivateEPC) CanonicalKey() []byte {
+	return []byte("private\x00" + strconv.Itoa(int(e.id)))
+}
+
+// registerPrivateEPC registers testPrivateEPC once, even if tests are repeated.
+var registerPrivateEPC sync.Once
+
+func TestRegisterScheme(t *testing.T) {
+	w := expect.WrapT(t)
+
+	const header = 0xE7
+	registerPrivateEPC.Do(func() {
+		w.ShouldBeFalse(SupportsHeader(header))
+		RegisterScheme(header, 3, func(b []byte) (EPC, error) {
+			return testPrivateEPC{id: uint16(b[1])<<8 | uint16(b[2])}, nil
+		})
+	})
+	w.ShouldBeTrue(SupportsHeader(header))
+
+	e := w.ShouldHaveResult(DecodeEPC([]byte{header, 0x01, 0x02})).(EPC)
+	w.ShouldBeEqual(e.URI(), "urn:example:private:258")
+	w.ShouldHaveError(DecodeEPC([]byte{header, 0x01}))
+
+	panics := func(f func()) (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		f()
+		return
+	}
+	w.ShouldBeTrue(panics(func() { RegisterScheme(header, 3, decodeGIDEPC) }))
+	w.ShouldBeTrue(panics(func() { RegisterScheme(SGTIN96Header, 12, decodeGIDEPC) }))
+	w.ShouldBeTrue(panics(func() { RegisterScheme(0xE8, 3, nil) }))
+	w.ShouldBeFalse(SupportsHeader(0xE8))
+}
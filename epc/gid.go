@@ -0,0 +1,192 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	GIDPureURIPrefix = "urn:epc:id:gid"
+	GID96NumBytes    = 12
+	GID96Header      = 0x35
+)
+
+// GID is a General Identifier, an EPC scheme that is independent of any GS1
+// specifications or identity keys. It consists of a General Manager Number,
+// which identifies an organizational entity responsible for maintaining the
+// numbers in subsequent fields, an Object Class, which identifies a class or
+// "type" of thing, and a Serial Number, unique within each object class.
+//
+// Unlike SGTINs, none of the GID's fields have significant leading '0's; each
+// is just a decimal integer, and so it's represented as one.
+type GID struct {
+	manager int
+	class   int
+	serial  int
+}
+
+// ManagerNumber returns the GID's General Manager Number.
+func (g GID) ManagerNumber() int {
+	return g.manager
+}
+
+// ObjectClass returns the GID's Object Class.
+func (g GID) ObjectClass() int {
+	return g.class
+}
+
+// Serial returns the GID's Serial Number.
+func (g GID) Serial() int {
+	return g.serial
+}
+
+// NewGID returns a GID with the given values. If the values do not fit within
+// the ranges permitted by GID-96, the error is non-nil, but the GID is still
+// returned.
+func NewGID(manager, class, serial int) (GID, error) {
+	g := GID{manager: manager, class: class, serial: serial}
+	return g, g.ValidateRanges()
+}
+
+const (
+	gidManagerLen = 28
+	gidClassLen   = 24
+	gidSerialLen  = 36
+
+	gidManagerStartBit = headerLen
+	gidClassStartBit   = gidManagerStartBit + gidManagerLen
+	gidSerialStartBit  = gidClassStartBit + gidClassLen
+)
+
+var (
+	gidManagerExt = bitextract.New(gidManagerStartBit, gidManagerLen)
+	gidClassExt   = bitextract.New(gidClassStartBit, gidClassLen)
+	gidSerialExt  = bitextract.New(gidSerialStartBit, gidSerialLen)
+)
+
+// Scheme returns "gid", the EPC scheme of GIDs.
+func (g GID) Scheme() string {
+	return "gid"
+}
+
+// ValidateRanges checks a GID's values to ensure they fit the range restrictions
+// of their respective GID-96 fields.
+func (g GID) ValidateRanges() error {
+	if g.manager < 0 || g.manager >= 1<<gidManagerLen {
+		return errors.Errorf("general manager number must be in [0, %d], "+
+			"but is %d", 1<<gidManagerLen-1, g.manager)
+	}
+	if g.class < 0 || g.class >= 1<<gidClassLen {
+		return errors.Errorf("object class must be in [0, %d], "+
+			"but is %d", 1<<gidClassLen-1, g.class)
+	}
+	if g.serial < 0 || g.serial >= 1<<gidSerialLen {
+		return errors.Errorf("serial must be in [0, %d], "+
+			"but is %d", 1<<gidSerialLen-1, g.serial)
+	}
+	return nil
+}
+
+// URI returns the EPC Pure Identity URI for this GID, of the format:
+//     urn:epc:id:gid:ManagerNumber.ObjectClass.SerialNumber
+func (g GID) URI() string {
+	return fmt.Sprintf("%s:%d.%d.%d", GIDPureURIPrefix,
+		g.manager, g.class, g.serial)
+}
+
+// ParseGIDURI parses a GID Pure Identity URI, of the format:
+//     urn:epc:id:gid:ManagerNumber.ObjectClass.SerialNumber
+// and returns the GID it represents, or an error if the URI is malformed or its
+// values are out of range. Per the EPC Tag Data Standard, the fields must be
+// decimal integers without leading '0's (other than the value '0' itself).
+func ParseGIDURI(uri string) (GID, error) {
+	if !strings.HasPrefix(uri, GIDPureURIPrefix+":") {
+		return GID{}, errors.Errorf("GID URIs must start with %q", GIDPureURIPrefix+":")
+	}
+
+	return parseGIDFields(uri[len(GIDPureURIPrefix)+1:])
+}
+
+// parseGIDFields parses the '.' separated fields shared by GID Pure Identity
+// and Tag URIs.
+func parseGIDFields(fields string) (GID, error) {
+	parts := strings.Split(fields, ".")
+	if len(parts) != 3 {
+		return GID{}, errors.Errorf("GID URIs have 3 parts separated by "+
+			"'.', but %q has %d", fields, len(parts))
+	}
+
+	var values [3]int
+	for i, p := range parts {
+		if !isDigits(p) || (p[0] == '0' && p != "0") {
+			return GID{}, errors.Errorf("GID field %q must be a decimal "+
+				"integer without leading '0's", p)
+		}
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return GID{}, errors.Wrapf(err, "invalid GID field %q", p)
+		}
+		values[i] = v
+	}
+	return NewGID(values[0], values[1], values[2])
+}
+
+// Encode returns the GID-96 binary encoding of this GID, or an error if its
+// values are out of range.
+func (g GID) Encode() ([]byte, error) {
+	if err := g.ValidateRanges(); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, GID96NumBytes)
+	b[0] = GID96Header
+	putBits(b, gidManagerStartBit, gidManagerLen, uint64(g.manager))
+	putBits(b, gidClassStartBit, gidClassLen, uint64(g.class))
+	putBits(b, gidSerialStartBit, gidSerialLen, uint64(g.serial))
+	return b, nil
+}
+
+// DecodeGIDString accepts a big endian, hex-encoded GID-96 EPC and returns its
+// GID representation, or an error if it cannot be decoded as such.
+func DecodeGIDString(epc string) (GID, error) {
+	b, err := hex.DecodeString(epc)
+	if err != nil {
+		return GID{}, err
+	}
+	return DecodeGID(b)
+}
+
+// DecodeGID decodes a GID-96 encoded EPC to a GID structure, or returns an
+// error if the data cannot be converted to a GID.
+//
+// Since every field of GID-96 uses its full bit range, any GID that decodes
+// without error also passes ValidateRanges.
+func DecodeGID(b []byte) (GID, error) {
+	if len(b) == 0 {
+		return GID{}, errors.New("no data provided")
+	}
+	if b[0] != GID96Header {
+		return GID{}, errors.Errorf("GID-96 header is %#X, but this is: %#X",
+			GID96Header, b[0])
+	}
+	if len(b) != GID96NumBytes {
+		return GID{}, errors.Errorf("GID-96 should have %d bytes, "+
+			"but this has %d bytes", GID96NumBytes, len(b))
+	}
+
+	return GID{
+		manager: int(gidManagerExt.ExtractUInt64(b)),
+		class:   int(gidClassExt.ExtractUInt64(b)),
+		serial:  int(gidSerialExt.ExtractUInt64(b)),
+	}, nil
+}
@@ -0,0 +1,71 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestGID(t *testing.T) {
+	type gidTest struct {
+		manager, class, serial int
+		uri                    string
+	}
+
+	for i, tt := range []gidTest{
+		{95100000, 12345, 400, "95100000.12345.400"},
+		{0, 0, 0, "0.0.0"},
+		{1<<28 - 1, 1<<24 - 1, 1<<36 - 1, "268435455.16777215.68719476735"},
+	} {
+		t.Run(fmt.Sprintf("%02d_%s", i, tt.uri), func(t *testing.T) {
+			w := expect.WrapT(t)
+			g := w.ShouldHaveResult(NewGID(tt.manager, tt.class, tt.serial)).(GID)
+			w.ShouldBeEqual(g.URI(), GIDPureURIPrefix+":"+tt.uri)
+			parsed := w.ShouldHaveResult(ParseGIDURI(g.URI())).(GID)
+			w.ShouldBeEqual(parsed, g)
+
+			b := w.ShouldHaveResult(g.Encode()).([]byte)
+			w.ShouldHaveLength(b, GID96NumBytes)
+			w.ShouldBeEqual(b[0], uint8(GID96Header))
+
+			decoded := w.ShouldHaveResult(DecodeGID(b)).(GID)
+			w.ShouldBeEqual(decoded, g)
+			w.ShouldBeEqual(decoded.ManagerNumber(), tt.manager)
+			w.ShouldBeEqual(decoded.ObjectClass(), tt.class)
+			w.ShouldBeEqual(decoded.Serial(), tt.serial)
+		})
+	}
+}
+
+func TestGID_invalid(t *testing.T) {
+	w := expect.WrapT(t)
+
+	w.ShouldHaveError(NewGID(-1, 0, 0))
+	w.ShouldHaveError(NewGID(1<<28, 0, 0))
+	w.ShouldHaveError(NewGID(0, 1<<24, 0))
+	w.ShouldHaveError(NewGID(0, 0, 1<<36))
+
+	for _, uri := range []string{
+		"",
+		"urn:epc:id:gid:1.2",
+		"urn:epc:id:gid:1.2.3.4",
+		"urn:epc:id:gid:01.2.3",
+		"urn:epc:id:gid:1.-2.3",
+		"urn:epc:id:gid:1..3",
+		"urn:epc:id:gid:268435456.2.3",
+		"urn:epc:id:sgtin:1.2.3",
+	} {
+		w.As(uri).ShouldHaveError(ParseGIDURI(uri))
+	}
+
+	w.ShouldHaveError(DecodeGIDString(""))
+	w.ShouldHaveError(DecodeGIDString("XX"))
+	w.ShouldHaveError(DecodeGIDString("300000000000044000000001"))
+	w.ShouldHaveError(DecodeGIDString("35000000000004400000000100"))
+}
@@ -0,0 +1,145 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+// PrefixLengthLookup determines the length of the GS1 Company Prefix at the
+// start of a GS1 key, such as a GTIN. The gcp package's Table implements it
+// using the GS1 Company Prefix Length Table.
+type PrefixLengthLookup interface {
+	PrefixLength(key string) (int, error)
+}
+
+// FixedPrefixLength is a PrefixLengthLookup that reports the same company
+// prefix length for every key, for when the caller already knows it.
+type FixedPrefixLength int
+
+// PrefixLength returns the FixedPrefixLength, regardless of the key.
+func (l FixedPrefixLength) PrefixLength(key string) (int, error) {
+	return int(l), nil
+}
+
+// NewSGTINFromGTIN returns an SGTIN for the given GTIN and serial.
+//
+// The GTIN may be given as an 8, 12, 13, or 14 digit string, and its check
+// digit must be correct. Since the division between the company prefix and
+// item reference isn't part of the GTIN, the prefixLen lookup is used to find
+// it; it's consulted with the 14 digit form of the GTIN. The SGTIN's partition
+// value is determined from the company prefix length, which must be 6 to 12.
+//
+// As with NewSGTIN, if the error is non-nil because the values are out of range
+// for an SGTIN, the inconsistent SGTIN is still returned. Likewise, if the GTIN
+// is a coupon code, the SGTIN is returned along with a *CouponError.
+func NewSGTINFromGTIN(filter FilterValue, gtin string, prefixLen PrefixLengthLookup, serial string) (SGTIN, error) {
+	gtin14, err := normalizeGTIN(gtin)
+	if err != nil {
+		return SGTIN{}, err
+	}
+
+	length, err := prefixLen.PrefixLength(gtin14)
+	if err != nil {
+		return SGTIN{}, errors.Wrapf(err, "unable to determine the company "+
+			"prefix length of %s", gtin14)
+	}
+	if length < 6 || length > 12 {
+		return SGTIN{}, errors.Errorf("company prefix length must be in "+
+			"[6,12], but is %d", length)
+	}
+
+	indicator := int(gtin14[0] - '0')
+	companyPrefix, _ := strconv.Atoi(gtin14[1 : 1+length])
+	itemRef := 0
+	if length < 12 {
+		itemRef, _ = strconv.Atoi(gtin14[1+length : 13])
+	}
+	s, err := NewSGTIN(filter, 12-length, indicator, companyPrefix, itemRef, serial)
+	if err == nil && classifyGTIN14(gtin14) == Coupon {
+		err = &CouponError{GTIN: gtin14}
+	}
+	return s, err
+}
+
+// GTIN13 returns the 13 digit form of the SGTIN's GTIN, such as is encoded in
+// EAN-13 barcodes, or an error if the GTIN's indicator digit isn't '0', since
+// dropping it would then change the GTIN.
+func (s SGTIN) GTIN13() (string, error) {
+	return s.shortGTIN(13)
+}
+
+// GTIN12 returns the 12 digit form of the SGTIN's GTIN, such as is encoded in
+// U.P.C.-A barcodes, or an error if the 14 digit GTIN doesn't start with "00".
+func (s SGTIN) GTIN12() (string, error) {
+	return s.shortGTIN(12)
+}
+
+// GTIN8 returns the 8 digit form of the SGTIN's GTIN, such as is encoded in
+// EAN-8 barcodes, or an error if the 14 digit GTIN doesn't start with "000000".
+func (s SGTIN) GTIN8() (string, error) {
+	return s.shortGTIN(8)
+}
+
+// shortGTIN returns the last n digits of the SGTIN's GTIN, or an error if any
+// of the digits it would drop aren't '0'.
+func (s SGTIN) shortGTIN(n int) (string, error) {
+	gtin := s.GTIN()
+	if len(gtin) != 14 {
+		return "", errors.Errorf("GTIN %s does not have 14 digits", gtin)
+	}
+	pad := gtin[:14-n]
+	if pad != "00000000000000"[:14-n] {
+		return "", errors.Errorf("GTIN %s cannot be shortened to GTIN-%d "+
+			"without losing the leading digits %s", gtin, n, pad)
+	}
+	return gtin[14-n:], nil
+}
+
+// normalizeGTIN validates a GTIN-8, -12, -13, or -14 and returns it as 14
+// digits, left-padded with '0's.
+func normalizeGTIN(gtin string) (string, error) {
+	switch len(gtin) {
+	case 8, 12, 13, 14:
+	default:
+		return "", errors.Errorf("GTINs must have 8, 12, 13, or 14 digits, "+
+			"but %q has %d", gtin, len(gtin))
+	}
+	if !isDigits(gtin) {
+		return "", errors.Errorf("GTINs may only contain digits, but %q has "+
+			"other characters", gtin)
+	}
+
+	cd := gs1CheckDigit(gtin[:len(gtin)-1])
+	if int(gtin[len(gtin)-1]-'0') != cd {
+		return "", errors.Errorf("invalid check digit for GTIN %s; "+
+			"it should be %d", gtin, cd)
+	}
+	return "00000000000000"[len(gtin):] + gtin, nil
+}
+
+// isDigits returns true if s is non-empty and only contains the digits 0-9.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// gs1CheckDigit returns the GS1 check digit for a string of digits, which must
+// not include the check digit itself. The string is assumed to be only digits.
+// Each digit's weight comes from checkSum, as with SGTIN.checkDigit.
+func gs1CheckDigit(digits string) int {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		sum += checkSum(int(digits[len(digits)-1-i]-'0'), i+1)
+	}
+	return (10 - (sum % 10)) % 10
+}
@@ -0,0 +1,90 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/pkg/errors"
+	"testing"
+)
+
+type noPrefixLengths struct{}
+
+func (noPrefixLengths) PrefixLength(string) (int, error) {
+	return 0, errors.New("unknown")
+}
+
+func TestNewSGTINFromGTIN(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for _, tt := range []struct {
+		gtin      string
+		prefixLen int
+		uri       string
+	}{
+		{"00614141007349", 7, "0614141.000734.314159"},
+		{"0614141007349", 7, "0614141.000734.314159"},
+		{"614141007349", 7, "0614141.000734.314159"},
+		{"80614141123458", 7, "0614141.812345.314159"},
+		{"10011892394440", 12, "001189239444.1.314159"},
+		{"00000010000014", 6, "000001.0000001.314159"},
+	} {
+		s := w.As(tt.gtin).ShouldHaveResult(NewSGTINFromGTIN(POS, tt.gtin,
+			FixedPrefixLength(tt.prefixLen), "314159")).(SGTIN)
+		w.As(tt.gtin).ShouldBeEqual(s.URI(), SGTINPureURIPrefix+":"+tt.uri)
+		w.As(tt.gtin).ShouldBeEqual(s.GTIN()[14-len(tt.gtin):], tt.gtin)
+		w.ShouldBeEqual(s.Partition(), 12-tt.prefixLen)
+	}
+
+	w.As("bad check digit").ShouldHaveError(
+		NewSGTINFromGTIN(POS, "00614141007340", FixedPrefixLength(7), "1"))
+	w.As("non-digit").ShouldHaveError(
+		NewSGTINFromGTIN(POS, "0061414100734X", FixedPrefixLength(7), "1"))
+	w.As("bad length").ShouldHaveError(
+		NewSGTINFromGTIN(POS, "006141410073", FixedPrefixLength(7), "1"))
+	w.As("short prefix").ShouldHaveError(
+		NewSGTINFromGTIN(POS, "00614141007349", FixedPrefixLength(5), "1"))
+	w.As("long prefix").ShouldHaveError(
+		NewSGTINFromGTIN(POS, "00614141007349", FixedPrefixLength(13), "1"))
+	w.As("lookup error").ShouldHaveError(
+		NewSGTINFromGTIN(POS, "00614141007349", noPrefixLengths{}, "1"))
+}
+
+func TestSGTIN_ShortGTINs(t *testing.T) {
+	w := expect.WrapT(t)
+	s := w.ShouldHaveResult(NewSGTINFromGTIN(POS, "614141007349",
+		FixedPrefixLength(7), "1")).(SGTIN)
+	w.ShouldBeEqual(w.ShouldHaveResult(s.GTIN13()), "0614141007349")
+	w.ShouldBeEqual(w.ShouldHaveResult(s.GTIN12()), "614141007349")
+	w.ShouldHaveError(s.GTIN8())
+
+	s = w.ShouldHaveResult(NewSGTINFromGTIN(POS, "96385074",
+		FixedPrefixLength(8), "1")).(SGTIN)
+	w.ShouldBeEqual(w.ShouldHaveResult(s.GTIN13()), "0000096385074")
+	w.ShouldBeEqual(w.ShouldHaveResult(s.GTIN12()), "000096385074")
+	w.ShouldBeEqual(w.ShouldHaveResult(s.GTIN8()), "96385074")
+
+	s = w.ShouldHaveResult(NewSGTINFromGTIN(POS, "4012345678901",
+		FixedPrefixLength(7), "1")).(SGTIN)
+	w.ShouldBeEqual(w.ShouldHaveResult(s.GTIN13()), "4012345678901")
+	w.ShouldHaveError(s.GTIN12())
+	w.ShouldHaveError(s.GTIN8())
+
+	s = w.ShouldHaveResult(NewSGTINFromGTIN(POS, "10614141007346",
+		FixedPrefixLength(7), "1")).(SGTIN)
+	w.ShouldHaveError(s.GTIN13())
+	w.ShouldHaveError(s.GTIN12())
+	w.ShouldHaveError(s.GTIN8())
+}
+
+func TestGS1CheckDigit(t *testing.T) {
+	w := expect.WrapT(t)
+	w.ShouldBeEqual(gs1CheckDigit("0061414100734"), 9)
+	w.ShouldBeEqual(gs1CheckDigit("8061414112345"), 8)
+	w.ShouldBeEqual(gs1CheckDigit("0000000000000"), 0)
+	w.ShouldBeEqual(gs1CheckDigit("9638507"), 4)
+}
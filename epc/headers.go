@@ -0,0 +1,60 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+// headerScheme describes an EPC binary encoding identified by a header byte.
+type headerScheme struct {
+	name      string
+	bitLength int // 0 for variable length encodings
+}
+
+// tdsHeaders maps the header values assigned by the EPC Tag Data Standard to
+// the encoding schemes they identify.
+var tdsHeaders = map[byte]headerScheme{
+	0x2C: {"gdti-96", 96},
+	0x2D: {"gsrn-96", 96},
+	0x2E: {"gsrnp-96", 96},
+	0x2F: {"usdod-96", 96},
+	0x30: {"sgtin-96", 96},
+	0x31: {"sscc-96", 96},
+	0x32: {"sgln-96", 96},
+	0x33: {"grai-96", 96},
+	0x34: {"giai-96", 96},
+	0x35: {"gid-96", 96},
+	0x36: {"sgtin-198", 198},
+	0x37: {"grai-170", 170},
+	0x38: {"giai-202", 202},
+	0x39: {"sgln-195", 195},
+	0x3A: {"gdti-113", 113},
+	0x3B: {"adi-var", 0},
+	0x3C: {"cpi-96", 96},
+	0x3D: {"cpi-var", 0},
+	0x3E: {"gdti-174", 174},
+	0x3F: {"sgcn-96", 96},
+}
+
+// SchemeForHeader returns the name and bit length of the EPC encoding scheme
+// the EPC Tag Data Standard assigns to the given header byte, such as
+// ("sgtin-96", 96), or false if the header isn't assigned. Variable length
+// schemes, such as "adi-var", have a bit length of 0.
+//
+// This reports the schemes defined by the standard, not just those this
+// package can decode; use SupportsHeader to check the latter.
+func SchemeForHeader(header byte) (name string, bitLength int, ok bool) {
+	hs, ok := tdsHeaders[header]
+	return hs.name, hs.bitLength, ok
+}
+
+// SupportsHeader returns true if DecodeEPC can decode EPCs with the given
+// header byte, either because this package implements its scheme or because
+// a decoder was added for it with RegisterScheme.
+func SupportsHeader(header byte) bool {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	_, ok := schemes[header]
+	return ok
+}
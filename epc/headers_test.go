@@ -0,0 +1,49 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestSchemeForHeader(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for header, scheme := range map[byte]headerScheme{
+		0x30: {"sgtin-96", 96},
+		0x36: {"sgtin-198", 198},
+		0x32: {"sgln-96", 96},
+		0x3B: {"adi-var", 0},
+	} {
+		name, bitLength, ok := SchemeForHeader(header)
+		w.As(header).ShouldBeTrue(ok)
+		w.As(header).ShouldBeEqual(name, scheme.name)
+		w.As(header).ShouldBeEqual(bitLength, scheme.bitLength)
+	}
+
+	for _, header := range []byte{0x00, 0x2B, 0x40, 0xE2, 0xFF} {
+		_, _, ok := SchemeForHeader(header)
+		w.As(header).ShouldBeFalse(ok)
+	}
+
+	w.ShouldBeTrue(SupportsHeader(SGTIN96Header))
+	w.ShouldBeTrue(SupportsHeader(SSCC96Header))
+	w.ShouldBeFalse(SupportsHeader(0x32))
+	w.ShouldBeFalse(SupportsHeader(0xE2))
+
+	// the headers this package implements are assigned by the TDS
+	for header, scheme := range map[byte]string{
+		SGTIN96Header: "sgtin", SGTIN198Header: "sgtin",
+		SSCC96Header: "sscc", GID96Header: "gid",
+	} {
+		w.As(header).ShouldBeTrue(SupportsHeader(header))
+		name, _, ok := SchemeForHeader(header)
+		w.As(header).ShouldBeTrue(ok)
+		w.As(header).ShouldContainStr(name, scheme)
+	}
+}
@@ -0,0 +1,73 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+// Canonical keys start with the EPC's scheme and this separator, so that keys
+// of one scheme sort together, and a scheme can't be a prefix of another.
+const keySchemeSep = 0
+
+// CanonicalKey returns a key identifying this SGTIN which sorts by company
+// prefix, then item reference, then serial. Its layout is:
+//     "sgtin" 0x00 CompanyPrefix '.' IndicatorAndItemRef '.' Serial
+// The company prefix and item reference together always have 13 digits, and
+// since '.' sorts before the digits, range-scanning by the key of a company
+// prefix or GTIN is a matter of using this layout up through the '.' following
+// it. The serial is unescaped, and it's compared byte-wise, not numerically.
+//
+// As with Pure Identity URIs, the filter and partition aren't included, so
+// keys are equal if and only if the SGTINs' URIs are equal.
+func (s SGTIN) CanonicalKey() []byte {
+	dst := make([]byte, 0, len("sgtin")+16+len(s.serial))
+	dst = append(dst, "sgtin"...)
+	dst = append(dst, keySchemeSep)
+	dst = appendPadded(dst, s.companyPrefix, 12-s.partition)
+	dst = append(dst, '.')
+	dst = appendPadded(dst, s.indicator, 1)
+	if s.partition != 0 {
+		dst = appendPadded(dst, s.itemRef, s.partition)
+	}
+	dst = append(dst, '.')
+	return append(dst, s.serial...)
+}
+
+// CanonicalKey returns a key identifying this SSCC which sorts by company
+// prefix, then serial reference. Its layout is:
+//     "sscc" 0x00 CompanyPrefix '.' SerialReference
+// The company prefix and serial reference together always have 17 digits, so
+// as with SGTINs, keys may be range-scanned by company prefix.
+func (s SSCC) CanonicalKey() []byte {
+	dst := make([]byte, 0, len("sscc")+19)
+	dst = append(dst, "sscc"...)
+	dst = append(dst, keySchemeSep)
+	dst = appendPadded(dst, s.companyPrefix, 12-s.partition)
+	dst = append(dst, '.')
+	return appendPadded(dst, s.serialRef, 5+s.partition)
+}
+
+// Widths of GID fields in canonical keys: the number of decimal digits in the
+// largest value each field may hold.
+const (
+	gidManagerDigits = 9  // 2^28-1 = 268435455
+	gidClassDigits   = 8  // 2^24-1 = 16777215
+	gidSerialDigits  = 11 // 2^36-1 = 68719476735
+)
+
+// CanonicalKey returns a key identifying this GID which sorts numerically by
+// general manager number, then object class, then serial. Its layout is:
+//     "gid" 0x00 ManagerNumber '.' ObjectClass '.' SerialNumber
+// where the fields are zero-padded to 9, 8, and 11 digits, respectively, the
+// most each may have.
+func (g GID) CanonicalKey() []byte {
+	dst := make([]byte, 0, len("gid")+31)
+	dst = append(dst, "gid"...)
+	dst = append(dst, keySchemeSep)
+	dst = appendPadded(dst, g.manager, gidManagerDigits)
+	dst = append(dst, '.')
+	dst = appendPadded(dst, g.class, gidClassDigits)
+	dst = append(dst, '.')
+	return appendPadded(dst, g.serial, gidSerialDigits)
+}
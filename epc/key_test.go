@@ -0,0 +1,77 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"bytes"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"sort"
+	"testing"
+)
+
+func TestCanonicalKey(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for _, tt := range []struct {
+		uri string
+		key string
+	}{
+		{"urn:epc:id:sgtin:0614141.812345.6789", "sgtin\x000614141.812345.6789"},
+		{"urn:epc:id:sgtin:0614141.812345.a%2Fb", "sgtin\x000614141.812345.a/b"},
+		{"urn:epc:id:sgtin:061414112345.8.0", "sgtin\x00061414112345.8.0"},
+		{"urn:epc:id:sscc:0614141.1234567890", "sscc\x000614141.1234567890"},
+		{"urn:epc:id:gid:95100000.12345.399", "gid\x00095100000.00012345.00000000399"},
+	} {
+		e := w.ShouldHaveResult(ParsePureIdentityURI(tt.uri)).(EPC)
+		w.As(tt.uri).ShouldBeEqual(string(e.CanonicalKey()), tt.key)
+	}
+
+	// the filter and encoding don't change the key
+	s := w.ShouldHaveResult(NewSGTIN(POS, 5, 8, 614141, 12345, "6789")).(SGTIN)
+	b96 := w.ShouldHaveResult(s.WithFilter(UnitLoad).EncodeSGTIN96()).([]byte)
+	b198 := w.ShouldHaveResult(s.EncodeSGTIN198()).([]byte)
+	e96 := w.ShouldHaveResult(DecodeEPC(b96)).(EPC)
+	e198 := w.ShouldHaveResult(DecodeEPC(b198)).(EPC)
+	w.ShouldBeEqual(e96.CanonicalKey(), s.CanonicalKey())
+	w.ShouldBeEqual(e198.CanonicalKey(), s.CanonicalKey())
+}
+
+func TestCanonicalKey_order(t *testing.T) {
+	w := expect.WrapT(t)
+
+	// in the order their keys should sort
+	uris := []string{
+		"urn:epc:id:gid:9.12345.399",
+		"urn:epc:id:gid:10.0.0",
+		"urn:epc:id:gid:95100000.12345.399",
+		"urn:epc:id:sgtin:0614141.012345.1",
+		"urn:epc:id:sgtin:0614141.812345.1",
+		"urn:epc:id:sgtin:0614141.812345.10",
+		"urn:epc:id:sgtin:0614141.812345.9",
+		"urn:epc:id:sgtin:06141410.12345.1",
+		"urn:epc:id:sgtin:0614142.012345.1",
+		"urn:epc:id:sscc:0614141.1234567890",
+	}
+
+	keys := make([][]byte, len(uris))
+	for i, uri := range uris {
+		keys[i] = w.ShouldHaveResult(ParsePureIdentityURI(uri)).(EPC).CanonicalKey()
+	}
+	w.ShouldBeTrue(sort.SliceIsSorted(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	}))
+
+	// range-scan the SGTINs of a company prefix
+	prefix := []byte("sgtin\x000614141.")
+	n := 0
+	for _, k := range keys {
+		if bytes.HasPrefix(k, prefix) {
+			n++
+		}
+	}
+	w.ShouldBeEqual(n, 4)
+}
@@ -0,0 +1,49 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+// MarshalText implements encoding.TextMarshaler, using the SGTIN's Pure
+// Identity URI. It returns an error if the SGTIN's values are out of range.
+func (s SGTIN) MarshalText() ([]byte, error) {
+	if err := s.ValidateRanges(); err != nil {
+		return nil, err
+	}
+	return []byte(s.URI()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing a Pure Identity
+// URI with ParseSGTINURI. Since the URI doesn't include a filter value, the
+// SGTIN's filter is set to Other.
+func (s *SGTIN) UnmarshalText(text []byte) error {
+	parsed, err := ParseSGTINURI(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, using the SGTIN-96 binary
+// encoding if the serial permits it, and otherwise SGTIN-198.
+func (s SGTIN) MarshalBinary() ([]byte, error) {
+	return s.Encode()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding SGTIN-96 or
+// SGTIN-198 data with DecodeSGTIN. Unlike DecodeSGTIN, it returns an error if
+// the SGTIN's values are out of range.
+func (s *SGTIN) UnmarshalBinary(data []byte) error {
+	decoded, err := DecodeSGTIN(data)
+	if err != nil {
+		return err
+	}
+	if err := decoded.ValidateRanges(); err != nil {
+		return err
+	}
+	*s = decoded
+	return nil
+}
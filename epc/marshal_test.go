@@ -0,0 +1,91 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestSGTIN_MarshalText(t *testing.T) {
+	w := expect.WrapT(t)
+
+	s := w.ShouldHaveResult(DecodeSGTINString("36143639F84191A465D9B37A176C5EB1769D72E557D52E5CBC")).(SGTIN)
+	counts := map[SGTIN]int{s: 3}
+	data := w.ShouldHaveResult(json.Marshal(counts)).([]byte)
+	w.ShouldBeEqual(string(data),
+		`{"urn:epc:id:sgtin:0888446.067142.Hello!;1=1;'..*_*..%2F":3}`)
+
+	decoded := map[SGTIN]int{}
+	w.ShouldSucceed(json.Unmarshal(data, &decoded))
+	w.ShouldBeEqual(decoded, counts)
+
+	var config struct{ Item SGTIN }
+	w.ShouldSucceed(json.Unmarshal(
+		[]byte(`{"Item": "urn:epc:id:sgtin:0614141.812345.6789"}`), &config))
+	w.ShouldBeEqual(config.Item.GTIN(), "80614141123458")
+	w.ShouldBeEqual(config.Item.Serial(), "6789")
+
+	w.ShouldFail(json.Unmarshal([]byte(`{"Item": "urn:epc:id:sgtin:0614141.6789"}`), &config))
+	w.ShouldHaveError(SGTIN{}.MarshalText())
+}
+
+func TestSGTIN_MarshalBinary(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for _, epc := range []string{
+		"3074257BF7194E4000001A85",
+		"36143639F84191A465D9B37A176C5EB1769D72E557D52E5CBC",
+	} {
+		s := w.ShouldHaveResult(DecodeSGTINString(epc)).(SGTIN)
+		s.filter = POS
+
+		buff := &bytes.Buffer{}
+		w.ShouldSucceed(gob.NewEncoder(buff).Encode(s))
+		var decoded SGTIN
+		w.ShouldSucceed(gob.NewDecoder(buff).Decode(&decoded))
+		w.ShouldBeEqual(decoded, s)
+	}
+
+	var s SGTIN
+	w.ShouldFail(s.UnmarshalBinary([]byte{SGTIN96Header}))
+	w.ShouldFail(s.UnmarshalBinary([]byte{0x30, 0x10, 0x00, 0x18, 0x1C,
+		0x7F, 0xFF, 0xD3, 0xA8, 0xB4, 0x37, 0x11}))
+}
+
+func TestParseSGTINURI(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for _, uri := range []string{
+		"urn:epc:id:sgtin:0614141.812345.6789",
+		"urn:epc:id:sgtin:000000000001.1.1",
+		"urn:epc:id:sgtin:000001.0000001.1",
+		"urn:epc:id:sgtin:0888446.067142.Hello!;1=1;'..*_*..%2F",
+		"urn:epc:id:sgtin:0614141.000734.007",
+	} {
+		s := w.As(uri).ShouldHaveResult(ParseSGTINURI(uri)).(SGTIN)
+		w.As(uri).ShouldBeEqual(s.URI(), uri)
+	}
+
+	for _, uri := range []string{
+		"",
+		"urn:epc:id:sgtin:",
+		"urn:epc:id:sgtin:0614141.812345",
+		"urn:epc:id:sgtin:0614141.81234.6789",
+		"urn:epc:id:sgtin:0614141.8123456.6789",
+		"urn:epc:id:sgtin:06141.81234567.6789",
+		"urn:epc:id:sgtin:061414A.812345.6789",
+		"urn:epc:id:sgtin:0614141.812345.",
+		"urn:epc:id:sgtin:0614141.812345.abc~",
+		"urn:epc:id:sscc:0614141.1234567890",
+	} {
+		w.As(uri).ShouldHaveError(ParseSGTINURI(uri))
+	}
+}
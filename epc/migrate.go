@@ -0,0 +1,101 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+// MigrationRecord is an audit record of an EPC converted from one scheme to
+// another: it holds the binary encodings and Pure Identity URIs of both the
+// original EPC and the one that replaces it.
+type MigrationRecord struct {
+	OldEPC []byte
+	OldURI string
+	NewEPC []byte
+	NewURI string
+}
+
+// String formats the record as a single line suitable for an audit log.
+func (r MigrationRecord) String() string {
+	return fmt.Sprintf("%X (%s) -> %X (%s)", r.OldEPC, r.OldURI, r.NewEPC, r.NewURI)
+}
+
+// Migrator converts binary-encoded EPCs from one scheme to another.
+type Migrator interface {
+	Migrate(old []byte) (MigrationRecord, error)
+}
+
+// GIDToSGTIN is a Migrator that re-encodes GID-96 EPCs as SGTINs.
+//
+// GIDs don't carry any GS1 information, so the mapping has to be supplied by
+// the caller: CompanyPrefixes maps General Manager Numbers to the GS1 Company
+// Prefix that replaces them, and ItemReference maps each Object Class to an
+// item reference. Each company prefix must be given as a string of 6 to 12
+// digits, as its length determines the SGTIN's partition value.
+//
+// The GID's serial number is used as the SGTIN serial. Since GID-96 serials
+// are numeric and fewer than 38 bits, the new EPC is always encoded as SGTIN-96.
+type GIDToSGTIN struct {
+	CompanyPrefixes map[int]string
+
+	// ItemReference returns the item reference for the given GID fields; if it
+	// is nil, the Object Class is used as the item reference directly.
+	ItemReference func(manager, class int) (int, error)
+
+	Indicator int
+	Filter    FilterValue
+}
+
+// Migrate decodes old as a GID-96 and returns a MigrationRecord with its SGTIN
+// replacement, or an error if old isn't a GID-96 or the mapping doesn't produce
+// a valid SGTIN for it.
+func (m GIDToSGTIN) Migrate(old []byte) (MigrationRecord, error) {
+	gid, err := DecodeGID(old)
+	if err != nil {
+		return MigrationRecord{}, err
+	}
+
+	prefix, ok := m.CompanyPrefixes[gid.manager]
+	if !ok {
+		return MigrationRecord{}, errors.Errorf("no company prefix is mapped "+
+			"to general manager number %d", gid.manager)
+	}
+	if len(prefix) < 6 || len(prefix) > 12 || !isDigits(prefix) {
+		return MigrationRecord{}, errors.Errorf("company prefix %q must have "+
+			"6 to 12 digits", prefix)
+	}
+	companyPrefix, _ := strconv.Atoi(prefix)
+
+	itemRef := gid.class
+	if m.ItemReference != nil {
+		if itemRef, err = m.ItemReference(gid.manager, gid.class); err != nil {
+			return MigrationRecord{}, errors.Wrapf(err,
+				"unable to map object class %d", gid.class)
+		}
+	}
+
+	sgtin, err := NewSGTIN(m.Filter, 12-len(prefix), m.Indicator,
+		companyPrefix, itemRef, strconv.Itoa(gid.serial))
+	if err != nil {
+		return MigrationRecord{}, errors.Wrapf(err, "%s maps to an invalid SGTIN",
+			gid.URI())
+	}
+	b, err := sgtin.EncodeSGTIN96()
+	if err != nil {
+		return MigrationRecord{}, err
+	}
+
+	return MigrationRecord{
+		OldEPC: old,
+		OldURI: gid.URI(),
+		NewEPC: b,
+		NewURI: sgtin.URI(),
+	}, nil
+}
@@ -0,0 +1,64 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/pkg/errors"
+	"testing"
+)
+
+func TestGIDToSGTIN_Migrate(t *testing.T) {
+	w := expect.WrapT(t)
+
+	m := GIDToSGTIN{
+		CompanyPrefixes: map[int]string{95100000: "0614141"},
+		Filter:          POS,
+	}
+
+	gid := w.ShouldHaveResult(NewGID(95100000, 734, 314159)).(GID)
+	old := w.ShouldHaveResult(gid.Encode()).([]byte)
+
+	rec := w.ShouldHaveResult(m.Migrate(old)).(MigrationRecord)
+	w.ShouldBeEqual(rec.OldEPC, old)
+	w.ShouldBeEqual(rec.OldURI, "urn:epc:id:gid:95100000.734.314159")
+	w.ShouldBeEqual(rec.NewURI, "urn:epc:id:sgtin:0614141.000734.314159")
+
+	s := w.ShouldHaveResult(DecodeSGTIN(rec.NewEPC)).(SGTIN)
+	w.ShouldBeEqual(s.Filter(), POS)
+	w.ShouldBeEqual(s.GTIN(), "00614141007349")
+	w.ShouldContainStr(rec.String(), rec.NewURI)
+
+	m.Indicator = 1
+	m.ItemReference = func(manager, class int) (int, error) {
+		if class == 734 {
+			return 42, nil
+		}
+		return 0, errors.New("unknown class")
+	}
+	rec = w.ShouldHaveResult(m.Migrate(old)).(MigrationRecord)
+	w.ShouldBeEqual(rec.NewURI, "urn:epc:id:sgtin:0614141.100042.314159")
+
+	other := w.ShouldHaveResult(NewGID(95100000, 1, 1)).(GID)
+	w.As("unmapped class").ShouldFail(m.Migrate(
+		w.ShouldHaveResult(other.Encode()).([]byte)))
+
+	other = w.ShouldHaveResult(NewGID(1, 734, 1)).(GID)
+	w.As("unmapped manager").ShouldFail(m.Migrate(
+		w.ShouldHaveResult(other.Encode()).([]byte)))
+
+	m.ItemReference = func(manager, class int) (int, error) { return 1000000, nil }
+	w.As("item ref too large").ShouldFail(m.Migrate(old))
+
+	w.As("not a GID").ShouldFail(m.Migrate(make([]byte, 12)))
+
+	m.ItemReference = nil
+	for _, prefix := range []string{"+614141", "-614141", "061414A", "06141", ""} {
+		m.CompanyPrefixes[95100000] = prefix
+		w.As(prefix).ShouldFail(m.Migrate(old))
+	}
+}
@@ -0,0 +1,118 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+// NDCFormat identifies how a 10 digit U.S. National Drug Code is divided into
+// its labeler, product, and package code segments. The division isn't part of
+// the NDC's digits, so it must be known from another source, such as the FDA's
+// NDC Directory or the drug's labeling.
+type NDCFormat int
+
+const (
+	NDC442 = NDCFormat(iota) // 4 digit labeler, 4 digit product, 2 digit package
+	NDC532                   // 5 digit labeler, 3 digit product, 2 digit package
+	NDC541                   // 5 digit labeler, 4 digit product, 1 digit package
+)
+
+func (f NDCFormat) String() string {
+	switch f {
+	case NDC442:
+		return "4-4-2"
+	case NDC532:
+		return "5-3-2"
+	case NDC541:
+		return "5-4-1"
+	}
+	return "Unknown NDC format: " + strconv.Itoa(int(f))
+}
+
+// segments returns the lengths of the labeler and product codes; the remaining
+// digits of the NDC are the package code.
+func (f NDCFormat) segments() (labeler, product int, err error) {
+	switch f {
+	case NDC442:
+		return 4, 4, nil
+	case NDC532:
+		return 5, 3, nil
+	case NDC541:
+		return 5, 4, nil
+	}
+	return 0, 0, errors.Errorf("unknown NDC format: %d", f)
+}
+
+// NDC is a U.S. National Drug Code, split into its segments.
+type NDC struct {
+	Labeler string
+	Product string
+	Package string
+}
+
+// String returns the NDC with its segments separated by hyphens.
+func (n NDC) String() string {
+	return n.Labeler + "-" + n.Product + "-" + n.Package
+}
+
+// NDC11 returns the 11 digit, 5-4-2 form of the NDC used in billing, in which
+// each segment is left-padded with '0's, separated by hyphens.
+func (n NDC) NDC11() string {
+	return "00000"[len(n.Labeler):] + n.Labeler + "-" +
+		"0000"[len(n.Product):] + n.Product + "-" +
+		"00"[len(n.Package):] + n.Package
+}
+
+// NDCFromGTIN returns the National Drug Code embedded in a GTIN-12, -13, or -14,
+// split according to the given format.
+//
+// GS1 US reserves GS1 Prefix 03 (U.P.C. number system 3) for drugs and other
+// health care products, and the 10 digits following it in the GTIN are the
+// product's NDC. GTIN-14s with non-zero indicator digits (e.g., cases) hold the
+// same NDC as their contents. This returns an error if the GTIN is invalid or
+// doesn't have prefix 03.
+func NDCFromGTIN(gtin string, format NDCFormat) (NDC, error) {
+	if len(gtin) == 8 {
+		return NDC{}, errors.New("GTIN-8s do not hold NDCs")
+	}
+	gtin14, err := normalizeGTIN(gtin)
+	if err != nil {
+		return NDC{}, err
+	}
+	return ndcFromGTIN14(gtin14, format)
+}
+
+// NDC returns the National Drug Code embedded in the SGTIN's GTIN, split
+// according to the given format; see NDCFromGTIN.
+func (s SGTIN) NDC(format NDCFormat) (NDC, error) {
+	gtin := s.GTIN()
+	if len(gtin) != 14 || !isDigits(gtin) {
+		return NDC{}, errors.Errorf("GTIN %s is not valid", gtin)
+	}
+	return ndcFromGTIN14(gtin, format)
+}
+
+// ndcFromGTIN14 splits the NDC from a valid, 14 digit GTIN.
+func ndcFromGTIN14(gtin14 string, format NDCFormat) (NDC, error) {
+	labeler, product, err := format.segments()
+	if err != nil {
+		return NDC{}, err
+	}
+	if gtin14[1:3] != "03" {
+		return NDC{}, errors.Errorf("GTIN %s does not hold an NDC, since "+
+			"it does not have GS1 Prefix 03", gtin14)
+	}
+
+	ndc := gtin14[3:13]
+	return NDC{
+		Labeler: ndc[:labeler],
+		Product: ndc[labeler : labeler+product],
+		Package: ndc[labeler+product:],
+	}, nil
+}
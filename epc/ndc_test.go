@@ -0,0 +1,42 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestNDCFromGTIN(t *testing.T) {
+	for _, tt := range []struct {
+		gtin   string
+		format NDCFormat
+		ndc    string
+		ndc11  string
+	}{
+		{"305730145305", NDC442, "0573-0145-30", "00573-0145-30"},
+		{"0305730145305", NDC532, "05730-145-30", "05730-0145-30"},
+		{"00305730145305", NDC541, "05730-1453-0", "05730-1453-00"},
+		{"50305730145300", NDC442, "0573-0145-30", "00573-0145-30"},
+	} {
+		w := expect.WrapT(t).As(tt.gtin)
+		ndc := w.ShouldHaveResult(NDCFromGTIN(tt.gtin, tt.format)).(NDC)
+		w.ShouldBeEqual(ndc.String(), tt.ndc)
+		w.ShouldBeEqual(ndc.NDC11(), tt.ndc11)
+
+		s := w.ShouldHaveResult(NewSGTINFromGTIN(POS, tt.gtin,
+			FixedPrefixLength(7), "1")).(SGTIN)
+		w.ShouldBeEqual(w.ShouldHaveResult(s.NDC(tt.format)), ndc)
+	}
+
+	w := expect.WrapT(t)
+	w.ShouldHaveError(NDCFromGTIN("00614141007349", NDC442))
+	w.ShouldHaveError(NDCFromGTIN("03614141007346", NDC442))
+	w.ShouldHaveError(NDCFromGTIN("305730145306", NDC442))
+	w.ShouldHaveError(NDCFromGTIN("96385074", NDC442))
+	w.ShouldHaveError(NDCFromGTIN("305730145305", NDCFormat(3)))
+}
@@ -0,0 +1,39 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultONSDomain is the root domain of GS1's Object Name Service.
+const DefaultONSDomain = "onsepc.com"
+
+// ONSHostname returns the DNS name at which the GS1 Object Name Service (ONS)
+// publishes records for the SGTIN's class of product, using the given root
+// domain, or DefaultONSDomain if domain is empty.
+//
+// The name is formed from the SGTIN's Pure Identity URI by dropping the serial
+// number (since ONS records describe products, not instances), reversing the
+// remaining fields, and appending "id" and the root domain. For example,
+// urn:epc:id:sgtin:0614141.812345.6789 becomes:
+//     812345.0614141.sgtin.id.onsepc.com
+//
+// The SGTIN's values are not validated.
+func (s SGTIN) ONSHostname(domain string) string {
+	if domain == "" {
+		domain = DefaultONSDomain
+	}
+	domain = strings.TrimSuffix(strings.TrimPrefix(domain, "."), ".")
+
+	iir := strconv.Itoa(s.indicator)
+	if s.partition > 0 {
+		iir += s.ItemReference()
+	}
+	return iir + "." + s.CompanyPrefix() + ".sgtin.id." + domain
+}
@@ -0,0 +1,23 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestSGTIN_ONSHostname(t *testing.T) {
+	w := expect.WrapT(t)
+	s := w.ShouldHaveResult(ParseSGTINURI("urn:epc:id:sgtin:0614141.812345.6789")).(SGTIN)
+	w.ShouldBeEqual(s.ONSHostname(""), "812345.0614141.sgtin.id.onsepc.com")
+	w.ShouldBeEqual(s.ONSHostname("ons.example.com."),
+		"812345.0614141.sgtin.id.ons.example.com")
+
+	s = w.ShouldHaveResult(ParseSGTINURI("urn:epc:id:sgtin:061414100734.1.6789")).(SGTIN)
+	w.ShouldBeEqual(s.ONSHostname(""), "1.061414100734.sgtin.id.onsepc.com")
+}
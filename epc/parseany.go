@@ -0,0 +1,168 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"net/url"
+	"strings"
+)
+
+// ParseOption configures optional behavior of ParseAny.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	prefixLen PrefixLengthLookup
+	filter    FilterValue
+}
+
+// WithPrefixLengths sets the lookup ParseAny uses to find the length of the GS1
+// Company Prefix in GTINs from element strings and Digital Link URIs, since,
+// unlike EPCs, they don't indicate it. Without it, ParseAny returns an error
+// for those forms.
+func WithPrefixLengths(prefixLen PrefixLengthLookup) ParseOption {
+	return func(o *parseOptions) {
+		o.prefixLen = prefixLen
+	}
+}
+
+// WithGTINFilter sets the filter value of SGTINs ParseAny creates from element
+// strings and Digital Link URIs, which don't include one. By default, it's 0.
+func WithGTINFilter(filter FilterValue) ParseOption {
+	return func(o *parseOptions) {
+		o.filter = filter
+	}
+}
+
+// ParseAny parses an EPC given in any of the following forms, and returns the
+// EPC it represents, or an error if it can't:
+//   - an EPC Pure Identity URI, such as urn:epc:id:sgtin:0614141.812345.6789
+//   - an EPC Tag URI, such as urn:epc:tag:sgtin-96:1.0614141.812345.6789
+//   - a GS1 Digital Link URI with a GTIN and serial, such as
+//     https://id.gs1.org/01/80614141123458/21/6789
+//   - a GS1 element string with a GTIN and serial, either with parentheses, as
+//     in (01)80614141123458(21)6789, or without, as in 0180614141123458216789
+//   - hex-encoded binary EPC data, optionally prefixed with "0x", as decoded
+//     by DecodeEPC
+//
+// Leading and trailing whitespace is ignored. Element strings and Digital Link
+// URIs require the WithPrefixLengths option. Unlike the scheme-specific parsers,
+// EPCs decoded from binary data aren't validated; use ValidateRanges to do so.
+// Likewise, coupon GTINs aren't rejected; use SGTIN's ValidateClass for that.
+func ParseAny(s string, opts ...ParseOption) (EPC, error) {
+	var o parseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return nil, errors.New("no data provided")
+	case strings.HasPrefix(s, PureIdentityURIPrefix):
+		return ParsePureIdentityURI(s)
+	case strings.HasPrefix(s, TagURIPrefix):
+		e, _, err := ParseTagURI(s)
+		return e, err
+	case strings.HasPrefix(s, "http://"), strings.HasPrefix(s, "https://"):
+		return o.parseDigitalLink(s)
+	case s[0] == '(':
+		return o.parseElementString(s)
+	case strings.HasPrefix(s, "01") && isDigits(s):
+		// hex EPCs don't start with "01", since there's no such header
+		return o.parseElementString(s)
+	}
+
+	h := s
+	if strings.HasPrefix(h, "0x") || strings.HasPrefix(h, "0X") {
+		h = h[2:]
+	}
+	b, err := hex.DecodeString(h)
+	if err != nil {
+		return nil, errors.Errorf("%q is not an EPC URI, GS1 element string, "+
+			"Digital Link URI, or hex-encoded EPC", s)
+	}
+	return DecodeEPC(b)
+}
+
+// parseElementString parses a GS1 element string consisting of AI (01), a
+// GTIN, followed by AI (21), a serial, with or without the parentheses.
+func (o parseOptions) parseElementString(s string) (EPC, error) {
+	es := s
+	if es[0] == '(' {
+		es = strings.Replace(strings.Replace(es, "(", "", 2), ")", "", 2)
+	}
+	if len(es) < 18 || es[:2] != "01" || es[16:18] != "21" {
+		return nil, errors.Errorf("element string %q must consist of AI (01) "+
+			"with a GTIN-14 followed by AI (21) with a serial", s)
+	}
+	return o.newSGTIN(es[2:16], es[18:])
+}
+
+// parseDigitalLink parses a GS1 Digital Link URI with path segments for AI 01
+// (a GTIN) and AI 21 (a serial). Other segments and any query are ignored.
+func (o parseOptions) parseDigitalLink(s string) (EPC, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid Digital Link URI %q", s)
+	}
+
+	var gtin, serial string
+	segments := strings.Split(strings.Trim(u.EscapedPath(), "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		switch segments[i] {
+		case "01":
+			gtin = segments[i+1]
+		case "21":
+			serial = segments[i+1]
+		default:
+			continue
+		}
+		i++
+	}
+	if gtin == "" || serial == "" {
+		return nil, errors.Errorf("Digital Link URI %q must have both a "+
+			"GTIN (01) and a serial (21)", s)
+	}
+	if serial, err = url.PathUnescape(serial); err != nil {
+		return nil, errors.Wrapf(err, "invalid serial in Digital Link URI %q", s)
+	}
+	return o.newSGTIN(gtin, serial)
+}
+
+func (o parseOptions) newSGTIN(gtin, serial string) (EPC, error) {
+	if o.prefixLen == nil {
+		return nil, errors.New("parsing a GTIN requires a company prefix " +
+			"length lookup; use the WithPrefixLengths option")
+	}
+	s, err := NewSGTINFromGTIN(o.filter, gtin, o.prefixLen, serial)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*CouponError); !ok {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// CanonicalURI returns the Pure Identity URI of an EPC given in any form that
+// ParseAny accepts, such as hex-encoded binary data or a Tag URI, and so is
+// the canonical representation recommended in this package's documentation.
+// Unlike ParseAny, it returns an error if the EPC's values are out of range.
+//
+// Since two EPCs are the same if and only if their Pure Identity URIs are
+// identical, the results may be compared directly.
+func CanonicalURI(input string, opts ...ParseOption) (string, error) {
+	e, err := ParseAny(input, opts...)
+	if err != nil {
+		return "", err
+	}
+	if err := e.ValidateRanges(); err != nil {
+		return "", err
+	}
+	return e.URI(), nil
+}
@@ -0,0 +1,93 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestParseAny(t *testing.T) {
+	w := expect.WrapT(t)
+	opts := []ParseOption{WithPrefixLengths(FixedPrefixLength(7))}
+
+	for _, tt := range []struct {
+		input string
+		uri   string
+	}{
+		{"urn:epc:id:sgtin:0614141.812345.6789", "urn:epc:id:sgtin:0614141.812345.6789"},
+		{"urn:epc:tag:sgtin-96:1.0614141.812345.6789", "urn:epc:id:sgtin:0614141.812345.6789"},
+		{"urn:epc:id:sscc:0614141.1234567890", "urn:epc:id:sscc:0614141.1234567890"},
+		{"urn:epc:tag:gid-96:95100000.12345.399", "urn:epc:id:gid:95100000.12345.399"},
+		{"3034257BF7194E4000001A85", "urn:epc:id:sgtin:0614141.812345.6789"},
+		{"0x3034257bf7194e4000001a85", "urn:epc:id:sgtin:0614141.812345.6789"},
+		{" 3134257BF4499602D2000000\n", "urn:epc:id:sscc:0614141.1234567890"},
+		{"(01)80614141123458(21)6789", "urn:epc:id:sgtin:0614141.812345.6789"},
+		{"0180614141123458216789", "urn:epc:id:sgtin:0614141.812345.6789"},
+		{"(01)80614141123458(21)a/b", "urn:epc:id:sgtin:0614141.812345.a%2Fb"},
+		{"https://id.gs1.org/01/80614141123458/21/6789", "urn:epc:id:sgtin:0614141.812345.6789"},
+		{"https://example.com/products/01/80614141123458/21/a%2Fb?linkType=all",
+			"urn:epc:id:sgtin:0614141.812345.a%2Fb"},
+	} {
+		e := w.As(tt.input).ShouldHaveResult(ParseAny(tt.input, opts...)).(EPC)
+		w.As(tt.input).ShouldBeEqual(e.URI(), tt.uri)
+	}
+
+	for _, input := range []string{
+		"",
+		"   ",
+		"urn:epc:id:grai:0614141.12345.400",
+		"urn:epc:tag:sgtin-64:1.0614141.812345.6789",
+		"3034257BF7194E4000001A8",  // odd length
+		"3034257BF7194E4000001A",   // too short for SGTIN-96
+		"FF34257BF7194E4000001A85", // unknown header
+		"not an epc",
+		"(01)80614141123459(21)6789", // bad check digit
+		"(01)80614141123458",         // no serial
+		"(00)106141411234567897",
+		"https://id.gs1.org/01/80614141123458",
+		"https://id.gs1.org/21/6789",
+	} {
+		w.As(input).ShouldHaveError(ParseAny(input, opts...))
+	}
+
+	// GTINs require a prefix length lookup
+	w.ShouldHaveError(ParseAny("(01)80614141123458(21)6789"))
+	w.ShouldHaveError(ParseAny("https://id.gs1.org/01/80614141123458/21/6789"))
+}
+
+func TestParseAny_filter(t *testing.T) {
+	w := expect.WrapT(t)
+	e := w.ShouldHaveResult(ParseAny("(01)80614141123458(21)6789",
+		WithPrefixLengths(FixedPrefixLength(7)), WithGTINFilter(POS))).(EPC)
+	s := e.(SGTIN)
+	w.ShouldBeEqual(s.Filter(), POS)
+	w.ShouldBeEqual(w.ShouldHaveResult(TagURI(s, 96)),
+		"urn:epc:tag:sgtin-96:1.0614141.812345.6789")
+}
+
+func TestCanonicalURI(t *testing.T) {
+	w := expect.WrapT(t)
+
+	const uri = "urn:epc:id:sgtin:0614141.812345.6789"
+	for _, input := range []string{
+		uri,
+		"urn:epc:tag:sgtin-96:1.0614141.812345.6789",
+		"urn:epc:tag:sgtin-198:2.0614141.812345.6789",
+		"3034257BF7194E4000001A85",
+		"3034257bf7194e4000001a85",
+	} {
+		w.As(input).ShouldBeEqual(w.ShouldHaveResult(CanonicalURI(input)), uri)
+	}
+	w.ShouldBeEqual(w.ShouldHaveResult(CanonicalURI("(01)80614141123458(21)6789",
+		WithPrefixLengths(FixedPrefixLength(7)))), uri)
+
+	w.ShouldHaveError(CanonicalURI("(01)80614141123458(21)6789"))
+	w.ShouldHaveError(CanonicalURI("urn:epc:id:sgtin:0614141.812345"))
+	// decodes, but its company prefix is out of range for partition 5
+	w.ShouldHaveError(CanonicalURI("3034FFFFFFFFFE4000001A85"))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
 	"github.com/pkg/errors"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -131,6 +132,23 @@ func SGTINToGTIN14(epc string) (string, error) {
 	return sgtin.GTIN(), nil
 }
 
+// ExtractGTIN14 returns the GS1 GTIN element string of an SGTIN-96 or SGTIN-198
+// encoded EPC, without decoding its serial. This makes it considerably faster
+// than SGTINToGTIN14 for SGTIN-198, whose serial is 20 packed 7-bit characters.
+//
+// The values that make up the GTIN are validated as ValidateRanges would, but
+// the filter and serial are not.
+func ExtractGTIN14(epc []byte) (string, error) {
+	s, err := DecodeSGTIN(epc, WithoutSerial())
+	if err != nil {
+		return "", err
+	}
+	if err := s.validateGTINRanges(); err != nil {
+		return "", err
+	}
+	return s.GTIN(), nil
+}
+
 // SGTINToPureURI is a convenience method for decoding an SGTIN encoded EPC
 // from a big-endian, hex string to its corresponding GS1 Pure Identity URI.
 //
@@ -147,6 +165,57 @@ func SGTINToPureURI(epc string) (string, error) {
 	return sgtin.URI(), nil
 }
 
+// ParseSGTINURI parses an SGTIN Pure Identity URI, of the format:
+//     urn:epc:id:sgtin:CompanyPrefix.ItemRefAndIndicator.SerialNumber
+// and returns the SGTIN it represents, or an error if the URI is malformed or
+// its values are out of range.
+//
+// The company prefix must have 6 to 12 digits, and together with the indicator
+// and item reference, there must be 13 digits. The serial number's escape
+// sequences are unescaped. Since the filter value isn't part of the Pure
+// Identity URI, the returned SGTIN's filter is always Other.
+func ParseSGTINURI(uri string) (SGTIN, error) {
+	if !strings.HasPrefix(uri, SGTINPureURIPrefix+":") {
+		return SGTIN{}, errors.Errorf("SGTIN URIs must start with %q", SGTINPureURIPrefix+":")
+	}
+
+	return parseSGTINFields(uri[len(SGTINPureURIPrefix)+1:], Other)
+}
+
+// parseSGTINFields parses the '.' separated company prefix, indicator and item
+// reference, and serial fields shared by SGTIN Pure Identity and Tag URIs.
+func parseSGTINFields(fields string, filter FilterValue) (SGTIN, error) {
+	parts := strings.SplitN(fields, ".", 3)
+	if len(parts) != 3 {
+		return SGTIN{}, errors.Errorf("SGTIN URIs have 3 parts separated by "+
+			"'.', but %q has %d", fields, len(parts))
+	}
+	prefix, iir, serial := parts[0], parts[1], parts[2]
+
+	if len(prefix) < 6 || len(prefix) > 12 || !isDigits(prefix) {
+		return SGTIN{}, errors.Errorf("company prefix %q must have 6 to 12 digits", prefix)
+	}
+	if len(prefix)+len(iir) != 13 || !isDigits(iir) {
+		return SGTIN{}, errors.Errorf("indicator and item reference %q must "+
+			"have %d digits", iir, 13-len(prefix))
+	}
+
+	partition := 12 - len(prefix)
+	companyPrefix, _ := strconv.Atoi(prefix)
+	indicator := int(iir[0] - '0')
+	itemRef := 0
+	if partition > 0 {
+		itemRef, _ = strconv.Atoi(iir[1:])
+	}
+	return NewSGTIN(filter, partition, indicator, companyPrefix, itemRef,
+		UnescapeGS1(serial))
+}
+
+// Scheme returns "sgtin", the EPC scheme of SGTINs.
+func (s SGTIN) Scheme() string {
+	return "sgtin"
+}
+
 // ValidateRanges checks an SGTIN's values to ensure they fit the range
 // restrictions of their respective fields.
 //
@@ -156,23 +225,12 @@ func SGTINToPureURI(epc string) (string, error) {
 // method only validates that they fit within the available ranges, but not that
 // they are otherwise legal.
 func (s SGTIN) ValidateRanges() error {
-	if s.indicator < 0 || s.indicator > 9 {
-		return errors.Errorf("indicator must be in [0,9], but is %d", s.indicator)
-	}
 	if !s.filter.IsValid() {
 		return errors.Errorf("filter must be in {0, 1, 3, 4, 6, 7, 8, 9}, "+
 			"but this is: %d", s.filter)
 	}
-	if s.partition < 0 || s.partition > 6 {
-		return errors.Errorf("partition must be in [0,6], but is %d", s.partition)
-	}
-	if s.itemRef < 0 || s.itemRef > maxItems[s.partition]-1 {
-		return errors.Errorf("item refs in partition %d must be in [0, %d], "+
-			"but is %d", s.partition, maxItems[s.partition]-1, s.itemRef)
-	}
-	if s.companyPrefix < 0 || s.companyPrefix > maxPrefix[s.partition] {
-		return errors.Errorf("company prefix in partition %d must be in [0, %d], "+
-			"but is %d", s.partition, maxPrefix[s.partition], s.companyPrefix)
+	if err := s.validateGTINRanges(); err != nil {
+		return err
 	}
 	if s.serial == "" {
 		return errors.New("serial is empty")
@@ -191,6 +249,26 @@ func (s SGTIN) ValidateRanges() error {
 	return nil
 }
 
+// validateGTINRanges checks the ranges of the values that make up the SGTIN's
+// GTIN: its indicator, partition, item reference, and company prefix.
+func (s SGTIN) validateGTINRanges() error {
+	if s.indicator < 0 || s.indicator > 9 {
+		return errors.Errorf("indicator must be in [0,9], but is %d", s.indicator)
+	}
+	if s.partition < 0 || s.partition > 6 {
+		return errors.Errorf("partition must be in [0,6], but is %d", s.partition)
+	}
+	if s.itemRef < 0 || s.itemRef > maxItems[s.partition]-1 {
+		return errors.Errorf("item refs in partition %d must be in [0, %d], "+
+			"but is %d", s.partition, maxItems[s.partition]-1, s.itemRef)
+	}
+	if s.companyPrefix < 0 || s.companyPrefix > maxPrefix[s.partition] {
+		return errors.Errorf("company prefix in partition %d must be in [0, %d], "+
+			"but is %d", s.partition, maxPrefix[s.partition], s.companyPrefix)
+	}
+	return nil
+}
+
 // CanSGTIN96 returns true if the SGTIN's serial may be encoded as SGTIN-96.
 //
 // The EPC Tag Data Standard specifies that SGTIN-96 encoded serial numbers must
@@ -200,31 +278,54 @@ func (s SGTIN) CanSGTIN96() error {
 	if s.serial == "" {
 		return errors.New("serial is empty")
 	}
-	_, err := strconv.ParseUint(s.serial, 10, 38)
-	if err != nil {
-		return errors.Wrap(err, "SGTIN96 serial numbers must be numeric")
+	if !s.IsNumericSerial() {
+		return errors.New("SGTIN96 serial numbers must be numeric and cannot " +
+			"have leading '0's, except for the unique value '0'")
 	}
-	if s.serial[0] == '0' && s.serial != "0" {
-		return errors.New("serials cannot have leading '0's, " +
-			"except for the unique value '0'")
+	if n, ok := s.SerialUint64(); !ok || n >= 1<<serial96Len {
+		return errors.Errorf("SGTIN96 serial numbers must be less than %d",
+			uint64(1)<<serial96Len)
 	}
 	return nil
 }
 
+// IsNumericSerial returns true if the SGTIN's serial is a decimal integer in
+// its canonical form: only the digits 0-9, with no leading '0's, except for the
+// unique value '0'. Serials such as '007' are valid, but are not numeric in this
+// sense, since they are distinct from the serial '7'.
+func (s SGTIN) IsNumericSerial() bool {
+	return isDigits(s.serial) && (s.serial[0] != '0' || s.serial == "0")
+}
+
+// SerialUint64 returns the SGTIN's serial as an integer, or false if the serial
+// isn't numeric (see IsNumericSerial) or is too large for a uint64.
+func (s SGTIN) SerialUint64() (uint64, bool) {
+	if !s.IsNumericSerial() {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(s.serial, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
 // GTIN returns the GS1 GTIN element string represented by this SGTIN.
 func (s SGTIN) GTIN() string {
-	if s.partition == 0 {
-		// no item reference
-		return fmt.Sprintf("%d%012d%d",
-			s.indicator,
-			s.companyPrefix,
-			s.checkDigit())
+	var buf [14]byte
+	return string(s.AppendGTIN(buf[:0]))
+}
+
+// AppendGTIN appends the GS1 GTIN element string represented by this SGTIN to
+// dst and returns the extended buffer. Unlike GTIN, it doesn't allocate, as
+// long as dst has capacity for the GTIN's 14 digits.
+func (s SGTIN) AppendGTIN(dst []byte) []byte {
+	dst = appendPadded(dst, s.indicator, 1)
+	dst = appendPadded(dst, s.companyPrefix, 12-s.partition)
+	if s.partition != 0 {
+		dst = appendPadded(dst, s.itemRef, s.partition)
 	}
-	return fmt.Sprintf("%d%0[2]*d%0[4]*d%d",
-		s.indicator,
-		12-s.partition, s.companyPrefix,
-		s.partition, s.itemRef,
-		s.checkDigit())
+	return appendPadded(dst, s.checkDigit(), 1)
 }
 
 // URI returns the EPC Pure Identity URI for this SGTIN, of the format:
@@ -232,19 +333,53 @@ func (s SGTIN) GTIN() string {
 // The serial number is escaped, if necessary, to conform with GS1 specs, but
 // it is not validated.
 func (s SGTIN) URI() string {
-	if s.partition == 0 {
-		// no item reference; just indicator
-		return fmt.Sprintf("%s:%0[2]*d.%d.%s",
-			SGTINPureURIPrefix,
-			12-s.partition, s.companyPrefix,
-			s.indicator,
-			gs1Escaper.Replace(s.serial))
+	return string(s.AppendURI(make([]byte, 0, len(SGTINPureURIPrefix)+17+3*len(s.serial))))
+}
+
+// String returns the SGTIN's Pure Identity URI, its canonical representation.
+func (s SGTIN) String() string {
+	return s.URI()
+}
+
+// AppendURI appends the EPC Pure Identity URI for this SGTIN to dst and returns
+// the extended buffer, as URI does. It doesn't allocate, as long as dst has
+// enough capacity for the URI; in the worst case, this is 77 bytes, since
+// escaping a 20 character serial may triple its length.
+func (s SGTIN) AppendURI(dst []byte) []byte {
+	dst = append(dst, SGTINPureURIPrefix...)
+	dst = append(dst, ':')
+	dst = appendPadded(dst, s.companyPrefix, 12-s.partition)
+	dst = append(dst, '.')
+	dst = appendPadded(dst, s.indicator, 1)
+	if s.partition != 0 {
+		// partition 0 has no item reference; just indicator
+		dst = appendPadded(dst, s.itemRef, s.partition)
+	}
+	dst = append(dst, '.')
+	return appendGS1Escaped(dst, s.serial)
+}
+
+// appendPadded appends the decimal form of n to dst, left-padded with '0's to
+// the given width, matching fmt's "%0*d" verb.
+func appendPadded(dst []byte, n, width int) []byte {
+	var buf [20]byte
+	if n < 0 {
+		dst = append(dst, '-')
+		width--
+	}
+	digits := strconv.AppendUint(buf[:0], absInt(n), 10)
+	for i := len(digits); i < width; i++ {
+		dst = append(dst, '0')
+	}
+	return append(dst, digits...)
+}
+
+// absInt returns the absolute value of n as a uint64.
+func absInt(n int) uint64 {
+	if n < 0 {
+		return uint64(-int64(n))
 	}
-	return fmt.Sprintf("%s:%0[2]*d.%d%0[5]*d.%s",
-		SGTINPureURIPrefix,
-		12-s.partition, s.companyPrefix,
-		s.indicator, s.partition, s.itemRef,
-		gs1Escaper.Replace(s.serial))
+	return uint64(n)
 }
 
 // checkSum returns the portion of the GS1 check sum that n contributes, given
@@ -313,13 +448,13 @@ var (
 	// company prefix and 10^(partition-1) values to the IIR field; note that
 	// because the indicator is required, partition 0 does not allow any items.
 	companyExt = [7]bitextract.BitExtractor{
-		bitextract.New(gcpStartBit, 40),
-		bitextract.New(gcpStartBit, 37),
-		bitextract.New(gcpStartBit, 34),
-		bitextract.New(gcpStartBit, 30),
-		bitextract.New(gcpStartBit, 27),
-		bitextract.New(gcpStartBit, 24),
-		bitextract.New(gcpStartBit, 20),
+		bitextract.New(gcpStartBit, companyBits[0]),
+		bitextract.New(gcpStartBit, companyBits[1]),
+		bitextract.New(gcpStartBit, companyBits[2]),
+		bitextract.New(gcpStartBit, companyBits[3]),
+		bitextract.New(gcpStartBit, companyBits[4]),
+		bitextract.New(gcpStartBit, companyBits[5]),
+		bitextract.New(gcpStartBit, companyBits[6]),
 	}
 	// indicator digit + item ref
 	iirExt = [7]bitextract.BitExtractor{
@@ -332,6 +467,10 @@ var (
 		bitextract.New(serialStartBit-24, 24),
 	}
 
+	// number of bits allocated to the company prefix in each partition; the
+	// remaining bits of the 44 bit field hold the indicator and item ref
+	companyBits = [7]int{40, 37, 34, 30, 27, 24, 20}
+
 	// max number of item references that each partition allows = (10^partition)
 	// note: partition 0 doesn't really allow any items, as the company prefix
 	// takes the entire field. it can be thought of as a single item, though
@@ -358,6 +497,44 @@ var (
 	}
 )
 
+// DecodeOption configures optional behavior of DecodeSGTIN.
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	withoutSerial      bool
+	strict             bool
+	allowCharAfterNull bool
+}
+
+// WithoutSerial skips decoding an SGTIN's serial, leaving it empty, for callers
+// that only need the GTIN. This avoids unpacking SGTIN-198's 7-bit characters.
+// Note that an SGTIN without a serial fails ValidateRanges.
+func WithoutSerial() DecodeOption {
+	return func(o *decodeOptions) {
+		o.withoutSerial = true
+	}
+}
+
+// Strict validates the SGTIN and its encoding as ValidateSGTINStrict does,
+// without a company prefix length lookup, and returns its error, if any. The
+// serial is always decoded for validation, even if WithoutSerial is also used.
+func Strict() DecodeOption {
+	return func(o *decodeOptions) {
+		o.strict = true
+	}
+}
+
+// AllowCharAfterNull sets whether DecodeSGTIN accepts SGTIN-198 serials with
+// non-null characters following a null character. By default, it does, and
+// the returned serial includes all 20 characters so that it may be validated
+// later; if allow is false, DecodeSGTIN returns an error for such serials.
+// Since it only applies to decoded serials, it has no effect with WithoutSerial.
+func AllowCharAfterNull(allow bool) DecodeOption {
+	return func(o *decodeOptions) {
+		o.allowCharAfterNull = allow
+	}
+}
+
 // DecodeSGTIN decodes SGTIN-96 and SGTIN-198 encoded EPCs to SGTIN structures,
 // or returns an error if the data cannot be converted to an SGTIN.
 //
@@ -368,13 +545,20 @@ var (
 // otherwise validate that the values fall within the range of acceptable, non-
 // reserved, encodeable values as defined by the EPC Tag Data Standard.
 //
-// Use ValidateRanges to check the values are within the EPC ranges.
+// Use ValidateRanges to check the values are within the EPC ranges, or the
+// Strict option to validate them while decoding.
 //
 // This function evaluates the MSB of the first byte as the MSB of the EPC data.
 // For SGTIN-198, the data's leading bit should be the first bit of the first
 // byte, and the final byte should be padded with two trailing 0s, since 198
 // bits is not otherwise byte-aligned.
-func DecodeSGTIN(b []byte) (SGTIN, error) {
+func DecodeSGTIN(b []byte, opts ...DecodeOption) (SGTIN, error) {
+	o := decodeOptions{allowCharAfterNull: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	decodeSerial := !o.withoutSerial || o.strict
+
 	if len(b) == 0 {
 		return SGTIN{}, errors.New("no data provided")
 	}
@@ -386,15 +570,24 @@ func DecodeSGTIN(b []byte) (SGTIN, error) {
 			return SGTIN{}, errors.Errorf("SGTIN-96 should have %d bytes, "+
 				"but this has %d bytes", SGTIN96NumBytes, len(b))
 		}
-		serial = fmt.Sprintf("%d", int(serial96Ext.ExtractUInt64(b)))
+		if decodeSerial {
+			serial = strconv.FormatUint(serial96Ext.ExtractUInt64(b), 10)
+		}
 	case SGTIN198Header:
 		if len(b) != SGTIN198NumBytes {
 			return SGTIN{}, errors.Errorf("SGTIN-198 should have %d bytes, "+
 				"but this has %d bytes", SGTIN198NumBytes, len(b))
 		}
+		if !decodeSerial {
+			break
+		}
 		// SGTIN-198 serials are 20, 7-bit ISO 646 values
 		s, n, charAfterNull := DecodeASCIIAt(b[serialStartByte:], serialOffsetBit)
 		if charAfterNull {
+			if !o.allowCharAfterNull {
+				return SGTIN{}, errors.New("SGTIN-198 serial has " +
+					"characters after its null terminator")
+			}
 			serial = s // technically, invalid, but available for validation
 		} else {
 			serial = s[:n] // null terminated
@@ -420,17 +613,111 @@ func DecodeSGTIN(b []byte) (SGTIN, error) {
 	indicator := iir / maxItems[partition]
 	itemRef := 0
 	if partition > 0 {
-		itemRef = iir - (indicator * maxItems[partition] * 10)
+		itemRef = iir - (indicator * maxItems[partition])
 	}
 
-	return SGTIN{
+	sgtin := SGTIN{
 		filter:        filter,
 		partition:     partition,
 		companyPrefix: companyPrefix,
 		indicator:     indicator,
 		itemRef:       itemRef,
 		serial:        serial,
-	}, nil
+	}
+	if o.strict {
+		if err := validateEncodingStrict(b, sgtin, nil); err != nil {
+			return SGTIN{}, err
+		}
+		if o.withoutSerial {
+			sgtin.serial = ""
+		}
+	}
+	return sgtin, nil
+}
+
+// EncodeSGTIN96 returns the SGTIN-96 binary encoding of this SGTIN, or an
+// error if its values are out of range or its serial cannot be encoded as an
+// SGTIN-96 serial (see CanSGTIN96).
+func (s SGTIN) EncodeSGTIN96() ([]byte, error) {
+	if err := s.ValidateRanges(); err != nil {
+		return nil, err
+	}
+	if err := s.CanSGTIN96(); err != nil {
+		return nil, err
+	}
+	serial, _ := s.SerialUint64()
+
+	b := make([]byte, SGTIN96NumBytes)
+	b[0] = SGTIN96Header
+	s.putPrefixIIR(b)
+	putBits(b, serialStartBit, serial96Len, serial)
+	return b, nil
+}
+
+// EncodeSGTIN198 returns the SGTIN-198 binary encoding of this SGTIN, or an
+// error if its values are out of range. Since 198 bits are not byte-aligned,
+// the final byte is padded with two trailing 0s.
+func (s SGTIN) EncodeSGTIN198() ([]byte, error) {
+	if err := s.ValidateRanges(); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, SGTIN198NumBytes)
+	b[0] = SGTIN198Header
+	s.putPrefixIIR(b)
+	encodeASCIIAt(b, serialStartBit, s.serial)
+	return b, nil
+}
+
+// Encode returns the SGTIN's binary encoding, using SGTIN-96 if the serial
+// permits it, and otherwise SGTIN-198.
+func (s SGTIN) Encode() ([]byte, error) {
+	if s.CanSGTIN96() == nil {
+		return s.EncodeSGTIN96()
+	}
+	return s.EncodeSGTIN198()
+}
+
+// WithFilter returns a copy of the SGTIN with the given filter value. Since the
+// filter is part of the SGTIN's encoding, but not its identity, this changes
+// its binary representation but not its URI.
+func (s SGTIN) WithFilter(filter FilterValue) SGTIN {
+	s.filter = filter
+	return s
+}
+
+// RetagSGTIN returns a copy of an SGTIN-96 or SGTIN-198 encoded EPC with its
+// filter value replaced, such as when a re-tagging station changes a tag from
+// POS to Full Case. The EPC is re-encoded using its original scheme, so its
+// length is unchanged. It returns an error if the EPC can't be decoded, or if
+// it or the new filter value is invalid.
+func RetagSGTIN(epc []byte, filter FilterValue) ([]byte, error) {
+	s, err := DecodeSGTIN(epc)
+	if err != nil {
+		return nil, err
+	}
+	s = s.WithFilter(filter)
+	if epc[0] == SGTIN96Header {
+		return s.EncodeSGTIN96()
+	}
+	return s.EncodeSGTIN198()
+}
+
+// putPrefixIIR writes the filter, partition, company prefix, and indicator/item
+// ref fields, which SGTIN-96 and SGTIN-198 share, into b.
+func (s SGTIN) putPrefixIIR(b []byte) {
+	iir := s.indicator*maxItems[s.partition] + s.itemRef
+	putBits(b, filterStartBit, filterLen, uint64(s.filter))
+	putBits(b, partitionStartBit, partitionLen, uint64(s.partition))
+	putBits(b, gcpStartBit, companyBits[s.partition], uint64(s.companyPrefix))
+	putBits(b, gcpStartBit+companyBits[s.partition],
+		prefixIIRLen-companyBits[s.partition], uint64(iir))
+}
+
+// putBits writes the lowest length bits of v into dst, starting at the given
+// bit, where bit 0 is the highest-order bit of dst[0].
+func putBits(dst []byte, start, length int, v uint64) {
+	bitextract.New(start, length).InsertUInt64(dst, v)
 }
 
 type FilterValue int
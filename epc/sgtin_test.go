@@ -7,6 +7,7 @@
 package epc
 
 import (
+	"encoding/hex"
 	"fmt"
 	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
 	"math"
@@ -74,6 +75,8 @@ func TestDecodeSGTIN(t *testing.T) {
 			"40004285602049", "000428560204.4.69940467929"),
 		pass("indicator 1", "3000011B896A506B29C18539",
 			"10011892394440", "001189239444.1.185384142137"),
+		pass("indicator 8 with item ref", "3074257BF7194E4000001A85",
+			"80614141123458", "0614141.812345.6789"),
 
 		pass("SGTIN-198-numeric", "36143639F8419198B966E1AB366E5B3470DC00000000000000",
 			"00888446671424", "0888446.067142.193853396487"),
@@ -87,10 +90,18 @@ func TestDecodeSGTIN(t *testing.T) {
 		fail("Too short for SGTIN-198", "36143636C5EB1769D72E557D52E5CBADDFC"),
 		fail("Partition value should be <=6", "301C00004000004000000001"),
 
-		badRange("Item reference out of range", "301000181C2CC193A8B43711"),
-		badRange("Item reference out of range", "361000181C2CC1A465D9B37A176C5EB1769D72E557D52E5CBC"),
-		badRange("Item reference out of range", "30244032EACFF145202001E8"),
-		badRange("Item reference out of range", "36244032EACFF1A465D9B37A176C5EB1769D72E557D52E5CBC"),
+		// the indicator digit is split from the item reference, so these are in range
+		pass("indicator 4, partition 4", "301000181C2CC193A8B43711",
+			"40001234458306", "00012344.45830.84434761489"),
+		pass("indicator 4, partition 4", "361000181C2CC1A465D9B37A176C5EB1769D72E557D52E5CBC",
+			"40001234458306", "00012344.45830.Hello!;1=1;'..*_*..%2F"),
+		pass("indicator 6, partition 1", "30244032EACFF145202001E8",
+			"60861662988790", "08616629887.69.22013805032"),
+		pass("indicator 7, partition 1", "36244032EACFF1A465D9B37A176C5EB1769D72E557D52E5CBC",
+			"70861662988704", "08616629887.70.Hello!;1=1;'..*_*..%2F"),
+
+		badRange("Item reference out of range", "301000181C7FFFD3A8B43711"),
+		badRange("Item reference out of range", "30244032EACFFFC5202001E8"),
 		badRange("SGTIN-198 serial with chars after null", "36044032EAC191A465D9B37A176C5EB1769D72E557D5200CBC"),
 	} {
 		t.Run(fmt.Sprintf("%02d_%s", i, tt.name), func(t *testing.T) {
@@ -212,6 +223,8 @@ func TestSGTIN_CanSGTIN96(t *testing.T) {
 		fail("Leading '0' 2", "000"),
 		fail("Leading '0' 3", " 0"),
 		fail("Leading '0' 4", "01"),
+		fail("Too large", "274877906944"),
+		fail("Signed", "+1"),
 	} {
 		t.Run(fmt.Sprintf("%02d_%s", i, tt.name), func(t *testing.T) {
 			w := expect.WrapT(t)
@@ -224,3 +237,244 @@ func TestSGTIN_CanSGTIN96(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeSGTIN_options(t *testing.T) {
+	w := expect.WrapT(t)
+	b96 := w.ShouldHaveResult(hex.DecodeString("3034257BF7194E4000001A85")).([]byte)
+	s := w.ShouldHaveResult(NewSGTIN(POS, 5, 8, 614141, 12345, "ab")).(SGTIN)
+	b198 := w.ShouldHaveResult(s.EncodeSGTIN198()).([]byte)
+
+	for _, b := range [][]byte{b96, b198} {
+		noSerial := w.ShouldHaveResult(DecodeSGTIN(b, WithoutSerial())).(SGTIN)
+		w.ShouldBeEqual(noSerial.Serial(), "")
+		w.ShouldBeEqual(noSerial.GTIN(), "80614141123458")
+
+		strict := w.ShouldHaveResult(DecodeSGTIN(b, Strict())).(SGTIN)
+		w.ShouldBeEqual(strict, w.ShouldHaveResult(DecodeSGTIN(b)))
+		noSerial = w.ShouldHaveResult(DecodeSGTIN(b, Strict(), WithoutSerial())).(SGTIN)
+		w.ShouldBeEqual(noSerial.Serial(), "")
+	}
+
+	// set the low bit of the 4th serial character, after the null terminator
+	bit := serialStartBit + 7*3 + 6
+	b198[bit/8] |= 0x80 >> uint(bit%8)
+	s = w.ShouldHaveResult(DecodeSGTIN(b198)).(SGTIN)
+	w.ShouldHaveLength(s.Serial(), 20)
+	w.ShouldHaveError(DecodeSGTIN(b198, AllowCharAfterNull(false)))
+	w.ShouldHaveError(DecodeSGTIN(b198, Strict()))
+	w.ShouldHaveResult(DecodeSGTIN(b198, AllowCharAfterNull(false), WithoutSerial()))
+
+	rcn := w.ShouldHaveResult(NewSGTIN(POS, 5, 0, 212345, 12345, "1")).(SGTIN)
+	b96 = w.ShouldHaveResult(rcn.EncodeSGTIN96()).([]byte)
+	w.ShouldHaveResult(DecodeSGTIN(b96))
+	w.ShouldHaveError(DecodeSGTIN(b96, Strict()))
+}
+
+func TestSGTIN_AppendURI(t *testing.T) {
+	w := expect.WrapT(t)
+	for _, tt := range []struct {
+		s         SGTIN
+		uri, gtin string
+	}{
+		{SGTIN{partition: 5, indicator: 8, companyPrefix: 614141, itemRef: 12345, serial: "6789"},
+			"0614141.812345.6789", "80614141123458"},
+		{SGTIN{partition: 0, indicator: 1, companyPrefix: 1, serial: "a/b%c\x00"},
+			"000000000001.1.a%2Fb%25c", "10000000000014"},
+		{SGTIN{partition: 6, companyPrefix: 614141, itemRef: 1, serial: `"#%&/<>?`},
+			"614141.0000001.%22%23%25%26%2F%3C%3E%3F", "06141410000014"},
+		{SGTIN{partition: 3, indicator: -1, companyPrefix: -12, itemRef: 5, serial: "1"},
+			"-00000012.-1005.1", "-1-000000120055"},
+	} {
+		uri := SGTINPureURIPrefix + ":" + tt.uri
+		w.ShouldBeEqual(tt.s.URI(), uri)
+		w.ShouldBeEqual(string(tt.s.AppendURI([]byte("x"))), "x"+uri)
+		w.ShouldBeEqual(tt.s.GTIN(), tt.gtin)
+		w.ShouldBeEqual(string(tt.s.AppendGTIN([]byte("x"))), "x"+tt.gtin)
+	}
+
+	s := SGTIN{partition: 5, indicator: 8, companyPrefix: 614141, itemRef: 12345,
+		serial: "6789"}
+	w.ShouldBeEqual(fmt.Sprint(s), "urn:epc:id:sgtin:0614141.812345.6789")
+	w.ShouldBeEqual(fmt.Sprintf("%v", &s), "urn:epc:id:sgtin:0614141.812345.6789")
+
+	buf := make([]byte, 0, 77)
+	w.ShouldBeEqual(testing.AllocsPerRun(100, func() {
+		buf = s.AppendURI(buf[:0])
+		buf = s.AppendGTIN(buf[:0])
+	}), float64(0))
+}
+
+func BenchmarkSGTIN_AppendURI(b *testing.B) {
+	s := SGTIN{partition: 5, indicator: 8, companyPrefix: 614141, itemRef: 12345,
+		serial: "6789"}
+	buf := make([]byte, 0, 77)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = s.AppendURI(buf[:0])
+	}
+}
+
+func BenchmarkSGTIN_URI(b *testing.B) {
+	s := SGTIN{partition: 5, indicator: 8, companyPrefix: 614141, itemRef: 12345,
+		serial: "6789"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = s.URI()
+	}
+}
+
+func TestExtractGTIN14(t *testing.T) {
+	w := expect.WrapT(t)
+	s := w.ShouldHaveResult(NewSGTIN(POS, 5, 8, 614141, 12345, "ab")).(SGTIN)
+	b198 := w.ShouldHaveResult(s.EncodeSGTIN198()).([]byte)
+	w.ShouldBeEqual(w.ShouldHaveResult(ExtractGTIN14(b198)), "80614141123458")
+
+	for _, epc := range []string{
+		"3034257BF7194E4000001A85",
+		"300000000000044000000001",
+		"301000181C7FFFD3A8B43711", // item reference out of range
+		"30244032EACFFFC5202001E8",
+		"3018000000000000000000", // too short
+		"361800",
+	} {
+		b := w.ShouldHaveResult(hex.DecodeString(epc)).([]byte)
+		gtin, err := ExtractGTIN14(b)
+		expected, expectedErr := SGTINToGTIN14(epc)
+		w.As(epc).ShouldBeEqual(gtin, expected)
+		w.As(epc).ShouldBeEqual(err == nil, expectedErr == nil)
+	}
+}
+
+func BenchmarkExtractGTIN14(b *testing.B) {
+	s, _ := NewSGTIN(POS, 5, 8, 614141, 12345, "ABCDEFGHIJKLMNOPQRST")
+	epc, _ := s.EncodeSGTIN198()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ExtractGTIN14(epc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeSGTIN_198(b *testing.B) {
+	s, _ := NewSGTIN(POS, 5, 8, 614141, 12345, "ABCDEFGHIJKLMNOPQRST")
+	epc, _ := s.EncodeSGTIN198()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodeSGTIN(epc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSGTINToGTIN14(b *testing.B) {
+	s, _ := NewSGTIN(POS, 5, 8, 614141, 12345, "ABCDEFGHIJKLMNOPQRST")
+	epc, _ := s.EncodeSGTIN198()
+	epcHex := hex.EncodeToString(epc)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := SGTINToGTIN14(epcHex); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestRetagSGTIN(t *testing.T) {
+	w := expect.WrapT(t)
+	b96 := w.ShouldHaveResult(hex.DecodeString("3034257BF7194E4000001A85")).([]byte)
+	retagged := w.ShouldHaveResult(RetagSGTIN(b96, FullCase)).([]byte)
+	w.ShouldBeEqual(hex.EncodeToString(retagged), "3054257bf7194e4000001a85")
+	w.ShouldBeEqual(hex.EncodeToString(b96), "3034257bf7194e4000001a85")
+
+	s := w.ShouldHaveResult(NewSGTIN(POS, 5, 8, 614141, 12345, "6789")).(SGTIN)
+	b198 := w.ShouldHaveResult(s.EncodeSGTIN198()).([]byte)
+	retagged = w.ShouldHaveResult(RetagSGTIN(b198, UnitLoad)).([]byte)
+	w.ShouldHaveLength(retagged, SGTIN198NumBytes)
+	decoded := w.ShouldHaveResult(DecodeSGTIN(retagged)).(SGTIN)
+	w.ShouldBeEqual(decoded, s.WithFilter(UnitLoad))
+	w.ShouldBeEqual(decoded.URI(), s.URI())
+
+	w.ShouldHaveError(RetagSGTIN(b96, reserved1))
+	w.ShouldHaveError(RetagSGTIN(b96[:11], POS))
+}
+
+func TestSGTIN_SerialUint64(t *testing.T) {
+	for _, tt := range []struct {
+		serial  string
+		numeric bool
+		value   uint64
+		ok      bool
+	}{
+		{"0", true, 0, true},
+		{"6789", true, 6789, true},
+		{"274877906944", true, 274877906944, true},
+		{"18446744073709551615", true, 18446744073709551615, true},
+		{"18446744073709551616", true, 0, false},
+		{"", false, 0, false},
+		{"007", false, 0, false},
+		{"-1", false, 0, false},
+		{"1A", false, 0, false},
+	} {
+		w := expect.WrapT(t).As(tt.serial)
+		s := SGTIN{serial: tt.serial}
+		w.ShouldBeEqual(s.IsNumericSerial(), tt.numeric)
+		value, ok := s.SerialUint64()
+		w.ShouldBeEqual(ok, tt.ok)
+		w.ShouldBeEqual(value, tt.value)
+	}
+}
+
+func TestSGTIN_Encode(t *testing.T) {
+	for i, epc := range []string{
+		"300000000000044000000001",
+		"301800004000004000000001",
+		"30143639F84191AD22901607",
+		"3034257BF400B7800004CB2F",
+		"3034257BF7194E4000001A85",
+		"3000011B896A506B29C18539",
+		"36143639F8419198B966E1AB366E5B3470DC00000000000000",
+		"36143639F84191A465D9B37A176C5EB1769D72E557D52E5CBC",
+	} {
+		t.Run(fmt.Sprintf("%02d_%s", i, epc), func(t *testing.T) {
+			w := expect.WrapT(t)
+			s := w.ShouldHaveResult(DecodeSGTINString(epc)).(SGTIN)
+			b := w.ShouldHaveResult(hex.DecodeString(epc)).([]byte)
+
+			var encoded []byte
+			if b[0] == SGTIN96Header {
+				encoded = w.ShouldHaveResult(s.EncodeSGTIN96()).([]byte)
+			} else {
+				encoded = w.ShouldHaveResult(s.EncodeSGTIN198()).([]byte)
+			}
+			w.ShouldBeEqual(encoded, b)
+
+			// SGTIN-198 can hold any serial SGTIN-96 can
+			long := w.ShouldHaveResult(s.EncodeSGTIN198()).([]byte)
+			decoded := w.ShouldHaveResult(DecodeSGTIN(long)).(SGTIN)
+			w.ShouldBeEqual(decoded, s)
+		})
+	}
+}
+
+func TestSGTIN_Encode_invalid(t *testing.T) {
+	w := expect.WrapT(t)
+
+	s := SGTIN{partition: 5, companyPrefix: 614141, itemRef: 734, serial: "007"}
+	w.ShouldSucceed(s.ValidateRanges())
+	w.As("leading 0s").ShouldHaveError(s.EncodeSGTIN96())
+	b := w.ShouldHaveResult(s.Encode()).([]byte)
+	w.ShouldBeEqual(b[0], uint8(SGTIN198Header))
+
+	s.itemRef = maxItems[5]
+	w.As("item ref out of range").ShouldHaveError(s.EncodeSGTIN198())
+}
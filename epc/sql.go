@@ -0,0 +1,113 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"github.com/pkg/errors"
+)
+
+// scanText returns the text of a database value, which drivers may present as
+// either a string or a []byte.
+func scanText(src interface{}) (string, error) {
+	switch v := src.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case nil:
+		return "", errors.New("cannot scan NULL; use a pointer to allow NULLs")
+	}
+	return "", errors.Errorf("cannot scan %T; expected a string or []byte", src)
+}
+
+// Value implements driver.Valuer, storing the SGTIN as its Pure Identity URI.
+// It returns an error if the SGTIN's values are out of range.
+//
+// Use HexSGTIN to store the binary encoding instead.
+func (s SGTIN) Value() (driver.Value, error) {
+	text, err := s.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+// Scan implements sql.Scanner, loading an SGTIN from its Pure Identity URI.
+// Since the URI doesn't include a filter value, the SGTIN's filter is Other.
+func (s *SGTIN) Scan(src interface{}) error {
+	text, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	return s.UnmarshalText([]byte(text))
+}
+
+// HexSGTIN is an SGTIN that's stored in databases as its upper-case, hex-encoded
+// binary encoding (see SGTIN.MarshalBinary) rather than its Pure Identity URI.
+// Unlike the URI, the binary encoding preserves the SGTIN's filter value.
+type HexSGTIN struct {
+	SGTIN
+}
+
+// Value implements driver.Valuer, storing the SGTIN's binary encoding as hex.
+func (s HexSGTIN) Value() (driver.Value, error) {
+	b, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return hexUpper(b), nil
+}
+
+// Scan implements sql.Scanner, loading an SGTIN from its hex-encoded binary
+// encoding.
+func (s *HexSGTIN) Scan(src interface{}) error {
+	text, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	b, err := hex.DecodeString(text)
+	if err != nil {
+		return errors.Wrap(err, "unable to decode SGTIN as hex")
+	}
+	return s.UnmarshalBinary(b)
+}
+
+// Value implements driver.Valuer, storing the GID as its Pure Identity URI.
+// It returns an error if the GID's values are out of range.
+func (g GID) Value() (driver.Value, error) {
+	if err := g.ValidateRanges(); err != nil {
+		return nil, err
+	}
+	return g.URI(), nil
+}
+
+// Scan implements sql.Scanner, loading a GID from its Pure Identity URI.
+func (g *GID) Scan(src interface{}) error {
+	text, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseGIDURI(text)
+	if err != nil {
+		return err
+	}
+	*g = parsed
+	return nil
+}
+
+// hexUpper returns the upper-case hex encoding of b.
+func hexUpper(b []byte) string {
+	const digits = "0123456789ABCDEF"
+	out := make([]byte, len(b)*2)
+	for i, v := range b {
+		out[i*2] = digits[v>>4]
+		out[i*2+1] = digits[v&0x0F]
+	}
+	return string(out)
+}
@@ -0,0 +1,66 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = SGTIN{}
+	_ sql.Scanner   = &SGTIN{}
+	_ driver.Valuer = HexSGTIN{}
+	_ sql.Scanner   = &HexSGTIN{}
+	_ driver.Valuer = GID{}
+	_ sql.Scanner   = &GID{}
+)
+
+func TestSGTIN_Value(t *testing.T) {
+	w := expect.WrapT(t)
+	const uri = "urn:epc:id:sgtin:0614141.812345.6789"
+	const epc = "3034257BF7194E4000001A85"
+
+	s := w.ShouldHaveResult(DecodeSGTINString(epc)).(SGTIN)
+	w.ShouldBeEqual(w.ShouldHaveResult(s.Value()), uri)
+
+	var scanned SGTIN
+	w.ShouldSucceed(scanned.Scan(uri))
+	w.ShouldBeEqual(scanned.URI(), uri)
+	w.ShouldSucceed(scanned.Scan([]byte(uri)))
+	w.ShouldBeEqual(scanned.URI(), uri)
+
+	w.ShouldFail(scanned.Scan(nil))
+	w.ShouldFail(scanned.Scan(42))
+	w.ShouldFail(scanned.Scan("urn:epc:id:sgtin:0614141.6789"))
+	w.ShouldHaveError(SGTIN{}.Value())
+
+	h := HexSGTIN{s}
+	w.ShouldBeEqual(w.ShouldHaveResult(h.Value()), epc)
+	var scannedHex HexSGTIN
+	w.ShouldSucceed(scannedHex.Scan(epc))
+	w.ShouldBeEqual(scannedHex, h)
+	w.ShouldBeEqual(scannedHex.Filter(), POS)
+	w.ShouldFail(scannedHex.Scan("not hex"))
+	w.ShouldFail(scannedHex.Scan(uri))
+}
+
+func TestGID_Value(t *testing.T) {
+	w := expect.WrapT(t)
+	const uri = "urn:epc:id:gid:95100000.12345.400"
+
+	g := w.ShouldHaveResult(NewGID(95100000, 12345, 400)).(GID)
+	w.ShouldBeEqual(w.ShouldHaveResult(g.Value()), uri)
+
+	var scanned GID
+	w.ShouldSucceed(scanned.Scan([]byte(uri)))
+	w.ShouldBeEqual(scanned, g)
+	w.ShouldFail(scanned.Scan(nil))
+	w.ShouldHaveError(GID{serial: -1}.Value())
+}
@@ -0,0 +1,238 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	SSCCPureURIPrefix = "urn:epc:id:sscc"
+	SSCC96NumBytes    = 12
+	SSCC96Header      = 0x31
+)
+
+// SSCC is a Serial Shipping Container Code, which identifies a logistics unit,
+// such as a pallet or a parcel. It consists of a GS1 Company Prefix and a
+// serial reference, whose first digit is the SSCC's extension digit.
+//
+// As with SGTINs, the partition value determines the number of digits in the
+// company prefix and serial reference, which together always have 17 digits.
+type SSCC struct {
+	filter    int
+	partition int
+
+	companyPrefix int
+	serialRef     int
+}
+
+// Filter returns the SSCC's filter value; per the EPC Tag Data Standard, 0 is
+// "All Others", 2 is "Logistics / Shipping Unit", and all others are reserved.
+func (s SSCC) Filter() int {
+	return s.filter
+}
+
+// Partition returns the SSCC's partition value.
+func (s SSCC) Partition() int {
+	return s.partition
+}
+
+// CompanyPrefix returns the SSCC's GS1 Company Prefix.
+func (s SSCC) CompanyPrefix() string {
+	return fmt.Sprintf("%0[1]*d", 12-s.partition, s.companyPrefix)
+}
+
+// SerialReference returns the SSCC's serial reference, including its leading
+// extension digit.
+func (s SSCC) SerialReference() string {
+	return fmt.Sprintf("%0[1]*d", 5+s.partition, s.serialRef)
+}
+
+// NewSSCC returns an SSCC with the given values. If they don't fit within the
+// ranges permitted by SSCC-96, the error is non-nil, but the SSCC is still
+// returned. The serialRef includes the SSCC's extension digit.
+func NewSSCC(filter, partition, companyPrefix, serialRef int) (SSCC, error) {
+	s := SSCC{
+		filter:        filter,
+		partition:     partition,
+		companyPrefix: companyPrefix,
+		serialRef:     serialRef,
+	}
+	return s, s.ValidateRanges()
+}
+
+// Scheme returns "sscc", the EPC scheme of SSCCs.
+func (s SSCC) Scheme() string {
+	return "sscc"
+}
+
+// ValidateRanges checks an SSCC's values to ensure they fit the range
+// restrictions of their respective fields.
+func (s SSCC) ValidateRanges() error {
+	if s.filter < 0 || s.filter > 7 {
+		return errors.Errorf("filter must be in [0,7], but is %d", s.filter)
+	}
+	if s.partition < 0 || s.partition > 6 {
+		return errors.Errorf("partition must be in [0,6], but is %d", s.partition)
+	}
+	if s.companyPrefix < 0 || s.companyPrefix > maxPrefix[s.partition] {
+		return errors.Errorf("company prefix in partition %d must be in [0, %d], "+
+			"but is %d", s.partition, maxPrefix[s.partition], s.companyPrefix)
+	}
+	if s.serialRef < 0 || s.serialRef > ssccMaxSerialRef[s.partition] {
+		return errors.Errorf("serial refs in partition %d must be in [0, %d], "+
+			"but is %d", s.partition, ssccMaxSerialRef[s.partition], s.serialRef)
+	}
+	return nil
+}
+
+// URI returns the EPC Pure Identity URI for this SSCC, of the format:
+//     urn:epc:id:sscc:CompanyPrefix.SerialReference
+func (s SSCC) URI() string {
+	return SSCCPureURIPrefix + ":" + s.CompanyPrefix() + "." + s.SerialReference()
+}
+
+// ParseSSCCURI parses an SSCC Pure Identity URI, of the format:
+//     urn:epc:id:sscc:CompanyPrefix.SerialReference
+// and returns the SSCC it represents, or an error if the URI is malformed or its
+// values are out of range. The company prefix must have 6 to 12 digits, and
+// together with the serial reference, there must be 17 digits. Since the filter
+// value isn't part of the Pure Identity URI, the returned SSCC's filter is 0.
+func ParseSSCCURI(uri string) (SSCC, error) {
+	if !strings.HasPrefix(uri, SSCCPureURIPrefix+":") {
+		return SSCC{}, errors.Errorf("SSCC URIs must start with %q", SSCCPureURIPrefix+":")
+	}
+
+	return parseSSCCFields(uri[len(SSCCPureURIPrefix)+1:], 0)
+}
+
+// parseSSCCFields parses the '.' separated company prefix and serial reference
+// fields shared by SSCC Pure Identity and Tag URIs.
+func parseSSCCFields(fields string, filter int) (SSCC, error) {
+	parts := strings.Split(fields, ".")
+	if len(parts) != 2 {
+		return SSCC{}, errors.Errorf("SSCC URIs have 2 parts separated by "+
+			"'.', but %q has %d", fields, len(parts))
+	}
+	prefix, ref := parts[0], parts[1]
+
+	if len(prefix) < 6 || len(prefix) > 12 || !isDigits(prefix) {
+		return SSCC{}, errors.Errorf("company prefix %q must have 6 to 12 digits", prefix)
+	}
+	if len(prefix)+len(ref) != 17 || !isDigits(ref) {
+		return SSCC{}, errors.Errorf("serial reference %q must have %d digits",
+			ref, 17-len(prefix))
+	}
+
+	companyPrefix, _ := strconv.Atoi(prefix)
+	serialRef, _ := strconv.Atoi(ref)
+	return NewSSCC(filter, 12-len(prefix), companyPrefix, serialRef)
+}
+
+// SSCC returns the 18 digit GS1 SSCC element string represented by this SSCC:
+// its extension digit, company prefix, the rest of its serial reference, and
+// a check digit.
+func (s SSCC) SSCC() string {
+	ref := s.SerialReference()
+	digits := ref[:1] + s.CompanyPrefix() + ref[1:]
+	if len(digits) != 17 || !isDigits(digits) {
+		return digits
+	}
+	return fmt.Sprintf("%s%d", digits, gs1CheckDigit(digits))
+}
+
+// Encode returns the SSCC-96 binary encoding of this SSCC, or an error if its
+// values are out of range.
+func (s SSCC) Encode() ([]byte, error) {
+	if err := s.ValidateRanges(); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, SSCC96NumBytes)
+	b[0] = SSCC96Header
+	putBits(b, filterStartBit, filterLen, uint64(s.filter))
+	putBits(b, partitionStartBit, partitionLen, uint64(s.partition))
+	putBits(b, gcpStartBit, companyBits[s.partition], uint64(s.companyPrefix))
+	putBits(b, gcpStartBit+companyBits[s.partition],
+		ssccPrefixRefLen-companyBits[s.partition], uint64(s.serialRef))
+	return b, nil
+}
+
+const (
+	// the company prefix and serial reference share 58 bits, divided by
+	// the partition; the remaining 24 bits are reserved and must be 0s.
+	ssccPrefixRefLen  = 58
+	ssccReservedStart = gcpStartBit + ssccPrefixRefLen
+	ssccReservedLen   = 96 - ssccReservedStart
+)
+
+var (
+	// max value for serial reference each partition allows = 10^(5+p)-1
+	ssccMaxSerialRef = [7]int{
+		99999,
+		999999,
+		9999999,
+		99999999,
+		999999999,
+		9999999999,
+		99999999999,
+	}
+
+	ssccReservedExt = bitextract.New(ssccReservedStart, ssccReservedLen)
+)
+
+// DecodeSSCCString accepts a big endian, hex-encoded SSCC-96 EPC and returns
+// its SSCC representation, or an error if it cannot be decoded as such.
+func DecodeSSCCString(epc string) (SSCC, error) {
+	b, err := hex.DecodeString(epc)
+	if err != nil {
+		return SSCC{}, err
+	}
+	return DecodeSSCC(b)
+}
+
+// DecodeSSCC decodes an SSCC-96 encoded EPC to an SSCC structure, or returns an
+// error if the data cannot be converted to an SSCC. Like DecodeSGTIN, it only
+// returns an error for structural problems: unknown headers, invalid lengths,
+// invalid partitions, and non-zero reserved bits. Use ValidateRanges to check
+// the values are within the EPC ranges.
+func DecodeSSCC(b []byte) (SSCC, error) {
+	if len(b) == 0 {
+		return SSCC{}, errors.New("no data provided")
+	}
+	if b[0] != SSCC96Header {
+		return SSCC{}, errors.Errorf("SSCC-96 header is %#X, but this is: %#X",
+			SSCC96Header, b[0])
+	}
+	if len(b) != SSCC96NumBytes {
+		return SSCC{}, errors.Errorf("SSCC-96 should have %d bytes, "+
+			"but this has %d bytes", SSCC96NumBytes, len(b))
+	}
+
+	partition := int(partitionExt.ExtractUInt64(b))
+	if partition < 0 || partition > 6 {
+		return SSCC{}, errors.Errorf("invalid partition: %d", partition)
+	}
+	if reserved := ssccReservedExt.ExtractUInt64(b); reserved != 0 {
+		return SSCC{}, errors.Errorf("SSCC-96 reserved bits must be 0, "+
+			"but are %#X", reserved)
+	}
+
+	refLen := ssccPrefixRefLen - companyBits[partition]
+	return SSCC{
+		filter:        int(filterExt.ExtractUInt64(b)),
+		partition:     partition,
+		companyPrefix: int(companyExt[partition].ExtractUInt64(b)),
+		serialRef: int(bitextract.New(gcpStartBit+companyBits[partition],
+			refLen).ExtractUInt64(b)),
+	}, nil
+}
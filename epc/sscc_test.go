@@ -0,0 +1,53 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeSSCC(t *testing.T) {
+	w := expect.WrapT(t)
+
+	s := w.ShouldHaveResult(DecodeSSCCString("3134257BF4499602D2000000")).(SSCC)
+	w.ShouldSucceed(s.ValidateRanges())
+	w.ShouldBeEqual(s.URI(), "urn:epc:id:sscc:0614141.1234567890")
+	w.ShouldBeEqual(s.SSCC(), "106141412345678908")
+	w.ShouldBeEqual(s.Filter(), 1)
+	w.ShouldBeEqual(s.Partition(), 5)
+	w.ShouldBeEqual(s.Scheme(), "sscc")
+	w.ShouldSucceed(ValidateSSCC(s.SSCC()))
+
+	b := w.ShouldHaveResult(s.Encode()).([]byte)
+	w.ShouldBeEqual(strings.ToUpper(hex.EncodeToString(b)), "3134257BF4499602D2000000")
+
+	for p := 0; p <= 6; p++ {
+		s := w.ShouldHaveResult(NewSSCC(2, p, maxPrefix[p], ssccMaxSerialRef[p])).(SSCC)
+		w.ShouldHaveLength(s.SSCC(), 18)
+		b := w.ShouldHaveResult(s.Encode()).([]byte)
+		w.ShouldBeEqual(w.ShouldHaveResult(DecodeSSCC(b)), s)
+	}
+
+	for _, epc := range []string{
+		"",
+		"3034257BF4499602D2000000",   // SGTIN header
+		"3134257BF4499602D20000",     // too short
+		"3134257BF4499602D2000001",   // reserved bits
+		"313C257BF4499602D2000000",   // partition 7
+		"3134257BF4499602D200000000", // too long
+	} {
+		w.As(epc).ShouldHaveError(DecodeSSCCString(epc))
+	}
+
+	w.ShouldHaveError(NewSSCC(8, 5, 614141, 1))
+	w.ShouldHaveError(NewSSCC(0, 5, 614141, 10000000000))
+	w.ShouldHaveError(NewSSCC(0, 5, 100000000, 1))
+	w.ShouldHaveError(NewSSCC(0, 7, 1, 1))
+}
@@ -0,0 +1,80 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ValidateStrict checks that the SGTIN conforms to the rules of the EPC Tag Data
+// Standard, beyond the range restrictions of ValidateRanges (which already
+// requires the serial to use the GS1 AI Encodable Character Set 82 and the
+// filter to be a non-reserved SGTIN filter value):
+//   - its GTIN must not be a Restricted Circulation Number or coupon code (see
+//     ValidateClass)
+//   - if prefixLen is non-nil, the SGTIN's partition must agree with the length
+//     of the company prefix it reports for the SGTIN's GTIN
+//
+// Because ValidateRanges already limits the serial to characters that SGTIN-198
+// can encode, and Encode chooses SGTIN-96 only for serials it can represent,
+// every SGTIN that passes this check has a valid binary encoding. To validate an
+// existing binary encoding, use ValidateSGTINStrict.
+func (s SGTIN) ValidateStrict(prefixLen PrefixLengthLookup) error {
+	if err := s.ValidateRanges(); err != nil {
+		return err
+	}
+	if err := s.ValidateClass(); err != nil {
+		return err
+	}
+	if prefixLen == nil {
+		return nil
+	}
+
+	gtin := s.GTIN()
+	length, err := prefixLen.PrefixLength(gtin)
+	if err != nil {
+		return errors.Wrapf(err, "unable to determine the company "+
+			"prefix length of %s", gtin)
+	}
+	if length != 12-s.partition {
+		return errors.Errorf("GTIN %s has a %d digit company prefix, "+
+			"but partition %d indicates %d digits",
+			gtin, length, s.partition, 12-s.partition)
+	}
+	return nil
+}
+
+// ValidateSGTINStrict checks that b is an SGTIN-96 or SGTIN-198 encoding that
+// conforms to the rules of the EPC Tag Data Standard. In addition to the rules
+// checked by SGTIN.ValidateStrict, the encoding itself must be canonical:
+// - an SGTIN-198 serial must not have non-null characters after a null
+// - the 2 pad bits that follow the 198 bits of SGTIN-198 must be 0s
+func ValidateSGTINStrict(b []byte, prefixLen PrefixLengthLookup) error {
+	s, err := DecodeSGTIN(b)
+	if err != nil {
+		return err
+	}
+	return validateEncodingStrict(b, s, prefixLen)
+}
+
+// validateEncodingStrict performs ValidateSGTINStrict's checks on b and s, its
+// decoded form.
+func validateEncodingStrict(b []byte, s SGTIN, prefixLen PrefixLengthLookup) error {
+	if b[0] == SGTIN198Header {
+		_, _, charAfterNull := DecodeASCIIAt(b[serialStartByte:], serialOffsetBit)
+		if charAfterNull {
+			return errors.New("SGTIN-198 serial has characters after " +
+				"its null terminator")
+		}
+		if pad := b[len(b)-1] & 0x03; pad != 0 {
+			return errors.Errorf("SGTIN-198 pad bits must be 0, "+
+				"but are %02b", pad)
+		}
+	}
+
+	return s.ValidateStrict(prefixLen)
+}
@@ -0,0 +1,50 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestSGTIN_ValidateStrict(t *testing.T) {
+	w := expect.WrapT(t)
+
+	s := w.ShouldHaveResult(NewSGTIN(POS, 5, 8, 614141, 12345, "6789")).(SGTIN)
+	w.ShouldSucceed(s.ValidateStrict(nil))
+	w.ShouldSucceed(s.ValidateStrict(FixedPrefixLength(7)))
+	w.ShouldFail(s.ValidateStrict(FixedPrefixLength(6)))
+	w.ShouldFail(s.ValidateStrict(noPrefixLengths{}))
+
+	rcn := w.ShouldHaveResult(NewSGTIN(POS, 5, 0, 212345, 12345, "1")).(SGTIN)
+	w.ShouldFail(rcn.ValidateStrict(nil))
+
+	w.ShouldFail(SGTIN{partition: 5, serial: "1", filter: reserved1}.ValidateStrict(nil))
+}
+
+func TestValidateSGTINStrict(t *testing.T) {
+	w := expect.WrapT(t)
+
+	s := w.ShouldHaveResult(NewSGTIN(POS, 5, 8, 614141, 12345, "ab")).(SGTIN)
+	b := w.ShouldHaveResult(s.EncodeSGTIN198()).([]byte)
+	w.ShouldSucceed(ValidateSGTINStrict(b, FixedPrefixLength(7)))
+	w.ShouldFail(ValidateSGTINStrict(b, FixedPrefixLength(8)))
+
+	padded := append([]byte{}, b...)
+	padded[len(padded)-1] |= 0x01
+	w.ShouldFail(ValidateSGTINStrict(padded, nil))
+
+	// set the low bit of the 4th serial character, after the null terminator
+	extra := append([]byte{}, b...)
+	bit := serialStartBit + 7*3 + 6
+	extra[bit/8] |= 0x80 >> uint(bit%8)
+	w.ShouldFail(ValidateSGTINStrict(extra, nil))
+
+	b = w.ShouldHaveResult(SGTIN{filter: POS, partition: 5, companyPrefix: 614141, indicator: 8, itemRef: 12345, serial: "6789"}.EncodeSGTIN96()).([]byte)
+	w.ShouldSucceed(ValidateSGTINStrict(b, nil))
+	w.ShouldFail(ValidateSGTINStrict(b[:11], nil))
+}
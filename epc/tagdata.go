@@ -0,0 +1,121 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"fmt"
+)
+
+// Confidence is how likely it is that tag data is an EPC binary encoding.
+type Confidence int
+
+const (
+	// NotEPC indicates the data can't be an EPC binary encoding: it's empty,
+	// its header isn't assigned to an EPC scheme, its length is wrong for
+	// its scheme, or it can't be split into its scheme's fields.
+	NotEPC = Confidence(iota)
+	// Unlikely indicates the data decodes as an EPC, but its values are out of
+	// range or it breaks other rules of the EPC Tag Data Standard.
+	Unlikely
+	// Possible indicates the data has an assigned header and the right length
+	// for its scheme, but this package can't decode it to check its fields.
+	Possible
+	// Likely indicates the data decodes as an EPC that passes validation.
+	Likely
+)
+
+func (c Confidence) String() string {
+	switch c {
+	case NotEPC:
+		return "NotEPC"
+	case Unlikely:
+		return "Unlikely"
+	case Possible:
+		return "Possible"
+	case Likely:
+		return "Likely"
+	}
+	return "Unknown confidence"
+}
+
+// Classification is the result of ClassifyTagData.
+type Classification struct {
+	Confidence Confidence
+	// Scheme is the name of the encoding scheme indicated by the header, such
+	// as "sgtin-96", or the decoded EPC's scheme if it was decoded with a
+	// decoder added by RegisterScheme. It's empty if the header is unknown.
+	Scheme string
+	// EPC is the decoded EPC, or nil if the data couldn't be decoded.
+	EPC EPC
+	// Reason explains why the Confidence isn't Likely.
+	Reason string
+}
+
+// ClassifyTagData reports how likely it is that b is the binary encoding of an
+// EPC, rather than arbitrary data, as discussed in this package's overview.
+//
+// The data is checked in order of increasing confidence: its header must be
+// assigned by the EPC Tag Data Standard or added with RegisterScheme, its
+// length must match its scheme's, it must decode (e.g., its partition value
+// must be valid), and the decoded EPC must pass ValidateRanges. SGTINs must
+// also pass ValidateSGTINStrict, without a company prefix length lookup.
+//
+// Binary EPCs don't include check digits, so they can't be used as evidence.
+// Even Likely data may not really be an EPC: arbitrary data that happens to
+// start with an EPC header can still pass every check, particularly for
+// schemes such as GID-96, whose fields accept every value.
+func ClassifyTagData(b []byte) Classification {
+	var c Classification
+	if len(b) == 0 {
+		c.Reason = "no data provided"
+		return c
+	}
+
+	name, bitLength, assigned := SchemeForHeader(b[0])
+	c.Scheme = name
+	if assigned && bitLength > 0 && len(b) != (bitLength+7)/8 {
+		c.Reason = fmt.Sprintf("%s EPCs have %d bytes, but this has %d",
+			name, (bitLength+7)/8, len(b))
+		return c
+	}
+
+	if !SupportsHeader(b[0]) {
+		if !assigned {
+			c.Reason = fmt.Sprintf("header %#X isn't assigned to an EPC scheme", b[0])
+			return c
+		}
+		c.Confidence = Possible
+		c.Reason = fmt.Sprintf("unable to decode %s EPCs to validate them", name)
+		return c
+	}
+
+	e, err := DecodeEPC(b)
+	if err != nil {
+		c.Reason = err.Error()
+		return c
+	}
+	c.EPC = e
+	if c.Scheme == "" {
+		c.Scheme = e.Scheme()
+	}
+
+	if err := e.ValidateRanges(); err != nil {
+		c.Confidence = Unlikely
+		c.Reason = err.Error()
+		return c
+	}
+	if s, ok := e.(SGTIN); ok {
+		if err := validateEncodingStrict(b, s, nil); err != nil {
+			c.Confidence = Unlikely
+			c.Reason = err.Error()
+			return c
+		}
+	}
+
+	c.Confidence = Likely
+	return c
+}
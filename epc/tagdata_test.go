@@ -0,0 +1,58 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestClassifyTagData(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for _, tt := range []struct {
+		data       string
+		confidence Confidence
+		scheme     string
+	}{
+		{"3034257BF7194E4000001A85", Likely, "sgtin-96"},
+		{"3134257BF4499602D2000000", Likely, "sscc-96"},
+		{"355AB1C6000303900000018F", Likely, "gid-96"},
+		{"3034FFFFFFFFFE4000001A85", Unlikely, "sgtin-96"}, // company prefix too large
+		{"30B4257BF7194E4000001A85", Unlikely, "sgtin-96"}, // reserved filter
+		{"3374257BF7194E4000001A85", Possible, "grai-96"},
+		{"303C257BF7194E4000001A85", NotEPC, "sgtin-96"}, // partition 7
+		{"3134257BF4499602D2000001", NotEPC, "sscc-96"},  // reserved bits
+		{"3034257BF7194E4000001A", NotEPC, "sgtin-96"},   // too short
+		{"3B", Possible, "adi-var"},
+		{"FF34257BF7194E4000001A85", NotEPC, ""},
+		{"", NotEPC, ""},
+	} {
+		b := w.ShouldHaveResult(hex.DecodeString(tt.data)).([]byte)
+		c := ClassifyTagData(b)
+		w.As(tt.data).ShouldBeEqual(c.Confidence, tt.confidence)
+		w.As(tt.data).ShouldBeEqual(c.Scheme, tt.scheme)
+		w.As(tt.data).ShouldBeEqual(c.Reason == "", tt.confidence == Likely)
+		w.As(tt.data).ShouldBeEqual(c.EPC != nil,
+			tt.confidence == Likely || tt.confidence == Unlikely)
+	}
+
+	// SGTIN-198 pad bits must be 0
+	s := w.ShouldHaveResult(NewSGTIN(POS, 5, 8, 614141, 12345, "a/b")).(SGTIN)
+	b := w.ShouldHaveResult(s.EncodeSGTIN198()).([]byte)
+	w.ShouldBeEqual(ClassifyTagData(b).Confidence, Likely)
+	b[len(b)-1] |= 1
+	w.ShouldBeEqual(ClassifyTagData(b).Confidence, Unlikely)
+}
+
+func TestConfidence_String(t *testing.T) {
+	w := expect.WrapT(t)
+	w.ShouldBeEqual(NotEPC.String(), "NotEPC")
+	w.ShouldBeEqual(Likely.String(), "Likely")
+	w.ShouldBeEqual(Confidence(-1).String(), "Unknown confidence")
+}
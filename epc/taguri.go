@@ -0,0 +1,134 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+// TagURIPrefix starts the EPC Tag URIs of all EPC schemes.
+const TagURIPrefix = "urn:epc:tag:"
+
+// TagURI returns the EPC Tag URI of an EPC, which, unlike its Pure Identity URI,
+// includes its filter value (for schemes that have one) and the bit length of
+// its binary encoding. For example, an SGTIN-96 Tag URI has the format:
+//     urn:epc:tag:sgtin-96:Filter.CompanyPrefix.ItemRefAndIndicator.Serial
+//
+// If bitLength is 0, it uses the same encoding as EncodeEPC; otherwise, it must
+// be a bit length supported by the EPC's scheme, and the EPC must be encodable
+// with that length. In any case, the EPC's values must be in range.
+func TagURI(e EPC, bitLength int) (string, error) {
+	if _, err := EncodeEPC(e, bitLength); err != nil {
+		return "", err
+	}
+
+	// the Tag URI's fields follow the filter, and match the Pure Identity URI
+	fields := strings.SplitN(e.URI(), ":", 5)[4]
+	switch v := e.(type) {
+	case SGTIN:
+		if bitLength == 0 {
+			bitLength = 198
+			if v.CanSGTIN96() == nil {
+				bitLength = 96
+			}
+		}
+		fields = strconv.Itoa(int(v.filter)) + "." + fields
+	case SSCC:
+		bitLength = 96
+		fields = strconv.Itoa(v.filter) + "." + fields
+	case GID:
+		bitLength = 96
+	}
+	return TagURIPrefix + e.Scheme() + "-" + strconv.Itoa(bitLength) + ":" + fields, nil
+}
+
+// EncodeEPC returns the binary encoding of an EPC with the given bit length, or
+// an error if its scheme doesn't support that length or its values can't be
+// encoded. If bitLength is 0, it uses the shortest encoding that can represent
+// the EPC; e.g., SGTIN-96 if the SGTIN's serial permits it, else SGTIN-198.
+func EncodeEPC(e EPC, bitLength int) ([]byte, error) {
+	switch v := e.(type) {
+	case SGTIN:
+		switch bitLength {
+		case 0:
+			return v.Encode()
+		case 96:
+			return v.EncodeSGTIN96()
+		case 198:
+			return v.EncodeSGTIN198()
+		}
+	case SSCC:
+		if bitLength == 0 || bitLength == 96 {
+			return v.Encode()
+		}
+	case GID:
+		if bitLength == 0 || bitLength == 96 {
+			return v.Encode()
+		}
+	default:
+		return nil, errors.Errorf("unsupported EPC type: %T", e)
+	}
+	return nil, errors.Errorf("the %s scheme has no %d bit encoding",
+		e.Scheme(), bitLength)
+}
+
+// ParseTagURI parses an EPC Tag URI of any scheme and bit length this package
+// supports, and returns the EPC it represents, with its filter value, and the
+// bit length given in the URI. It returns an error if the URI is malformed, its
+// scheme or bit length is unsupported, or its values can't be encoded with that
+// bit length.
+func ParseTagURI(uri string) (EPC, int, error) {
+	if !strings.HasPrefix(uri, TagURIPrefix) {
+		return nil, 0, errors.Errorf("EPC Tag URIs must start with %q",
+			TagURIPrefix)
+	}
+	parts := strings.SplitN(uri[len(TagURIPrefix):], ":", 2)
+	if len(parts) != 2 {
+		return nil, 0, errors.Errorf("EPC Tag URI %q has no fields", uri)
+	}
+	scheme, fields := parts[0], parts[1]
+
+	var e EPC
+	var err error
+	switch scheme {
+	case "sgtin-96", "sgtin-198":
+		var filter int
+		if filter, fields, err = splitTagURIFilter(fields); err == nil {
+			e, err = parseSGTINFields(fields, FilterValue(filter))
+		}
+	case "sscc-96":
+		var filter int
+		if filter, fields, err = splitTagURIFilter(fields); err == nil {
+			e, err = parseSSCCFields(fields, filter)
+		}
+	case "gid-96":
+		e, err = parseGIDFields(fields)
+	default:
+		return nil, 0, errors.Errorf("unsupported EPC Tag URI scheme: %q", scheme)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	bitLength, _ := strconv.Atoi(scheme[strings.IndexByte(scheme, '-')+1:])
+	if _, err := EncodeEPC(e, bitLength); err != nil {
+		return nil, 0, err
+	}
+	return e, bitLength, nil
+}
+
+// splitTagURIFilter splits the leading filter value from a Tag URI's fields.
+func splitTagURIFilter(fields string) (int, string, error) {
+	i := strings.IndexByte(fields, '.')
+	if i != 1 || fields[0] < '0' || fields[0] > '7' {
+		return 0, "", errors.Errorf("EPC Tag URI fields %q must start with "+
+			"a filter value in [0,7]", fields)
+	}
+	return int(fields[0] - '0'), fields[2:], nil
+}
@@ -0,0 +1,76 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package epc
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"strings"
+	"testing"
+)
+
+func TestTagURI(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for _, tt := range []struct {
+		epc       string
+		bitLength int
+		tagURI    string
+	}{
+		{"3034257BF7194E4000001A85", 96, "urn:epc:tag:sgtin-96:1.0614141.812345.6789"},
+		{"3134257BF4499602D2000000", 96, "urn:epc:tag:sscc-96:1.0614141.1234567890"},
+		{"355AB1C6000303900000018F", 96, "urn:epc:tag:gid-96:95100000.12345.399"},
+	} {
+		b := w.ShouldHaveResult(hex.DecodeString(tt.epc)).([]byte)
+		e := w.ShouldHaveResult(DecodeEPC(b)).(EPC)
+		w.As(tt.epc).ShouldBeEqual(w.ShouldHaveResult(TagURI(e, 0)), tt.tagURI)
+		w.As(tt.epc).ShouldBeEqual(w.ShouldHaveResult(TagURI(e, tt.bitLength)), tt.tagURI)
+
+		parsed, bitLength, err := ParseTagURI(tt.tagURI)
+		w.As(tt.tagURI).ShouldSucceed(err)
+		w.As(tt.tagURI).ShouldBeEqual(parsed, e)
+		w.As(tt.tagURI).ShouldBeEqual(bitLength, tt.bitLength)
+
+		encoded := w.ShouldHaveResult(EncodeEPC(parsed, bitLength)).([]byte)
+		w.As(tt.tagURI).ShouldBeEqual(strings.ToUpper(hex.EncodeToString(encoded)), tt.epc)
+	}
+
+	s := w.ShouldHaveResult(NewSGTIN(UnitLoad, 5, 8, 614141, 12345, "a/b")).(SGTIN)
+	w.ShouldBeEqual(w.ShouldHaveResult(TagURI(s, 0)),
+		"urn:epc:tag:sgtin-198:6.0614141.812345.a%2Fb")
+	w.ShouldHaveError(TagURI(s, 96))
+	w.ShouldHaveError(TagURI(s, 64))
+
+	s = w.ShouldHaveResult(NewSGTIN(POS, 5, 8, 614141, 12345, "6789")).(SGTIN)
+	w.ShouldBeEqual(w.ShouldHaveResult(TagURI(s, 198)),
+		"urn:epc:tag:sgtin-198:1.0614141.812345.6789")
+	parsed, bitLength, err := ParseTagURI("urn:epc:tag:sgtin-198:1.0614141.812345.6789")
+	w.ShouldSucceed(err)
+	w.ShouldBeEqual(parsed, s)
+	w.ShouldBeEqual(bitLength, 198)
+
+	g := w.ShouldHaveResult(NewGID(1, 2, 3)).(GID)
+	w.ShouldHaveError(TagURI(g, 198))
+	w.ShouldHaveError(TagURI(SGTIN{}, 0))
+
+	for _, uri := range []string{
+		"",
+		"urn:epc:id:sgtin:0614141.812345.6789",
+		"urn:epc:tag:sgtin-96",
+		"urn:epc:tag:sgtin-64:1.0614141.812345.6789",
+		"urn:epc:tag:sgtin-96:1.0614141.812345.a",
+		"urn:epc:tag:sgtin-96:0614141.812345.6789",
+		"urn:epc:tag:sgtin-96:8.0614141.812345.6789",
+		"urn:epc:tag:sgtin-96:3.0614141.812345.6789",
+		"urn:epc:tag:sscc-96:10.0614141.1234567890",
+		"urn:epc:tag:gid-96:0.95100000.12345.399",
+	} {
+		e, _, err := ParseTagURI(uri)
+		w.As(uri).ShouldFail(err)
+		w.As(uri).ShouldBeTrue(e == nil)
+	}
+}
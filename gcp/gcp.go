@@ -0,0 +1,170 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package gcp determines the length of GS1 Company Prefixes using the GS1
+// Company Prefix Length Table.
+//
+// Many GS1 keys (GTINs, GLNs, SSCCs, etc.) start with a GS1 Company Prefix, but
+// the keys themselves don't indicate where the prefix stops. That division is
+// significant for EPCs: the SGTIN partition value, for instance, is determined
+// by the company prefix length. GS1 publishes a table of prefixes and their
+// associated company prefix lengths, available here:
+// - https://www.gs1.org/standards/bc-epc-interop
+//
+// This package loads the JSON form of that table, which looks like this:
+//     {"GCPPrefixFormatList": {
+//         "date": "2019-05-01T00:00:00",
+//         "entry": [
+//             {"prefix": "0614141", "gcpLength": 7},
+//             ...
+//         ]
+//     }}
+package gcp
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+)
+
+// Table maps the leading digits of GS1 keys to their company prefix lengths.
+//
+// Tables are safe for concurrent use, as they are not modified after creation.
+type Table struct {
+	date    string
+	lengths map[string]int
+	longest int // length of the longest prefix in lengths
+}
+
+type tableJSON struct {
+	GCPPrefixFormatList struct {
+		Date  string `json:"date"`
+		Entry []struct {
+			Prefix    string `json:"prefix"`
+			GCPLength int    `json:"gcpLength"`
+		} `json:"entry"`
+	} `json:"GCPPrefixFormatList"`
+}
+
+// Load reads a GS1 Company Prefix Length Table in GS1's JSON format.
+func Load(r io.Reader) (*Table, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read GCP length table")
+	}
+	return Parse(data)
+}
+
+// Parse parses a GS1 Company Prefix Length Table in GS1's JSON format.
+func Parse(data []byte) (*Table, error) {
+	tj := tableJSON{}
+	if err := json.Unmarshal(data, &tj); err != nil {
+		return nil, errors.Wrap(err, "unable to parse GCP length table")
+	}
+
+	entries := make(map[string]int, len(tj.GCPPrefixFormatList.Entry))
+	for _, e := range tj.GCPPrefixFormatList.Entry {
+		entries[e.Prefix] = e.GCPLength
+	}
+	t, err := New(entries)
+	if err != nil {
+		return nil, err
+	}
+	t.date = tj.GCPPrefixFormatList.Date
+	return t, nil
+}
+
+// New returns a Table using the given mapping of key prefixes to company prefix
+// lengths. Prefixes must consist only of digits.
+//
+// As in GS1's table, a length of 0 indicates that keys with that prefix don't
+// start with a GS1 Company Prefix (e.g., because they are restricted
+// circulation numbers); otherwise, lengths must be at least as long as their
+// prefix, and no more than 12.
+func New(entries map[string]int) (*Table, error) {
+	t := &Table{lengths: make(map[string]int, len(entries))}
+	for prefix, length := range entries {
+		if !isDigits(prefix) {
+			return nil, errors.Errorf("prefix %q must contain only digits", prefix)
+		}
+		if length != 0 && (length < len(prefix) || length > 12) {
+			return nil, errors.Errorf("company prefix length for %q must "+
+				"be 0 or in [%d, 12], but is %d", prefix, len(prefix), length)
+		}
+		t.lengths[prefix] = length
+		if len(prefix) > t.longest {
+			t.longest = len(prefix)
+		}
+	}
+	return t, nil
+}
+
+// Date returns the publication date listed in the table, if it had one.
+func (t *Table) Date() string {
+	return t.date
+}
+
+// Len returns the number of prefixes in the table.
+func (t *Table) Len() int {
+	return len(t.lengths)
+}
+
+// PrefixLength returns the length of the GS1 Company Prefix that begins the
+// given GS1 key, using the longest matching prefix in the table.
+//
+// The key is interpreted based on its length:
+// - 14 digit GTINs and 18 digit SSCCs start with an indicator or extension
+//   digit, which is ignored
+// - 12 digit GTINs (U.P.C.s) are treated as their 13 digit equivalent, with a
+//   leading '0'
+// - GTIN-8s don't have GS1 Company Prefixes, so they result in an error
+// - all other keys, such as 13 digit GTINs and GLNs, are used as-is
+//
+// This returns an error if no prefix in the table matches the key or if the
+// matching prefix indicates keys in that range have no company prefix.
+func (t *Table) PrefixLength(gtinOrKey string) (int, error) {
+	if !isDigits(gtinOrKey) {
+		return 0, errors.Errorf("key %q must contain only digits", gtinOrKey)
+	}
+
+	key := gtinOrKey
+	switch len(key) {
+	case 8:
+		return 0, errors.New("GTIN-8s do not contain a GS1 Company Prefix")
+	case 12:
+		key = "0" + key
+	case 14, 18:
+		key = key[1:]
+	}
+
+	longest := t.longest
+	if longest > len(key) {
+		longest = len(key)
+	}
+	for l := longest; l > 0; l-- {
+		length, ok := t.lengths[key[:l]]
+		if !ok {
+			continue
+		}
+		if length == 0 {
+			return 0, errors.Errorf("keys with prefix %s do not have a "+
+				"GS1 Company Prefix", key[:l])
+		}
+		return length, nil
+	}
+	return 0, errors.Errorf("no company prefix length is known for %s", gtinOrKey)
+}
+
+// isDigits returns true if s is non-empty and only contains the digits 0-9.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,74 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gcp
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"strings"
+	"testing"
+)
+
+const testTable = `{"GCPPrefixFormatList": {
+	"date": "2019-05-01T00:00:00",
+	"entry": [
+		{"prefix": "0", "gcpLength": 7},
+		{"prefix": "02", "gcpLength": 0},
+		{"prefix": "0614141", "gcpLength": 7},
+		{"prefix": "08884466", "gcpLength": 9},
+		{"prefix": "95", "gcpLength": 8}
+	]
+}}`
+
+func TestTable_PrefixLength(t *testing.T) {
+	w := expect.WrapT(t)
+	table := w.ShouldHaveResult(Load(strings.NewReader(testTable))).(*Table)
+	w.ShouldBeEqual(table.Len(), 5)
+	w.ShouldBeEqual(table.Date(), "2019-05-01T00:00:00")
+
+	for key, length := range map[string]int{
+		"00614141007349":     7, // GTIN-14
+		"80614141123458":     7, // indicator digit is ignored
+		"0614141007349":      7, // GTIN-13
+		"614141007349":       7, // GTIN-12
+		"00888446671424":     9, // longest match wins
+		"00123456789012":     7, // shortest match
+		"9512345000016":      8, // GLN
+		"006141410000000019": 7, // SSCC
+	} {
+		w.As(key).ShouldBeEqual(
+			w.ShouldHaveResult(table.PrefixLength(key)).(int), length)
+	}
+
+	for _, key := range []string{
+		"",
+		"96385074",       // GTIN-8
+		"00212345678906", // RCN
+		"01234567890A",
+		"1234567890123", // no match
+	} {
+		w.As(key).ShouldHaveError(table.PrefixLength(key))
+	}
+}
+
+func TestNew_invalid(t *testing.T) {
+	w := expect.WrapT(t)
+	w.ShouldHaveError(New(map[string]int{"A": 7}))
+	w.ShouldHaveError(New(map[string]int{"0614141": 6}))
+	w.ShouldHaveError(New(map[string]int{"0": 13}))
+	w.ShouldHaveError(Parse([]byte(`{"GCPPrefixFormatList": [}`)))
+}
+
+func TestTable_SGTIN(t *testing.T) {
+	w := expect.WrapT(t)
+	table := w.ShouldHaveResult(Parse([]byte(testTable))).(*Table)
+
+	s := w.ShouldHaveResult(epc.NewSGTINFromGTIN(epc.POS, "00614141007349",
+		table, "314159")).(epc.SGTIN)
+	w.ShouldBeEqual(s.URI(), "urn:epc:id:sgtin:0614141.000734.314159")
+	w.ShouldBeEqual(s.Partition(), 5)
+}
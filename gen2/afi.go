@@ -0,0 +1,82 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// AFIDecoder decodes the ISO/IEC 15961 Unique Item Identifier in the EPC bank
+// of tags whose PC's Toggle bit is set, and returns its identifier, such as the
+// UII's data elements as text.
+type AFIDecoder func(uii []byte) (string, error)
+
+var (
+	afiMu       sync.RWMutex
+	afiDecoders = map[uint8]AFIDecoder{}
+)
+
+// RegisterAFI makes a decoder available to DecodeUII for UIIs with the given
+// Application Family Identifier, such as those assigned to ISO 17365 transport
+// units or ISO 17367 product tagging. The decoder may be called concurrently.
+// RegisterAFI panics if decoder is nil or the AFI already has a decoder. It's
+// typically called from an init function.
+func RegisterAFI(afi uint8, decoder AFIDecoder) {
+	if decoder == nil {
+		panic("gen2: RegisterAFI decoder is nil")
+	}
+
+	afiMu.Lock()
+	defer afiMu.Unlock()
+	if _, dup := afiDecoders[afi]; dup {
+		panic(fmt.Sprintf("gen2: RegisterAFI called twice for AFI %02Xh", afi))
+	}
+	afiDecoders[afi] = decoder
+}
+
+// LookupAFI returns the decoder registered for the AFI, or false if there isn't
+// one.
+func LookupAFI(afi uint8) (AFIDecoder, bool) {
+	afiMu.RLock()
+	defer afiMu.RUnlock()
+	d, ok := afiDecoders[afi]
+	return d, ok
+}
+
+// DecodeUII decodes the data that follows the PC in a tag's EPC bank or reply,
+// such as a Reply's EPC, according to the PC's Toggle bit. If it's not set, the
+// data is an EPC, which is trimmed of its word padding with TrimEPC, decoded
+// with epc.DecodeEPC, and checked with its ValidateRanges method, and its Pure
+// Identity URI is returned. Otherwise, the
+// data is an ISO UII, whose EPC header would be meaningless, so it's decoded by
+// the AFIDecoder registered for the PC's AFI, and its result is returned.
+//
+// It returns an error if the data can't be decoded, including if no decoder is
+// registered for the AFI.
+func DecodeUII(pc PC, uii []byte) (string, error) {
+	if !pc.Toggle {
+		e, err := epc.DecodeEPC(TrimEPC(uii))
+		if err != nil {
+			return "", err
+		}
+		if err := e.ValidateRanges(); err != nil {
+			return "", errors.Wrapf(err, "invalid %s", e.Scheme())
+		}
+		return e.URI(), nil
+	}
+
+	d, ok := LookupAFI(pc.NSI)
+	if !ok {
+		return "", errors.Errorf("the PC's Toggle bit is set, but no decoder "+
+			"is registered for its AFI, %02Xh", pc.NSI)
+	}
+	id, err := d(uii)
+	return id, errors.Wrapf(err, "unable to decode the UII with AFI %02Xh", pc.NSI)
+}
@@ -0,0 +1,72 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/pkg/errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// registerTestAFI registers the test AFI decoder once, even if tests are
+// repeated.
+var registerTestAFI sync.Once
+
+// testAFI is the AFI of the test decoder, which returns its UII in hex.
+const testAFI = 0xA2
+
+func TestDecodeUII(t *testing.T) {
+	w := expect.WrapT(t)
+
+	registerTestAFI.Do(func() {
+		_, ok := LookupAFI(testAFI)
+		w.ShouldBeFalse(ok)
+		RegisterAFI(testAFI, func(uii []byte) (string, error) {
+			if len(uii) == 0 {
+				return "", errors.New("empty UII")
+			}
+			return strings.ToUpper(hex.EncodeToString(uii)), nil
+		})
+	})
+	_, ok := LookupAFI(testAFI)
+	w.ShouldBeTrue(ok)
+
+	sgtin := w.ShouldHaveResult(hex.DecodeString("3034257BF7194E4000001A85")).([]byte)
+	w.ShouldBeEqual(w.ShouldHaveResult(DecodeUII(DecodePC(0x3000), sgtin)),
+		"urn:epc:id:sgtin:0614141.812345.6789")
+	// the same data as an ISO UII isn't decoded as an EPC
+	w.ShouldBeEqual(w.ShouldHaveResult(DecodeUII(DecodePC(0x31A2), sgtin)),
+		"3034257BF7194E4000001A85")
+
+	// SGTIN-198s are padded to 13 words in the EPC bank and replies
+	sgtin198 := w.ShouldHaveResult(hex.DecodeString(
+		"3614257BF7194E60C286C4000000000000000000000000000000")).([]byte)
+	w.ShouldBeEqual(w.ShouldHaveResult(DecodeUII(DecodePC(0x6800), sgtin198)),
+		"urn:epc:id:sgtin:0614141.812345.ABC1")
+	w.ShouldBeEqual(w.ShouldHaveResult(DecodeUII(DecodePC(0x6800), sgtin198[:25])),
+		"urn:epc:id:sgtin:0614141.812345.ABC1")
+	w.ShouldHaveError(DecodeUII(DecodePC(0x7000), append(sgtin198, 0, 0)))
+
+	w.ShouldHaveError(DecodeUII(DecodePC(0x31A2), nil))
+	w.ShouldHaveError(DecodeUII(DecodePC(0x31A3), sgtin))
+	w.ShouldHaveError(DecodeUII(DecodePC(0x3000), []byte{0xE2, 0x80}))
+	invalidSGTIN := w.ShouldHaveResult(hex.DecodeString("301000181C7FFFD3A8B43711")).([]byte)
+	w.ShouldHaveError(DecodeUII(DecodePC(0x3000), invalidSGTIN))
+
+	panics := func(f func()) (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		f()
+		return
+	}
+	w.ShouldBeTrue(panics(func() {
+		RegisterAFI(testAFI, func([]byte) (string, error) { return "", nil })
+	}))
+	w.ShouldBeTrue(panics(func() { RegisterAFI(0xA3, nil) }))
+}
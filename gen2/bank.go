@@ -0,0 +1,121 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"encoding/binary"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"github.com/pkg/errors"
+)
+
+// BankOption sets an option of the EPC bank BuildEPCBank builds.
+type BankOption func(*bankOptions)
+
+// bankOptions are the settings BankOptions set. Their zero value is the default.
+type bankOptions struct {
+	umi        bool
+	attributes uint8
+	crcZeroed  bool
+	bitLength  int
+}
+
+// WithUMI sets the PC's UMI bit, which says the tag has data in its user memory
+// bank. It's clear by default. Tags that compute their UMI bit ignore it.
+func WithUMI(umi bool) BankOption {
+	return func(o *bankOptions) {
+		o.umi = umi
+	}
+}
+
+// WithAttributes sets the EPCglobal attribute bits of the PC, bits 18h-1Fh,
+// which are 0 by default.
+func WithAttributes(bits uint8) BankOption {
+	return func(o *bankOptions) {
+		o.attributes = bits
+	}
+}
+
+// WithCRCPlaceholder makes the StoredCRC word 0 rather than the CRC16 of the
+// StoredPC and EPC. Tags compute their StoredCRC on power-up, and many don't
+// allow it to be written, so it's only a placeholder in the bank's image.
+func WithCRCPlaceholder() BankOption {
+	return func(o *bankOptions) {
+		o.crcZeroed = true
+	}
+}
+
+// WithBitLength sets the bit length of the EPC's encoding, such as 96 or 198, as
+// given to epc.EncodeEPC. By default, it's the one the EPC's Encode method
+// chooses, which is SGTIN-96 for SGTINs with numeric serials, so use this to
+// keep an SGTIN-198 tag's scheme when re-provisioning it.
+func WithBitLength(bits int) BankOption {
+	return func(o *bankOptions) {
+		o.bitLength = bits
+	}
+}
+
+// encoder is implemented by the EPCs package epc can encode, such as SGTINs.
+type encoder interface {
+	Encode() ([]byte, error)
+}
+
+// BuildEPCBank returns the words of the EPC bank of a tag with the given EPC,
+// from word 0: the StoredCRC, the StoredPC, whose L field is the length of the
+// EPC in words, and the EPC's binary encoding, padded with 0s to a whole word,
+// as for SGTIN-198s. The PC's XI and Toggle bits are clear, since tags compute
+// the former and EPCs aren't ISO UIIs.
+//
+// To program a tag, write the bank from word 1, the StoredPC, with a reader's
+// BlockWrite or Write commands; the tag computes its StoredCRC itself.
+//
+// The EPC must be one package epc can encode, such as an epc.SGTIN, SSCC, or
+// GID. It returns an error if it can't, if its values are out of range, or if
+// its encoding is too long for the PC's L field.
+func BuildEPCBank(e epc.EPC, opts ...BankOption) ([]uint16, error) {
+	var o bankOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	enc, ok := e.(encoder)
+	if !ok {
+		return nil, errors.Errorf("unable to encode EPCs of scheme %q", e.Scheme())
+	}
+	if err := e.ValidateRanges(); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", e.Scheme())
+	}
+	var b []byte
+	var err error
+	if o.bitLength != 0 {
+		b, err = epc.EncodeEPC(e, o.bitLength)
+	} else {
+		b, err = enc.Encode()
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to encode the %s", e.Scheme())
+	}
+
+	epcWords := (len(b) + 1) / 2
+	if epcWords > 0x1F {
+		return nil, errors.Errorf("the EPC has %d words, but the PC's L field "+
+			"can give at most %d", epcWords, 0x1F)
+	}
+	pc := PC{Length: epcWords, UMI: o.umi, NSI: o.attributes}
+
+	data := make([]byte, 2+2*epcWords)
+	binary.BigEndian.PutUint16(data, pc.Word())
+	copy(data[2:], b)
+
+	words := make([]uint16, 1+len(data)/2)
+	if !o.crcZeroed {
+		words[0] = CRC16(data)
+	}
+	for i := 1; i < len(words); i++ {
+		words[i] = binary.BigEndian.Uint16(data[2*(i-1):])
+	}
+	return words, nil
+}
@@ -0,0 +1,92 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"testing"
+)
+
+// unencodable is an EPC package epc can't encode.
+type unencodable struct{}
+
+func (unencodable) URI() string           { return "urn:epc:id:test:1" }
+func (unencodable) Scheme() string        { return "test" }
+func (unencodable) ValidateRanges() error { return nil }
+func (unencodable) CanonicalKey() []byte  { return []byte("test\x001") }
+
+// bankBytes returns the bytes of the words, as they're read from a tag.
+func bankBytes(words []uint16) []byte {
+	b := make([]byte, 2*len(words))
+	for i, word := range words {
+		binary.BigEndian.PutUint16(b[2*i:], word)
+	}
+	return b
+}
+
+func TestBuildEPCBank(t *testing.T) {
+	w := expect.WrapT(t)
+
+	b := w.ShouldHaveResult(hex.DecodeString("3034257BF7194E4000001A85")).([]byte)
+	sgtin := w.ShouldHaveResult(epc.DecodeSGTIN(b)).(epc.SGTIN)
+
+	words := w.ShouldHaveResult(BuildEPCBank(sgtin)).([]uint16)
+	w.ShouldBeEqual(words, []uint16{0xEE2C, 0x3000,
+		0x3034, 0x257B, 0xF719, 0x4E40, 0x0000, 0x1A85})
+	w.ShouldSucceed(VerifyStoredCRC(bankBytes(words)))
+
+	words = w.ShouldHaveResult(BuildEPCBank(sgtin, WithUMI(true),
+		WithAttributes(0x01))).([]uint16)
+	w.ShouldBeEqual(words[1], uint16(0x3401))
+	w.ShouldSucceed(VerifyStoredCRC(bankBytes(words)))
+
+	words = w.ShouldHaveResult(BuildEPCBank(sgtin, WithCRCPlaceholder())).([]uint16)
+	w.ShouldBeEqual(words[0], uint16(0))
+	w.ShouldBeEqual(words[1], uint16(0x3000))
+
+	// SGTIN-198s are padded to 13 words
+	long := w.ShouldHaveResult(epc.NewSGTIN(1, 5, 8, 614141, 12345, "A1B2")).(epc.SGTIN)
+	words = w.ShouldHaveResult(BuildEPCBank(long)).([]uint16)
+	w.ShouldBeEqual(len(words), 2+13)
+	w.ShouldBeEqual(words[1], uint16(0x6800))
+	w.ShouldBeEqual(words[2]>>8, uint16(0x36))
+	w.ShouldBeEqual(words[len(words)-1]&0x03FF, uint16(0))
+	w.ShouldSucceed(VerifyStoredCRC(bankBytes(words)))
+	uri := w.ShouldHaveResult(DecodeUII(DecodePC(words[1]), bankBytes(words)[4:4+25])).(string)
+	w.ShouldBeEqual(uri, long.URI())
+
+	// an SGTIN-198 with a numeric serial keeps its scheme with WithBitLength
+	words = w.ShouldHaveResult(BuildEPCBank(sgtin, WithBitLength(198))).([]uint16)
+	w.ShouldBeEqual(len(words), 2+13)
+	w.ShouldBeEqual(words[1], uint16(0x6800))
+	w.ShouldBeEqual(words[2:6], []uint16{0x3634, 0x257B, 0xF719, 0x4E5B})
+	w.ShouldSucceed(VerifyStoredCRC(bankBytes(words)))
+	words = w.ShouldHaveResult(BuildEPCBank(sgtin, WithBitLength(96))).([]uint16)
+	w.ShouldBeEqual(words[1], uint16(0x3000))
+	w.ShouldFail(BuildEPCBank(sgtin, WithBitLength(64)))
+	w.ShouldFail(BuildEPCBank(long, WithBitLength(96)))
+
+	w.ShouldHaveError(BuildEPCBank(unencodable{}))
+	invalid := w.ShouldHaveResult(hex.DecodeString("301000181C7FFFD3A8B43711")).([]byte)
+	w.ShouldHaveError(BuildEPCBank(w.ShouldHaveResult(epc.DecodeSGTIN(invalid)).(epc.SGTIN)))
+}
+
+func TestBuildEPCBank_sgtin198RoundTrip(t *testing.T) {
+	w := expect.WrapT(t)
+
+	const uri = "urn:epc:id:sgtin:0614141.812345.ABC1"
+	e := w.ShouldHaveResult(epc.ParsePureIdentityURI(uri)).(epc.EPC)
+	words := w.ShouldHaveResult(BuildEPCBank(e)).([]uint16)
+
+	// a reply is the bank from the StoredPC
+	r := w.ShouldHaveResult(ParseReply(bankBytes(words)[2:])).(Reply)
+	w.ShouldBeEqual(len(r.EPC), 26)
+	w.ShouldBeEqual(w.ShouldHaveResult(DecodeUII(r.PC, r.EPC)), uri)
+}
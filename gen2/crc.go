@@ -0,0 +1,80 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/pkg/errors"
+)
+
+// crcTable is the CRC-16 of each byte, with a zero preset and no final XOR.
+var crcTable = func() (table [256]uint16) {
+	for i := range table {
+		crc := uint16(i) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return
+}()
+
+// CRC16 returns the CRC-16 Gen2 uses to protect the EPC bank and tags' replies:
+// the CRC-CCITT of ISO/IEC 13239, with polynomial x^16 + x^12 + x^5 + 1
+// (0x1021), preset to FFFFh, not reflected, and complemented, which is also
+// known as CRC-16/GENIBUS.
+func CRC16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc = crc<<8 ^ crcTable[byte(crc>>8)^b]
+	}
+	return ^crc
+}
+
+// CRCError is returned by VerifyStoredCRC when an EPC bank's StoredCRC doesn't
+// match its StoredPC and EPC, as when a read or write was corrupted.
+type CRCError struct {
+	Stored   uint16 // the bank's StoredCRC
+	Computed uint16 // the CRC-16 of its StoredPC and EPC
+}
+
+func (e *CRCError) Error() string {
+	return fmt.Sprintf("the StoredCRC is %04X, but should be %04X",
+		e.Stored, e.Computed)
+}
+
+// VerifyStoredCRC checks the StoredCRC of an EPC memory bank read from its first
+// word, which is the CRC16 of the StoredPC and the number of EPC words the PC's
+// L field gives; the bank may have more words after them, such as XPC words.
+// It returns a *CRCError if the StoredCRC doesn't match, or another error if
+// the bank is too short for its PC.
+//
+// Tags compute their StoredCRC on power-up, so to verify a write, read the bank
+// back from a tag that's since been powered up again.
+func VerifyStoredCRC(bank []byte) error {
+	if len(bank) < 4 {
+		return errors.Errorf("an EPC bank needs at least a StoredCRC and "+
+			"StoredPC, 4 bytes, but has %d bytes", len(bank))
+	}
+	pc := DecodePC(binary.BigEndian.Uint16(bank[2:]))
+	end := 4 + 2*pc.Length
+	if len(bank) < end {
+		return errors.Errorf("the StoredPC gives an EPC of %d words, but the "+
+			"bank has only %d bytes after it", pc.Length, len(bank)-4)
+	}
+
+	stored := binary.BigEndian.Uint16(bank)
+	if computed := CRC16(bank[2:end]); computed != stored {
+		return &CRCError{Stored: stored, Computed: computed}
+	}
+	return nil
+}
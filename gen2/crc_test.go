@@ -0,0 +1,55 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/pkg/errors"
+	"testing"
+)
+
+func TestCRC16(t *testing.T) {
+	w := expect.WrapT(t)
+
+	// the CRC-16/GENIBUS check value
+	w.ShouldBeEqual(CRC16([]byte("123456789")), uint16(0xD64E))
+	w.ShouldBeEqual(CRC16(nil), uint16(0))
+
+	pcEPC := w.ShouldHaveResult(hex.DecodeString("30003034257BF7194E4000001A85")).([]byte)
+	w.ShouldBeEqual(CRC16(pcEPC), uint16(0xEE2C))
+}
+
+func TestVerifyStoredCRC(t *testing.T) {
+	w := expect.WrapT(t)
+
+	const bank = "EE2C30003034257BF7194E4000001A85"
+	for _, valid := range []string{
+		bank,
+		bank + "00000000", // with words past the EPC
+		"E2F00000",        // an empty EPC, whose CRC is of the PC alone
+	} {
+		b := w.ShouldHaveResult(hex.DecodeString(valid)).([]byte)
+		w.As(valid).ShouldSucceed(VerifyStoredCRC(b))
+	}
+
+	// a corrupted bit in the EPC
+	b := w.ShouldHaveResult(hex.DecodeString(bank)).([]byte)
+	b[len(b)-1] ^= 1
+	err := VerifyStoredCRC(b)
+	crcErr, ok := errors.Cause(err).(*CRCError)
+	w.ShouldBeTrue(ok)
+	w.ShouldBeEqual(*crcErr, CRCError{Stored: 0xEE2C, Computed: CRC16(b[2:])})
+
+	for _, invalid := range []string{"", "EE2C30", "EE2C3000", bank[:len(bank)-2]} {
+		b := w.ShouldHaveResult(hex.DecodeString(invalid)).([]byte)
+		err := VerifyStoredCRC(b)
+		w.As(invalid).ShouldFail(err)
+		_, isCRCErr := err.(*CRCError)
+		w.As(invalid).ShouldBeFalse(isCRCErr)
+	}
+}
@@ -0,0 +1,85 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package gen2 decodes the memory banks and replies of EPC UHF Gen2 RFID tags,
+// as specified by the GS1 EPC UHF Gen2 Air Interface Protocol, version 2.0.1,
+// so that the EPC binary encodings package epc decodes can be located within
+// them:
+// - https://www.gs1.org/sites/default/files/docs/epc/Gen2_Protocol_Standard.pdf
+//
+// Gen2 tags store data in 16-bit words, and number bits in each memory bank
+// from its most significant bit, in hex, so the Protocol Control (PC) word of
+// the EPC bank is bits 10h to 1Fh. As elsewhere in this module, words are big
+// endian in byte slices.
+package gen2
+
+import (
+	"fmt"
+)
+
+// PC is a decoded Protocol Control word, which precedes a tag's EPC in its EPC
+// memory bank, as the StoredPC, and in its replies to Gen2 ACK commands, as the
+// PacketPC.
+type PC struct {
+	// Length is the L field, bits 10h-14h: the length of the EPC in words.
+	// In a PacketPC, it also counts the XPC words the tag sends with it.
+	Length int
+	// UMI, the User Memory Indicator, bit 15h, is set if the tag has data
+	// in its user memory bank.
+	UMI bool
+	// XI, the XPC_W1 Indicator, bit 16h, is set if any of the bits of the
+	// tag's XPC_W1 word are set, in which case its replies include it.
+	XI bool
+	// Toggle, bit 17h, is set if the EPC bank holds an ISO/IEC 15961 Unique
+	// Item Identifier rather than an EPC, in which case NSI is its AFI.
+	Toggle bool
+	// NSI is bits 18h-1Fh, which, with Toggle, form the Numbering System
+	// Identifier: an ISO/IEC 15961 Application Family Identifier if Toggle
+	// is set, or otherwise the EPCglobal attribute bits.
+	NSI uint8
+}
+
+// pcLengthShift is the position of the PC word's L field.
+const pcLengthShift = 11
+
+// PC word bits.
+const (
+	pcUMI    = 1 << 10
+	pcXI     = 1 << 9
+	pcToggle = 1 << 8
+)
+
+// DecodePC decodes a PC word.
+func DecodePC(word uint16) PC {
+	return PC{
+		Length: int(word >> pcLengthShift),
+		UMI:    word&pcUMI != 0,
+		XI:     word&pcXI != 0,
+		Toggle: word&pcToggle != 0,
+		NSI:    uint8(word),
+	}
+}
+
+// Word returns the PC's encoding. Its Length is truncated to 5 bits.
+func (pc PC) Word() uint16 {
+	word := uint16(pc.Length&0x1F)<<pcLengthShift | uint16(pc.NSI)
+	if pc.UMI {
+		word |= pcUMI
+	}
+	if pc.XI {
+		word |= pcXI
+	}
+	if pc.Toggle {
+		word |= pcToggle
+	}
+	return word
+}
+
+// String formats the PC as its hex encoding followed by its fields.
+func (pc PC) String() string {
+	return fmt.Sprintf("%04X (L=%d UMI=%t XI=%t T=%t NSI=%02X)",
+		pc.Word(), pc.Length, pc.UMI, pc.XI, pc.Toggle, pc.NSI)
+}
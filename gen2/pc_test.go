@@ -0,0 +1,37 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestDecodePC(t *testing.T) {
+	w := expect.WrapT(t)
+
+	testCases := []struct {
+		word uint16
+		pc   PC
+	}{
+		{0x3000, PC{Length: 6}},
+		{0x3400, PC{Length: 6, UMI: true}},
+		{0x3A00, PC{Length: 7, XI: true}},
+		{0x3100, PC{Length: 6, Toggle: true}},
+		{0x31A2, PC{Length: 6, Toggle: true, NSI: 0xA2}},
+		{0xFFFF, PC{Length: 31, UMI: true, XI: true, Toggle: true, NSI: 0xFF}},
+		{0x0000, PC{}},
+	}
+	for _, tc := range testCases {
+		w := w.As(tc.word)
+		w.ShouldBeEqual(DecodePC(tc.word), tc.pc)
+		w.ShouldBeEqual(tc.pc.Word(), tc.word)
+	}
+
+	w.ShouldBeEqual(PC{Length: 6, UMI: true}.String(),
+		"3400 (L=6 UMI=true XI=false T=false NSI=00)")
+}
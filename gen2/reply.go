@@ -0,0 +1,96 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"encoding/binary"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"github.com/pkg/errors"
+)
+
+// Reply is a tag's reply to a Gen2 ACK command, split into its parts.
+type Reply struct {
+	PC  PC
+	XPC XPC
+	// EPC is the tag's EPC, or UII if PC.Toggle is set. It's padded with 0s
+	// to a whole word, so decode it with DecodeUII, or trim it with TrimEPC
+	// before decoding it with package epc.
+	EPC []byte
+}
+
+// ParseReply splits a tag's reply to an ACK command, as reported by readers
+// without its trailing CRC-16, into its PacketPC, XPC words, if its XI bit is
+// set, and EPC. Per Gen2, the PacketPC's L field counts the XPC words as well
+// as the EPC's; the Reply's PC is as sent, but its EPC excludes them.
+//
+// It returns an error if the data is too short for the words its PC and XPC
+// say follow them, or has bytes after them. The Reply's EPC is a slice of the
+// data, rather than a copy.
+func ParseReply(data []byte) (Reply, error) {
+	var r Reply
+	if len(data) < 2 {
+		return r, errors.Errorf("a reply needs at least a 2-byte PC word, "+
+			"but has %d bytes", len(data))
+	}
+	r.PC = DecodePC(binary.BigEndian.Uint16(data))
+	rest := data[2:]
+
+	xpcWords := 0
+	if r.PC.XI {
+		if len(rest) < 2 {
+			return r, errors.New("the PC's XI bit is set, but the reply " +
+				"has no XPC_W1 word")
+		}
+		w1 := binary.BigEndian.Uint16(rest)
+		var w2 uint16
+		if w1&xpcXEB != 0 {
+			if len(rest) < 4 {
+				return r, errors.New("the XPC_W1's XEB bit is set, but the " +
+					"reply has no XPC_W2 word")
+			}
+			w2 = binary.BigEndian.Uint16(rest[2:])
+		}
+		r.XPC = DecodeXPC(w1, w2)
+		xpcWords = 1
+		if r.XPC.XEB {
+			xpcWords = 2
+		}
+		rest = rest[2*xpcWords:]
+	}
+
+	epcWords := r.PC.Length - xpcWords
+	if epcWords < 0 {
+		return r, errors.Errorf("the PC's length, %d words, is less than its "+
+			"%d XPC words", r.PC.Length, xpcWords)
+	}
+	if len(rest) != 2*epcWords {
+		return r, errors.Errorf("the PC gives an EPC of %d words, or %d "+
+			"bytes, but the reply has %d bytes after the PC and XPC",
+			epcWords, 2*epcWords, len(rest))
+	}
+	r.EPC = rest
+	return r, nil
+}
+
+// TrimEPC returns the EPC in data, which is padded with 0s to a whole word, as
+// in a tag's EPC bank or reply, without the bytes past the bit length that
+// epc.SchemeForHeader gives for its header, so that package epc can decode it.
+// E.g., SGTIN-198s are 13 words, or 26 bytes, but their encoding has 25 bytes.
+//
+// Data whose header has no fixed bit length, or whose length isn't that bit
+// length rounded up to a whole word, is returned as it is. The result is a
+// slice of the data, rather than a copy.
+func TrimEPC(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+	_, bits, ok := epc.SchemeForHeader(data[0])
+	if !ok || bits == 0 || len(data) != (bits+15)/16*2 {
+		return data
+	}
+	return data[:(bits+7)/8]
+}
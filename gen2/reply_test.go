@@ -0,0 +1,78 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestParseReply(t *testing.T) {
+	w := expect.WrapT(t)
+
+	const epcHex = "3034257BF7194E4000001A85"
+	testCases := []struct {
+		name  string
+		reply string
+		pc    PC
+		xpc   XPC
+	}{
+		{"no xpc", "3000" + epcHex, PC{Length: 6}, XPC{}},
+		{"umi", "3400" + epcHex, PC{Length: 6, UMI: true}, XPC{}},
+		{"xpc_w1", "3A00" + "0880" + epcHex, PC{Length: 7, XI: true},
+			DecodeXPC(0x0880, 0)},
+		{"xpc_w2", "4200" + "80401234" + epcHex, PC{Length: 8, XI: true},
+			DecodeXPC(0x8040, 0x1234)},
+	}
+	for _, tc := range testCases {
+		w := w.As(tc.name)
+		data := w.ShouldHaveResult(hex.DecodeString(tc.reply)).([]byte)
+		r := w.ShouldHaveResult(ParseReply(data)).(Reply)
+		w.ShouldBeEqual(r.PC, tc.pc)
+		w.ShouldBeEqual(r.XPC, tc.xpc)
+		w.ShouldBeEqual(hex.EncodeToString(r.EPC), "3034257bf7194e4000001a85")
+	}
+
+	empty := w.ShouldHaveResult(ParseReply([]byte{0, 0})).(Reply)
+	w.ShouldBeEqual(len(empty.EPC), 0)
+
+	for _, invalid := range []string{
+		"",
+		"30",
+		"3000" + epcHex[2:],      // too short
+		"3000" + epcHex + "00",   // too long
+		"3A00",                   // no XPC_W1
+		"3A008040",               // no XPC_W2
+		"02000880",               // L doesn't count the XPC_W1
+		"3200" + "0880" + epcHex, // L doesn't count the XPC_W1
+	} {
+		data := w.ShouldHaveResult(hex.DecodeString(invalid)).([]byte)
+		w.As(invalid).ShouldFail(ParseReply(data))
+	}
+}
+
+func TestTrimEPC(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for _, tc := range []struct{ data, trimmed string }{
+		{"3614257BF7194E60C286C4000000000000000000000000000000",
+			"3614257BF7194E60C286C40000000000000000000000000000"},
+		{"3614257BF7194E60C286C40000000000000000000000000000",
+			"3614257BF7194E60C286C40000000000000000000000000000"},
+		{"3034257BF7194E4000001A85", "3034257BF7194E4000001A85"},
+		// unassigned headers and unexpected lengths are as they are
+		{"FF00", "FF00"},
+		{"3614257BF7194E60C286C400000000000000000000000000000000",
+			"3614257BF7194E60C286C400000000000000000000000000000000"},
+		{"", ""},
+	} {
+		data := w.ShouldHaveResult(hex.DecodeString(tc.data)).([]byte)
+		trimmed := w.ShouldHaveResult(hex.DecodeString(tc.trimmed)).([]byte)
+		w.As(tc.data).ShouldBeEqual(TrimEPC(data), trimmed)
+	}
+}
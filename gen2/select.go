@@ -0,0 +1,182 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+// MemoryBank is a Gen2 memory bank, as numbered by the MemBank fields of Select,
+// Read, and Write commands.
+type MemoryBank int
+
+const (
+	BankReserved = MemoryBank(iota)
+	BankEPC
+	BankTID
+	BankUser
+)
+
+func (mb MemoryBank) String() string {
+	switch mb {
+	case BankReserved:
+		return "Reserved"
+	case BankEPC:
+		return "EPC"
+	case BankTID:
+		return "TID"
+	case BankUser:
+		return "User"
+	}
+	return "MemoryBank(" + strconv.Itoa(int(mb)) + ")"
+}
+
+// EPCStartBit is the bit address in the EPC bank of the first bit of the EPC,
+// after the StoredCRC and StoredPC.
+const EPCStartBit = 0x20
+
+// SelectMask holds the parameters of a Gen2 Select command that matches tags
+// whose memory has the Mask's bits at the given address, as configured by
+// reader Select, or C1G2Filter, filters.
+type SelectMask struct {
+	Bank MemoryBank
+	// Pointer is the bit address of the first bit to compare.
+	Pointer int
+	// Length is the number of bits to compare.
+	Length int
+	// Mask holds the bits to compare, starting with the highest-order bit
+	// of Mask[0], and padded to a whole number of bytes with 0s.
+	Mask []byte
+}
+
+// SelectMaskForGTIN returns the SelectMask that matches SGTINs of the given
+// GTIN, regardless of their filter values or serials: their partition, GS1
+// Company Prefix, and indicator/item reference fields, starting at bit 2Bh of
+// the EPC bank. As with epc.NewSGTINFromGTIN, the GTIN may have 8, 12, 13, or
+// 14 digits, and prefixLen gives the length of its company prefix, which
+// determines the fields' offsets.
+//
+// Since SGTIN-96 and SGTIN-198 EPCs have the same fields at these offsets, it
+// matches both, as well as other EPCs that happen to have the same bits there.
+// To match only SGTIN-96s, filter with SelectMaskForHeader too.
+func SelectMaskForGTIN(gtin string, prefixLen epc.PrefixLengthLookup) (SelectMask, error) {
+	s, err := epc.NewSGTINFromGTIN(0, gtin, prefixLen, "0")
+	if err != nil {
+		return SelectMask{}, err
+	}
+	return sgtinMask(s, "Indicator/Item Reference")
+}
+
+// SelectMaskForCompanyPrefix returns the SelectMask that matches SGTINs with the
+// given GS1 Company Prefix, which must have 6 to 12 digits: their partition
+// and company prefix fields, starting at bit 2Bh of the EPC bank. As with
+// SelectMaskForGTIN, it matches SGTIN-96s and SGTIN-198s.
+func SelectMaskForCompanyPrefix(prefix string) (SelectMask, error) {
+	if len(prefix) < 6 || len(prefix) > 12 {
+		return SelectMask{}, errors.Errorf("company prefixes must have 6 to "+
+			"12 digits, but %q has %d", prefix, len(prefix))
+	}
+	companyPrefix, err := strconv.Atoi(prefix)
+	if err != nil || companyPrefix < 0 || prefix[0] == '+' {
+		return SelectMask{}, errors.Errorf("company prefix %q must be all "+
+			"digits", prefix)
+	}
+	s, err := epc.NewSGTIN(0, 12-len(prefix), 0, companyPrefix, 0, "0")
+	if err != nil {
+		return SelectMask{}, err
+	}
+	return sgtinMask(s, "GS1 Company Prefix")
+}
+
+// SelectMaskForHeader returns the SelectMask that matches EPCs with the given
+// header, such as epc.SGTIN96Header.
+func SelectMaskForHeader(header byte) SelectMask {
+	return SelectMask{Bank: BankEPC, Pointer: EPCStartBit, Length: 8,
+		Mask: []byte{header}}
+}
+
+// sgtinMask returns the SelectMask for the bits of the SGTIN's SGTIN-96
+// encoding from its partition to the end of the named field.
+func sgtinMask(s epc.SGTIN, last string) (SelectMask, error) {
+	b, err := s.EncodeSGTIN96()
+	if err != nil {
+		return SelectMask{}, err
+	}
+	fields, err := s.Describe()
+	if err != nil {
+		return SelectMask{}, err
+	}
+
+	start, end := -1, -1
+	for _, f := range fields {
+		if f.Name == "Partition" {
+			start = f.StartBit
+		}
+		if f.Name == last {
+			end = f.StartBit + f.BitLength
+		}
+	}
+	if start < 0 || end < 0 {
+		return SelectMask{}, errors.Errorf("the SGTIN has no %q field", last)
+	}
+	return SelectMask{
+		Bank:    BankEPC,
+		Pointer: EPCStartBit + start,
+		Length:  end - start,
+		Mask:    copyBits(b, start, end-start),
+	}, nil
+}
+
+// copyBits returns length bits of b, starting at the given bit, where bit 0 is
+// the highest-order bit of b[0], left-aligned in a new slice.
+func copyBits(b []byte, start, length int) []byte {
+	out := make([]byte, (length+7)/8)
+	putBits(out, 0, b, start, length)
+	return out
+}
+
+// putBits copies length bits of src, starting at bit start, to dst, starting at
+// bit at, numbering bits as copyBits does.
+func putBits(dst []byte, at int, src []byte, start, length int) {
+	for i := 0; i < length; i++ {
+		s, d := start+i, at+i
+		if src[s/8]&(0x80>>uint(s%8)) != 0 {
+			dst[d/8] |= 0x80 >> uint(d%8)
+		} else {
+			dst[d/8] &^= 0x80 >> uint(d%8)
+		}
+	}
+}
+
+// Matches returns true if the memory bank, read from its first word, has the
+// Mask's bits at its Pointer, as a tag would determine for a Select command.
+// Tags whose bank ends before the last of those bits don't match.
+func (m SelectMask) Matches(bank []byte) bool {
+	if m.Pointer < 0 || m.Length < 0 || len(m.Mask)*8 < m.Length ||
+		len(bank)*8 < m.Pointer+m.Length {
+		return false
+	}
+	for i := 0; i < m.Length; i++ {
+		bit := m.Pointer + i
+		want := m.Mask[i/8]&(0x80>>uint(i%8)) != 0
+		have := bank[bit/8]&(0x80>>uint(bit%8)) != 0
+		if want != have {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the SelectMask's parameters, with its Mask in hex, as readers
+// usually take it.
+func (m SelectMask) String() string {
+	return fmt.Sprintf("bank=%v pointer=%d length=%d mask=%X",
+		m.Bank, m.Pointer, m.Length, m.Mask)
+}
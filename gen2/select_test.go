@@ -0,0 +1,85 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"testing"
+)
+
+func TestSelectMaskForGTIN(t *testing.T) {
+	w := expect.WrapT(t)
+
+	// urn:epc:id:sgtin:0614141.812345.6789 in an EPC bank, after its CRC & PC
+	bank := w.ShouldHaveResult(hex.DecodeString("00003000" +
+		"3034257BF7194E4000001A85")).([]byte)
+	otherItem := w.ShouldHaveResult(hex.DecodeString("00003000" +
+		"3034257BF7194E8000001A85")).([]byte)
+	otherFilter := w.ShouldHaveResult(hex.DecodeString("00003000" +
+		"3054257BF7194E4000001A86")).([]byte)
+
+	m := w.ShouldHaveResult(SelectMaskForGTIN("80614141123458",
+		epc.FixedPrefixLength(7))).(SelectMask)
+	w.ShouldBeEqual(m.Bank, BankEPC)
+	w.ShouldBeEqual(m.Pointer, EPCStartBit+11)
+	w.ShouldBeEqual(m.Length, 3+24+20)
+	w.ShouldBeEqual(len(m.Mask), 6)
+	w.ShouldBeTrue(m.Matches(bank))
+	w.ShouldBeTrue(m.Matches(otherFilter))
+	w.ShouldBeFalse(m.Matches(otherItem))
+	w.ShouldBeFalse(m.Matches(bank[:8]))
+	w.ShouldBeEqual(m.String(), "bank=EPC pointer=43 length=47 mask=A12BDFB8CA72")
+
+	// the same GTIN with a different company prefix length is encoded differently
+	m2 := w.ShouldHaveResult(SelectMaskForGTIN("80614141123458",
+		epc.FixedPrefixLength(8))).(SelectMask)
+	w.ShouldBeFalse(m2.Matches(bank))
+
+	w.ShouldFail(SelectMaskForGTIN("80614141123459", epc.FixedPrefixLength(7)))
+	w.ShouldFail(SelectMaskForGTIN("8061414112345", epc.FixedPrefixLength(7)))
+	w.ShouldFail(SelectMaskForGTIN("80614141123458", epc.FixedPrefixLength(13)))
+}
+
+func TestSelectMaskForCompanyPrefix(t *testing.T) {
+	w := expect.WrapT(t)
+
+	bank := w.ShouldHaveResult(hex.DecodeString("00003000" +
+		"3034257BF7194E4000001A85")).([]byte)
+	otherItem := w.ShouldHaveResult(hex.DecodeString("00003000" +
+		"3034257BF7194E8000001A85")).([]byte)
+
+	m := w.ShouldHaveResult(SelectMaskForCompanyPrefix("0614141")).(SelectMask)
+	w.ShouldBeEqual(m.Pointer, EPCStartBit+11)
+	w.ShouldBeEqual(m.Length, 3+24)
+	w.ShouldBeTrue(m.Matches(bank))
+	w.ShouldBeTrue(m.Matches(otherItem))
+
+	w.ShouldBeFalse(w.ShouldHaveResult(SelectMaskForCompanyPrefix("0614142")).(SelectMask).Matches(bank))
+	w.ShouldBeFalse(w.ShouldHaveResult(SelectMaskForCompanyPrefix("06141410")).(SelectMask).Matches(bank))
+
+	for _, prefix := range []string{"", "12345", "1234567890123", "06141a1", "-614141", "+614141"} {
+		w.As(prefix).ShouldFail(SelectMaskForCompanyPrefix(prefix))
+	}
+}
+
+func TestSelectMaskForHeader(t *testing.T) {
+	w := expect.WrapT(t)
+
+	bank := w.ShouldHaveResult(hex.DecodeString("00003000" +
+		"3034257BF7194E4000001A85")).([]byte)
+	w.ShouldBeTrue(SelectMaskForHeader(0x30).Matches(bank))
+	w.ShouldBeFalse(SelectMaskForHeader(0x36).Matches(bank))
+}
+
+func TestMemoryBank_String(t *testing.T) {
+	w := expect.WrapT(t)
+	w.ShouldBeEqual(BankReserved.String(), "Reserved")
+	w.ShouldBeEqual(BankUser.String(), "User")
+	w.ShouldBeEqual(MemoryBank(4).String(), "MemoryBank(4)")
+}
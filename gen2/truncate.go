@@ -0,0 +1,85 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"github.com/pkg/errors"
+)
+
+// truncatedLeadBits is the number of 0 bits tags send before a truncated EPC,
+// in place of the PacketPC.
+const truncatedLeadBits = 5
+
+// ReconstructTruncatedEPC returns the full EPC of a tag that replied to an ACK
+// command with a truncated reply, as it does if the last Select command it
+// matched had its Truncate bit set. Per Gen2, such tags send 00000b followed by
+// only the part of their EPC after the end of the Select's mask, since the
+// reader already knows the rest; the EPC is those bits appended to the mask's.
+//
+// The mask must be in the EPC bank and start at EPCStartBit, so that it has all
+// the EPC bits the tag doesn't send, and end within the EPC. Since truncated
+// replies have no PC, epcWords gives the length of the tag's EPC in words; if
+// it's 0, it's taken from the bit length epc.SchemeForHeader gives for the
+// header in the mask, rounded up to a whole word, such as 6 for SGTIN-96s.
+//
+// The reply is the tag's reply, as reported by readers, starting with its 0s
+// and without its trailing CRC-16; it may be padded with 0s to a whole byte or
+// word. It returns an error if the reply doesn't start with 0s or has the wrong
+// number of bits for the rest of the EPC.
+//
+// The EPC is returned without its word padding, as by TrimEPC, so that package
+// epc can decode it; e.g., an SGTIN-198 is 13 words, but 25 bytes.
+func ReconstructTruncatedEPC(mask SelectMask, epcWords int, reply []byte) ([]byte, error) {
+	if mask.Bank != BankEPC || mask.Pointer != EPCStartBit {
+		return nil, errors.Errorf("truncated EPCs can only be reconstructed with "+
+			"masks starting at the EPC, bit %Xh of the EPC bank, but the mask "+
+			"starts at bit %Xh of the %v bank", EPCStartBit, mask.Pointer, mask.Bank)
+	}
+	if mask.Length < 0 || len(mask.Mask)*8 < mask.Length {
+		return nil, errors.Errorf("the mask's length, %d bits, doesn't fit its "+
+			"%d bytes", mask.Length, len(mask.Mask))
+	}
+
+	if epcWords == 0 {
+		if mask.Length < 8 {
+			return nil, errors.Errorf("the EPC's length isn't given, and the "+
+				"mask, with %d bits, doesn't have its header", mask.Length)
+		}
+		name, bits, ok := epc.SchemeForHeader(mask.Mask[0])
+		if !ok || bits == 0 {
+			return nil, errors.Errorf("the EPC's length isn't given, and can't "+
+				"be determined from the mask's header, %02X (%q)", mask.Mask[0], name)
+		}
+		epcWords = (bits + 15) / 16
+	}
+	if epcWords < 0 || epcWords > 31 {
+		return nil, errors.Errorf("EPCs have 0 to 31 words, not %d", epcWords)
+	}
+	epcBits := epcWords * 16
+	if mask.Length > epcBits {
+		return nil, errors.Errorf("the mask's %d bits extend past the end of "+
+			"the %d-bit EPC, so tags don't truncate their replies", mask.Length, epcBits)
+	}
+
+	tailBits := epcBits - mask.Length
+	replyBits := truncatedLeadBits + tailBits
+	if len(reply) != (replyBits+7)/8 && len(reply) != (replyBits+15)/16*2 {
+		return nil, errors.Errorf("a truncated reply with %d EPC bits after the "+
+			"%d-bit mask has %d bits, but the reply has %d bytes",
+			tailBits, mask.Length, replyBits, len(reply))
+	}
+	if reply[0]>>(8-truncatedLeadBits) != 0 {
+		return nil, errors.Errorf("truncated replies start with %d 0 bits, but "+
+			"the reply starts with %02X", truncatedLeadBits, reply[0])
+	}
+
+	b := make([]byte, epcBits/8)
+	putBits(b, 0, mask.Mask, 0, mask.Length)
+	putBits(b, mask.Length, reply, truncatedLeadBits, tailBits)
+	return TrimEPC(b), nil
+}
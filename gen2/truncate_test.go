@@ -0,0 +1,72 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"testing"
+)
+
+func TestReconstructTruncatedEPC(t *testing.T) {
+	w := expect.WrapT(t)
+
+	full := "3034257BF7194E4000001A85"
+	// the header, filter, partition, and company prefix of the SGTIN
+	mask := SelectMask{Bank: BankEPC, Pointer: EPCStartBit, Length: 38,
+		Mask: w.ShouldHaveResult(hex.DecodeString("3034257BF4")).([]byte)}
+	reply := w.ShouldHaveResult(hex.DecodeString("06329C800000350A")).([]byte)
+
+	b := w.ShouldHaveResult(ReconstructTruncatedEPC(mask, 6, reply)).([]byte)
+	w.ShouldBeEqual(hex.EncodeToString(b), "3034257bf7194e4000001a85")
+	// the EPC length may come from the mask's header
+	b = w.ShouldHaveResult(ReconstructTruncatedEPC(mask, 0, reply)).([]byte)
+	w.ShouldBeEqual(hex.EncodeToString(b), "3034257bf7194e4000001a85")
+
+	// a mask of the whole EPC leaves only the 0s
+	all := SelectMask{Bank: BankEPC, Pointer: EPCStartBit, Length: 96,
+		Mask: w.ShouldHaveResult(hex.DecodeString(full)).([]byte)}
+	b = w.ShouldHaveResult(ReconstructTruncatedEPC(all, 0, []byte{0})).([]byte)
+	w.ShouldBeEqual(hex.EncodeToString(b), "3034257bf7194e4000001a85")
+	// which may be padded to a word
+	b = w.ShouldHaveResult(ReconstructTruncatedEPC(all, 0, []byte{0, 0})).([]byte)
+	w.ShouldBeEqual(hex.EncodeToString(b), "3034257bf7194e4000001a85")
+	w.ShouldHaveError(ReconstructTruncatedEPC(all, 0, []byte{0, 0, 0}))
+
+	// SGTIN-198s are 13 words, but are returned without their padding
+	mask198 := SelectMask{Bank: BankEPC, Pointer: EPCStartBit, Length: 38,
+		Mask: w.ShouldHaveResult(hex.DecodeString("3614257BF4")).([]byte)}
+	reply198 := w.ShouldHaveResult(hex.DecodeString(
+		"06329CC1850D88000000000000000000000000000000")).([]byte)
+	for _, words := range []int{0, 13} {
+		b = w.ShouldHaveResult(ReconstructTruncatedEPC(mask198, words, reply198)).([]byte)
+		w.ShouldBeEqual(hex.EncodeToString(b),
+			"3614257bf7194e60c286c40000000000000000000000000000")
+		e := w.ShouldHaveResult(epc.DecodeEPC(b)).(epc.EPC)
+		w.ShouldBeEqual(e.URI(), "urn:epc:id:sgtin:0614141.812345.ABC1")
+	}
+
+	w.ShouldHaveError(ReconstructTruncatedEPC(mask, 6, reply[:7]))
+	w.ShouldHaveError(ReconstructTruncatedEPC(mask, 6, append(reply, 0)))
+	w.ShouldHaveError(ReconstructTruncatedEPC(mask, 7, reply))
+	w.ShouldHaveError(ReconstructTruncatedEPC(mask, 32, reply))
+	w.ShouldHaveError(ReconstructTruncatedEPC(mask, 2, reply))
+	w.ShouldHaveError(ReconstructTruncatedEPC(mask, 6, []byte{0x86, 0x32, 0x9C, 0x80, 0, 0, 0x35, 0x0A}))
+
+	// masks must start at the EPC and be long enough for the header if no
+	// length is given
+	gtin := w.ShouldHaveResult(SelectMaskForGTIN("80614141123458", epc.FixedPrefixLength(7))).(SelectMask)
+	w.ShouldHaveError(ReconstructTruncatedEPC(gtin, 6, reply))
+	short := SelectMask{Bank: BankEPC, Pointer: EPCStartBit, Length: 4, Mask: []byte{0x30}}
+	w.ShouldHaveError(ReconstructTruncatedEPC(short, 0, reply))
+	tid := mask
+	tid.Bank = BankTID
+	w.ShouldHaveError(ReconstructTruncatedEPC(tid, 6, reply))
+	adi := SelectMask{Bank: BankEPC, Pointer: EPCStartBit, Length: 8, Mask: []byte{0x3B}}
+	w.ShouldHaveError(ReconstructTruncatedEPC(adi, 0, reply))
+}
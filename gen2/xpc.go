@@ -0,0 +1,136 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// XPC is a tag's decoded eXtended Protocol Control words. XPC_W1 is bits
+// 210h-21Fh of the EPC bank, and if its XEB bit is set, XPC_W2 follows it at
+// bits 220h-22Fh. Tags that support them send them between the PacketPC and
+// the EPC in their replies to ACK commands, if the PC's XI bit is set.
+type XPC struct {
+	// W1 and W2 are the raw XPC words. W2 is 0 unless XEB is set.
+	W1, W2 uint16
+
+	// XEB, the XPC_W2 indicator, is set if XPC_W2 follows XPC_W1.
+	XEB bool
+	// SensorAlarm (SA) is set if a sensor on the tag is in alarm.
+	SensorAlarm bool
+	// SimpleSensor (SS) is set if the tag has a simple sensor.
+	SimpleSensor bool
+	// FullSensor (FS) is set if the tag has a full-function sensor.
+	FullSensor bool
+	// SnapshotSensor (SN) is set if the tag has a snapshot sensor.
+	SnapshotSensor bool
+	// BatteryAssisted (B) is set if the tag is battery-assisted passive.
+	BatteryAssisted bool
+	// ComputedResponse (C) is set if the tag has a computed response for
+	// the reader, such as from an Authenticate command.
+	ComputedResponse bool
+	// SLIndicator (SLI) is set if the tag's SL flag was asserted when it
+	// was singulated.
+	SLIndicator bool
+	// TagNotification (TN) is set to indicate a tag-specific condition,
+	// such as having been altered.
+	TagNotification bool
+	// Untraceable (U) is set if the tag is hiding some of its memory or
+	// reducing its range, as by an Untraceable command.
+	Untraceable bool
+	// Killable (K) is set if the tag can be killed.
+	Killable bool
+	// NonRemovable (NR) is set if the tag can't be removed from its item
+	// without damaging it.
+	NonRemovable bool
+	// Hazmat (H) is set if the tagged item is hazardous material.
+	Hazmat bool
+}
+
+// XPC_W1 bits.
+const (
+	xpcXEB = 1 << (15 - iota)
+	_
+	_
+	_
+	xpcSA
+	xpcSS
+	xpcFS
+	xpcSN
+	xpcB
+	xpcC
+	xpcSLI
+	xpcTN
+	xpcU
+	xpcK
+	xpcNR
+	xpcH
+)
+
+// xpcNames are the Gen2 names of the XPC_W1 flags.
+var xpcNames = []struct {
+	bit  uint16
+	name string
+}{
+	{xpcXEB, "XEB"}, {xpcSA, "SA"}, {xpcSS, "SS"}, {xpcFS, "FS"}, {xpcSN, "SN"},
+	{xpcB, "B"}, {xpcC, "C"}, {xpcSLI, "SLI"}, {xpcTN, "TN"}, {xpcU, "U"},
+	{xpcK, "K"}, {xpcNR, "NR"}, {xpcH, "H"},
+}
+
+// DecodeXPC decodes XPC words. w2 is ignored unless w1's XEB bit is set.
+func DecodeXPC(w1, w2 uint16) XPC {
+	x := XPC{
+		W1:               w1,
+		XEB:              w1&xpcXEB != 0,
+		SensorAlarm:      w1&xpcSA != 0,
+		SimpleSensor:     w1&xpcSS != 0,
+		FullSensor:       w1&xpcFS != 0,
+		SnapshotSensor:   w1&xpcSN != 0,
+		BatteryAssisted:  w1&xpcB != 0,
+		ComputedResponse: w1&xpcC != 0,
+		SLIndicator:      w1&xpcSLI != 0,
+		TagNotification:  w1&xpcTN != 0,
+		Untraceable:      w1&xpcU != 0,
+		Killable:         w1&xpcK != 0,
+		NonRemovable:     w1&xpcNR != 0,
+		Hazmat:           w1&xpcH != 0,
+	}
+	if x.XEB {
+		x.W2 = w2
+	}
+	return x
+}
+
+// Words returns the number of XPC words a tag sends with its PacketPC: 0 if
+// XPC_W1 is 0, since its PC's XI bit isn't set, 2 if its XEB bit is set, and 1
+// otherwise.
+func (x XPC) Words() int {
+	switch {
+	case x.W1 == 0:
+		return 0
+	case x.W1&xpcXEB != 0:
+		return 2
+	}
+	return 1
+}
+
+// String formats the XPC as its hex words followed by the Gen2 names of its
+// set flags, in bit order, such as "0880 (SA,B)".
+func (x XPC) String() string {
+	var names []string
+	for _, f := range xpcNames {
+		if x.W1&f.bit != 0 {
+			names = append(names, f.name)
+		}
+	}
+	words := fmt.Sprintf("%04X", x.W1)
+	if x.W1&xpcXEB != 0 {
+		words += fmt.Sprintf(" %04X", x.W2)
+	}
+	return words + " (" + strings.Join(names, ",") + ")"
+}
@@ -0,0 +1,62 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package gen2
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestDecodeXPC(t *testing.T) {
+	w := expect.WrapT(t)
+
+	x := DecodeXPC(0x0880, 0x1234)
+	w.ShouldBeEqual(x, XPC{W1: 0x0880, SensorAlarm: true, BatteryAssisted: true})
+	w.ShouldBeEqual(x.Words(), 1)
+	w.ShouldBeEqual(x.String(), "0880 (SA,B)")
+
+	x = DecodeXPC(0x8041, 0x1234)
+	w.ShouldBeEqual(x, XPC{W1: 0x8041, W2: 0x1234, XEB: true,
+		ComputedResponse: true, Hazmat: true})
+	w.ShouldBeEqual(x.Words(), 2)
+	w.ShouldBeEqual(x.String(), "8041 1234 (XEB,C,H)")
+
+	x = DecodeXPC(0xFFFF, 0)
+	w.ShouldBeEqual(x, XPC{W1: 0xFFFF, XEB: true, SensorAlarm: true,
+		SimpleSensor: true, FullSensor: true, SnapshotSensor: true,
+		BatteryAssisted: true, ComputedResponse: true, SLIndicator: true,
+		TagNotification: true, Untraceable: true, Killable: true,
+		NonRemovable: true, Hazmat: true})
+	w.ShouldBeEqual(x.String(), "FFFF 0000 (XEB,SA,SS,FS,SN,B,C,SLI,TN,U,K,NR,H)")
+
+	x = DecodeXPC(0, 0)
+	w.ShouldBeEqual(x, XPC{})
+	w.ShouldBeEqual(x.Words(), 0)
+	w.ShouldBeEqual(x.String(), "0000 ()")
+
+	// each flag has its own bit
+	for _, tc := range []struct {
+		w1   uint16
+		flag func(XPC) bool
+	}{
+		{0x0800, func(x XPC) bool { return x.SensorAlarm }},
+		{0x0400, func(x XPC) bool { return x.SimpleSensor }},
+		{0x0200, func(x XPC) bool { return x.FullSensor }},
+		{0x0100, func(x XPC) bool { return x.SnapshotSensor }},
+		{0x0080, func(x XPC) bool { return x.BatteryAssisted }},
+		{0x0040, func(x XPC) bool { return x.ComputedResponse }},
+		{0x0020, func(x XPC) bool { return x.SLIndicator }},
+		{0x0010, func(x XPC) bool { return x.TagNotification }},
+		{0x0008, func(x XPC) bool { return x.Untraceable }},
+		{0x0004, func(x XPC) bool { return x.Killable }},
+		{0x0002, func(x XPC) bool { return x.NonRemovable }},
+		{0x0001, func(x XPC) bool { return x.Hazmat }},
+	} {
+		w.As(tc.w1).ShouldBeTrue(tc.flag(DecodeXPC(tc.w1, 0)))
+		w.As(tc.w1).ShouldBeFalse(tc.flag(DecodeXPC(^tc.w1, 0)))
+	}
+}
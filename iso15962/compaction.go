@@ -0,0 +1,117 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package iso15962
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitstream"
+	"github.com/pkg/errors"
+	"math/big"
+	"strings"
+)
+
+// Compaction is the compaction scheme given by an object's precursor, which
+// determines how its data was compacted from its original characters.
+type Compaction uint8
+
+const (
+	// ApplicationDefined data is as the application wrote it.
+	ApplicationDefined = Compaction(iota)
+	// Integer data is a decimal number without leading 0s, as a big endian
+	// unsigned integer.
+	Integer
+	// Numeric data is a string of decimal digits.
+	Numeric
+	// FiveBit data is packed 5-bit characters, from ASCII '@' to '_'.
+	FiveBit
+	// SixBit data is packed 6-bit characters, from ASCII ' ' to '_'.
+	SixBit
+	// SevenBit data is packed 7-bit ASCII characters.
+	SevenBit
+	// OctetString data is uncompacted bytes.
+	OctetString
+)
+
+func (c Compaction) String() string {
+	switch c {
+	case ApplicationDefined:
+		return "application-defined"
+	case Integer:
+		return "integer"
+	case Numeric:
+		return "numeric"
+	case FiveBit:
+		return "5-bit"
+	case SixBit:
+		return "6-bit"
+	case SevenBit:
+		return "7-bit"
+	case OctetString:
+		return "octet string"
+	}
+	return fmt.Sprintf("Compaction(%d)", uint8(c))
+}
+
+// Decompact returns the original characters of data compacted with the given
+// scheme. The packed character schemes pad their last byte with 0 bits, so it
+// ignores bits left over after the last whole character, and trailing
+// characters that are all 0 bits, which would otherwise be '@' or NUL. 6- and
+// 7-bit characters are decoded by package bitextract's SixBitASCII and
+// SevenBitASCII.
+//
+// It returns an error for Numeric data, which isn't supported, or for schemes
+// that aren't defined.
+func Decompact(c Compaction, data []byte) (string, error) {
+	switch c {
+	case ApplicationDefined, OctetString:
+		return string(data), nil
+	case Integer:
+		if len(data) == 0 {
+			return "", errors.New("integer data needs at least 1 byte")
+		}
+		return new(big.Int).SetBytes(data).String(), nil
+	case FiveBit:
+		return unpack(data, 5, func(c byte) byte { return c | 0x40 }, "@")
+	case SixBit:
+		return unpackASCII(data, 6, bitextract.SixBitASCII)
+	case SevenBit:
+		return unpackASCII(data, 7, bitextract.SevenBitASCII)
+	case Numeric:
+		return "", errors.New("numeric compaction isn't supported")
+	}
+	return "", errors.Errorf("compaction scheme %d isn't defined", uint8(c))
+}
+
+// unpackASCII returns the width-bit characters packed in data, decoded by one of
+// package bitextract's ASCII transforms, such as SixBitASCII, which expect the
+// characters in the low-order bits of their field, rather than followed by pad
+// bits.
+func unpackASCII(data []byte, width int, decode bitextract.Transform) (string, error) {
+	bits := len(data) * 8 / width * width
+	if bits == 0 {
+		return "", nil
+	}
+	chars, err := decode(bitextract.New(0, bits).Extract(data), bits)
+	return string(chars), err
+}
+
+// unpack returns the width-bit characters packed in data, mapped to ASCII by
+// toASCII, without trailing pad characters.
+func unpack(data []byte, width int, toASCII func(byte) byte, pad string) (string, error) {
+	chars := make([]byte, len(data)*8/width)
+	r := bitstream.NewReader(bytes.NewReader(data))
+	for i := range chars {
+		c, err := r.ReadBits(width)
+		if err != nil {
+			return "", err
+		}
+		chars[i] = toASCII(byte(c))
+	}
+	return strings.TrimRight(string(chars), pad), nil
+}
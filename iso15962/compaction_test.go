@@ -0,0 +1,43 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package iso15962
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestDecompact(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for _, tc := range []struct {
+		c    Compaction
+		data string
+		s    string
+	}{
+		{ApplicationDefined, "00FF", "\x00\xFF"},
+		{OctetString, "414243", "ABC"},
+		{Integer, "3039", "12345"},
+		{Integer, "00", "0"},
+		{Integer, "0123456789ABCDEF0123", "5373003642731685151011"},
+		{FiveBit, "4158C780", "HELLO"},
+		{SixBit, "2090", "HI"},
+		{SixBit, "071B42", "A1-B"},
+		{SevenBit, "830A18", "ABC"},
+		{SevenBit, "", ""},
+	} {
+		w := w.As(tc.c.String() + " " + tc.data)
+		data := w.ShouldHaveResult(hex.DecodeString(tc.data)).([]byte)
+		w.ShouldBeEqual(w.ShouldHaveResult(Decompact(tc.c, data)), tc.s)
+	}
+
+	w.ShouldHaveError(Decompact(Integer, nil))
+	w.ShouldHaveError(Decompact(Numeric, []byte{0x01}))
+	w.ShouldHaveError(Decompact(Compaction(7), []byte{0x01}))
+	w.ShouldBeEqual(Compaction(7).String(), "Compaction(7)")
+}
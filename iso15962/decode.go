@@ -0,0 +1,135 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package iso15962
+
+import (
+	"bytes"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitstream"
+	"github.com/pkg/errors"
+	"io"
+)
+
+// Data is the decoded data of a tag formatted per ISO/IEC 15962.
+type Data struct {
+	DSFID   DSFID
+	Objects []Object
+}
+
+// Object is a decoded data object.
+type Object struct {
+	// OID is the object's full OID: the root OID followed by RelativeOID.
+	OID         OID
+	RelativeOID uint64
+	Compaction  Compaction
+	// Compacted is the object's data as stored, and Value its original
+	// characters, as returned by Decompact.
+	Compacted []byte
+	Value     string
+}
+
+// Precursor bits.
+const (
+	precursorOffset     = 0x80
+	precursorCompaction = 0x70
+	precursorOID        = 0x0F
+
+	compactionShift = 4
+	// extendedOID is the relative OID that means the relative OID follows
+	// the precursor, less extendedOID, as an EBV-8.
+	extendedOID = 0x0F
+)
+
+// Decode decodes data formatted per ISO/IEC 15962, starting with its DSFID, as
+// read from the start of a tag's user memory bank. Its objects are decoded as
+// by DecodeObjects with its data format's root OID.
+//
+// It returns an error if the DSFID's access method isn't NoDirectory, if it's
+// Extended, or if its data format doesn't have a root OID, since those aren't
+// supported, or under the same conditions as DecodeObjects.
+func Decode(b []byte) (Data, error) {
+	var d Data
+	if len(b) == 0 {
+		return d, errors.New("no data provided")
+	}
+	d.DSFID = DecodeDSFID(b[0])
+	if d.DSFID.AccessMethod != NoDirectory {
+		return d, errors.Errorf("the %v access method isn't supported",
+			d.DSFID.AccessMethod)
+	}
+	if d.DSFID.Extended {
+		return d, errors.New("extended DSFIDs aren't supported")
+	}
+	root, ok := d.DSFID.RootOID()
+	if !ok {
+		return d, errors.Errorf("data format %d doesn't have a root OID, and "+
+			"isn't supported", d.DSFID.DataFormat)
+	}
+
+	objects, err := DecodeObjects(root, b[1:])
+	d.Objects = objects
+	return d, err
+}
+
+// DecodeObjects decodes the data objects that follow a DSFID with the given
+// root OID, in the no-directory access method. They're decoded in order, up to
+// a 0 byte, which terminates them, or the end of the data, so the rest of the
+// memory bank may be included.
+//
+// Each object starts with a precursor byte: its top bit is set if an offset
+// byte follows the precursor, the next 3 are its Compaction, and the low 4 its
+// relative OID, or if those are 1111b, the relative OID, less 15, follows it,
+// as an EBV-8. The object's length, in bytes, follows those, as an EBV-8,
+// followed by its data and, if it has an offset byte, that many pad bytes,
+// which may align the next object to a block boundary so it can be locked.
+//
+// It returns the objects decoded before any error, and an error if an object
+// is cut off or its data can't be decompacted.
+func DecodeObjects(root OID, b []byte) ([]Object, error) {
+	var objects []Object
+	for i := 0; i < len(b) && b[i] != 0; {
+		p := b[i]
+		o := Object{Compaction: Compaction((p & precursorCompaction) >> compactionShift)}
+
+		r := bitstream.NewReader(bytes.NewReader(b[i+1:]))
+		o.RelativeOID = uint64(p & precursorOID)
+		var offset, length uint64
+		var err error
+		if o.RelativeOID == extendedOID {
+			o.RelativeOID, err = r.ReadEBV(8)
+			o.RelativeOID += extendedOID
+		}
+		if err == nil && p&precursorOffset != 0 {
+			offset, err = r.ReadBits(8)
+		}
+		if err == nil {
+			length, err = r.ReadEBV(8)
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return objects, errors.Wrapf(err, "unable to read the precursor "+
+				"of the object at byte %d", i)
+		}
+		o.OID = root.Append(o.RelativeOID)
+
+		start := i + 1 + int(r.Offset()/8)
+		end := start + int(length)
+		if length > uint64(len(b)) || end > len(b) {
+			return objects, errors.Errorf("object %v has %d bytes, but there "+
+				"are only %d after its precursor", o.OID, length, len(b)-start)
+		}
+		o.Compacted = b[start:end]
+		if o.Value, err = Decompact(o.Compaction, o.Compacted); err != nil {
+			return objects, errors.Wrapf(err, "unable to decompact object %v",
+				o.OID)
+		}
+		objects = append(objects, o)
+		i = end + int(offset)
+	}
+	return objects, nil
+}
@@ -0,0 +1,77 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package iso15962
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	w := expect.WrapT(t)
+
+	data := w.ShouldHaveResult(hex.DecodeString("09" +
+		"11" + "02" + "3039" + // integer, OID 1
+		"5F" + "05" + "03" + "830A18" + // 7-bit, extended OID 20
+		"C2" + "01" + "02" + "2090" + "00" + // 6-bit, OID 2, 1 pad byte
+		"00" + "FFFF")).([]byte) // terminator, then unused memory
+
+	d := w.ShouldHaveResult(Decode(data)).(Data)
+	w.ShouldBeEqual(d.DSFID.DataFormat, uint8(9))
+	w.ShouldBeEqual(len(d.Objects), 3)
+
+	o := d.Objects[0]
+	w.ShouldBeEqual(o.OID.String(), "1.0.15961.9.1")
+	w.ShouldBeEqual(o.Compaction, Integer)
+	w.ShouldBeEqual(o.Compacted, []byte{0x30, 0x39})
+	w.ShouldBeEqual(o.Value, "12345")
+
+	o = d.Objects[1]
+	w.ShouldBeEqual(o.RelativeOID, uint64(20))
+	w.ShouldBeEqual(o.OID.String(), "1.0.15961.9.20")
+	w.ShouldBeEqual(o.Compaction, SevenBit)
+	w.ShouldBeEqual(o.Value, "ABC")
+
+	o = d.Objects[2]
+	w.ShouldBeEqual(o.OID.String(), "1.0.15961.9.2")
+	w.ShouldBeEqual(o.Compaction, SixBit)
+	w.ShouldBeEqual(o.Value, "HI")
+
+	// without a terminator
+	d = w.ShouldHaveResult(Decode(data[:5])).(Data)
+	w.ShouldBeEqual(len(d.Objects), 1)
+	d = w.ShouldHaveResult(Decode(data[:1])).(Data)
+	w.ShouldBeEqual(len(d.Objects), 0)
+}
+
+func TestDecode_errors(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for _, tc := range []struct {
+		name, data string
+		objects    int
+	}{
+		{"empty", "", 0},
+		{"directory", "49" + "1102303900", 0},
+		{"packed objects", "89", 0},
+		{"extended DSFID", "29" + "1102303900", 0},
+		{"full featured", "01" + "1102303900", 0},
+		{"root OID encoded", "02" + "1102303900", 0},
+		{"no length", "09" + "11", 0},
+		{"no extended OID", "09" + "1F", 0},
+		{"no offset", "09" + "91", 0},
+		{"short object", "09" + "11023039" + "11033039", 1},
+		{"numeric", "09" + "210201", 0},
+	} {
+		w := w.As(tc.name)
+		data := w.ShouldHaveResult(hex.DecodeString(tc.data)).([]byte)
+		d, err := Decode(data)
+		w.ShouldFail(err)
+		w.ShouldBeEqual(len(d.Objects), tc.objects)
+	}
+}
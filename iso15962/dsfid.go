@@ -0,0 +1,104 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package iso15962 decodes data written to RFID tags as specified by ISO/IEC
+// 15962 and 15961, as is common in logistics outside of GS1's EPC schemes:
+// a Data Storage Format Identifier (DSFID), which gives the access method and
+// data format, followed by data objects, each of which has a precursor giving
+// its compaction scheme and its object identifier (OID) relative to the data
+// format's root OID.
+//
+// It decodes the no-directory access method, with data formats that give a
+// root OID, and the integer, 5-, 6-, and 7-bit, and octet string compaction
+// schemes. Packed Objects, the access method GS1 uses for user memory, aren't
+// supported.
+package iso15962
+
+import (
+	"fmt"
+)
+
+// AccessMethod is the access method given by a DSFID, which determines how the
+// data objects that follow it are laid out.
+type AccessMethod uint8
+
+const (
+	NoDirectory = AccessMethod(iota)
+	Directory
+	PackedObjects
+	TagDataProfile
+)
+
+func (am AccessMethod) String() string {
+	switch am {
+	case NoDirectory:
+		return "no-directory"
+	case Directory:
+		return "directory"
+	case PackedObjects:
+		return "packed objects"
+	case TagDataProfile:
+		return "tag data profile"
+	}
+	return fmt.Sprintf("AccessMethod(%d)", uint8(am))
+}
+
+// Data formats with special meanings. Other data formats identify the data
+// constructs registered under ISO/IEC 15961-2, whose root OID is 1.0.15961
+// followed by the data format.
+const (
+	// NotFormatted means the data isn't formatted per ISO/IEC 15962.
+	NotFormatted = 0
+	// FullFeatured means each object has its full OID.
+	FullFeatured = 1
+	// RootOIDEncoded means the first object gives the root OID of the rest.
+	RootOIDEncoded = 2
+)
+
+// DSFID is a decoded Data Storage Format Identifier, the first byte of data
+// formatted per ISO/IEC 15962.
+type DSFID struct {
+	// AccessMethod is the top 2 bits.
+	AccessMethod AccessMethod
+	// Extended, the next bit, is set if more DSFID bytes follow.
+	Extended bool
+	// DataFormat is the low 5 bits.
+	DataFormat uint8
+}
+
+// DecodeDSFID decodes a DSFID byte.
+func DecodeDSFID(b byte) DSFID {
+	return DSFID{
+		AccessMethod: AccessMethod(b >> 6),
+		Extended:     b&0x20 != 0,
+		DataFormat:   b & 0x1F,
+	}
+}
+
+// Byte returns the DSFID's encoding.
+func (d DSFID) Byte() byte {
+	b := byte(d.AccessMethod&0x3)<<6 | d.DataFormat&0x1F
+	if d.Extended {
+		b |= 0x20
+	}
+	return b
+}
+
+// RootOID returns the root OID of the DSFID's data format, or false if the data
+// format doesn't have one, because it's NotFormatted, FullFeatured, or
+// RootOIDEncoded.
+func (d DSFID) RootOID() (OID, bool) {
+	if d.DataFormat <= RootOIDEncoded {
+		return nil, false
+	}
+	return OID{1, 0, 15961, uint64(d.DataFormat)}, true
+}
+
+// String formats the DSFID as its hex encoding followed by its fields.
+func (d DSFID) String() string {
+	return fmt.Sprintf("%02X (%v, extended=%t, data format %d)",
+		d.Byte(), d.AccessMethod, d.Extended, d.DataFormat)
+}
@@ -0,0 +1,49 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package iso15962
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestDecodeDSFID(t *testing.T) {
+	w := expect.WrapT(t)
+
+	d := DecodeDSFID(0x09)
+	w.ShouldBeEqual(d, DSFID{AccessMethod: NoDirectory, DataFormat: 9})
+	w.ShouldBeEqual(d.Byte(), byte(0x09))
+	root, ok := d.RootOID()
+	w.ShouldBeTrue(ok)
+	w.ShouldBeEqual(root.String(), "1.0.15961.9")
+	w.ShouldBeEqual(d.String(), "09 (no-directory, extended=false, data format 9)")
+
+	d = DecodeDSFID(0xA1)
+	w.ShouldBeEqual(d, DSFID{AccessMethod: PackedObjects, Extended: true,
+		DataFormat: FullFeatured})
+	w.ShouldBeEqual(d.Byte(), byte(0xA1))
+	_, ok = d.RootOID()
+	w.ShouldBeFalse(ok)
+
+	for b := 0; b < 0x100; b++ {
+		w.ShouldBeEqual(DecodeDSFID(byte(b)).Byte(), byte(b))
+	}
+	w.ShouldBeEqual(TagDataProfile.String(), "tag data profile")
+	w.ShouldBeEqual(AccessMethod(4).String(), "AccessMethod(4)")
+}
+
+func TestOID_Append(t *testing.T) {
+	w := expect.WrapT(t)
+
+	root := OID{1, 0, 15961, 9}
+	a := root[:3].Append(10)
+	b := root[:3].Append(11, 1)
+	w.ShouldBeEqual(a.String(), "1.0.15961.10")
+	w.ShouldBeEqual(b.String(), "1.0.15961.11.1")
+	w.ShouldBeEqual(root.String(), "1.0.15961.9")
+	w.ShouldBeEqual(OID{}.String(), "")
+}
@@ -0,0 +1,33 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package iso15962
+
+import (
+	"strconv"
+	"strings"
+)
+
+// OID is an object identifier: a sequence of arcs, such as 1.0.15961.9.
+type OID []uint64
+
+// Append returns a new OID of the OID's arcs followed by the given arcs, as for
+// an object's OID relative to a root OID. Unlike append, it never modifies the
+// OID's array.
+func (oid OID) Append(arcs ...uint64) OID {
+	o := make(OID, len(oid), len(oid)+len(arcs))
+	copy(o, oid)
+	return append(o, arcs...)
+}
+
+// String formats the OID as its arcs, in decimal, separated by "."s.
+func (oid OID) String() string {
+	arcs := make([]string, len(oid))
+	for i, arc := range oid {
+		arcs[i] = strconv.FormatUint(arc, 10)
+	}
+	return strings.Join(arcs, ".")
+}
@@ -0,0 +1,136 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bittag"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// Reasons a decoder may fail to decode data it accepted with CanDecode, as
+// reported to Metrics.
+const (
+	// ReasonChecksum means a bittag Decoder's checksum field didn't match.
+	ReasonChecksum = "checksum"
+	// ReasonValidation means a field didn't meet a bittag Decoder's
+	// Validators.
+	ReasonValidation = "validation"
+	// ReasonInvalid means the data couldn't be decoded for any other reason,
+	// such as an SGTIN with out of range values.
+	ReasonInvalid = "invalid"
+)
+
+// Metrics receives measurements of a DecoderChain's decoding, such as to export
+// them to a monitoring system. Decoders are identified by their config entries,
+// such as "sgtin". Its methods are called concurrently if the chain is used
+// concurrently, such as by DecodeBatch or a Stream.
+type Metrics interface {
+	// DecodeAttempt is called before a decoder decodes data it accepted
+	// with CanDecode.
+	DecodeAttempt(decoder string)
+	// DecodeFailure is called when a decoder fails to decode data, with one
+	// of the Reason constants.
+	DecodeFailure(decoder, reason string)
+	// DecodeLatency is called with the time a decoder took to decode data,
+	// whether or not it succeeded.
+	DecodeLatency(decoder string, d time.Duration)
+	// NoMatch is called when no decoder in the chain decodes data, which
+	// typically falls back to a URI such as bittag.FallbackURI.
+	NoMatch()
+	// CacheHit is called when a chain with a cache finds data's result in
+	// it, and CacheMiss when it doesn't; see DecoderChain.WithCache.
+	CacheHit()
+	CacheMiss()
+}
+
+// NopMetrics is a Metrics that ignores its measurements. It's the default for
+// DecoderChains.
+type NopMetrics struct{}
+
+func (NopMetrics) DecodeAttempt(decoder string)                  {}
+func (NopMetrics) DecodeFailure(decoder, reason string)          {}
+func (NopMetrics) DecodeLatency(decoder string, d time.Duration) {}
+func (NopMetrics) NoMatch()                                      {}
+func (NopMetrics) CacheHit()                                     {}
+func (NopMetrics) CacheMiss()                                    {}
+
+// MetricFuncs is a Metrics that calls the function for each measurement that's
+// set, which makes it easy to adapt to Prometheus collectors without this
+// module depending on them. For instance, with CounterVecs labeled by decoder
+// and reason, and a HistogramVec labeled by decoder:
+//     tagcode.MetricFuncs{
+//         Attempts: func(decoder string) {
+//             attempts.WithLabelValues(decoder).Inc()
+//         },
+//         Failures: func(decoder, reason string) {
+//             failures.WithLabelValues(decoder, reason).Inc()
+//         },
+//         Latency: func(decoder string, seconds float64) {
+//             latency.WithLabelValues(decoder).Observe(seconds)
+//         },
+//         NoMatches:   noMatches.Inc,
+//         CacheHits:   cacheHits.Inc,
+//         CacheMisses: cacheMisses.Inc,
+//     }
+//
+// Following Prometheus conventions, latencies are in seconds.
+type MetricFuncs struct {
+	Attempts    func(decoder string)
+	Failures    func(decoder, reason string)
+	Latency     func(decoder string, seconds float64)
+	NoMatches   func()
+	CacheHits   func()
+	CacheMisses func()
+}
+
+func (mf MetricFuncs) DecodeAttempt(decoder string) {
+	if mf.Attempts != nil {
+		mf.Attempts(decoder)
+	}
+}
+
+func (mf MetricFuncs) DecodeFailure(decoder, reason string) {
+	if mf.Failures != nil {
+		mf.Failures(decoder, reason)
+	}
+}
+
+func (mf MetricFuncs) DecodeLatency(decoder string, d time.Duration) {
+	if mf.Latency != nil {
+		mf.Latency(decoder, d.Seconds())
+	}
+}
+
+func (mf MetricFuncs) NoMatch() {
+	if mf.NoMatches != nil {
+		mf.NoMatches()
+	}
+}
+
+func (mf MetricFuncs) CacheHit() {
+	if mf.CacheHits != nil {
+		mf.CacheHits()
+	}
+}
+
+func (mf MetricFuncs) CacheMiss() {
+	if mf.CacheMisses != nil {
+		mf.CacheMisses()
+	}
+}
+
+// failureReason returns the Reason constant describing a decoding error.
+func failureReason(err error) string {
+	switch errors.Cause(err).(type) {
+	case *bittag.ChecksumError:
+		return ReasonChecksum
+	case *bittag.ValidationError:
+		return ReasonValidation
+	}
+	return ReasonInvalid
+}
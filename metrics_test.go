@@ -0,0 +1,99 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bittag"
+	"github.com/pkg/errors"
+	"sync"
+	"testing"
+)
+
+// countMetrics counts the measurements of MetricFuncs, by their labels.
+type countMetrics struct {
+	sync.Mutex
+	counts map[string]int
+}
+
+func (cm *countMetrics) inc(key string) {
+	cm.Lock()
+	defer cm.Unlock()
+	cm.counts[key]++
+}
+
+func (cm *countMetrics) funcs() MetricFuncs {
+	return MetricFuncs{
+		Attempts: func(decoder string) { cm.inc("attempt " + decoder) },
+		Failures: func(decoder, reason string) { cm.inc("failure " + decoder + " " + reason) },
+		Latency: func(decoder string, seconds float64) {
+			if seconds >= 0 {
+				cm.inc("latency " + decoder)
+			}
+		},
+		NoMatches:   func() { cm.inc("no match") },
+		CacheHits:   func() { cm.inc("cache hit") },
+		CacheMisses: func() { cm.inc("cache miss") },
+	}
+}
+
+func TestDecoderChain_WithMetrics(t *testing.T) {
+	w := expect.WrapT(t)
+
+	checked := w.ShouldHaveResult(bittag.NewDecoder(
+		"test.com", "2019-01-01", []int{8, 8})).(bittag.Decoder)
+	w.ShouldSucceed(checked.SetChecksum(1, bittag.CRC8))
+	validated := w.ShouldHaveResult(bittag.NewDecoder(
+		"test.com", "2019-01-01", []int{8, 8})).(bittag.Decoder)
+	w.ShouldSucceed(validated.AddValidator(0, bittag.Constant(1)))
+	plain := DecoderChain{
+		names:    []string{"sgtin", "checked", "validated"},
+		decoders: []TagDecoder{SGTINDecoder{}, BitTagDecoder{checked}, BitTagDecoder{validated}},
+	}
+
+	cm := &countMetrics{counts: map[string]int{}}
+	chain := plain.WithMetrics(cm.funcs())
+	for _, data := range []string{
+		"3034257BF7194E4000001A85", // an SGTIN
+		"301000181C7FFFD3A8B43711", // an out of range SGTIN
+		"0107",                     // checksum matches
+		"0100",                     // validated
+		"0200",                     // fails both
+		"01",                       // no decoder can decode it
+	} {
+		plainURI, _, plainErr := plain.DecodeString(data)
+		uri, _, err := chain.DecodeString(data)
+		w.As(data).ShouldBeEqual(uri, plainURI)
+		w.As(data).ShouldBeEqual(err != nil, plainErr != nil)
+	}
+
+	w.ShouldBeEqual(cm.counts, map[string]int{
+		"attempt sgtin":                2,
+		"latency sgtin":                2,
+		"failure sgtin invalid":        1,
+		"attempt checked":              3,
+		"latency checked":              3,
+		"failure checked checksum":     2,
+		"attempt validated":            2,
+		"latency validated":            2,
+		"failure validated validation": 1,
+		"no match":                     3,
+	})
+
+	// the default ignores them
+	w.ShouldBeTrue(chain.WithMetrics(NopMetrics{}).metrics == nil)
+	w.ShouldBeTrue(chain.WithMetrics(nil).metrics == nil)
+	MetricFuncs{}.DecodeAttempt("sgtin")
+}
+
+func TestFailureReason(t *testing.T) {
+	w := expect.WrapT(t)
+
+	w.ShouldBeEqual(failureReason(errors.Wrap(&bittag.ChecksumError{}, "x")), ReasonChecksum)
+	w.ShouldBeEqual(failureReason(&bittag.ValidationError{}), ReasonValidation)
+	w.ShouldBeEqual(failureReason(errors.New("x")), ReasonInvalid)
+}
@@ -0,0 +1,218 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package observation
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of transition an ExitDetector reports.
+type EventType int
+
+const (
+	// Arrived indicates a tag was observed that wasn't already present.
+	Arrived = EventType(iota)
+	// Moved indicates a present tag was observed at a different read point.
+	Moved
+	// Departed indicates a present tag hasn't been observed within its
+	// absence window, and so is no longer considered present.
+	Departed
+)
+
+func (et EventType) String() string {
+	switch et {
+	case Arrived:
+		return "Arrived"
+	case Moved:
+		return "Moved"
+	case Departed:
+		return "Departed"
+	}
+	return "Unknown event type"
+}
+
+// Event is a transition in a tag's presence.
+type Event struct {
+	Type EventType
+	URI  string
+	// ReadPoint is the read point the tag arrived at, moved to, or was last
+	// observed at before it departed.
+	ReadPoint string
+	// PrevReadPoint is the read point a tag moved from.
+	PrevReadPoint string
+	Time          time.Time
+}
+
+// ExitConfig configures an ExitDetector.
+type ExitConfig struct {
+	// AbsenceWindow is how long a present tag may go unobserved before it's
+	// considered departed. It must be positive.
+	AbsenceWindow time.Duration
+
+	// ExitReadPoints are read points at which a tag is expected to be leaving,
+	// such as those at a dock door or store exit. If a tag was last observed at
+	// one of these, it departs after ExitWindow instead of AbsenceWindow; if
+	// ExitWindow isn't positive, AbsenceWindow is used for these as well.
+	ExitReadPoints []string
+	ExitWindow     time.Duration
+}
+
+type presence struct {
+	readPoint string
+	lastSeen  time.Time
+}
+
+// ExitDetector is a state machine that tracks whether tags are present or have
+// departed, based on the read points at which they're observed and how long
+// they've gone without being observed.
+//
+// Observations are passed to Add, which reports tags arriving or moving between
+// read points, as well as departures of observed tags Tick hasn't yet reported.
+// Since departure is determined by the _absence_ of observations, callers
+// should call Tick periodically to report departures; the detector doesn't use
+// the wall clock on its own. Departed tags are forgotten, so that observing them
+// again reports them as arrived.
+//
+// Read points are compared exactly, so if a tag is visible from several read
+// points at once, callers should aggregate them (or deduplicate reads) first,
+// lest the tag appear to move back and forth between them.
+//
+// ExitDetectors are safe for concurrent use.
+type ExitDetector struct {
+	conf  ExitConfig
+	exits map[string]bool
+
+	mu   sync.Mutex
+	tags map[string]*presence
+}
+
+// NewExitDetector returns a new ExitDetector using the given configuration.
+func NewExitDetector(conf ExitConfig) *ExitDetector {
+	if conf.AbsenceWindow <= 0 {
+		panic("absence window must be positive")
+	}
+	d := &ExitDetector{
+		conf:  conf,
+		exits: make(map[string]bool, len(conf.ExitReadPoints)),
+		tags:  make(map[string]*presence),
+	}
+	for _, rp := range conf.ExitReadPoints {
+		d.exits[rp] = true
+	}
+	return d
+}
+
+// Add processes an Observation and returns the Events it causes, if any.
+//
+// If the tag's absence window has elapsed since it was last observed, but Tick
+// hasn't yet reported it as departed, the tag departs before the observation,
+// so Add returns its Departed event followed by its Arrived event.
+func (d *ExitDetector) Add(o Observation) []Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var events []Event
+	p, ok := d.tags[o.URI]
+	if ok {
+		departs := p.lastSeen.Add(d.windowFor(p.readPoint))
+		if departs.Before(o.Time) {
+			// it departed, but Tick hasn't been called since
+			events = append(events, Event{Type: Departed, URI: o.URI,
+				ReadPoint: p.readPoint, Time: departs})
+			ok = false
+		}
+	}
+	if !ok {
+		d.tags[o.URI] = &presence{readPoint: o.ReadPoint, lastSeen: o.Time}
+		return append(events, Event{Type: Arrived, URI: o.URI,
+			ReadPoint: o.ReadPoint, Time: o.Time})
+	}
+
+	if o.Time.Before(p.lastSeen) {
+		// a late observation doesn't change where the tag is now
+		return nil
+	}
+	p.lastSeen = o.Time
+	if p.readPoint == o.ReadPoint {
+		return nil
+	}
+
+	e := Event{Type: Moved, URI: o.URI, ReadPoint: o.ReadPoint,
+		PrevReadPoint: p.readPoint, Time: o.Time}
+	p.readPoint = o.ReadPoint
+	return []Event{e}
+}
+
+// Tick returns Departed events for tags that haven't been observed within
+// their absence window as of now, in order of departure time.
+//
+// A departure's Time is the moment its absence window elapsed, rather than now.
+func (d *ExitDetector) Tick(now time.Time) []Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var events []Event
+	for uri, p := range d.tags {
+		departs := p.lastSeen.Add(d.windowFor(p.readPoint))
+		if departs.After(now) {
+			continue
+		}
+		delete(d.tags, uri)
+		events = append(events, Event{Type: Departed, URI: uri,
+			ReadPoint: p.readPoint, Time: departs})
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Time.Equal(events[j].Time) {
+			return events[i].URI < events[j].URI
+		}
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events
+}
+
+// Present returns the read point at which a tag was last observed, or false if
+// the tag isn't currently present.
+func (d *ExitDetector) Present(uri string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	p, ok := d.tags[uri]
+	if !ok {
+		return "", false
+	}
+	return p.readPoint, true
+}
+
+// PresentTags returns a map of the URIs of all present tags to the read points
+// at which they were last observed.
+func (d *ExitDetector) PresentTags() map[string]string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	present := make(map[string]string, len(d.tags))
+	for uri, p := range d.tags {
+		present[uri] = p.readPoint
+	}
+	return present
+}
+
+// NumPresent returns the number of tags currently present.
+func (d *ExitDetector) NumPresent() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.tags)
+}
+
+func (d *ExitDetector) windowFor(readPoint string) time.Duration {
+	if d.exits[readPoint] && d.conf.ExitWindow > 0 {
+		return d.conf.ExitWindow
+	}
+	return d.conf.AbsenceWindow
+}
@@ -0,0 +1,90 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package observation
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+	"time"
+)
+
+func TestExitDetector(t *testing.T) {
+	w := expect.WrapT(t)
+	start := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time { return start.Add(time.Duration(sec) * time.Second) }
+
+	d := NewExitDetector(ExitConfig{
+		AbsenceWindow:  time.Minute,
+		ExitReadPoints: []string{"exit"},
+		ExitWindow:     10 * time.Second,
+	})
+
+	events := d.Add(Observation{URI: testURI, ReadPoint: "sales floor", Time: at(0)})
+	w.ShouldBeEqual(events, []Event{{Type: Arrived, URI: testURI,
+		ReadPoint: "sales floor", Time: at(0)}})
+
+	w.ShouldHaveLength(d.Add(Observation{URI: testURI, ReadPoint: "sales floor", Time: at(5)}), 0)
+
+	events = d.Add(Observation{URI: otherURI, ReadPoint: "backroom", Time: at(5)})
+	w.ShouldHaveLength(events, 1)
+	w.ShouldBeEqual(events[0].Type, Arrived)
+
+	events = d.Add(Observation{URI: testURI, ReadPoint: "exit", Time: at(30)})
+	w.ShouldBeEqual(events, []Event{{Type: Moved, URI: testURI, ReadPoint: "exit",
+		PrevReadPoint: "sales floor", Time: at(30)}})
+
+	// late observations don't move the tag back
+	w.ShouldHaveLength(d.Add(Observation{URI: testURI, ReadPoint: "sales floor", Time: at(20)}), 0)
+	rp, ok := d.Present(testURI)
+	w.ShouldBeTrue(ok)
+	w.ShouldBeEqual(rp, "exit")
+
+	w.ShouldHaveLength(d.Tick(at(39)), 0)
+	w.ShouldBeEqual(d.Tick(at(40)), []Event{{Type: Departed, URI: testURI,
+		ReadPoint: "exit", Time: at(40)}})
+	_, ok = d.Present(testURI)
+	w.ShouldBeFalse(ok)
+	w.ShouldBeEqual(d.NumPresent(), 1)
+
+	w.ShouldBeEqual(d.Tick(at(100)), []Event{{Type: Departed, URI: otherURI,
+		ReadPoint: "backroom", Time: at(65)}})
+
+	// departed tags arrive again
+	events = d.Add(Observation{URI: testURI, ReadPoint: "exit", Time: at(200)})
+	w.ShouldHaveLength(events, 1)
+	w.ShouldBeEqual(events[0].Type, Arrived)
+	w.ShouldBeEqual(events[0].Type.String(), "Arrived")
+}
+
+func TestExitDetector_Add_departedBeforeTick(t *testing.T) {
+	w := expect.WrapT(t)
+	start := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time { return start.Add(time.Duration(sec) * time.Second) }
+
+	d := NewExitDetector(ExitConfig{
+		AbsenceWindow:  time.Minute,
+		ExitReadPoints: []string{"exit"},
+		ExitWindow:     10 * time.Second,
+	})
+	d.Add(Observation{URI: testURI, ReadPoint: "exit", Time: at(0)})
+
+	// seen again after its window, but before a Tick reports its departure
+	events := d.Add(Observation{URI: testURI, ReadPoint: "sales floor", Time: at(15)})
+	w.ShouldBeEqual(events, []Event{
+		{Type: Departed, URI: testURI, ReadPoint: "exit", Time: at(10)},
+		{Type: Arrived, URI: testURI, ReadPoint: "sales floor", Time: at(15)},
+	})
+	w.ShouldHaveLength(d.Tick(at(16)), 0)
+	rp, ok := d.Present(testURI)
+	w.ShouldBeTrue(ok)
+	w.ShouldBeEqual(rp, "sales floor")
+
+	// at the end of its window, it's still present
+	w.ShouldBeEqual(d.Add(Observation{URI: testURI, ReadPoint: "exit", Time: at(75)}),
+		[]Event{{Type: Moved, URI: testURI, ReadPoint: "exit",
+			PrevReadPoint: "sales floor", Time: at(75)}})
+}
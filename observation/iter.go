@@ -0,0 +1,48 @@
+//go:build go1.23
+// +build go1.23
+
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package observation
+
+import (
+	"iter"
+)
+
+// All returns an iterator over the URIs the Tracker holds and their current
+// metrics. Metrics are computed as the iteration reaches each URI, so
+// observations added during the iteration may be reflected in later values.
+//
+// For Go versions before 1.23, use Snapshot.
+func (t *Tracker) All() iter.Seq2[string, Stats] {
+	return func(yield func(string, Stats) bool) {
+		for _, uri := range t.URIs() {
+			s, ok := t.Stats(uri)
+			if !ok {
+				continue // pruned during iteration
+			}
+			if !yield(uri, s) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the URIs of present tags and the read points at
+// which they were last observed. It iterates over a copy of the present tags,
+// so it's safe to call Add and Tick during the iteration.
+//
+// For Go versions before 1.23, use PresentTags.
+func (d *ExitDetector) All() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for uri, rp := range d.PresentTags() {
+			if !yield(uri, rp) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+//go:build go1.23
+// +build go1.23
+
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package observation
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+	"time"
+)
+
+func TestTracker_All(t *testing.T) {
+	w := expect.WrapT(t)
+	now := time.Now()
+
+	tr := NewTracker(time.Second)
+	tr.Add(Observation{URI: testURI, ReadPoint: "dock", Time: now})
+	tr.Add(Observation{URI: otherURI, ReadPoint: "dock", Time: now})
+	tr.Add(Observation{URI: otherURI, ReadPoint: "dock", Time: now})
+
+	reads := map[string]int{}
+	for uri, s := range tr.All() {
+		reads[uri] = s.Reads
+	}
+	w.ShouldBeEqual(reads, map[string]int{testURI: 1, otherURI: 2})
+	w.ShouldHaveLength(tr.Snapshot(), 2)
+
+	n := 0
+	for range tr.All() {
+		n++
+		break
+	}
+	w.ShouldBeEqual(n, 1)
+}
+
+func TestExitDetector_All(t *testing.T) {
+	w := expect.WrapT(t)
+	now := time.Now()
+
+	d := NewExitDetector(ExitConfig{AbsenceWindow: time.Second})
+	d.Add(Observation{URI: testURI, ReadPoint: "dock", Time: now})
+	d.Add(Observation{URI: otherURI, ReadPoint: "exit", Time: now})
+
+	present := map[string]string{}
+	for uri, rp := range d.All() {
+		present[uri] = rp
+	}
+	w.ShouldBeEqual(present, d.PresentTags())
+	w.ShouldBeEqual(present, map[string]string{testURI: "dock", otherURI: "exit"})
+}
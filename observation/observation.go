@@ -0,0 +1,230 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package observation computes metrics derived from a stream of tag reads, such
+// as read rates, first and last seen times, and dwell times at read points.
+//
+// Observations identify tags by their canonical URIs (see package epc), so the
+// metrics are independent of how a tag's identifier happened to be encoded.
+// Time is measured using the observations themselves, rather than the wall
+// clock, so the same stream of observations always produces the same results.
+package observation
+
+import (
+	"sync"
+	"time"
+)
+
+// Observation is a single read of a tag's identifier at a read point.
+type Observation struct {
+	// URI is the canonical URI of the tag's identifier.
+	URI string
+	// ReadPoint identifies where the tag was read, such as an SGLN URI or
+	// an antenna/reader name.
+	ReadPoint string
+	Time      time.Time
+}
+
+// Dwell describes a tag's current visit to a read point: a series of reads at
+// that read point, each within the Tracker's window of the previous one.
+type Dwell struct {
+	ReadPoint string
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Reads     int
+}
+
+// Duration returns the length of time the tag has dwelled at the read point.
+func (d Dwell) Duration() time.Duration {
+	return d.LastSeen.Sub(d.FirstSeen)
+}
+
+// Stats holds the metrics a Tracker computes for a single URI.
+type Stats struct {
+	URI       string
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// Reads is the total number of observations of the URI.
+	Reads int
+	// ReadRate is the number of reads per second within the Tracker's window.
+	ReadRate float64
+	// LastReadPoint is the read point of the most recent observation.
+	LastReadPoint string
+	// Dwells holds the tag's visit to each read point at which it's been seen.
+	Dwells map[string]Dwell
+}
+
+type tagState struct {
+	firstSeen, lastSeen time.Time
+	reads               int
+	lastReadPoint       string
+	recent              []time.Time // read times within the window, in order
+	dwells              map[string]Dwell
+}
+
+// Tracker computes read rates and dwell times from a stream of Observations.
+//
+// Observations should be added roughly in the order they occurred; the Tracker's
+// notion of "now" is the latest observation time it has seen.
+//
+// Trackers are safe for concurrent use.
+type Tracker struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	now  time.Time
+	tags map[string]*tagState
+}
+
+// NewTracker returns a Tracker that computes read rates over the given window
+// of time, and which considers a tag's visit to a read point over when it hasn't
+// been read there for longer than the window.
+//
+// The window must be positive.
+func NewTracker(window time.Duration) *Tracker {
+	if window <= 0 {
+		panic("window must be positive")
+	}
+	return &Tracker{
+		window: window,
+		tags:   make(map[string]*tagState),
+	}
+}
+
+// Window returns the Tracker's window duration.
+func (t *Tracker) Window() time.Duration {
+	return t.window
+}
+
+// Add updates the Tracker's metrics with the given Observation.
+func (t *Tracker) Add(o Observation) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if o.Time.After(t.now) {
+		t.now = o.Time
+	}
+
+	ts, ok := t.tags[o.URI]
+	if !ok {
+		ts = &tagState{firstSeen: o.Time, dwells: make(map[string]Dwell)}
+		t.tags[o.URI] = ts
+	}
+	if o.Time.Before(ts.firstSeen) {
+		ts.firstSeen = o.Time
+	}
+	if !o.Time.Before(ts.lastSeen) {
+		ts.lastSeen = o.Time
+		ts.lastReadPoint = o.ReadPoint
+	}
+	ts.reads++
+	ts.recent = append(ts.recent, o.Time)
+	ts.trimRecent(t.now.Add(-t.window))
+
+	d, ok := ts.dwells[o.ReadPoint]
+	if !ok || o.Time.Sub(d.LastSeen) > t.window {
+		// a new visit to the read point
+		d = Dwell{ReadPoint: o.ReadPoint, FirstSeen: o.Time}
+	}
+	if o.Time.After(d.LastSeen) {
+		d.LastSeen = o.Time
+	}
+	d.Reads++
+	ts.dwells[o.ReadPoint] = d
+}
+
+// AddAll adds Observations from the channel until it's closed.
+func (t *Tracker) AddAll(observations <-chan Observation) {
+	for o := range observations {
+		t.Add(o)
+	}
+}
+
+// Stats returns the current metrics for the given URI, or false if the Tracker
+// hasn't observed it (or has since pruned it).
+func (t *Tracker) Stats(uri string) (Stats, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ts, ok := t.tags[uri]
+	if !ok {
+		return Stats{}, false
+	}
+	return t.stats(uri, ts), true
+}
+
+// Snapshot returns the current metrics for every URI the Tracker holds.
+//
+// With Go 1.23 or later, All iterates over the same metrics without collecting
+// them into a slice.
+func (t *Tracker) Snapshot() []Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make([]Stats, 0, len(t.tags))
+	for uri, ts := range t.tags {
+		stats = append(stats, t.stats(uri, ts))
+	}
+	return stats
+}
+
+// stats returns the metrics for a URI; the caller must hold the lock.
+func (t *Tracker) stats(uri string, ts *tagState) Stats {
+	ts.trimRecent(t.now.Add(-t.window))
+
+	s := Stats{
+		URI:           uri,
+		FirstSeen:     ts.firstSeen,
+		LastSeen:      ts.lastSeen,
+		Reads:         ts.reads,
+		ReadRate:      float64(len(ts.recent)) / t.window.Seconds(),
+		LastReadPoint: ts.lastReadPoint,
+		Dwells:        make(map[string]Dwell, len(ts.dwells)),
+	}
+	for rp, d := range ts.dwells {
+		s.Dwells[rp] = d
+	}
+	return s
+}
+
+// URIs returns the URIs the Tracker currently holds metrics for.
+func (t *Tracker) URIs() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	uris := make([]string, 0, len(t.tags))
+	for uri := range t.tags {
+		uris = append(uris, uri)
+	}
+	return uris
+}
+
+// Prune discards the metrics of URIs that haven't been observed since before
+// the given time, and returns the number discarded. Since the Tracker otherwise
+// keeps metrics for every URI it sees, long-running streams should call this
+// periodically.
+func (t *Tracker) Prune(before time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := 0
+	for uri, ts := range t.tags {
+		if ts.lastSeen.Before(before) {
+			delete(t.tags, uri)
+			n++
+		}
+	}
+	return n
+}
+
+// trimRecent removes read times older than the cutoff.
+func (ts *tagState) trimRecent(cutoff time.Time) {
+	i := 0
+	for i < len(ts.recent) && !ts.recent[i].After(cutoff) {
+		i++
+	}
+	ts.recent = append(ts.recent[:0], ts.recent[i:]...)
+}
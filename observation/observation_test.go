@@ -0,0 +1,85 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package observation
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+	"time"
+)
+
+const (
+	testURI  = "urn:epc:id:sgtin:0614141.000734.314159"
+	otherURI = "urn:epc:id:sgtin:0614141.000734.1"
+)
+
+func TestTracker(t *testing.T) {
+	w := expect.WrapT(t)
+	start := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time { return start.Add(time.Duration(sec) * time.Second) }
+
+	tr := NewTracker(10 * time.Second)
+	for sec := 0; sec < 20; sec += 2 {
+		tr.Add(Observation{URI: testURI, ReadPoint: "dock", Time: at(sec)})
+	}
+	tr.Add(Observation{URI: testURI, ReadPoint: "exit", Time: at(20)})
+
+	s, ok := tr.Stats(testURI)
+	w.StopOnMismatch().ShouldBeTrue(ok)
+	w.ShouldBeEqual(s.Reads, 11)
+	w.ShouldBeEqual(s.FirstSeen, at(0))
+	w.ShouldBeEqual(s.LastSeen, at(20))
+	w.ShouldBeEqual(s.LastReadPoint, "exit")
+	// reads at 12, 14, 16, 18, and 20 are within the 10s window ending at 20
+	w.ShouldBeEqual(s.ReadRate, 0.5)
+
+	w.ShouldHaveLength(s.Dwells, 2)
+	w.ShouldBeEqual(s.Dwells["dock"].Duration(), 18*time.Second)
+	w.ShouldBeEqual(s.Dwells["dock"].Reads, 10)
+	w.ShouldBeEqual(s.Dwells["exit"].Duration(), time.Duration(0))
+
+	// returning to the dock after more than the window starts a new visit
+	tr.Add(Observation{URI: testURI, ReadPoint: "dock", Time: at(40)})
+	tr.Add(Observation{URI: testURI, ReadPoint: "dock", Time: at(45)})
+	s, _ = tr.Stats(testURI)
+	w.ShouldBeEqual(s.Dwells["dock"].FirstSeen, at(40))
+	w.ShouldBeEqual(s.Dwells["dock"].Duration(), 5*time.Second)
+	w.ShouldBeEqual(s.ReadRate, 0.2)
+
+	// rates are relative to the latest observation of any tag
+	tr.Add(Observation{URI: otherURI, ReadPoint: "dock", Time: at(100)})
+	s, _ = tr.Stats(testURI)
+	w.ShouldBeEqual(s.ReadRate, 0.0)
+	w.ShouldBeEqual(s.Reads, 13)
+
+	_, ok = tr.Stats("urn:epc:id:sgtin:0614141.000734.2")
+	w.ShouldBeFalse(ok)
+
+	w.ShouldHaveLength(tr.URIs(), 2)
+	w.ShouldBeEqual(tr.Prune(at(50)), 1)
+	_, ok = tr.Stats(testURI)
+	w.ShouldBeFalse(ok)
+	w.ShouldBeEqual(tr.URIs(), []string{otherURI})
+}
+
+func TestTracker_AddAll(t *testing.T) {
+	w := expect.WrapT(t)
+	tr := NewTracker(time.Second)
+	now := time.Now()
+
+	c := make(chan Observation, 3)
+	c <- Observation{URI: testURI, Time: now}
+	c <- Observation{URI: testURI, Time: now.Add(time.Millisecond)}
+	c <- Observation{URI: otherURI, Time: now}
+	close(c)
+	tr.AddAll(c)
+
+	s, ok := tr.Stats(testURI)
+	w.StopOnMismatch().ShouldBeTrue(ok)
+	w.ShouldBeEqual(s.Reads, 2)
+	w.ShouldBeEqual(s.ReadRate, 2.0)
+}
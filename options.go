@@ -0,0 +1,140 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bittag"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// FallbackName is the decoder name non-strict DecoderChains return for data
+// none of their decoders accept; see WithStrict. It can't be registered.
+const FallbackName = "fallback"
+
+// Option sets one of the settings of a DecoderChain, which also apply to the
+// DecodeBatch calls and Streams that use it. Options are given to
+// NewDecoderChain, or set as package-level defaults with Configure.
+type Option func(*options)
+
+// options are the settings Options set. Their zero value is the default.
+type options struct {
+	lenient         bool
+	authority, date string
+	cacheSize       int
+	workers         int
+}
+
+var (
+	defaultsMu sync.RWMutex
+	defaults   options
+)
+
+// Configure sets the package-level defaults of the settings of DecoderChains
+// made afterwards by NewDecoderChain, which may override them with their own
+// Options, so that a service's operational tuning lives in one place:
+//     err := tagcode.Configure(
+//         tagcode.WithStrict(false),
+//         tagcode.WithFallbackEntity("example.com", "2019-01-01"),
+//         tagcode.WithCacheSize(10000),
+//         tagcode.WithWorkers(4),
+//     )
+//
+// The Options apply in order on top of the current defaults. If the resulting
+// settings are invalid, it returns an error without changing the defaults. It's
+// typically called once, at startup; existing chains keep their settings.
+func Configure(opts ...Option) error {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	o := defaults
+	o.apply(opts)
+	if err := o.check(); err != nil {
+		return err
+	}
+	defaults = o
+	return nil
+}
+
+// WithStrict sets whether a DecoderChain is strict, as it is by default. Strict
+// chains return an error for data none of their decoders accept. Other chains
+// instead return its bittag.FallbackURI, with the tagging entity set by
+// WithFallbackEntity, and FallbackName as the decoder's name, so every read
+// gets a URI, although they still report a NoMatch to their Metrics.
+func WithStrict(strict bool) Option {
+	return func(o *options) {
+		o.lenient = !strict
+	}
+}
+
+// WithFallbackEntity sets the tagging entity of the fallback URIs of non-strict
+// DecoderChains. The authority and date have the restrictions described by
+// bittag's Decoder.SetTaggingEntity.
+func WithFallbackEntity(authority, date string) Option {
+	return func(o *options) {
+		o.authority, o.date = authority, date
+	}
+}
+
+// WithCacheSize sets the number of results a DecoderChain caches, as set by its
+// WithCache method. By default, or if it's 0, chains don't cache results.
+func WithCacheSize(size int) Option {
+	return func(o *options) {
+		o.cacheSize = size
+	}
+}
+
+// WithWorkers sets the number of goroutines DecodeBatch uses to decode a batch
+// with a DecoderChain, and NewStream uses by default for a Stream. By default,
+// or if it's 0, they use GOMAXPROCS.
+func WithWorkers(n int) Option {
+	return func(o *options) {
+		o.workers = n
+	}
+}
+
+// defaultOptions returns the package-level defaults set by Configure.
+func defaultOptions() options {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	return defaults
+}
+
+// apply applies the Options to o, in order.
+func (o *options) apply(opts []Option) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// check returns an error if the settings are invalid.
+func (o options) check() error {
+	if o.authority != "" || o.date != "" {
+		var btd bittag.Decoder
+		if err := btd.SetTaggingEntity(o.authority, o.date); err != nil {
+			return errors.Wrap(err, "invalid fallback tagging entity")
+		}
+	} else if o.lenient {
+		return errors.New("non-strict decoder chains need a fallback tagging entity")
+	}
+	if o.cacheSize < 0 {
+		return errors.Errorf("the cache size can't be negative, but is %d", o.cacheSize)
+	}
+	if o.workers < 0 {
+		return errors.Errorf("the number of workers can't be negative, but is %d",
+			o.workers)
+	}
+	return nil
+}
+
+// fallbackEntity returns the tagging entity of fallback URIs, or "" if chains
+// are strict.
+func (o options) fallbackEntity() string {
+	if !o.lenient {
+		return ""
+	}
+	return o.authority + "," + o.date
+}
@@ -0,0 +1,90 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bittag"
+	"testing"
+)
+
+func TestConfigure(t *testing.T) {
+	w := expect.WrapT(t)
+	defer func() { defaults = options{} }()
+
+	w.ShouldSucceed(Configure(
+		WithStrict(false),
+		WithFallbackEntity("example.com", "2019-01-01"),
+		WithCacheSize(10),
+		WithWorkers(3),
+	))
+	chain := w.ShouldHaveResult(NewDecoderChain([]string{"sgtin"})).(DecoderChain)
+	w.ShouldBeEqual(chain.fallback, "example.com,2019-01-01")
+	w.ShouldBeEqual(chain.workers, 3)
+	w.ShouldBeTrue(chain.cache != nil)
+
+	data, _ := hex.DecodeString("0F00000000000C00000014D2")
+	uri, name, err := chain.Decode(data)
+	w.ShouldSucceed(err)
+	w.ShouldBeEqual(uri, bittag.FallbackURI("example.com,2019-01-01", data))
+	w.ShouldBeEqual(name, FallbackName)
+
+	// chains' Options override the defaults
+	chain = w.ShouldHaveResult(NewDecoderChain([]string{"sgtin"},
+		WithStrict(true), WithCacheSize(0), WithWorkers(0))).(DecoderChain)
+	w.ShouldBeEqual(chain.fallback, "")
+	w.ShouldBeEqual(chain.workers, 0)
+	w.ShouldBeTrue(chain.cache == nil)
+	_, _, err = chain.Decode(data)
+	w.ShouldFail(err)
+
+	// invalid settings don't change the defaults
+	for _, opts := range [][]Option{
+		{WithFallbackEntity("", "")},
+		{WithFallbackEntity("Example.com", "2019-01-01")},
+		{WithFallbackEntity("example.com", "01-01-2019")},
+		{WithCacheSize(-1)},
+		{WithWorkers(-1)},
+	} {
+		w.ShouldFail(Configure(opts...))
+		w.ShouldFail(NewDecoderChain([]string{"sgtin"}, opts...))
+	}
+	w.ShouldBeEqual(defaultOptions(), options{
+		lenient:   true,
+		authority: "example.com",
+		date:      "2019-01-01",
+		cacheSize: 10,
+		workers:   3,
+	})
+}
+
+func TestWithStrict(t *testing.T) {
+	w := expect.WrapT(t)
+
+	w.ShouldFail(NewDecoderChain([]string{"sgtin"}, WithStrict(false)))
+
+	m := &countMetrics{counts: map[string]int{}}
+	chain := w.ShouldHaveResult(NewDecoderChain([]string{"sgtin"},
+		WithStrict(false), WithFallbackEntity("user@example.com", "2019"),
+		WithCacheSize(10))).(DecoderChain).WithMetrics(m.funcs())
+	for i := 0; i < 2; i++ {
+		uri, name, err := chain.DecodeString("0F00")
+		w.ShouldSucceed(err)
+		w.ShouldBeEqual(uri, "tag:user@example.com,2019:sha256:"+
+			"46e04d129d7b45d054469ce34e24069a1426b3e34f1b68a3d1bff1e070aee192")
+		w.ShouldBeEqual(name, FallbackName)
+	}
+	// the cached fallback is still reported as a NoMatch
+	w.ShouldBeEqual(m.counts["no match"], 2)
+	w.ShouldBeEqual(m.counts["cache hit"], 1)
+
+	uri, name, err := chain.DecodeString("3034257BF7194E4000001A85")
+	w.ShouldSucceed(err)
+	w.ShouldBeEqual(uri, "urn:epc:id:sgtin:0614141.812345.6789")
+	w.ShouldBeEqual(name, "sgtin")
+}
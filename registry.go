@@ -0,0 +1,169 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registration describes a TagDecoder to register with RegisterDecoders.
+type Registration struct {
+	// Name is the decoder's name within its namespace.
+	Name    string
+	Decoder TagDecoder
+	// Priority orders decoders in Registered and namespace wildcards in
+	// DecoderChain configs: decoders with higher priorities come first.
+	Priority int
+}
+
+// registered is a TagDecoder in the registry.
+type registered struct {
+	decoder  TagDecoder
+	priority int
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]registered{
+		"sgtin": {decoder: SGTINDecoder{}},
+		"epc":   {decoder: EPCDecoder{}},
+	}
+)
+
+// Register makes a TagDecoder available by name, so that services can select
+// it in their configuration. The "sgtin" and "epc" names are registered for an
+// SGTINDecoder and an EPCDecoder. It's the same as RegisterWithPriority with a
+// priority of 0.
+//
+// The decoder may be called concurrently. Register panics if the name is empty
+// or contains any of ":*," or whitespace, which DecoderChain configs use, if the
+// decoder is nil, or if the name is FallbackName or already registered. It's
+// typically called from an init function.
+func Register(name string, decoder TagDecoder) {
+	RegisterWithPriority(name, decoder, 0)
+}
+
+// RegisterWithPriority registers a TagDecoder as Register does, with the given
+// priority; see Registration.
+func RegisterWithPriority(name string, decoder TagDecoder, priority int) {
+	if decoder == nil {
+		panic("tagcode: Register decoder is nil")
+	}
+	if err := checkName(name); err != nil {
+		panic(fmt.Sprintf("tagcode: Register %v", err))
+	}
+	if name == FallbackName {
+		panic(fmt.Sprintf("tagcode: Register name %q is reserved", name))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[name]; dup {
+		panic(fmt.Sprintf("tagcode: Register called twice for %q", name))
+	}
+	registry[name] = registered{decoder: decoder, priority: priority}
+}
+
+// RegisterDecoders registers customer-specific decoders under a namespace, such
+// as a company name, so that their names, which are "namespace/name", don't
+// collide with this module's or other namespaces' decoders. Rather than
+// panicking, it returns an error, without registering any of them, if the
+// namespace or any of the names are invalid or already registered, or any of
+// the decoders are nil. It returns the decoders' full names.
+//
+// It's typically called from an init function, or by package decoderplugin to
+// register the decoders of a plugin loaded at deployment time. DecoderChain
+// configs may then select the decoders by their full names, or all of the
+// namespace's decoders, in order of priority, with "namespace/*".
+func RegisterDecoders(namespace string, regs []Registration) ([]string, error) {
+	if namespace == "" || strings.ContainsAny(namespace, namespaceInvalid) {
+		return nil, errors.Errorf("invalid namespace %q: it must be non-empty "+
+			"and not contain any of %q", namespace, namespaceInvalid)
+	}
+
+	names := make([]string, len(regs))
+	for i, reg := range regs {
+		names[i] = namespace + "/" + reg.Name
+		if reg.Decoder == nil {
+			return nil, errors.Errorf("decoder %q is nil", names[i])
+		}
+		if err := checkName(reg.Name); err != nil || strings.Contains(reg.Name, "/") {
+			return nil, errors.Errorf("invalid decoder name %q in namespace %q",
+				reg.Name, namespace)
+		}
+		for _, prev := range names[:i] {
+			if prev == names[i] {
+				return nil, errors.Errorf("decoder %q is given twice", names[i])
+			}
+		}
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, name := range names {
+		if _, dup := registry[name]; dup {
+			return nil, errors.Errorf("decoder %q is already registered", name)
+		}
+	}
+	for i, reg := range regs {
+		registry[names[i]] = registered{decoder: reg.Decoder, priority: reg.Priority}
+	}
+	return names, nil
+}
+
+// namespaceInvalid are the characters namespaces can't contain. Names can't
+// contain them either, except for the '/' separating their namespace.
+const namespaceInvalid = "/:*, \t\n"
+
+// checkName returns an error if a decoder name is invalid.
+func checkName(name string) error {
+	if name == "" || strings.ContainsAny(name, strings.Trim(namespaceInvalid, "/")) {
+		return errors.Errorf("name %q is invalid: it must be non-empty and "+
+			"not contain any of %q", name, namespaceInvalid[1:])
+	}
+	return nil
+}
+
+// Lookup returns the TagDecoder registered with the given name, or false if
+// there isn't one.
+func Lookup(name string) (TagDecoder, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	r, ok := registry[name]
+	return r.decoder, ok
+}
+
+// Registered returns the names of the registered TagDecoders, from highest to
+// lowest priority, and in sorted order among those with the same priority.
+func Registered() []string {
+	return registeredWithPrefix("")
+}
+
+// registeredWithPrefix returns the names of the registered TagDecoders with the
+// given prefix, ordered as by Registered.
+func registeredWithPrefix(prefix string) []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		pi, pj := registry[names[i]].priority, registry[names[j]].priority
+		if pi != pj {
+			return pi > pj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
@@ -0,0 +1,125 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testDecoder is a TagDecoder for testing the registry.
+type testDecoder struct{}
+
+func (testDecoder) Decode(data []byte) (string, error) {
+	return "urn:example:test", nil
+}
+
+func (testDecoder) CanDecode(data []byte) bool {
+	return true
+}
+
+// registerTestDecoder registers testDecoder once, even if tests are repeated.
+var registerTestDecoder sync.Once
+
+func TestRegister(t *testing.T) {
+	w := expect.WrapT(t)
+
+	d, ok := Lookup("sgtin")
+	w.ShouldBeTrue(ok)
+	w.ShouldBeEqual(d, SGTINDecoder{})
+
+	registerTestDecoder.Do(func() {
+		_, ok := Lookup("test")
+		w.ShouldBeFalse(ok)
+		Register("test", testDecoder{})
+	})
+	d, ok = Lookup("test")
+	w.ShouldBeTrue(ok)
+	w.ShouldBeEqual(w.ShouldHaveResult(d.Decode(nil)), "urn:example:test")
+	var unnamespaced []string
+	for _, name := range Registered() {
+		if !strings.Contains(name, "/") {
+			unnamespaced = append(unnamespaced, name)
+		}
+	}
+	w.ShouldBeEqual(unnamespaced, []string{"epc", "sgtin", "test"})
+
+	panics := func(f func()) (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		f()
+		return
+	}
+	w.ShouldBeTrue(panics(func() { Register("test", testDecoder{}) }))
+	w.ShouldBeTrue(panics(func() { Register("sgtin", testDecoder{}) }))
+	w.ShouldBeTrue(panics(func() { Register("", testDecoder{}) }))
+	w.ShouldBeTrue(panics(func() { Register("nil", nil) }))
+	w.ShouldBeTrue(panics(func() { Register("bittag:x", testDecoder{}) }))
+	w.ShouldBeTrue(panics(func() { Register(FallbackName, testDecoder{}) }))
+	_, ok = Lookup("nil")
+	w.ShouldBeFalse(ok)
+}
+
+// registerNamespaced registers the namespaced test decoders once, even if tests
+// are repeated.
+var registerNamespaced sync.Once
+
+func TestRegisterDecoders(t *testing.T) {
+	w := expect.WrapT(t)
+
+	registerNamespaced.Do(func() {
+		names := w.ShouldHaveResult(RegisterDecoders("acme", []Registration{
+			{Name: "low", Decoder: testDecoder{}, Priority: -1},
+			{Name: "high", Decoder: testDecoder{}, Priority: 1},
+			{Name: "mid", Decoder: testDecoder{}},
+		})).([]string)
+		w.ShouldBeEqual(names, []string{"acme/low", "acme/high", "acme/mid"})
+		RegisterWithPriority("acme/top", testDecoder{}, 2)
+	})
+	w.ShouldBeEqual(registeredWithPrefix("acme"),
+		[]string{"acme/top", "acme/high", "acme/mid", "acme/low"})
+	w.ShouldBeEqual(Registered()[0], "acme/top")
+	d, ok := Lookup("acme/mid")
+	w.ShouldBeTrue(ok)
+	w.ShouldBeEqual(d, testDecoder{})
+
+	chain := w.ShouldHaveResult(NewDecoderChain([]string{"sgtin", "acme/*", "epc"})).(DecoderChain)
+	w.ShouldBeEqual(chain.Names(), []string{"sgtin", "acme/top", "acme/high", "acme/mid", "acme/low", "epc"})
+	w.ShouldFail(NewDecoderChain([]string{"none/*"}))
+
+	for _, tc := range []struct {
+		namespace string
+		regs      []Registration
+	}{
+		{"", []Registration{{Name: "x", Decoder: testDecoder{}}}},
+		{"a/b", []Registration{{Name: "x", Decoder: testDecoder{}}}},
+		{"a:b", []Registration{{Name: "x", Decoder: testDecoder{}}}},
+		{"new", []Registration{{Name: "", Decoder: testDecoder{}}}},
+		{"new", []Registration{{Name: "x/y", Decoder: testDecoder{}}}},
+		{"new", []Registration{{Name: "*", Decoder: testDecoder{}}}},
+		{"new", []Registration{{Name: "x"}}},
+		{"new", []Registration{
+			{Name: "x", Decoder: testDecoder{}},
+			{Name: "x", Decoder: testDecoder{}},
+		}},
+		// nothing is registered if any fail
+		{"new", []Registration{
+			{Name: "x", Decoder: testDecoder{}},
+			{Name: "y"},
+		}},
+		{"acme", []Registration{
+			{Name: "new", Decoder: testDecoder{}},
+			{Name: "mid", Decoder: testDecoder{}},
+		}},
+	} {
+		w.As(tc).ShouldHaveError(RegisterDecoders(tc.namespace, tc.regs))
+	}
+	w.ShouldBeEqual(registeredWithPrefix("new/"), []string{})
+	w.ShouldBeEqual(registeredWithPrefix("acme/"),
+		[]string{"acme/top", "acme/high", "acme/mid", "acme/low"})
+}
@@ -0,0 +1,127 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"github.com/pkg/errors"
+	"runtime"
+	"sync"
+)
+
+// ErrStreamClosed is returned when sending tag data to a closed Stream.
+var ErrStreamClosed = errors.New("the stream is closed")
+
+// Stream decodes tag reads with a DecoderChain as they arrive, using a pool of
+// workers, and emits their Results on a channel. Reads are sent to it with Send,
+// which may be used as a callback, or from a channel with Consume.
+//
+// Its channels are bounded, so it applies backpressure: if Results aren't
+// received, the workers block, and once the Stream's buffer fills, so do Send
+// and Consume. Close shuts it down gracefully: the reads already sent are still
+// decoded, and then the Results channel is closed. A typical consumer is:
+//     s := tagcode.NewStream(chain, 0, 1000)
+//     go func() {
+//         defer s.Close()
+//         s.Consume(reads)
+//     }()
+//     for r := range s.Results() {
+//         ...
+//     }
+type Stream struct {
+	chain   DecoderChain
+	reads   chan []byte
+	results chan Result
+
+	mu     sync.RWMutex // held for writing to close reads
+	closed bool
+}
+
+// NewStream returns a new Stream that decodes reads with the chain using the
+// given number of workers, or if it's not positive, the chain's number, set by
+// WithWorkers, or GOMAXPROCS, and buffers up to the given number of reads and
+// Results. With more than one worker, Results may be emitted in a different
+// order than their reads were sent.
+//
+// The chain's decoders must be safe for concurrent use, as those in this module
+// are. The Stream's workers run until it's closed and its Results are received.
+func NewStream(chain DecoderChain, workers, buffer int) *Stream {
+	if workers <= 0 {
+		workers = chain.workers
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if buffer < 0 {
+		buffer = 0
+	}
+	s := &Stream{
+		chain:   chain,
+		reads:   make(chan []byte, buffer),
+		results: make(chan Result, buffer),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for data := range s.reads {
+				s.results <- s.chain.DecodeResult(data)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(s.results)
+	}()
+	return s
+}
+
+// Send sends tag data to the Stream to be decoded, blocking while its buffer is
+// full. It returns ErrStreamClosed if the Stream is closed. The data must not be
+// modified until its Result is received.
+func (s *Stream) Send(data []byte) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrStreamClosed
+	}
+	s.reads <- data
+	return nil
+}
+
+// Consume sends the tag data received from reads to the Stream until reads is
+// closed, and returns nil, or until the Stream is closed, and returns
+// ErrStreamClosed. It doesn't close the Stream.
+func (s *Stream) Consume(reads <-chan []byte) error {
+	for data := range reads {
+		if err := s.Send(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Results returns the channel on which the Stream emits the Results of the
+// reads sent to it. It's closed once the Stream is closed and the Results of
+// all the reads sent before then have been received.
+func (s *Stream) Results() <-chan Result {
+	return s.results
+}
+
+// Close stops the Stream from accepting reads, so that its Results channel is
+// closed once they're all decoded. It waits for any blocked calls to Send to
+// finish, so the Results must still be received. It's safe to call more than
+// once.
+func (s *Stream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.reads)
+	}
+}
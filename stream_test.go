@@ -0,0 +1,81 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+	"time"
+)
+
+func TestStream(t *testing.T) {
+	w := expect.WrapT(t)
+
+	chain := w.ShouldHaveResult(NewDecoderChain([]string{
+		"sgtin", "bittag:test.com,2019-01-01:8.48.40"})).(DecoderChain)
+	data := batchData(w, 1000)
+	expected := map[string]Result{}
+	for _, r := range DecodeBatch(chain, data) {
+		expected[string(r.Data)] = r
+	}
+
+	reads := make(chan []byte)
+	s := NewStream(chain, 4, 10)
+	go func() {
+		defer s.Close()
+		w.ShouldSucceed(s.Consume(reads))
+	}()
+	go func() {
+		defer close(reads)
+		for _, d := range data {
+			reads <- d
+		}
+	}()
+
+	n := 0
+	for r := range s.Results() {
+		n++
+		e := expected[string(r.Data)]
+		w.As(r.Data).ShouldBeEqual(r.URI, e.URI)
+		w.As(r.Data).ShouldBeEqual(r.Decoder, e.Decoder)
+		w.As(r.Data).ShouldBeEqual(r.Err != nil, e.Err != nil)
+	}
+	w.ShouldBeEqual(n, len(data))
+
+	w.ShouldBeEqual(s.Send(data[0]), ErrStreamClosed)
+	s.Close()
+}
+
+func TestStream_backpressure(t *testing.T) {
+	w := expect.WrapT(t)
+
+	chain := w.ShouldHaveResult(NewDecoderChain([]string{"sgtin"})).(DecoderChain)
+	s := NewStream(chain, 1, 1)
+	data := batchData(w, 4)
+
+	// the worker holds one read and one Result, and the buffers hold one each
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		for _, d := range data {
+			w.ShouldSucceed(s.Send(d))
+		}
+		s.Close()
+	}()
+	select {
+	case <-sent:
+		t.Fatal("Send didn't block while the Stream's buffers were full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	n := 0
+	for range s.Results() {
+		n++
+	}
+	<-sent
+	w.ShouldBeEqual(n, len(data))
+}
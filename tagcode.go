@@ -0,0 +1,136 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package tagcode turns the data read from RFID tags into canonical URIs, using
+// the encoding schemes of its subpackages: EPCs, such as SGTINs, from package
+// epc, and proprietary bit layouts from package bittag. TagDecoder abstracts
+// over them, so services can be configured with the decoders their tags need
+// by name, using the registry.
+package tagcode
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bittag"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"github.com/pkg/errors"
+)
+
+// TagDecoder turns tag data into a canonical URI.
+type TagDecoder interface {
+	// Decode returns the canonical URI of the tag data, or an error if it
+	// can't be decoded.
+	Decode(data []byte) (uri string, err error)
+	// CanDecode returns true if the data looks like it's in the decoder's
+	// format, such as by having the right header and length, without fully
+	// decoding it, so Decode may still return an error. If it returns false,
+	// Decode would too.
+	CanDecode(data []byte) bool
+}
+
+// ValueDecoder is a TagDecoder that can also return the structured value it
+// decodes, such as an epc.SGTIN, which DecoderChain's DecodeResult method
+// includes in its Result. The decoders in this package are ValueDecoders.
+type ValueDecoder interface {
+	TagDecoder
+	// DecodeValue returns the value decoded from the tag data along with
+	// its canonical URI, which Decode returns.
+	DecodeValue(data []byte) (value interface{}, uri string, err error)
+}
+
+// SGTINDecoder decodes SGTIN-96 and SGTIN-198 EPCs to their Pure Identity URIs.
+// SGTINs with values outside the ranges of the EPC Tag Data Standard are
+// rejected, as by SGTIN.ValidateRanges.
+type SGTINDecoder struct{}
+
+// Decode returns the Pure Identity URI of the SGTIN data.
+func (sd SGTINDecoder) Decode(data []byte) (string, error) {
+	_, uri, err := sd.DecodeValue(data)
+	return uri, err
+}
+
+// DecodeValue returns the epc.SGTIN of the data and its Pure Identity URI.
+func (SGTINDecoder) DecodeValue(data []byte) (interface{}, string, error) {
+	s, err := epc.DecodeSGTIN(data)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.ValidateRanges(); err != nil {
+		return nil, "", errors.Wrap(err, "invalid SGTIN")
+	}
+	return s, s.URI(), nil
+}
+
+// CanDecode returns true if data has an SGTIN header and the right length for
+// it.
+func (SGTINDecoder) CanDecode(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	switch data[0] {
+	case epc.SGTIN96Header:
+		return len(data) == epc.SGTIN96NumBytes
+	case epc.SGTIN198Header:
+		return len(data) == epc.SGTIN198NumBytes
+	}
+	return false
+}
+
+// EPCDecoder decodes EPCs of any scheme epc.DecodeEPC supports, including those
+// added with epc.RegisterScheme, to their Pure Identity URIs. As with
+// SGTINDecoder, EPCs with out of range values are rejected.
+type EPCDecoder struct{}
+
+// Decode returns the Pure Identity URI of the EPC data.
+func (ed EPCDecoder) Decode(data []byte) (string, error) {
+	_, uri, err := ed.DecodeValue(data)
+	return uri, err
+}
+
+// DecodeValue returns the epc.EPC of the data and its Pure Identity URI.
+func (EPCDecoder) DecodeValue(data []byte) (interface{}, string, error) {
+	e, err := epc.DecodeEPC(data)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := e.ValidateRanges(); err != nil {
+		return nil, "", errors.Wrapf(err, "invalid %s", e.Scheme())
+	}
+	return e, e.URI(), nil
+}
+
+// CanDecode returns true if data has a header epc.DecodeEPC supports.
+func (EPCDecoder) CanDecode(data []byte) bool {
+	return len(data) > 0 && epc.SupportsHeader(data[0])
+}
+
+// BitTagDecoder decodes data with a bittag.Decoder to the URIs of its BitTags.
+type BitTagDecoder struct {
+	Decoder bittag.Decoder
+}
+
+// Decode returns the URI of the BitTag the Decoder decodes from the data.
+func (btd BitTagDecoder) Decode(data []byte) (string, error) {
+	bt, err := btd.Decoder.Decode(data)
+	if err != nil {
+		return "", err
+	}
+	return bt.URI(), nil
+}
+
+// DecodeValue returns the bittag.BitTag the Decoder decodes from the data and
+// its URI.
+func (btd BitTagDecoder) DecodeValue(data []byte) (interface{}, string, error) {
+	bt, err := btd.Decoder.Decode(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return bt, bt.URI(), nil
+}
+
+// CanDecode returns true if the data has the right length for the Decoder, as
+// by bittag.Decoder's CanDecode method.
+func (btd BitTagDecoder) CanDecode(data []byte) bool {
+	return btd.Decoder.CanDecode(data)
+}
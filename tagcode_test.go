@@ -0,0 +1,79 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bitextract"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/bittag"
+	"testing"
+)
+
+func TestTagDecoders(t *testing.T) {
+	w := expect.WrapT(t)
+
+	bitTagDecoder := w.ShouldHaveResult(bittag.NewDecoder(
+		"test.com", "2019-01-01", []int{8, 48, 40})).(bittag.Decoder)
+	restDecoder := w.ShouldHaveResult(bittag.NewDecoder(
+		"test.com", "2019-01-01", []int{8, bitextract.RestOfData})).(bittag.Decoder)
+
+	testCases := []struct {
+		name    string
+		decoder TagDecoder
+		data    string
+		uri     string
+	}{
+		{"sgtin-96", SGTINDecoder{}, "3034257BF7194E4000001A85",
+			"urn:epc:id:sgtin:0614141.812345.6789"},
+		{"sgtin-198", SGTINDecoder{}, "36143639F84191A465D9B37A176C5EB1769D72E557D52E5CBC",
+			"urn:epc:id:sgtin:0888446.067142.Hello!;1=1;'..*_*..%2F"},
+		{"epc sgtin", EPCDecoder{}, "3034257BF7194E4000001A85",
+			"urn:epc:id:sgtin:0614141.812345.6789"},
+		{"epc sscc", EPCDecoder{}, "3134257BF4499602D2000000",
+			"urn:epc:id:sscc:0614141.1234567890"},
+		{"bittag", BitTagDecoder{bitTagDecoder}, "0F00000000000C00000014D2",
+			"tag:test.com,2019-01-01:15.12.5330"},
+		{"bittag rest", BitTagDecoder{restDecoder}, "0F0C",
+			"tag:test.com,2019-01-01:15.12"},
+	}
+	for _, tc := range testCases {
+		w := w.As(tc.name)
+		data := w.ShouldHaveResult(hex.DecodeString(tc.data)).([]byte)
+		w.ShouldBeTrue(tc.decoder.CanDecode(data))
+		w.ShouldBeEqual(w.ShouldHaveResult(tc.decoder.Decode(data)), tc.uri)
+
+		value, uri, err := tc.decoder.(ValueDecoder).DecodeValue(data)
+		w.ShouldSucceed(err)
+		w.ShouldBeEqual(uri, tc.uri)
+		w.ShouldBeTrue(value != nil)
+	}
+
+	failCases := []struct {
+		name      string
+		decoder   TagDecoder
+		data      string
+		canDecode bool
+	}{
+		{"sgtin empty", SGTINDecoder{}, "", false},
+		{"sgtin header", SGTINDecoder{}, "3134257BF4499602D2000000", false},
+		{"sgtin length", SGTINDecoder{}, "3034257BF7194E4000001A", false},
+		{"sgtin range", SGTINDecoder{}, "301000181C7FFFD3A8B43711", true},
+		{"epc empty", EPCDecoder{}, "", false},
+		{"epc header", EPCDecoder{}, "E2801160600002054CC2096F", false},
+		{"epc length", EPCDecoder{}, "3034257BF7194E4000001A", true},
+		{"epc range", EPCDecoder{}, "301000181C7FFFD3A8B43711", true},
+		{"bittag length", BitTagDecoder{bitTagDecoder}, "0F00000000000C00000014", false},
+		{"bittag rest length", BitTagDecoder{restDecoder}, "", false},
+	}
+	for _, tc := range failCases {
+		w := w.As(tc.name)
+		data := w.ShouldHaveResult(hex.DecodeString(tc.data)).([]byte)
+		w.ShouldBeEqual(tc.decoder.CanDecode(data), tc.canDecode)
+		w.ShouldHaveError(tc.decoder.Decode(data))
+	}
+}
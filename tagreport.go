@@ -0,0 +1,132 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"fmt"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/epc"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/gen2"
+	"github.com/intel/rsp-sw-toolkit-im-suite-tagcode/tid"
+)
+
+// TagReport combines what's known about a tag from a single read: its PC, XPC,
+// EPC, and TID, as reader integrations report them, decoded and checked against
+// each other.
+type TagReport struct {
+	PC  gen2.PC
+	XPC gen2.XPC
+	// EPC is the tag's EPC, or its UII if PC.Toggle is set.
+	EPC []byte
+	// TID is the tag's decoded TID, or nil if it wasn't read or is invalid.
+	TID *tid.TID
+
+	// URI is the canonical URI of the EPC, or the UII's identifier, as
+	// returned by gen2.DecodeUII, or "" if it can't be decoded.
+	URI string
+	// Scheme is the EPC's scheme, such as "sgtin", taken from its URI.
+	Scheme string
+	// Vendor and Model are the names of the TID's mask designer and model,
+	// or "" if they're unknown.
+	Vendor, Model string
+
+	// LengthValid is set if the PC's length matches the EPC's.
+	LengthValid bool
+	// XPCValid is set if the PC's XI bit and the XPC's XEB bit match the
+	// XPC words read.
+	XPCValid bool
+	// EPCValid is set if the EPC, or UII, was decoded.
+	EPCValid bool
+	// TIDValid is set if the TID was decoded.
+	TIDValid bool
+	// Problems describes why each of the flags that isn't set isn't,
+	// except for TIDValid if no TID was read.
+	Problems []string
+}
+
+// Valid returns true if all the TagReport's validation flags are set, other than
+// TIDValid if no TID was read.
+func (tr TagReport) Valid() bool {
+	return len(tr.Problems) == 0
+}
+
+// ParseTagRead returns the TagReport of a tag read. The pc is the PC word sent
+// or stored with the EPC: since the PacketPC's length also counts XPC words,
+// either its length or the EPC's length without them is accepted. The xpc holds
+// the XPC words read, if any, which may be nil if the PC's XI bit isn't set;
+// likewise, the tidBank may be nil if the TID bank wasn't read. The epcData may
+// be padded to a whole word, as tags store it, or not, as package epc encodes
+// EPCs whose bit length isn't a whole number of words, such as SGTIN-198s.
+//
+// It doesn't return an error, since readers report whatever tags send; instead,
+// the TagReport's validation flags and Problems say what's wrong with the read.
+func ParseTagRead(pc uint16, epcData []byte, xpc []uint16, tidBank []byte) TagReport {
+	tr := TagReport{PC: gen2.DecodePC(pc), EPC: epcData}
+	problem := func(format string, args ...interface{}) {
+		tr.Problems = append(tr.Problems, fmt.Sprintf(format, args...))
+	}
+
+	var w1, w2 uint16
+	if len(xpc) > 0 {
+		w1 = xpc[0]
+	}
+	if len(xpc) > 1 {
+		w2 = xpc[1]
+	}
+	tr.XPC = gen2.DecodeXPC(w1, w2)
+	switch {
+	case len(xpc) > 2:
+		problem("tags have at most 2 XPC words, but %d were read", len(xpc))
+	case tr.PC.XI != (w1 != 0):
+		problem("the PC's XI bit is %t, but XPC_W1 is %04X", tr.PC.XI, w1)
+	case tr.XPC.XEB && len(xpc) != 2:
+		problem("XPC_W1's XEB bit is set, but XPC_W2 wasn't read")
+	case !tr.XPC.XEB && len(xpc) == 2:
+		problem("XPC_W2 was read, but XPC_W1's XEB bit isn't set")
+	default:
+		tr.XPCValid = true
+	}
+
+	epcWords := (len(epcData) + 1) / 2
+	switch {
+	case len(epcData)%2 != 0 && !isUnpaddedEPC(epcData):
+		problem("the EPC has %d bytes, which isn't a whole number of words",
+			len(epcData))
+	case tr.PC.Length != epcWords && tr.PC.Length != epcWords+tr.XPC.Words():
+		problem("the PC's length is %d words, but the EPC has %d words",
+			tr.PC.Length, epcWords)
+	default:
+		tr.LengthValid = true
+	}
+
+	if uri, err := gen2.DecodeUII(tr.PC, epcData); err != nil {
+		problem("invalid EPC: %v", err)
+	} else {
+		tr.URI, tr.Scheme, tr.EPCValid = uri, uriScheme(uri), true
+	}
+
+	if tidBank == nil {
+		return tr
+	}
+	if t, err := tid.Decode(tidBank); err != nil {
+		problem("invalid TID: %v", err)
+	} else {
+		tr.TID, tr.TIDValid = &t, true
+		tr.Vendor, tr.Model = t.MaskDesigner(), t.ModelName()
+	}
+	return tr
+}
+
+// isUnpaddedEPC returns true if data is an EPC without the padding to a whole
+// word the tag has, i.e., it has exactly the bytes for the bit length of the
+// scheme its header identifies.
+func isUnpaddedEPC(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	_, bits, ok := epc.SchemeForHeader(data[0])
+	return ok && bits > 0 && len(data) == (bits+7)/8
+}
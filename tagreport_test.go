@@ -0,0 +1,91 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tagcode
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"testing"
+)
+
+func TestParseTagRead(t *testing.T) {
+	w := expect.WrapT(t)
+	sgtin := w.ShouldHaveResult(hex.DecodeString("3034257BF7194E4000001A85")).([]byte)
+	tidBank := w.ShouldHaveResult(hex.DecodeString("E2801105200060034A2D09A2")).([]byte)
+
+	tr := ParseTagRead(0x3000, sgtin, nil, tidBank)
+	w.ShouldBeTrue(tr.Valid())
+	w.ShouldBeEqual(tr.URI, "urn:epc:id:sgtin:0614141.812345.6789")
+	w.ShouldBeEqual(tr.Scheme, "sgtin")
+	w.ShouldBeEqual(tr.Vendor, "Impinj")
+	w.ShouldBeEqual(tr.Model, "Monza 4QT")
+	w.ShouldBeEqual(tr.TID.UniqueID(), "E2.001.105.60034A2D09A2")
+	w.ShouldBeTrue(tr.LengthValid && tr.XPCValid && tr.EPCValid && tr.TIDValid)
+
+	// without a TID
+	tr = ParseTagRead(0x3000, sgtin, nil, nil)
+	w.ShouldBeTrue(tr.Valid())
+	w.ShouldBeFalse(tr.TIDValid)
+	w.ShouldBeEqual(tr.Vendor, "")
+
+	// with an XPC, the PacketPC's length may count it, or not
+	tr = ParseTagRead(0x3A00, sgtin, []uint16{0x0880}, nil)
+	w.ShouldBeTrue(tr.Valid())
+	w.ShouldBeTrue(tr.XPC.BatteryAssisted)
+	w.ShouldBeTrue(ParseTagRead(0x3200, sgtin, []uint16{0x0880}, nil).Valid())
+	w.ShouldBeTrue(ParseTagRead(0x3000, sgtin, []uint16{0}, nil).Valid())
+	w.ShouldBeTrue(ParseTagRead(0x4200, sgtin, []uint16{0x8800, 0x0001}, nil).Valid())
+
+	// SGTIN-198s are read as 13 words, but may be given without their padding
+	sgtin198 := w.ShouldHaveResult(hex.DecodeString(
+		"3614257BF7194E60C286C4000000000000000000000000000000")).([]byte)
+	for _, epcData := range [][]byte{sgtin198, sgtin198[:25]} {
+		tr = ParseTagRead(0x6800, epcData, nil, nil)
+		w.As(len(epcData)).ShouldBeTrue(tr.Valid())
+		w.ShouldBeEqual(tr.URI, "urn:epc:id:sgtin:0614141.812345.ABC1")
+	}
+	w.ShouldBeFalse(ParseTagRead(0x6800, sgtin198[:24], nil, nil).LengthValid)
+
+	for _, tc := range []struct {
+		name string
+		pc   uint16
+		epc  []byte
+		xpc  []uint16
+		tid  []byte
+		// problems is the number of Problems, if not 1
+		problems int
+		ok       func(TagReport) bool
+	}{
+		{"wrong length", 0x2800, sgtin, nil, nil, 0,
+			func(tr TagReport) bool { return !tr.LengthValid && tr.EPCValid }},
+		{"odd length", 0x3000, sgtin[:11], nil, nil, 2,
+			func(tr TagReport) bool { return !tr.LengthValid && !tr.EPCValid }},
+		{"no XI", 0x3000, sgtin, []uint16{0x0880}, nil, 0,
+			func(tr TagReport) bool { return !tr.XPCValid && tr.LengthValid }},
+		{"no XPC", 0x3200, sgtin, nil, nil, 0,
+			func(tr TagReport) bool { return !tr.XPCValid }},
+		{"no XPC_W2", 0x4200, sgtin, []uint16{0x8800}, nil, 0,
+			func(tr TagReport) bool { return !tr.XPCValid }},
+		{"extra XPC_W2", 0x3A00, sgtin, []uint16{0x0800, 0x0001}, nil, 0,
+			func(tr TagReport) bool { return !tr.XPCValid }},
+		{"3 XPC words", 0x4200, sgtin, []uint16{0x8800, 0x0001, 0}, nil, 0,
+			func(tr TagReport) bool { return !tr.XPCValid }},
+		{"unknown AFI", 0x31FF, sgtin, nil, nil, 0,
+			func(tr TagReport) bool { return !tr.EPCValid && tr.URI == "" }},
+		{"invalid TID", 0x3000, sgtin, nil, []byte{0xE2}, 0,
+			func(tr TagReport) bool { return !tr.TIDValid && tr.TID == nil && tr.EPCValid }},
+	} {
+		w := w.As(tc.name)
+		tr := ParseTagRead(tc.pc, tc.epc, tc.xpc, tc.tid)
+		w.ShouldBeFalse(tr.Valid())
+		if tc.problems == 0 {
+			tc.problems = 1
+		}
+		w.ShouldBeEqual(len(tr.Problems), tc.problems)
+		w.ShouldBeTrue(tc.ok(tr))
+	}
+}
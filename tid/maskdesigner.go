@@ -0,0 +1,136 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tid
+
+import (
+	"fmt"
+	"sync"
+)
+
+// MaxMDID is the largest mask designer ID, which is 9 bits.
+const MaxMDID = 0x1FF
+
+// MaxModel is the largest tag model number, which is 12 bits.
+const MaxModel = 0xFFF
+
+// modelKey identifies a mask designer's model.
+type modelKey struct {
+	mdid, model uint16
+}
+
+var (
+	registryMu    sync.RWMutex
+	maskDesigners = map[uint16]string{
+		0x001: "Impinj",
+		0x002: "Texas Instruments",
+		0x003: "Alien Technology",
+		0x004: "Intelleflex",
+		0x005: "Atmel",
+		0x006: "NXP Semiconductors",
+		0x007: "STMicroelectronics",
+		0x008: "EP Microelectronics",
+		0x009: "Motorola",
+		0x00A: "Sentech",
+		0x00B: "EM Microelectronic",
+		0x00C: "Renesas Technology",
+		0x00D: "Mstar",
+		0x00E: "Tyco International",
+		0x00F: "Quanray Electronics",
+		0x010: "Fujitsu",
+		0x011: "LSIS",
+		0x012: "CAEN RFID",
+		0x013: "Productivity Engineering",
+		0x014: "Federal Electric",
+		0x015: "ON Semiconductor",
+		0x016: "Ramtron",
+		0x017: "Tego",
+		0x018: "Ceitec",
+		0x019: "CPA Wernher von Braun",
+		0x01A: "TransCore",
+		0x01B: "Nationz",
+		0x01C: "Invengo",
+		0x01D: "Kiloway",
+		0x01E: "Longjing Microelectronics",
+		0x01F: "Chipus Microelectronics",
+	}
+	models = map[modelKey]string{
+		{0x001, 0x100}: "Monza 4D",
+		{0x001, 0x105}: "Monza 4QT",
+		{0x001, 0x10C}: "Monza 4E",
+		{0x001, 0x130}: "Monza 5",
+		{0x001, 0x160}: "Monza R6",
+		{0x001, 0x170}: "Monza R6-P",
+		{0x003, 0x412}: "Higgs-3",
+		{0x003, 0x414}: "Higgs-4",
+		{0x006, 0x810}: "UCODE 7",
+		{0x006, 0x894}: "UCODE 8",
+		{0x006, 0x915}: "UCODE 9",
+	}
+)
+
+// RegisterMaskDesigner names the mask designer with the given MDID, such as one
+// GS1 assigned after this package was written. It panics if the MDID is larger
+// than MaxMDID or already has a name, including those built into this package,
+// or the name is empty. It's typically called from an init function.
+func RegisterMaskDesigner(mdid uint16, name string) {
+	if mdid > MaxMDID {
+		panic(fmt.Sprintf("tid: RegisterMaskDesigner MDID %03X is larger "+
+			"than 9 bits", mdid))
+	}
+	if name == "" {
+		panic("tid: RegisterMaskDesigner name is empty")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := maskDesigners[mdid]; dup {
+		panic(fmt.Sprintf("tid: RegisterMaskDesigner called twice for MDID %03X",
+			mdid))
+	}
+	maskDesigners[mdid] = name
+}
+
+// RegisterModel names the model with the given tag model number by the mask
+// designer with the given MDID, such as "Monza R6" for Impinj's 160h. It panics
+// if the MDID or model number are too large, the model already has a name, or
+// the name is empty. It's typically called from an init function.
+func RegisterModel(mdid, model uint16, name string) {
+	if mdid > MaxMDID || model > MaxModel {
+		panic(fmt.Sprintf("tid: RegisterModel MDID %03X or model %03X is "+
+			"too large", mdid, model))
+	}
+	if name == "" {
+		panic("tid: RegisterModel name is empty")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	key := modelKey{mdid, model}
+	if _, dup := models[key]; dup {
+		panic(fmt.Sprintf("tid: RegisterModel called twice for MDID %03X "+
+			"model %03X", mdid, model))
+	}
+	models[key] = name
+}
+
+// MaskDesigner returns the name of the mask designer with the given MDID, or
+// false if it's unknown.
+func MaskDesigner(mdid uint16) (string, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	name, ok := maskDesigners[mdid]
+	return name, ok
+}
+
+// ModelName returns the name of the mask designer's model with the given tag
+// model number, or false if it's unknown.
+func ModelName(mdid, model uint16) (string, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	name, ok := models[modelKey{mdid, model}]
+	return name, ok
+}
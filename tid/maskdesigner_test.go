@@ -0,0 +1,53 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tid
+
+import (
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"sync"
+	"testing"
+)
+
+// registerTest registers the test mask designer and model once, even if tests
+// are repeated.
+var registerTest sync.Once
+
+func TestRegisterMaskDesigner(t *testing.T) {
+	w := expect.WrapT(t)
+
+	registerTest.Do(func() {
+		_, ok := MaskDesigner(0x1F0)
+		w.ShouldBeFalse(ok)
+		RegisterMaskDesigner(0x1F0, "Example Chips")
+		RegisterModel(0x1F0, 0x001, "EX-1")
+	})
+	name, ok := MaskDesigner(0x1F0)
+	w.ShouldBeTrue(ok)
+	w.ShouldBeEqual(name, "Example Chips")
+	name, ok = ModelName(0x1F0, 0x001)
+	w.ShouldBeTrue(ok)
+	w.ShouldBeEqual(name, "EX-1")
+	_, ok = ModelName(0x1F0, 0x002)
+	w.ShouldBeFalse(ok)
+
+	tid := TID{Class: ClassGS1, MDID: 0x1F0, Model: 0x001}
+	w.ShouldBeEqual(tid.MaskDesigner(), "Example Chips")
+	w.ShouldBeEqual(tid.ModelName(), "EX-1")
+
+	panics := func(f func()) (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		f()
+		return
+	}
+	w.ShouldBeTrue(panics(func() { RegisterMaskDesigner(0x1F0, "Again") }))
+	w.ShouldBeTrue(panics(func() { RegisterMaskDesigner(0x001, "Not Impinj") }))
+	w.ShouldBeTrue(panics(func() { RegisterMaskDesigner(0x200, "Too Large") }))
+	w.ShouldBeTrue(panics(func() { RegisterMaskDesigner(0x1F1, "") }))
+	w.ShouldBeTrue(panics(func() { RegisterModel(0x1F0, 0x001, "Again") }))
+	w.ShouldBeTrue(panics(func() { RegisterModel(0x001, 0x1000, "Too Large") }))
+	w.ShouldBeTrue(panics(func() { RegisterModel(0x1F0, 0x002, "") }))
+}
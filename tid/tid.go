@@ -0,0 +1,170 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package tid decodes the TID memory bank of Gen2 RFID tags, which identifies
+// the tag's chip: its allocation class, mask designer, which is the company
+// that designed it, model, and often a serial number, which UniqueID combines. Its layout is specified by the GS1 EPC Tag Data
+// Standard, section 16, and the GS1 EPC UHF Gen2 Air Interface Protocol; see
+// package gen2 for the other memory banks.
+//
+// Mask designers are identified by the MDIDs GS1 assigns, which MaskDesigner
+// names; the names of well-known designers and some of their models are built
+// in, and others can be added with RegisterMaskDesigner and RegisterModel.
+package tid
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+)
+
+// Allocation class identifiers, the first byte of the TID bank, which say how
+// the rest of it is laid out.
+const (
+	// ClassISO7816 TIDs have an 8-bit ISO/IEC 7816-6 IC manufacturer code
+	// and a 48-bit serial number.
+	ClassISO7816 = 0xE0
+	// ClassGS1 TIDs have the layout specified by GS1: XTID, security, and
+	// file indicators, a 9-bit mask designer ID, and a 12-bit model number,
+	// optionally followed by an XTID, which may include a serial number.
+	ClassGS1 = 0xE2
+)
+
+// TID is a decoded TID memory bank.
+type TID struct {
+	// Class is the allocation class identifier, bits 00h-07h.
+	Class uint8
+
+	// XTID, bit 08h of ClassGS1 TIDs, is set if the TID has an XTID header
+	// at bits 20h-2Fh, followed by the segments it describes.
+	XTID bool
+	// Security, bit 09h of ClassGS1 TIDs, is set if the tag supports
+	// Authenticate and Challenge commands.
+	Security bool
+	// File, bit 0Ah of ClassGS1 TIDs, is set if the tag supports the
+	// FileOpen command.
+	File bool
+	// MDID is the mask designer ID, bits 0Bh-13h of ClassGS1 TIDs.
+	MDID uint16
+	// Model is the tag model number (TMN), bits 14h-1Fh of ClassGS1 TIDs,
+	// which is assigned by the mask designer.
+	Model uint16
+	// XTIDHeader is the decoded XTID header of ClassGS1 TIDs with an XTID,
+	// or nil if they don't have one or the bank ends before it.
+	XTIDHeader *XTIDHeader
+
+	// Manufacturer is the ISO/IEC 7816-6 IC manufacturer code, bits
+	// 08h-0Fh of ClassISO7816 TIDs.
+	Manufacturer uint8
+	// Serial is the serial number: bits 10h-3Fh of ClassISO7816 TIDs, or
+	// the serial number segment of ClassGS1 TIDs' XTIDs, of 48 to 144 bits,
+	// starting at bit 30h. It's nil if the TID doesn't have one, or the bank
+	// ends before it.
+	Serial []byte
+}
+
+// Decode decodes a TID bank read from its first word. ClassGS1 TIDs need at
+// least 4 bytes, and ClassISO7816 TIDs, 8; any bytes after them are ignored.
+// It returns an error if the bank is too short, or its class is neither.
+//
+// Since readers are often configured to read only part of the TID bank, the
+// XTID header and segments of ClassGS1 TIDs are decoded only if the bank holds
+// them; otherwise, they're left unset. The TID's Serial is a slice of the bank,
+// rather than a copy.
+func Decode(bank []byte) (TID, error) {
+	if len(bank) == 0 {
+		return TID{}, errors.New("the TID bank is empty")
+	}
+
+	t := TID{Class: bank[0]}
+	switch t.Class {
+	case ClassGS1:
+		if len(bank) < 4 {
+			return TID{}, errors.Errorf("a GS1 TID needs at least 4 bytes, "+
+				"but has %d", len(bank))
+		}
+		t.XTID = bank[1]&0x80 != 0
+		t.Security = bank[1]&0x40 != 0
+		t.File = bank[1]&0x20 != 0
+		t.MDID = uint16(bank[1]&0x1F)<<4 | uint16(bank[2]>>4)
+		t.Model = uint16(bank[2]&0x0F)<<8 | uint16(bank[3])
+		if t.XTID && len(bank) >= 6 {
+			t.XTIDHeader, t.Serial = decodeXTID(bank)
+		}
+	case ClassISO7816:
+		if len(bank) < 8 {
+			return TID{}, errors.Errorf("an ISO/IEC 7816-6 TID needs at least "+
+				"8 bytes, but has %d", len(bank))
+		}
+		t.Manufacturer = bank[1]
+		t.Serial = bank[2:8]
+	default:
+		return TID{}, errors.Errorf("unsupported TID allocation class %02Xh",
+			t.Class)
+	}
+	return t, nil
+}
+
+// MaskDesigner returns the name of the mask designer of a ClassGS1 TID, or ""
+// if it's unknown; see RegisterMaskDesigner.
+func (t TID) MaskDesigner() string {
+	if t.Class != ClassGS1 {
+		return ""
+	}
+	name, _ := MaskDesigner(t.MDID)
+	return name
+}
+
+// ModelName returns the name of the model of a ClassGS1 TID, or "" if it's
+// unknown; see RegisterModel.
+func (t TID) ModelName() string {
+	if t.Class != ClassGS1 {
+		return ""
+	}
+	name, _ := ModelName(t.MDID, t.Model)
+	return name
+}
+
+// UniqueID returns an identifier unique to the tag's chip, if its TID has a
+// serial number, or "" otherwise. Unlike EPCs, which may be misprogrammed so
+// that several tags have the same one, TID serial numbers are programmed at the
+// factory, so the UniqueID can detect duplicate tags.
+//
+// It combines the TID's allocation class with the fields that identify chips
+// of that class, in hex, separated by "."s: the MDID, model number, and serial
+// of ClassGS1 TIDs, such as "E2.001.160.0123456789AB", or the manufacturer code
+// and serial of ClassISO7816 TIDs, such as "E0.04.0123456789AB".
+func (t TID) UniqueID() string {
+	switch {
+	case t.Serial == nil:
+		return ""
+	case t.Class == ClassISO7816:
+		return fmt.Sprintf("%02X.%02X.%X", t.Class, t.Manufacturer, t.Serial)
+	}
+	return fmt.Sprintf("%02X.%03X.%03X.%X", t.Class, t.MDID, t.Model, t.Serial)
+}
+
+// String formats the TID's fields, with the names of its mask designer and
+// model, if they're known.
+func (t TID) String() string {
+	if t.Class == ClassISO7816 {
+		return fmt.Sprintf("class=%02X manufacturer=%02X serial=%X",
+			t.Class, t.Manufacturer, t.Serial)
+	}
+
+	s := fmt.Sprintf("class=%02X xtid=%t security=%t file=%t mdid=%03X",
+		t.Class, t.XTID, t.Security, t.File, t.MDID)
+	if name := t.MaskDesigner(); name != "" {
+		s += fmt.Sprintf(" (%s)", name)
+	}
+	s += fmt.Sprintf(" model=%03X", t.Model)
+	if name := t.ModelName(); name != "" {
+		s += fmt.Sprintf(" (%s)", name)
+	}
+	if t.Serial != nil {
+		s += fmt.Sprintf(" serial=%X", t.Serial)
+	}
+	return s
+}
@@ -0,0 +1,116 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tid
+
+import (
+	"encoding/hex"
+	"github.com/intel/rsp-sw-toolkit-im-suite-expect"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	w := expect.WrapT(t)
+
+	testCases := []struct {
+		bank         string
+		tid          TID
+		maskDesigner string
+		model        string
+	}{
+		{"E2801160", TID{Class: ClassGS1, XTID: true, MDID: 0x001, Model: 0x160},
+			"Impinj", "Monza R6"},
+		{"E2801105", TID{Class: ClassGS1, XTID: true, MDID: 0x001, Model: 0x105},
+			"Impinj", "Monza 4QT"},
+		{"E2806894", TID{Class: ClassGS1, XTID: true, MDID: 0x006, Model: 0x894},
+			"NXP Semiconductors", "UCODE 8"},
+		{"E2003412", TID{Class: ClassGS1, MDID: 0x003, Model: 0x412},
+			"Alien Technology", "Higgs-3"},
+		{"E2E1F123", TID{Class: ClassGS1, XTID: true, Security: true, File: true,
+			MDID: 0x01F, Model: 0x123}, "Chipus Microelectronics", ""},
+		{"E21FF000", TID{Class: ClassGS1, MDID: 0x1FF}, "", ""},
+		{"E0040123456789AB", TID{Class: ClassISO7816, Manufacturer: 0x04,
+			Serial: []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB}}, "", ""},
+	}
+	for _, tc := range testCases {
+		w := w.As(tc.bank)
+		bank := w.ShouldHaveResult(hex.DecodeString(tc.bank)).([]byte)
+		tid := w.ShouldHaveResult(Decode(bank)).(TID)
+		w.ShouldBeEqual(tid, tc.tid)
+		w.ShouldBeEqual(tid.MaskDesigner(), tc.maskDesigner)
+		w.ShouldBeEqual(tid.ModelName(), tc.model)
+	}
+
+	for _, invalid := range []string{"", "E2", "E28011", "E004012345", "E1801160"} {
+		bank := w.ShouldHaveResult(hex.DecodeString(invalid)).([]byte)
+		w.As(invalid).ShouldFail(Decode(bank))
+	}
+}
+
+func TestTID_String(t *testing.T) {
+	w := expect.WrapT(t)
+
+	w.ShouldBeEqual(TID{Class: ClassGS1, XTID: true, MDID: 0x001, Model: 0x160}.String(),
+		"class=E2 xtid=true security=false file=false mdid=001 (Impinj) model=160 (Monza R6)")
+	w.ShouldBeEqual(TID{Class: ClassGS1, MDID: 0x1FF, Model: 0xFFF}.String(),
+		"class=E2 xtid=false security=false file=false mdid=1FF model=FFF")
+	w.ShouldBeEqual(TID{Class: ClassISO7816, Manufacturer: 0x04,
+		Serial: []byte{0, 0, 0, 0, 0, 0xAB}}.String(),
+		"class=E0 manufacturer=04 serial=0000000000AB")
+	w.ShouldBeEqual(TID{Class: ClassGS1, MDID: 0x006, Model: 0x894,
+		Serial: []byte{1, 2, 3, 4, 5, 6}}.String(), "class=E2 xtid=false security=false "+
+		"file=false mdid=006 (NXP Semiconductors) model=894 (UCODE 8) serial=010203040506")
+}
+
+func TestDecode_xtid(t *testing.T) {
+	w := expect.WrapT(t)
+
+	testCases := []struct {
+		name     string
+		bank     string
+		header   *XTIDHeader
+		serial   string
+		uniqueID string
+	}{
+		{"48-bit serial", "E2801105200060034A2D09A2",
+			&XTIDHeader{Word: 0x2000, Serialization: 1},
+			"60034A2D09A2", "E2.001.105.60034A2D09A2"},
+		{"64-bit serial", "E2806894" + "4800" + "0123456789ABCDEF" + "0000",
+			&XTIDHeader{Word: 0x4800, Serialization: 2, OptionalCommandSupport: true},
+			"0123456789ABCDEF", "E2.006.894.0123456789ABCDEF"},
+		{"segments", "E2801160" + "0E00",
+			&XTIDHeader{Word: 0x0E00, OptionalCommandSupport: true,
+				BlockWriteErase: true, UserMemoryPermaLock: true},
+			"", ""},
+		{"truncated serial", "E2801105" + "2000" + "60034A2D",
+			&XTIDHeader{Word: 0x2000, Serialization: 1}, "", ""},
+		{"extended", "E2801105" + "2001" + "60034A2D09A2",
+			&XTIDHeader{Word: 0x2001, Extended: true, Serialization: 1}, "", ""},
+		{"no header", "E2801105", nil, "", ""},
+		{"no xtid", "E2001105" + "2000" + "60034A2D09A2", nil, "", ""},
+		{"iso", "E0040123456789AB", nil, "0123456789AB", "E0.04.0123456789AB"},
+	}
+	for _, tc := range testCases {
+		w := w.As(tc.name)
+		bank := w.ShouldHaveResult(hex.DecodeString(tc.bank)).([]byte)
+		tid := w.ShouldHaveResult(Decode(bank)).(TID)
+		w.ShouldBeEqual(tid.XTIDHeader, tc.header)
+		w.ShouldBeEqual(hex.EncodeToString(tid.Serial), strings.ToLower(tc.serial))
+		w.ShouldBeEqual(tid.Serial == nil, tc.serial == "")
+		w.ShouldBeEqual(tid.UniqueID(), tc.uniqueID)
+	}
+}
+
+func TestXTIDHeader_SerialBits(t *testing.T) {
+	w := expect.WrapT(t)
+
+	for n, bits := range []int{0, 48, 64, 80, 96, 112, 128, 144} {
+		h := DecodeXTIDHeader(uint16(n) << 13)
+		w.As(n).ShouldBeEqual(h.Serialization, n)
+		w.As(n).ShouldBeEqual(h.SerialBits(), bits)
+	}
+}
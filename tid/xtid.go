@@ -0,0 +1,79 @@
+/* Apache v2 license
+ * Copyright (C) 2019 Intel Corporation
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tid
+
+import (
+	"encoding/binary"
+)
+
+// XTIDHeader is the decoded header of a ClassGS1 TID's eXtended TID, at bits
+// 20h-2Fh, which says which segments follow it, starting at bit 30h, in this
+// order: the serial number, and the optional command support, BlockWrite and
+// BlockErase, and user memory and BlockPermaLock segments.
+type XTIDHeader struct {
+	// Word is the raw header word.
+	Word uint16
+	// Extended, bit 2Fh, is set if more header words follow this one. No
+	// such words are defined, so the segments can't be located if it's set.
+	Extended bool
+	// Serialization, bits 20h-22h, is 0 if the XTID doesn't have a serial
+	// number, or otherwise N, for a serial number of 48 + 16(N - 1) bits.
+	Serialization int
+	// OptionalCommandSupport, bit 24h, is set if the XTID has a 16-bit
+	// segment describing the tag's support for optional commands.
+	OptionalCommandSupport bool
+	// BlockWriteErase, bit 25h, is set if the XTID has a 64-bit segment
+	// describing the tag's support for the BlockWrite and BlockErase
+	// commands.
+	BlockWriteErase bool
+	// UserMemoryPermaLock, bit 26h, is set if the XTID has a 32-bit segment
+	// describing the tag's user memory and its support for the
+	// BlockPermaLock command.
+	UserMemoryPermaLock bool
+}
+
+// XTID header bits.
+const (
+	xtidExtended            = 1 << 0
+	xtidUserMemoryPermaLock = 1 << 9
+	xtidBlockWriteErase     = 1 << 10
+	xtidOptionalCommands    = 1 << 11
+	xtidSerializationShift  = 13
+)
+
+// DecodeXTIDHeader decodes an XTID header word.
+func DecodeXTIDHeader(word uint16) XTIDHeader {
+	return XTIDHeader{
+		Word:                   word,
+		Extended:               word&xtidExtended != 0,
+		Serialization:          int(word >> xtidSerializationShift),
+		OptionalCommandSupport: word&xtidOptionalCommands != 0,
+		BlockWriteErase:        word&xtidBlockWriteErase != 0,
+		UserMemoryPermaLock:    word&xtidUserMemoryPermaLock != 0,
+	}
+}
+
+// SerialBits returns the length of the XTID's serial number in bits, which is 0
+// if it doesn't have one.
+func (h XTIDHeader) SerialBits() int {
+	if h.Serialization == 0 {
+		return 0
+	}
+	return 48 + 16*(h.Serialization-1)
+}
+
+// decodeXTID decodes the XTID header of a ClassGS1 TID bank with at least 6
+// bytes, and returns it with the serial number, if the XTID has one and the
+// bank holds all of it.
+func decodeXTID(bank []byte) (*XTIDHeader, []byte) {
+	h := DecodeXTIDHeader(binary.BigEndian.Uint16(bank[4:]))
+	end := 6 + h.SerialBits()/8
+	if h.Serialization == 0 || h.Extended || len(bank) < end {
+		return &h, nil
+	}
+	return &h, bank[6:end]
+}